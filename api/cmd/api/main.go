@@ -31,9 +31,12 @@ import (
 	"github.com/clerk/clerk-sdk-go/v2"
 	"github.com/deveasyclick/openb2b/internal/config"
 	"github.com/deveasyclick/openb2b/internal/db"
+	"github.com/deveasyclick/openb2b/internal/jobs"
 	"github.com/deveasyclick/openb2b/internal/middleware"
+	"github.com/deveasyclick/openb2b/internal/outbox"
 	"github.com/deveasyclick/openb2b/internal/routes"
 	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/safehttp"
 	clerkPkg "github.com/deveasyclick/openb2b/pkg/clerk"
 	"github.com/deveasyclick/openb2b/pkg/logger"
 	"github.com/deveasyclick/openb2b/pkg/mailer"
@@ -53,6 +56,8 @@ func main() {
 
 	mailer := mailer.NewMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPFrom)
 	dbConn := db.New(cfg.DBURL, logger)
+	jobQueue := jobs.New(dbConn, logger)
+	events := outbox.New(dbConn, jobQueue, logger)
 
 	appCtx := &deps.AppContext{
 		DB:     dbConn,
@@ -60,6 +65,10 @@ func main() {
 		Logger: logger,
 		Cache:  nil,
 		Mailer: mailer,
+		Jobs:   jobQueue,
+		Events: events,
+		// Webhook endpoints and other addresses orgs configure
+		HTTPClient: safehttp.NewClient(),
 	}
 
 	middlewares := middleware.New(appCtx)
@@ -67,6 +76,11 @@ func main() {
 
 	routes.Register(r, appCtx, middlewares, clerkService)
 
+	// Handlers and subscribers are registered by routes.Register, so start
+	// the worker and the event relay after it
+	jobQueue.Start(context.Background())
+	events.Start(context.Background())
+
 	port := cfg.Port
 	if port == 0 {
 		port = 8080 // default fallback
@@ -96,5 +110,15 @@ func main() {
 		logger.Fatal("Server forced to shutdown:", "error", err)
 	}
 
+	// Let running jobs finish, unfinished ones are picked up again on restart
+	jobCtx, jobCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer jobCancel()
+	if err := events.Stop(jobCtx); err != nil {
+		logger.Error("Event relay forced to shutdown:", "error", err)
+	}
+	if err := jobQueue.Stop(jobCtx); err != nil {
+		logger.Error("Job worker forced to shutdown:", "error", err)
+	}
+
 	logger.Info("Server exiting")
 }
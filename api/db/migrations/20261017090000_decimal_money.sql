@@ -0,0 +1,62 @@
+-- Amounts of money were stored as floats. Store them as exact decimals,
+-- rounded half up to the cent.
+
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE variants
+    ALTER COLUMN price TYPE numeric(12,2) USING round(price::numeric, 2);
+
+ALTER TABLE orders
+    ALTER COLUMN delivery_transport_fare TYPE numeric(12,2) USING round(delivery_transport_fare::numeric, 2),
+    ALTER COLUMN discount_amount TYPE numeric(12,2) USING round(discount_amount::numeric, 2),
+    ALTER COLUMN applied_discount TYPE numeric(12,2) USING round(applied_discount::numeric, 2),
+    ALTER COLUMN discount_total TYPE numeric(12,2) USING round(discount_total::numeric, 2),
+    ALTER COLUMN item_discount_total TYPE numeric(12,2) USING round(item_discount_total::numeric, 2),
+    ALTER COLUMN total TYPE numeric(12,2) USING round(total::numeric, 2),
+    ALTER COLUMN subtotal TYPE numeric(12,2) USING round(subtotal::numeric, 2),
+    ALTER COLUMN tax_total TYPE numeric(12,2) USING round(tax_total::numeric, 2);
+
+ALTER TABLE order_items
+    ALTER COLUMN unit_price TYPE numeric(12,2) USING round(unit_price::numeric, 2),
+    ALTER COLUMN total TYPE numeric(12,2) USING round(total::numeric, 2),
+    ALTER COLUMN tax_amount TYPE numeric(12,2) USING round(tax_amount::numeric, 2),
+    ALTER COLUMN discount_amount TYPE numeric(12,2) USING round(discount_amount::numeric, 2),
+    ALTER COLUMN applied_discount TYPE numeric(12,2) USING round(applied_discount::numeric, 2),
+    ALTER COLUMN applied_order_discount TYPE numeric(12,2) USING round(applied_order_discount::numeric, 2);
+
+ALTER TABLE invoice_items
+    ALTER COLUMN unit_price TYPE numeric(12,2) USING round(unit_price::numeric, 2),
+    ALTER COLUMN tax_amount TYPE numeric(12,2) USING round(tax_amount::numeric, 2),
+    ALTER COLUMN line_total TYPE numeric(12,2) USING round(line_total::numeric, 2),
+    ALTER COLUMN subtotal TYPE numeric(12,2) USING round(subtotal::numeric, 2);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE variants
+    ALTER COLUMN price TYPE double precision;
+
+ALTER TABLE orders
+    ALTER COLUMN delivery_transport_fare TYPE double precision,
+    ALTER COLUMN discount_amount TYPE double precision,
+    ALTER COLUMN applied_discount TYPE double precision,
+    ALTER COLUMN discount_total TYPE double precision,
+    ALTER COLUMN item_discount_total TYPE double precision,
+    ALTER COLUMN total TYPE double precision,
+    ALTER COLUMN subtotal TYPE double precision,
+    ALTER COLUMN tax_total TYPE double precision;
+
+ALTER TABLE order_items
+    ALTER COLUMN unit_price TYPE double precision,
+    ALTER COLUMN total TYPE double precision,
+    ALTER COLUMN tax_amount TYPE double precision,
+    ALTER COLUMN discount_amount TYPE double precision,
+    ALTER COLUMN applied_discount TYPE double precision,
+    ALTER COLUMN applied_order_discount TYPE double precision;
+
+ALTER TABLE invoice_items
+    ALTER COLUMN unit_price TYPE double precision,
+    ALTER COLUMN tax_amount TYPE double precision,
+    ALTER COLUMN line_total TYPE double precision,
+    ALTER COLUMN subtotal TYPE double precision;
+-- +goose StatementEnd
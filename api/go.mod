@@ -4,6 +4,7 @@ go 1.23.4
 
 require (
 	github.com/DATA-DOG/go-sqlmock v1.5.2
+	github.com/SebastiaanKlippert/go-wkhtmltopdf v1.9.3
 	github.com/clerk/clerk-sdk-go/v2 v2.3.1
 	github.com/go-chi/chi v1.5.5
 	github.com/go-chi/cors v1.2.2
@@ -24,7 +25,6 @@ require (
 
 require (
 	github.com/KyleBanks/depth v1.2.1 // indirect
-	github.com/SebastiaanKlippert/go-wkhtmltopdf v1.9.3 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/gabriel-vasile/mimetype v1.4.8 // indirect
 	github.com/go-jose/go-jose/v3 v3.0.3 // indirect
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/audit"
+	"github.com/deveasyclick/openb2b/internal/shared/tenant"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	gormlogger "github.com/deveasyclick/openb2b/pkg/logger/gorm"
 	"gorm.io/driver/postgres"
@@ -41,16 +43,57 @@ func New(url string, appLogger interfaces.Logger) *gorm.DB {
 		&model.Product{},
 		&model.Order{},
 		&model.OrderItem{},
+		&model.OrderStatusHistory{},
 		&model.User{},
 		&model.Org{},
 		&model.Invoice{},
 		&model.InvoiceItem{},
+		&model.Payment{},
+		&model.CreditNote{},
+		&model.CreditNoteItem{},
+		&model.StockMovement{},
+		&model.Job{},
+		&model.OutboxEvent{},
+		&model.WebhookSubscription{},
+		&model.WebhookDelivery{},
+		&model.APIKey{},
+		&model.IdempotencyKey{},
+		&model.AuditEntry{},
+		&model.ExchangeRate{},
+		&model.TaxRate{},
+		&model.TaxClass{},
+		&model.CustomerGroup{},
+		&model.PriceList{},
+		&model.PriceListItem{},
+		&model.Promotion{},
+		&model.PromotionRedemption{},
+		&model.OrderCharge{},
+		&model.InvoiceCharge{},
+		&model.Shipment{},
+		&model.ShipmentLine{},
+		&model.Supplier{},
+		&model.PurchaseOrder{},
+		&model.PurchaseOrderLine{},
+		&model.Warehouse{},
+		&model.StockLevel{},
+		&model.StockTransfer{},
+		&model.StockTransferLine{},
 	)
 
 	if err != nil {
 		appLogger.Fatal("failed to migrate database: %v", err)
 	}
 
+	// Scope every query on org owned models to the caller's org
+	if err := tenant.Register(db); err != nil {
+		appLogger.Fatal("failed to register tenant callbacks: %v", err)
+	}
+
+	// Record every change of org owned rows in the audit log
+	if err := audit.Register(db); err != nil {
+		appLogger.Fatal("failed to register audit callbacks: %v", err)
+	}
+
 	appLogger.Info("Connected to database")
 
 	return db
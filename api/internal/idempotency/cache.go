@@ -0,0 +1,129 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+)
+
+// lockTTL is how long, in seconds, a key is locked while it is read and
+// written.
+const lockTTL = 5
+
+// CacheStore keeps idempotency keys in a cache, such as Redis, instead of
+// the database.
+type CacheStore struct {
+	cache interfaces.Cache
+}
+
+func NewCacheStore(cache interfaces.Cache) interfaces.IdempotencyStore {
+	return &CacheStore{cache: cache}
+}
+
+func cacheKey(orgID uint, key string) string {
+	return fmt.Sprintf("idempotency:%d:%s", orgID, key)
+}
+
+func (s *CacheStore) Reserve(ctx context.Context, orgID uint, key string, fingerprint string) (*model.IdempotencyKey, bool, error) {
+	k := cacheKey(orgID, key)
+	s.cache.Lock(k, lockTTL)
+	defer s.cache.Unlock(k)
+
+	existing, err := s.get(k)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, false, nil
+	}
+
+	record := &model.IdempotencyKey{
+		OrgID:       orgID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   time.Now().Add(TTL),
+	}
+	if err := s.set(k, record); err != nil {
+		return nil, false, err
+	}
+
+	return record, true, nil
+}
+
+func (s *CacheStore) Complete(ctx context.Context, orgID uint, key string, status int, body []byte) error {
+	k := cacheKey(orgID, key)
+	s.cache.Lock(k, lockTTL)
+	defer s.cache.Unlock(k)
+
+	record, err := s.get(k)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("idempotency key %q is not reserved", key)
+	}
+
+	now := time.Now()
+	record.Status = status
+	record.Body = body
+	record.CompletedAt = &now
+	return s.set(k, record)
+}
+
+func (s *CacheStore) Release(ctx context.Context, orgID uint, key string) error {
+	k := cacheKey(orgID, key)
+	s.cache.Lock(k, lockTTL)
+	defer s.cache.Unlock(k)
+
+	record, err := s.get(k)
+	if err != nil {
+		return err
+	}
+	if record != nil && !record.Completed() {
+		s.cache.Delete(k)
+	}
+	return nil
+}
+
+// get returns the record cached under k, or nil. Records are cached as JSON
+// so any cache backend can hold them.
+func (s *CacheStore) get(k string) (*model.IdempotencyKey, error) {
+	var data []byte
+	switch v := s.cache.Get(k).(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return nil, fmt.Errorf("unexpected cached idempotency key of type %T", v)
+	}
+
+	var record model.IdempotencyKey
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (s *CacheStore) set(k string, record *model.IdempotencyKey) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	ttl := int(time.Until(record.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		s.cache.Delete(k)
+		return nil
+	}
+
+	s.cache.Set(k, data, ttl)
+	return nil
+}
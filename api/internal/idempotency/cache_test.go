@@ -0,0 +1,86 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCache is an interfaces.Cache keeping values in a map, ignoring TTLs.
+type memoryCache struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+	locks  map[string]*sync.Mutex
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: map[string]interface{}{}, locks: map[string]*sync.Mutex{}}
+}
+
+func (c *memoryCache) Get(key string) interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key]
+}
+
+func (c *memoryCache) Set(key string, value interface{}, ttl int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+}
+
+func (c *memoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, key)
+}
+
+func (c *memoryCache) lock(key string) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.locks[key] == nil {
+		c.locks[key] = &sync.Mutex{}
+	}
+	return c.locks[key]
+}
+
+func (c *memoryCache) Lock(key string, ttl int) { c.lock(key).Lock() }
+func (c *memoryCache) Unlock(key string)        { c.lock(key).Unlock() }
+
+func TestCacheStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewCacheStore(newMemoryCache())
+
+	record, reserved, err := store.Reserve(ctx, 1, "key", "fp")
+	require.NoError(t, err)
+	assert.True(t, reserved)
+	assert.False(t, record.Completed())
+
+	record, reserved, err = store.Reserve(ctx, 1, "key", "other")
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "fp", record.Fingerprint)
+	assert.False(t, record.Completed())
+
+	_, reserved, err = store.Reserve(ctx, 2, "key", "fp")
+	require.NoError(t, err)
+	assert.True(t, reserved, "keys are per org")
+
+	require.NoError(t, store.Complete(ctx, 1, "key", 201, []byte(`{"id":1}`)))
+	record, reserved, err = store.Reserve(ctx, 1, "key", "fp")
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.True(t, record.Completed())
+	assert.Equal(t, 201, record.Status)
+	assert.Equal(t, []byte(`{"id":1}`), record.Body)
+
+	require.NoError(t, store.Release(ctx, 2, "key"))
+	_, reserved, err = store.Reserve(ctx, 2, "key", "fp")
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	assert.Error(t, store.Complete(ctx, 3, "key", 200, nil))
+}
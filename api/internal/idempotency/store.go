@@ -0,0 +1,78 @@
+// Package idempotency stores the responses of requests sent with an
+// `Idempotency-Key` header, so that a client retrying a request it never
+// got the response of gets the original response instead of repeating it.
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/tenant"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TTL is how long a key is remembered. After that it can be reused.
+const TTL = 24 * time.Hour
+
+// Store keeps idempotency keys in the database.
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) interfaces.IdempotencyStore {
+	return &Store{db: db}
+}
+
+func (s *Store) Reserve(ctx context.Context, orgID uint, key string, fingerprint string) (*model.IdempotencyKey, bool, error) {
+	db := s.db.WithContext(tenant.WithOrg(ctx, orgID))
+	now := time.Now()
+
+	// Expired keys are forgotten, which also keeps the table small
+	err := db.Unscoped().Where("expires_at <= ?", now).Delete(&model.IdempotencyKey{}).Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	record := &model.IdempotencyKey{
+		OrgID:       orgID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(TTL),
+	}
+	res := db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if res.Error != nil {
+		return nil, false, res.Error
+	}
+	if res.RowsAffected == 1 {
+		return record, true, nil
+	}
+
+	var existing model.IdempotencyKey
+	if err := db.Where(map[string]any{"key": key}).First(&existing).Error; err != nil {
+		return nil, false, err
+	}
+
+	return &existing, false, nil
+}
+
+func (s *Store) Complete(ctx context.Context, orgID uint, key string, status int, body []byte) error {
+	return s.db.WithContext(tenant.WithOrg(ctx, orgID)).
+		Model(&model.IdempotencyKey{}).
+		Where(map[string]any{"key": key}).
+		Updates(map[string]any{
+			"status":       status,
+			"body":         body,
+			"completed_at": time.Now(),
+		}).Error
+}
+
+func (s *Store) Release(ctx context.Context, orgID uint, key string) error {
+	return s.db.WithContext(tenant.WithOrg(ctx, orgID)).
+		Unscoped().
+		Where(map[string]any{"key": key}).
+		Where("completed_at IS NULL").
+		Delete(&model.IdempotencyKey{}).Error
+}
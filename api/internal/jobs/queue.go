@@ -0,0 +1,328 @@
+// Package jobs is a durable background job queue backed by the jobs table.
+//
+// Jobs are enqueued in the same database as the data they act on, so they
+// can be enqueued in the transaction that makes them necessary. Workers
+// claim jobs with `SELECT ... FOR UPDATE SKIP LOCKED`, so any number of
+// workers can poll the table without handing out a job twice. Failed jobs
+// are retried with exponential backoff and dead-lettered once they run out
+// of attempts. Scheduled jobs enqueue their next run when they finish.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/tenant"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Options tune how jobs are run.
+type Options struct {
+	// Workers is the number of jobs run at the same time.
+	Workers int
+	// PollInterval is how long an idle worker waits before looking for jobs
+	// again.
+	PollInterval time.Duration
+	// MaxAttempts is how often a job is tried before it is dead-lettered.
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry. It doubles with every
+	// further attempt up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// LockTimeout is how long a job can stay claimed before it is considered
+	// abandoned, e.g. by a worker that crashed, and is claimed again.
+	LockTimeout time.Duration
+}
+
+// DefaultOptions are the options used by New.
+var DefaultOptions = Options{
+	Workers:      4,
+	PollInterval: time.Second,
+	MaxAttempts:  5,
+	BaseBackoff:  30 * time.Second,
+	MaxBackoff:   time.Hour,
+	LockTimeout:  10 * time.Minute,
+}
+
+// Queue enqueues jobs and runs them with the registered handlers.
+type Queue struct {
+	db       *gorm.DB
+	logger   interfaces.Logger
+	opts     Options
+	handlers map[string]interfaces.JobHandler
+	// intervals of the scheduled job types
+	schedules map[string]time.Duration
+	mu        *sync.RWMutex
+
+	// set by Start
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+}
+
+// New creates a queue with DefaultOptions.
+func New(db *gorm.DB, logger interfaces.Logger) *Queue {
+	return NewWithOptions(db, logger, DefaultOptions)
+}
+
+func NewWithOptions(db *gorm.DB, logger interfaces.Logger, opts Options) *Queue {
+	return &Queue{
+		db:        db,
+		logger:    logger,
+		opts:      opts,
+		handlers:  make(map[string]interfaces.JobHandler),
+		schedules: make(map[string]time.Duration),
+		mu:        &sync.RWMutex{},
+		wg:        &sync.WaitGroup{},
+	}
+}
+
+// Typed adapts a handler taking a decoded payload of type T to a
+// JobHandler.
+func Typed[T any](fn func(ctx context.Context, payload T) error) interfaces.JobHandler {
+	return func(ctx context.Context, raw []byte) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return fmt.Errorf("decode job payload: %w", err)
+		}
+		return fn(ctx, payload)
+	}
+}
+
+func (q *Queue) Handle(jobType string, handler interfaces.JobHandler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload any) error {
+	return q.enqueue(ctx, jobType, payload, time.Now())
+}
+
+func (q *Queue) Schedule(ctx context.Context, jobType string, interval time.Duration) error {
+	q.mu.Lock()
+	q.schedules[jobType] = interval
+	q.mu.Unlock()
+
+	// Runs are system jobs, across orgs
+	ctx = tenant.Bypass(ctx)
+	var waiting int64
+	err := q.db.WithContext(ctx).Model(&model.Job{}).
+		Where("type = ? AND status IN ?", jobType, []model.JobStatus{model.JobStatusPending, model.JobStatusRunning}).
+		Count(&waiting).Error
+	if err != nil || waiting > 0 {
+		return err
+	}
+	return q.enqueue(ctx, jobType, struct{}{}, time.Now())
+}
+
+// enqueue stores a job of jobType to run at runAt.
+func (q *Queue) enqueue(ctx context.Context, jobType string, payload any, runAt time.Time) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode job payload: %w", err)
+	}
+
+	job := &model.Job{
+		Type:        jobType,
+		Payload:     string(data),
+		Status:      model.JobStatusPending,
+		MaxAttempts: q.opts.MaxAttempts,
+		RunAt:       runAt,
+	}
+	if orgID, err := tenant.OrgFromContext(ctx); err == nil {
+		job.OrgID = &orgID
+	}
+
+	return q.db.WithContext(ctx).Create(job).Error
+}
+
+func (q *Queue) WithTx(tx *gorm.DB) interfaces.JobQueue {
+	txQueue := *q
+	txQueue.db = tx
+	return &txQueue
+}
+
+// Start runs the workers in the background until Stop is called.
+func (q *Queue) Start(ctx context.Context) {
+	ctx, q.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < q.opts.Workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			q.work(ctx)
+		}()
+	}
+}
+
+// Stop stops claiming new jobs and waits for the running ones to finish,
+// or for ctx to be done.
+func (q *Queue) Stop(ctx context.Context) error {
+	if q.cancel != nil {
+		q.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) work(ctx context.Context) {
+	for {
+		ran, err := q.RunOnce(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			q.logger.Error("failed to run job", "err", err)
+		}
+		if ran {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(q.opts.PollInterval):
+		}
+	}
+}
+
+// RunOnce claims the next due job and runs it. It reports whether a job was
+// run.
+func (q *Queue) RunOnce(ctx context.Context) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	job, err := q.claim(ctx)
+	if err != nil {
+		return false, err
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	// Let a job that was started finish even when the workers are stopping
+	runErr := q.run(context.WithoutCancel(ctx), job)
+	return true, q.finish(context.WithoutCancel(ctx), job, runErr)
+}
+
+// claim locks the next due job, or a job whose worker gave up on it, and
+// marks it running. It returns nil when there is nothing to do.
+func (q *Queue) claim(ctx context.Context) (*model.Job, error) {
+	var job model.Job
+	now := time.Now()
+
+	err := q.db.WithContext(tenant.Bypass(ctx)).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_at < ?)",
+				model.JobStatusPending, now, model.JobStatusRunning, now.Add(-q.opts.LockTimeout)).
+			Order("run_at, id").
+			Take(&job).Error
+		if err != nil {
+			return err
+		}
+
+		job.Status = model.JobStatusRunning
+		job.LockedAt = &now
+		job.Attempts++
+		return tx.Model(&job).Updates(map[string]any{
+			"status":    job.Status,
+			"locked_at": job.LockedAt,
+			"attempts":  job.Attempts,
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// errNoHandler is returned for jobs of a type nothing handles. They are
+// dead-lettered straight away.
+var errNoHandler = errors.New("no handler registered for job type")
+
+func (q *Queue) run(ctx context.Context, job *model.Job) (err error) {
+	q.mu.RLock()
+	handler, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w %q", errNoHandler, job.Type)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	if job.OrgID != nil {
+		ctx = tenant.WithOrg(ctx, *job.OrgID)
+	} else {
+		ctx = tenant.Bypass(ctx)
+	}
+
+	return handler(ctx, []byte(job.Payload))
+}
+
+// finish records the outcome of a job: done, retried later or dead.
+func (q *Queue) finish(ctx context.Context, job *model.Job, runErr error) error {
+	updates := map[string]any{"locked_at": nil}
+
+	switch {
+	case runErr == nil:
+		updates["status"] = model.JobStatusDone
+		updates["last_error"] = ""
+	case job.Attempts >= job.MaxAttempts || errors.Is(runErr, errNoHandler):
+		updates["status"] = model.JobStatusDead
+		updates["last_error"] = runErr.Error()
+		q.logger.Error("job dead-lettered", "job", job.ID, "type", job.Type, "attempts", job.Attempts, "err", runErr)
+	default:
+		updates["status"] = model.JobStatusPending
+		updates["last_error"] = runErr.Error()
+		updates["run_at"] = time.Now().Add(q.backoff(job.Attempts))
+		q.logger.Warn("job failed, retrying", "job", job.ID, "type", job.Type, "attempts", job.Attempts, "err", runErr)
+	}
+
+	ctx = tenant.Bypass(ctx)
+	if err := q.db.WithContext(ctx).Model(&model.Job{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	// A scheduled job runs again after its interval once it is done, or
+	// once it gave up, so a failing run does not end the schedule
+	q.mu.RLock()
+	interval, scheduled := q.schedules[job.Type]
+	q.mu.RUnlock()
+	if !scheduled || updates["status"] == model.JobStatusPending {
+		return nil
+	}
+	return q.enqueue(ctx, job.Type, struct{}{}, time.Now().Add(interval))
+}
+
+// backoff is the delay before retrying a job that failed attempts times.
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.opts.BaseBackoff
+	for i := 1; i < attempts && delay < q.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, q.opts.MaxBackoff)
+}
@@ -0,0 +1,240 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/tenant"
+	"github.com/deveasyclick/openb2b/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func setupQueue(t *testing.T) (*Queue, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// Every connection to :memory: opens a database of its own
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&model.Job{}))
+	require.NoError(t, tenant.Register(db))
+
+	opts := DefaultOptions
+	opts.MaxAttempts = 3
+	return NewWithOptions(db, logger.New("test"), opts), db
+}
+
+func findJob(t *testing.T, db *gorm.DB) model.Job {
+	t.Helper()
+	var job model.Job
+	require.NoError(t, db.WithContext(tenant.Bypass(context.Background())).First(&job).Error)
+	return job
+}
+
+func TestRunOnce(t *testing.T) {
+	q, db := setupQueue(t)
+
+	var got greeting
+	var gotOrg uint
+	q.Handle("greet", Typed(func(ctx context.Context, g greeting) error {
+		got = g
+		gotOrg, _ = tenant.OrgFromContext(ctx)
+		return nil
+	}))
+
+	ctx := tenant.WithOrg(context.Background(), 7)
+	require.NoError(t, q.Enqueue(ctx, "greet", greeting{Name: "ada"}))
+
+	ran, err := q.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, "ada", got.Name)
+	assert.Equal(t, uint(7), gotOrg)
+
+	job := findJob(t, db)
+	assert.Equal(t, model.JobStatusDone, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	assert.Nil(t, job.LockedAt)
+
+	ran, err = q.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, ran)
+}
+
+func TestFailedJobIsRetriedWithBackoff(t *testing.T) {
+	q, db := setupQueue(t)
+
+	calls := 0
+	q.Handle("flaky", func(ctx context.Context, payload []byte) error {
+		calls++
+		return errors.New("smtp unavailable")
+	})
+	require.NoError(t, q.Enqueue(context.Background(), "flaky", nil))
+
+	before := time.Now()
+	ran, err := q.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ran)
+
+	job := findJob(t, db)
+	assert.Equal(t, model.JobStatusPending, job.Status)
+	assert.Equal(t, "smtp unavailable", job.LastError)
+	assert.True(t, job.RunAt.After(before.Add(q.opts.BaseBackoff-time.Second)))
+
+	// Not due yet
+	ran, err = q.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, ran)
+	assert.Equal(t, 1, calls)
+}
+
+func TestJobIsDeadAfterMaxAttempts(t *testing.T) {
+	q, db := setupQueue(t)
+	system := db.WithContext(tenant.Bypass(context.Background()))
+
+	q.Handle("broken", func(ctx context.Context, payload []byte) error {
+		panic("boom")
+	})
+	require.NoError(t, q.Enqueue(context.Background(), "broken", nil))
+
+	for i := 0; i < q.opts.MaxAttempts; i++ {
+		ran, err := q.RunOnce(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, ran)
+		// Make the retry due straight away
+		require.NoError(t, system.Model(&model.Job{}).Where("status = ?", model.JobStatusPending).Update("run_at", time.Now()).Error)
+	}
+
+	job := findJob(t, db)
+	assert.Equal(t, model.JobStatusDead, job.Status)
+	assert.Equal(t, q.opts.MaxAttempts, job.Attempts)
+	assert.Equal(t, "job panicked: boom", job.LastError)
+
+	ran, err := q.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, ran)
+}
+
+func TestJobWithoutHandlerIsDead(t *testing.T) {
+	q, db := setupQueue(t)
+	require.NoError(t, q.Enqueue(context.Background(), "unknown", nil))
+
+	ran, err := q.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ran)
+
+	job := findJob(t, db)
+	assert.Equal(t, model.JobStatusDead, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+}
+
+func TestAbandonedJobIsClaimedAgain(t *testing.T) {
+	q, db := setupQueue(t)
+	system := db.WithContext(tenant.Bypass(context.Background()))
+
+	ran := false
+	q.Handle("greet", func(ctx context.Context, payload []byte) error {
+		ran = true
+		return nil
+	})
+	require.NoError(t, q.Enqueue(context.Background(), "greet", nil))
+
+	lockedAt := time.Now().Add(-q.opts.LockTimeout - time.Minute)
+	require.NoError(t, system.Model(&model.Job{}).Where("1 = 1").Updates(map[string]any{
+		"status":    model.JobStatusRunning,
+		"locked_at": lockedAt,
+	}).Error)
+
+	_, err := q.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, model.JobStatusDone, findJob(t, db).Status)
+}
+
+func TestEnqueueInTransaction(t *testing.T) {
+	q, db := setupQueue(t)
+
+	err := db.WithContext(context.Background()).Transaction(func(tx *gorm.DB) error {
+		require.NoError(t, q.WithTx(tx).Enqueue(context.Background(), "greet", nil))
+		return errors.New("rollback")
+	})
+	assert.Error(t, err)
+
+	var count int64
+	require.NoError(t, db.WithContext(tenant.Bypass(context.Background())).Model(&model.Job{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestScheduledJobRunsAgainAfterInterval(t *testing.T) {
+	q, db := setupQueue(t)
+
+	calls := 0
+	q.Handle("sweep", func(ctx context.Context, payload []byte) error {
+		calls++
+		return nil
+	})
+	require.NoError(t, q.Schedule(context.Background(), "sweep", time.Hour))
+	// A waiting run is not enqueued twice, e.g. on restart
+	require.NoError(t, q.Schedule(context.Background(), "sweep", time.Hour))
+
+	ran, err := q.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, 1, calls)
+
+	var jobs []model.Job
+	require.NoError(t, db.WithContext(tenant.Bypass(context.Background())).Order("id").Find(&jobs).Error)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, model.JobStatusDone, jobs[0].Status)
+	assert.Equal(t, model.JobStatusPending, jobs[1].Status)
+	assert.Nil(t, jobs[1].OrgID)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), jobs[1].RunAt, time.Minute)
+
+	// The next run is not due yet
+	ran, err = q.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, ran)
+}
+
+func TestStartAndStop(t *testing.T) {
+	q, db := setupQueue(t)
+	q.opts.PollInterval = 10 * time.Millisecond
+
+	done := make(chan struct{})
+	q.Handle("greet", func(ctx context.Context, payload []byte) error {
+		close(done)
+		return nil
+	})
+	require.NoError(t, q.Enqueue(context.Background(), "greet", nil))
+
+	q.Start(context.Background())
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, q.Stop(ctx))
+	assert.Equal(t, model.JobStatusDone, findJob(t, db).Status)
+}
+
+func TestBackoff(t *testing.T) {
+	q := NewWithOptions(nil, nil, DefaultOptions)
+
+	assert.Equal(t, 30*time.Second, q.backoff(1))
+	assert.Equal(t, time.Minute, q.backoff(2))
+	assert.Equal(t, 2*time.Minute, q.backoff(3))
+	assert.Equal(t, time.Hour, q.backoff(20))
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/tenant"
+	"github.com/deveasyclick/openb2b/internal/utils/apikeyutil"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+// lastUsedResolution is how stale the last used time of an API key may get,
+// so busy keys don't write on every request.
+const lastUsedResolution = time.Minute
+
+var errInvalidAPIKey = errors.New("invalid api key")
+
+// Authenticate enforces authentication on protected routes with either an
+// API key or a Clerk session.
+//
+// Requests whose `Authorization` header carries a bearer token starting with
+// "ob2b_" are authenticated with the API key, everything else goes through
+// ValidateJWT. Either way identity.UserFromContext returns the same
+// identity.ContextUser for the request, so handlers don't need to tell them
+// apart.
+//
+// Usage (Chi example):
+//
+//	r.Group(func(r chi.Router) {
+//	    r.Use(middleware.Authenticate())
+//	    r.Get("/orders", handler.Filter)
+//	})
+func (m *middleware) Authenticate() func(http.Handler) http.Handler {
+	return WithAPIKeys(m.appCtx.DB, m.appCtx.Logger, m.ValidateJWT())
+}
+
+// WithAPIKeys authenticates requests carrying an API key against the keys
+// in db and hands every other request to session. Requests that session
+// lets through are authenticated as the user of their Clerk session.
+func WithAPIKeys(db *gorm.DB, logger interfaces.Logger, session func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withSession := session(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Sessions of users still signing up don't carry a user ID yet,
+			// handlers that need one reject them on their own
+			user, err := identity.UserFromContext(r.Context())
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(identity.WithUser(r.Context(), user)))
+		}))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := apikeyutil.FromAuthorization(r.Header.Get("Authorization"))
+			if !ok {
+				withSession.ServeHTTP(w, r)
+				return
+			}
+
+			user, err := authenticateAPIKey(r.Context(), db, key)
+			if err != nil {
+				if !errors.Is(err, errInvalidAPIKey) {
+					logger.Error("failed to authenticate api key", "err", err)
+				}
+				writeUnauthorized(w)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(identity.WithUser(r.Context(), user)))
+		})
+	}
+}
+
+// authenticateAPIKey returns the user of a usable API key and records that
+// it was used.
+func authenticateAPIKey(ctx context.Context, db *gorm.DB, key string) (*identity.ContextUser, error) {
+	prefix, err := apikeyutil.Parse(key)
+	if err != nil {
+		return nil, errInvalidAPIKey
+	}
+
+	// The org is only known once the key is found
+	system := db.WithContext(tenant.Bypass(ctx))
+
+	var apiKey model.APIKey
+	err = system.Where("prefix = ?", prefix).First(&apiKey).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !apikeyutil.Matches(key, apiKey.SecretHash) || !apiKey.Usable(now) {
+		return nil, errInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastUsedResolution {
+		err := system.Model(&model.APIKey{}).Where("id = ?", apiKey.ID).Update("last_used_at", now).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &identity.ContextUser{
+		ID:       apiKey.CreatedByID,
+		Org:      apiKey.OrgID,
+		APIKeyID: apiKey.ID,
+		Scopes:   apiKey.Scopes,
+	}, nil
+}
+
+func writeUnauthorized(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusUnauthorized)
+	w.Write([]byte(`{"message": "unauthorized"}`))
+}
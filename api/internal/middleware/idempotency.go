@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed for a retry
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotent makes mutating requests sent with an `Idempotency-Key` header
+// safe to retry.
+//
+// The first request with a key runs as usual and its response is stored per
+// org and key. A retry with the same key by the same user or API key, with
+// the same method, path and body, gets the stored response back, with the `Idempotent-Replayed: true` header,
+// without running again. Reusing a key for a different request or by someone
+// else, or while the first request is still running, is rejected with 409
+// Conflict. Replays happen before the permission checks of the route, so a
+// stored response is never handed to anyone but who made the request.
+// Responses with a 5xx status are not stored, so such requests can be
+// retried with the same key.
+//
+// It must run after Authenticate, as keys are scoped to the org of the user.
+//
+// Usage (Chi example):
+//
+//	r.Group(func(r chi.Router) {
+//	    r.Use(middleware.Authenticate())
+//	    r.Use(middleware.Idempotent())
+//	    r.Post("/orders", handler.Create)
+//	})
+func (m *middleware) Idempotent() func(http.Handler) http.Handler {
+	return WithIdempotency(m.idempotency, m.appCtx.Logger)
+}
+
+// WithIdempotency makes requests sent with an `Idempotency-Key` header
+// idempotent, keeping their responses in store.
+func WithIdempotency(store interfaces.IdempotencyStore, logger interfaces.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || !mutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrIdempotencyKeyTooLong, logger)
+				return
+			}
+
+			// Keys are per org, requests of users without one can't use them
+			user, err := identity.UserFromContext(r.Context())
+			if err != nil || user.Org == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidRequestBody, logger)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			fingerprint := requestFingerprint(r, user, body)
+			record, reserved, err := store.Reserve(r.Context(), user.Org, key, fingerprint)
+			if err != nil {
+				response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCheckIdempotencyKey, logger)
+				return
+			}
+
+			if !reserved {
+				switch {
+				case record.Fingerprint != fingerprint:
+					response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrIdempotencyKeyReused, logger)
+				case !record.Completed():
+					response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrIdempotencyRequestInProgress, logger)
+				default:
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(record.Status)
+					w.Write(record.Body)
+				}
+				return
+			}
+
+			// The response is stored even if the client went away meanwhile
+			ctx := context.WithoutCancel(r.Context())
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			stored := false
+			defer func() {
+				if stored {
+					return
+				}
+				if err := store.Release(ctx, user.Org, key); err != nil {
+					logger.Error("failed to release idempotency key", "key", key, "err", err)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+			if err := store.Complete(ctx, user.Org, key, rec.status, rec.body.Bytes()); err != nil {
+				logger.Error("failed to store idempotent response", "key", key, "err", err)
+				return
+			}
+			stored = true
+		})
+	}
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// requestFingerprint identifies a request by who sent it, its method, path
+// and body. Requests with an API key are told apart from the session of the
+// user who created the key, as the key may be allowed less.
+func requestFingerprint(r *http.Request, user *identity.ContextUser, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "user %d key %d\n", user.ID, user.APIKeyID)
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder passes a response through while keeping a copy of its
+// status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
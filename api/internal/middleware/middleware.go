@@ -6,6 +6,7 @@ import (
 
 	"github.com/clerk/clerk-sdk-go/v2"
 	clerkHttp "github.com/clerk/clerk-sdk-go/v2/http"
+	"github.com/deveasyclick/openb2b/internal/idempotency"
 	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
 	"github.com/deveasyclick/openb2b/internal/shared/deps"
 	"github.com/deveasyclick/openb2b/internal/shared/identity"
@@ -14,12 +15,20 @@ import (
 )
 
 type middleware struct {
-	appCtx *deps.AppContext
+	appCtx      *deps.AppContext
+	idempotency interfaces.IdempotencyStore
 }
 
 func New(appCtx *deps.AppContext) interfaces.Middleware {
+	// Idempotency keys live in the cache when there is one
+	idempotencyStore := idempotency.NewStore(appCtx.DB)
+	if appCtx.Cache != nil {
+		idempotencyStore = idempotency.NewCacheStore(appCtx.Cache)
+	}
+
 	return &middleware{
-		appCtx: appCtx,
+		appCtx:      appCtx,
+		idempotency: idempotencyStore,
 	}
 }
 
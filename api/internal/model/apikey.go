@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// APIKey authenticates machine-to-machine requests of an org, such as ERP
+// and warehouse integrations. It is granted its scopes instead of a role.
+// @Description API key response model
+type APIKey struct {
+	BaseModel
+
+	OrgID uint   `gorm:"index;not null" json:"orgId"`
+	Name  string `gorm:"size:100;not null" json:"name"`
+	// Prefix identifies the key, the key itself is only shown on creation
+	Prefix     string   `gorm:"size:16;not null;uniqueIndex" json:"prefix"`
+	SecretHash string   `gorm:"size:64;not null" json:"-" audit:"-"`
+	Scopes     []string `gorm:"serializer:json;type:text;not null" json:"scopes"`
+
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+	CreatedByID uint       `gorm:"not null" json:"createdById"`
+
+	// Key is the full API key. It is only set in the response creating it.
+	Key string `gorm:"-" json:"key,omitempty"`
+}
+
+// Usable reports whether the key can authenticate requests at now.
+func (k *APIKey) Usable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,38 @@
+package model
+
+// AuditAction is the kind of change an audit entry records
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// AuditChange is the value of a column before and after a change. Before is
+// unset for creates and After for deletes.
+type AuditChange struct {
+	Before any `json:"before,omitempty"`
+	After  any `json:"after,omitempty"`
+}
+
+// AuditEntry records who changed which row of an org, how and from where.
+// @Description Audit log entry response model
+type AuditEntry struct {
+	BaseModel
+
+	OrgID uint `gorm:"not null;index:idx_audit_entries_org_entity" json:"orgId"`
+	// ActorID is the user who made the change, nil for changes made by the
+	// system, e.g. background jobs
+	ActorID *uint `gorm:"index" json:"actorId,omitempty"`
+	// APIKeyID is set when the change was made with an API key of the actor
+	APIKeyID *uint `json:"apiKeyId,omitempty"`
+
+	EntityType string                 `gorm:"size:100;not null;index:idx_audit_entries_org_entity" json:"entityType"` // table name, e.g. "products"
+	EntityID   uint                   `gorm:"not null;index:idx_audit_entries_org_entity" json:"entityId"`
+	Action     AuditAction            `gorm:"type:varchar(20);not null;check:action IN ('create','update','delete')" json:"action"`
+	Changes    map[string]AuditChange `gorm:"serializer:json;type:text" json:"changes"` // per column
+
+	RequestID string `gorm:"size:100" json:"requestId,omitempty"`
+	IP        string `gorm:"size:45" json:"ip,omitempty"`
+}
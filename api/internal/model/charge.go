@@ -0,0 +1,59 @@
+package model
+
+import "github.com/deveasyclick/openb2b/internal/shared/money"
+
+type ChargeType string
+
+const (
+	ChargeDelivery  ChargeType = "delivery"
+	ChargeHandling  ChargeType = "handling"
+	ChargePackaging ChargeType = "packaging"
+)
+
+// OrderCharge is an amount an order is charged besides its items, e.g. the
+// delivery fare. Discounts don't apply to charges. A charge with a tax class
+// is taxed with its rates like an item; Amount is gross when the order
+// prices include tax.
+// @Description Order charge
+type OrderCharge struct {
+	BaseModel
+	OrgID       uint        `gorm:"not null;index" json:"orgId"`
+	OrderID     uint        `gorm:"not null;index" json:"orderId"`
+	Type        ChargeType  `gorm:"type:varchar(20);not null;check:type IN ('delivery','handling','packaging')" json:"type"`
+	Description string      `gorm:"type:varchar(255)" json:"description"`
+	Amount      money.Money `gorm:"not null" json:"amount"` // in the order currency
+
+	TaxClassID *uint       `gorm:"index" json:"taxClassId,omitempty"`
+	TaxClass   *TaxClass   `gorm:"foreignKey:TaxClassID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	TaxAmount  money.Money `gorm:"not null" json:"taxAmount"`
+	Taxes      []TaxLine   `gorm:"serializer:json" json:"taxes"` // TaxAmount by tax
+	Total      money.Money `gorm:"not null" json:"total"`        // Amount, plus TaxAmount unless prices include tax
+}
+
+// InvoiceCharge is an order charge as it was invoiced.
+// @Description Invoice charge
+type InvoiceCharge struct {
+	BaseModel
+	OrgID       uint        `gorm:"not null;index" json:"orgId"`
+	InvoiceID   uint        `gorm:"not null;index" json:"invoiceId"`
+	Type        ChargeType  `gorm:"type:varchar(20);not null" json:"type"`
+	Description string      `gorm:"type:varchar(255)" json:"description"`
+	Amount      money.Money `gorm:"not null" json:"amount"`
+	TaxAmount   money.Money `gorm:"not null" json:"taxAmount"`
+	Taxes       []TaxLine   `gorm:"serializer:json" json:"taxes"`
+	Total       money.Money `gorm:"not null" json:"total"`
+}
+
+// Label is the description of the charge, else its type, e.g. "Delivery".
+func (c InvoiceCharge) Label() string {
+	if c.Description != "" {
+		return c.Description
+	}
+	return chargeLabels[c.Type]
+}
+
+var chargeLabels = map[ChargeType]string{
+	ChargeDelivery:  "Delivery",
+	ChargeHandling:  "Handling",
+	ChargePackaging: "Packaging",
+}
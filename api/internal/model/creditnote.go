@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// CreditNote reverses some or all of an issued invoice. Issued invoices are
+// never changed, so every correction is a credit note. Credit notes are
+// immutable once created.
+// @Description Credit note response model
+type CreditNote struct {
+	BaseModel
+
+	OrgID      uint     `gorm:"index;not null" json:"orgId"`
+	InvoiceID  uint     `gorm:"index;not null" json:"invoiceId"`
+	Invoice    *Invoice `gorm:"foreignKey:InvoiceID" json:"invoice,omitempty"`
+	CustomerID uint     `gorm:"index;not null" json:"customerId"`
+
+	CreditNoteNumber string    `gorm:"uniqueIndex;size:50;not null" json:"creditNoteNumber"`
+	Reason           string    `gorm:"type:text;not null" json:"reason"`
+	IssuedAt         time.Time `gorm:"not null" json:"issuedAt"`
+
+	// Restock is set when the credited quantities were put back on hand, as
+	// far as the order had deducted them from the stock.
+	Restock bool `gorm:"not null;default:false" json:"restock"`
+
+	Currency string      `gorm:"size:3;not null" json:"currency"`
+	Subtotal money.Money `gorm:"type:decimal(12,2);not null" json:"subtotal"`
+	TaxTotal money.Money `gorm:"type:decimal(12,2);not null" json:"taxTotal"`
+	Total    money.Money `gorm:"type:decimal(12,2);not null" json:"total"`
+
+	Items []CreditNoteItem `gorm:"foreignKey:CreditNoteID" json:"items"`
+}
+
+// CreditNoteItem credits a quantity of an invoice item. Amounts are the share
+// of the invoice item's amounts for the credited quantity.
+type CreditNoteItem struct {
+	BaseModel
+
+	OrgID         uint `gorm:"index;not null" json:"orgId"`
+	CreditNoteID  uint `gorm:"index;not null" json:"creditNoteId"`
+	InvoiceItemID uint `gorm:"index;not null" json:"invoiceItemId"`
+	VariantID     uint `gorm:"index;not null" json:"variantId"`
+
+	SKU       string      `gorm:"type:varchar(50)" json:"sku"`
+	Quantity  int         `gorm:"not null;check:quantity > 0" json:"quantity"`
+	UnitPrice money.Money `gorm:"type:decimal(12,2);not null" json:"unitPrice"`
+	TaxAmount money.Money `gorm:"type:decimal(12,2);not null" json:"taxAmount"`
+	LineTotal money.Money `gorm:"type:decimal(12,2);not null" json:"lineTotal"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "github.com/deveasyclick/openb2b/internal/shared/money"
+
 // Customer represents a customer belonging to a specific org.
 // To ensure a customer is unique within a org, we enforce a composite unique index
 // on (org_id, phone_number). Email is not used for uniqueness because it is optional.
@@ -11,7 +13,17 @@ type Customer struct {
 	Email       string   `gorm:"index;type:varchar(100);" json:"email"`
 	Address     *Address `gorm:"embedded;embeddedPrefix:address_" json:"address"`
 	Company     string   `gorm:"type:varchar(100)" json:"company"`
+	Currency    string   `gorm:"size:3" json:"currency"` // billing currency, the org base currency when empty
+	TaxExempt   bool     `gorm:"not null;default:false" json:"taxExempt"`
 	OrgID       uint     `gorm:"index" json:"orgId"`
 	Org         *Org     `gorm:"foreignKey:OrgID" json:"org,omitempty"`
 	Orders      []*Order `json:"orders,omitempty"`
+
+	// CustomerGroup is the tier of the customer, for price lists
+	CustomerGroupID *uint          `gorm:"index" json:"customerGroupId,omitempty"`
+	CustomerGroup   *CustomerGroup `gorm:"foreignKey:CustomerGroupID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"customerGroup,omitempty"`
+
+	// CreditBalance is money the customer has on account, e.g. from
+	// over-payments. It is only changed through AdjustCredit.
+	CreditBalance money.Money `gorm:"type:decimal(12,2);not null;default:0" json:"creditBalance"`
 }
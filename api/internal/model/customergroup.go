@@ -0,0 +1,10 @@
+package model
+
+// CustomerGroup is a tier of customers, e.g. "Wholesale", that price lists
+// can be assigned to.
+// @Description Customer group
+type CustomerGroup struct {
+	BaseModel
+	OrgID uint   `gorm:"not null;index" json:"orgId"`
+	Name  string `gorm:"type:varchar(100);not null;check:name <> ''" json:"name"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// ExchangeRate is what one unit of Currency is worth in BaseCurrency from
+// EffectiveAt on, until a later rate of the same pair takes over.
+// @Description Exchange rate
+type ExchangeRate struct {
+	BaseModel
+	OrgID        uint      `gorm:"not null;index:idx_exchange_rates_pair" json:"orgId"`
+	BaseCurrency string    `gorm:"size:3;not null;index:idx_exchange_rates_pair" json:"baseCurrency"`
+	Currency     string    `gorm:"size:3;not null;index:idx_exchange_rates_pair" json:"currency"`
+	Rate         float64   `gorm:"type:decimal(18,8);not null;check:rate > 0" json:"rate"`
+	EffectiveAt  time.Time `gorm:"not null;index:idx_exchange_rates_pair" json:"effectiveAt"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// IdempotencyKey is a request sent with an `Idempotency-Key` header and the
+// response it got, so that retries of the request get the same response
+// instead of repeating it.
+type IdempotencyKey struct {
+	BaseModel
+
+	OrgID uint   `gorm:"not null;uniqueIndex:idx_idempotency_keys_org_key" json:"orgId"`
+	Key   string `gorm:"size:255;not null;uniqueIndex:idx_idempotency_keys_org_key" json:"key"`
+	// Fingerprint is a hash of the method, path and body of the request
+	Fingerprint string `gorm:"size:64;not null" json:"fingerprint"`
+
+	// Status and Body are the response, unset while the request is in flight
+	Status      int        `gorm:"not null;default:0" json:"status"`
+	Body        []byte     `json:"body"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	ExpiresAt   time.Time  `gorm:"not null;index" json:"expiresAt"`
+}
+
+// Completed reports whether the response of the request has been stored.
+func (k *IdempotencyKey) Completed() bool {
+	return k.CompletedAt != nil
+}
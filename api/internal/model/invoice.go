@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
 
 // InvoiceStatus represents possible statuses for invoices
 type InvoiceStatus string
@@ -20,6 +24,8 @@ const (
 	InvoiceStatusCancelled InvoiceStatus = "cancelled"
 	// partially_paid, Invoice partially paid, customer has not paid.
 	InvoiceStatusPartiallyPaid InvoiceStatus = "partially_paid"
+	// void, Issued invoice annulled before any payment, kept for the record.
+	InvoiceStatusVoid InvoiceStatus = "void"
 )
 
 // Invoice represents an invoice linked to an order
@@ -30,22 +36,37 @@ type Invoice struct {
 	OrderID uint   `gorm:"index;not null" json:"orderId"`
 	Order   *Order `gorm:"foreignKey:OrderID" json:"order"`
 
+	// ShipmentID is the shipment invoiced, nil when the whole order is
+	ShipmentID *uint `gorm:"index" json:"shipmentId,omitempty"`
+
 	InvoiceNumber string        `gorm:"uniqueIndex;size:50;not null" json:"invoiceNumber"`
-	Status        InvoiceStatus `gorm:"type:varchar(20);default:'draft';not null" json:"status"`
+	Status        InvoiceStatus `gorm:"type:varchar(20);default:'draft';not null;check:status IN ('draft','pro_forma','issued','paid','overdue','cancelled','partially_paid','void')" json:"status"`
 
 	IssuedAt time.Time  `gorm:"not null" json:"issuedAt"`
 	DueDate  *time.Time `json:"dueDate"`
 
-	Currency      string  `gorm:"size:3;default:'NGN';not null" json:"currency"`
-	Subtotal      float64 `gorm:"type:decimal(12,2);not null" json:"subtotal"`
-	TaxTotal      float64 `gorm:"type:decimal(12,2);not null" json:"taxTotal"`
-	DiscountTotal float64 `gorm:"type:decimal(12,2);not null" json:"discountTotal"`
-	Total         float64 `gorm:"type:decimal(12,2);not null" json:"total"`
+	Currency       string      `gorm:"size:3;not null" json:"currency"`
+	ExchangeRate   float64     `gorm:"type:decimal(18,8);not null;default:1" json:"exchangeRate"` // to the org base currency, from the order
+	Subtotal       money.Money `gorm:"type:decimal(12,2);not null" json:"subtotal"`
+	ChargeTotal    money.Money `gorm:"type:decimal(12,2);not null;default:0" json:"chargeTotal"`
+	TaxTotal       money.Money `gorm:"type:decimal(12,2);not null" json:"taxTotal"`
+	DiscountTotal  money.Money `gorm:"type:decimal(12,2);not null" json:"discountTotal"`
+	Total          money.Money `gorm:"type:decimal(12,2);not null" json:"total"`
+	AmountPaid     money.Money `gorm:"type:decimal(12,2);not null;default:0" json:"amountPaid"`     // payments applied, never more than is owed
+	AmountCredited money.Money `gorm:"type:decimal(12,2);not null;default:0" json:"amountCredited"` // total of the credit notes
+	AmountDue      money.Money `gorm:"type:decimal(12,2);not null;default:0" json:"amountDue"`      // Total - AmountCredited - AmountPaid
+
+	// Tax treatment of the order, see Order
+	PricesIncludeTax bool `gorm:"not null;default:false" json:"pricesIncludeTax"`
+	TaxExempt        bool `gorm:"not null;default:false" json:"taxExempt"`
 
 	Notes  string `gorm:"type:text" json:"notes"`
 	PDFUrl string `gorm:"type:text" json:"pdf_url"`
 
-	Items []*InvoiceItem `gorm:"foreignKey:InvoiceID" json:"items"`
+	Items       []*InvoiceItem  `gorm:"foreignKey:InvoiceID" json:"items"`
+	Charges     []InvoiceCharge `gorm:"foreignKey:InvoiceID" json:"charges"`
+	Payments    []Payment       `gorm:"foreignKey:InvoiceID" json:"payments,omitempty"`
+	CreditNotes []CreditNote    `gorm:"foreignKey:InvoiceID" json:"creditNotes,omitempty"`
 
 	CustomerEmail   string   `gorm:"type:text" json:"customerEmail"`
 	CustomerPhone   string   `gorm:"type:text" json:"customerPhone"`
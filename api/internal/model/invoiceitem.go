@@ -1,5 +1,7 @@
 package model
 
+import "github.com/deveasyclick/openb2b/internal/shared/money"
+
 type InvoiceItem struct {
 	BaseModel
 
@@ -10,11 +12,12 @@ type InvoiceItem struct {
 	VariantID uint     `gorm:"not null;index"`
 	Variant   *Variant `gorm:"foreignKey:VariantID" json:"variant"`
 
-	SKU       string  `gorm:"type:varchar(50)" json:"sku"`
-	Notes     string  `gorm:"type:varchar(255)" json:"description"`
-	Quantity  int     `gorm:"not null;default:1" json:"quantity"`
-	UnitPrice float64 `gorm:"not null;default:0" json:"unitPrice"`
-	TaxAmount float64 `gorm:"not null;default:0" json:"taxAmount"`
-	LineTotal float64 `gorm:"not null;default:0" json:"lineTotal"` // Quantity * UnitPrice + TaxAmount
-	Subtotal  float64 `json:"subtotal"`                            // Sum of all item totals before discount and tax
+	SKU       string      `gorm:"type:varchar(50)" json:"sku"`
+	Notes     string      `gorm:"type:varchar(255)" json:"description"`
+	Quantity  int         `gorm:"not null;default:1" json:"quantity"`
+	UnitPrice money.Money `gorm:"not null;default:0" json:"unitPrice"`
+	TaxAmount money.Money `gorm:"not null;default:0" json:"taxAmount"`
+	Taxes     []TaxLine   `gorm:"serializer:json" json:"taxes"`        // TaxAmount by tax
+	LineTotal money.Money `gorm:"not null;default:0" json:"lineTotal"` // Quantity * UnitPrice - discounts, plus TaxAmount unless prices include tax
+	Subtotal  money.Money `json:"subtotal"`                            // Sum of all item totals before discount and tax
 }
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// JobStatus is the state of a background job
+type JobStatus string
+
+const (
+	// pending, waiting to run, either for the first time or for a retry.
+	JobStatusPending JobStatus = "pending"
+	// running, claimed by a worker.
+	JobStatusRunning JobStatus = "running"
+	// done, finished successfully.
+	JobStatusDone JobStatus = "done"
+	// dead, failed on every attempt or has no handler. Dead jobs are kept
+	// for inspection and are not retried.
+	JobStatusDead JobStatus = "dead"
+)
+
+// Job is a unit of background work stored in the jobs table and run by the
+// job worker.
+type Job struct {
+	BaseModel
+
+	// OrgID is the org the job was enqueued for, nil for system jobs. The
+	// worker runs the job scoped to it. Jobs are claimed across orgs, so this
+	// is a pointer to keep the table out of tenant scoping.
+	OrgID *uint `gorm:"index" json:"orgId,omitempty"`
+
+	Type    string    `gorm:"size:100;not null;index" json:"type"`
+	Payload string    `gorm:"type:text;not null" json:"payload"` // JSON
+	Status  JobStatus `gorm:"type:varchar(20);not null;default:'pending';index:idx_jobs_status_run_at,priority:1;check:status IN ('pending','running','done','dead')" json:"status"`
+
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int        `gorm:"not null" json:"maxAttempts"`
+	RunAt       time.Time  `gorm:"not null;index:idx_jobs_status_run_at,priority:2" json:"runAt"`
+	LockedAt    *time.Time `json:"lockedAt,omitempty"`
+	LastError   string     `gorm:"type:text" json:"lastError"`
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"gorm.io/gorm"
 )
 
@@ -25,9 +26,12 @@ const (
 	DiscountFixed      DiscountType = "fixed"
 )
 
+// DeliveryInfo is where and when an order is delivered. TransportFare is
+// the total of the delivery charges of the order. Status follows the
+// shipments of the order once it has any.
 type DeliveryInfo struct {
 	Address       *Address       `gorm:"embedded;embeddedPrefix:address_" json:"address"`
-	TransportFare float64        `gorm:"not null" json:"transportFare"`
+	TransportFare money.Money    `gorm:"not null" json:"transportFare"`
 	Status        DeliveryStatus `gorm:"type:varchar(20)" json:"status"`
 	Date          *time.Time     `json:"date"`
 	At            *time.Time     `json:"at"`
@@ -35,7 +39,7 @@ type DeliveryInfo struct {
 
 type DiscountInfo struct {
 	Type   DiscountType `gorm:"not null" json:"type"`
-	Amount float64      `gorm:"not null" json:"amount"`
+	Amount money.Money  `gorm:"not null" json:"amount"`
 }
 
 func (o *Order) BeforeSave(tx *gorm.DB) (err error) {
@@ -50,24 +54,64 @@ func (o *Order) BeforeSave(tx *gorm.DB) (err error) {
 type Order struct {
 	BaseModel
 
-	OrderNumber string       `gorm:"uniqueIndex;size:50" json:"orderNumber"`
-	CustomerID  uint         `gorm:"index;not null" json:"customerId"`
-	Customer    *Customer    `gorm:"foreignKey:CustomerID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
-	Status      OrderStatus  `gorm:"type:varchar(20);default:'pending';check:status IN ('pending','processing','shipped','completed')" json:"status"`
-	OrgID       uint         `gorm:"index" json:"orgId"`
-	Org         *Org         `gorm:"foreignKey:OrgID" json:"org"`
-	Items       []OrderItem  `gorm:"foreignKey:OrderID" json:"items"`
-	Delivery    DeliveryInfo `gorm:"embedded;embeddedPrefix:delivery_" json:"delivery"`
-	Notes       string       `json:"notes"`
+	OrderNumber string        `gorm:"uniqueIndex;size:50" json:"orderNumber"`
+	CustomerID  uint          `gorm:"index;not null" json:"customerId"`
+	Customer    *Customer     `gorm:"foreignKey:CustomerID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Status      OrderStatus   `gorm:"type:varchar(20);default:'pending';check:status IN ('pending','approved','delivered','cancelled')" json:"status"`
+	OrgID       uint          `gorm:"index" json:"orgId"`
+	Org         *Org          `gorm:"foreignKey:OrgID" json:"org"`
+	Items       []OrderItem   `gorm:"foreignKey:OrderID" json:"items"`
+	Charges     []OrderCharge `gorm:"foreignKey:OrderID" json:"charges"`
+	Shipments   []Shipment    `gorm:"foreignKey:OrderID" json:"shipments,omitempty"`
+	Delivery    DeliveryInfo  `gorm:"embedded;embeddedPrefix:delivery_" json:"delivery"`
+	Notes       string        `json:"notes"`
+
+	// WarehouseID is the fulfilment location the stock of the order is held
+	// at. Nil for orders of orgs without warehouses.
+	WarehouseID *uint      `gorm:"index" json:"warehouseId,omitempty"`
+	Warehouse   *Warehouse `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"`
+
+	// Amounts are in Currency. ExchangeRate is what one unit of Currency was
+	// worth in the org base currency when the order was created.
+	Currency     string  `gorm:"size:3;not null;default:'NGN'" json:"currency"`
+	ExchangeRate float64 `gorm:"type:decimal(18,8);not null;default:1" json:"exchangeRate"`
+
+	// PricesIncludeTax is copied from the org: unit prices and Subtotal
+	// include tax, which is taken out of them rather than added. TaxExempt is
+	// copied from the customer: no tax is charged.
+	PricesIncludeTax bool `gorm:"not null;default:false" json:"pricesIncludeTax"`
+	TaxExempt        bool `gorm:"not null;default:false" json:"taxExempt"`
 
 	Discount          DiscountInfo `gorm:"embedded;embeddedPrefix:discount_" json:"discount"`
-	AppliedDiscount   float64      `json:"appliedDiscount"` // Actual discount applied
-	DiscountTotal     float64      `json:"discountTotal"`   /// ItemDiscountTotal + AppliedDiscount
-	ItemDiscountTotal float64      // sum of all per-item discounts
+	AppliedDiscount   money.Money  `json:"appliedDiscount"` // Actual discount applied
+	DiscountTotal     money.Money  `json:"discountTotal"`   /// ItemDiscountTotal + PromotionDiscount + AppliedDiscount
+	ItemDiscountTotal money.Money  // sum of all per-item discounts
+
+	// PromotionID is the promotion applied by PromotionCode, with its rule
+	// as it was then in Promotion, amounts in Currency. PromotionDiscount is
+	// what it takes off the items it applies to.
+	PromotionID       *uint         `gorm:"index" json:"promotionId,omitempty"`
+	PromotionCode     string        `gorm:"type:varchar(50)" json:"promotionCode,omitempty"`
+	Promotion         PromotionRule `gorm:"embedded;embeddedPrefix:promotion_" json:"promotion"`
+	PromotionDiscount money.Money   `json:"promotionDiscount"`
 
-	Total    float64 `json:"total"`     // final payable amount = sum of all item totals
-	Subtotal float64 `json:"subtotal"`  // sum of item (unitPrice * qty), before discounts & tax
-	TaxTotal float64 `json:"taxAmount"` // Sum of all item tax amounts
+	Total       money.Money `json:"total"`       // final payable amount = sum of all item and charge totals
+	Subtotal    money.Money `json:"subtotal"`    // sum of item (unitPrice * qty), before discounts & tax
+	ChargeTotal money.Money `json:"chargeTotal"` // sum of charge amounts, before tax
+	TaxTotal    money.Money `json:"taxAmount"`   // Sum of all item and charge tax amounts
+
+	Invoices      []Invoice            `gorm:"foreignKey:OrderID" json:"invoices"`
+	StatusHistory []OrderStatusHistory `gorm:"foreignKey:OrderID" json:"statusHistory,omitempty"`
+}
+
+// OrderStatusHistory records a status change of an order.
+// @Description Order status change
+type OrderStatusHistory struct {
+	BaseModel
 
-	Invoices []Invoice `gorm:"foreignKey:OrderID" json:"invoices"`
+	OrgID      uint        `gorm:"index;not null" json:"orgId"`
+	OrderID    uint        `gorm:"index;not null" json:"orderId"`
+	FromStatus OrderStatus `gorm:"type:varchar(20)" json:"fromStatus"` // empty when the order was created
+	ToStatus   OrderStatus `gorm:"type:varchar(20);not null" json:"toStatus"`
+	UserID     *uint       `gorm:"index" json:"userId,omitempty"` // nil for system changes
 }
@@ -1,5 +1,7 @@
 package model
 
+import "github.com/deveasyclick/openb2b/internal/shared/money"
+
 type OrderItem struct {
 	BaseModel
 
@@ -10,17 +12,42 @@ type OrderItem struct {
 	VariantID uint     `gorm:"uniqueIndex:idx_order_variant" json:"variantId"`
 	Variant   *Variant `gorm:"foreignKey:VariantID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
 
-	SKU       string  `json:"sku"`
-	Quantity  int     `json:"quantity"`
-	UnitPrice float64 `json:"unitPrice"`
-	Total     float64 `json:"total"` // (UnitPrice*Qty - discounts) + tax
-	OrgID     uint    `json:"orgId"`
+	SKU       string      `json:"sku"`
+	Quantity  int         `json:"quantity"`
+	UnitPrice money.Money `json:"unitPrice"`
+	Total     money.Money `json:"total"` // UnitPrice*Qty - discounts, plus tax unless prices include it
+	OrgID     uint        `json:"orgId"`
+
+	// PriceListID is the price list UnitPrice comes from, nil for the
+	// variant price
+	PriceListID *uint `gorm:"index" json:"priceListId,omitempty"`
 
-	TaxRate   float64 `json:"taxRate"`   // e.g., 0.10 for 10%
-	TaxAmount float64 `json:"taxAmount"` // tax charged on this line (after discounts)
-	Notes     string  `json:"notes"`
+	TaxRate   float64     `json:"taxRate"`                      // combined rate of Taxes, e.g., 0.10 for 10%
+	TaxAmount money.Money `json:"taxAmount"`                    // tax charged on this line (after discounts)
+	Taxes     []TaxLine   `gorm:"serializer:json" json:"taxes"` // TaxAmount by tax
+	Notes     string      `json:"notes"`
 
 	Discount             DiscountInfo `gorm:"embedded;embeddedPrefix:discount_" json:"discount"`
-	AppliedDiscount      float64      `json:"appliedDiscount"`      // Actual discount applied
-	AppliedOrderDiscount float64      `json:"appliedOrderDiscount"` // proportional share of order-level discount
+	AppliedDiscount      money.Money  `json:"appliedDiscount"`      // Actual discount applied
+	AppliedOrderDiscount money.Money  `json:"appliedOrderDiscount"` // proportional share of order-level discount
+
+	// Promoted items get the order promotion, AppliedPromotionDiscount is
+	// what it takes off the item
+	Promoted                 bool        `gorm:"not null;default:false" json:"promoted"`
+	AppliedPromotionDiscount money.Money `json:"appliedPromotionDiscount"`
+
+	// ShippedQuantity and DeliveredQuantity are what shipments of the order
+	// shipped and delivered of the item so far. Shipped is fulfilled.
+	ShippedQuantity   int `gorm:"not null;default:0" json:"shippedQuantity"`
+	DeliveredQuantity int `gorm:"not null;default:0" json:"deliveredQuantity"`
+
+	// AllocatedQuantity is what the order holds of the stock of the variant,
+	// BackorderedQuantity what waits for the variant to be restocked. They
+	// add up to Quantity; only allocated quantities can ship.
+	AllocatedQuantity   int `gorm:"not null;default:0" json:"allocatedQuantity"`
+	BackorderedQuantity int `gorm:"not null;default:0" json:"backorderedQuantity"`
+
+	// RestockedQuantity is what credit notes put back on hand of the
+	// allocated quantity.
+	RestockedQuantity int `gorm:"not null;default:0" json:"restockedQuantity"`
 }
@@ -1,5 +1,8 @@
 package model
 
+// DefaultCurrency is the base currency of orgs that don't set one.
+const DefaultCurrency = "NGN"
+
 // Org represents an organization entity
 // @Description Organization response model
 type Org struct {
@@ -12,6 +15,8 @@ type Org struct {
 	Phone            string      `gorm:"not null;type:varchar(50);check:phone <> ''" json:"phone" validate:"required,max=50"`
 	Address          *Address    `gorm:"embedded;embeddedPrefix:address_" json:"address"`
 	OnboardedAt      bool        `gorm:"type:boolean;default:false" json:"onboardedAt"`
+	BaseCurrency     string      `gorm:"size:3;not null;default:'NGN'" json:"baseCurrency"` // currency of variant prices and reports
+	PricesIncludeTax bool        `gorm:"not null;default:false" json:"pricesIncludeTax"`    // variant prices are gross, tax is taken out of them
 	Users            []*User     `json:"users,omitempty"`
 	Products         []*Product  `json:"products,omitempty"`
 	Customers        []*Customer `json:"customers,omitempty"`
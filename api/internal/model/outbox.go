@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// OutboxEvent is a domain event recorded in the same transaction as the
+// change it describes. The outbox relay publishes it to the subscribers of
+// its type once the transaction has committed.
+type OutboxEvent struct {
+	BaseModel
+
+	// OrgID is the org the event happened in, nil for events outside any
+	// org. The relay reads events across orgs, so this is a pointer to keep
+	// the table out of tenant scoping.
+	OrgID *uint `gorm:"index" json:"orgId,omitempty"`
+
+	Type        string     `gorm:"size:100;not null;index" json:"type"`
+	AggregateID uint       `gorm:"not null" json:"aggregateId"`       // ID of the order, invoice, ... the event is about
+	Payload     string     `gorm:"type:text;not null" json:"payload"` // JSON
+	PublishedAt *time.Time `gorm:"index" json:"publishedAt,omitempty"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// PaymentMethod is how a payment was received
+type PaymentMethod string
+
+const (
+	PaymentMethodCash         PaymentMethod = "cash"
+	PaymentMethodBankTransfer PaymentMethod = "bank_transfer"
+	PaymentMethodCard         PaymentMethod = "card"
+)
+
+// Payment is money received from a customer against an invoice
+// @Description Payment response model
+type Payment struct {
+	BaseModel
+
+	OrgID      uint      `gorm:"index;not null" json:"orgId"`
+	InvoiceID  uint      `gorm:"index;not null" json:"invoiceId"`
+	Invoice    *Invoice  `gorm:"foreignKey:InvoiceID" json:"invoice,omitempty"`
+	CustomerID uint      `gorm:"index;not null" json:"customerId"`
+	Customer   *Customer `gorm:"foreignKey:CustomerID" json:"customer,omitempty"`
+
+	Amount   money.Money   `gorm:"type:decimal(12,2);not null;check:amount > 0" json:"amount"`
+	Currency string        `gorm:"size:3;not null" json:"currency"`
+	Method   PaymentMethod `gorm:"type:varchar(20);not null;check:method IN ('cash','bank_transfer','card')" json:"method"`
+
+	// CreditAmount is the part of Amount that exceeded what was due on the
+	// invoice and was credited to the customer instead.
+	CreditAmount money.Money `gorm:"type:decimal(12,2);not null;default:0" json:"creditAmount"`
+
+	Reference  string    `gorm:"size:100" json:"reference"` // e.g. bank transfer or card transaction reference
+	ReceivedAt time.Time `gorm:"not null" json:"receivedAt"`
+	Notes      string    `gorm:"type:text" json:"notes"`
+}
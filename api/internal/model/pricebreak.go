@@ -0,0 +1,28 @@
+package model
+
+import "github.com/deveasyclick/openb2b/internal/shared/money"
+
+// PriceBreak is the unit price when buying at least MinQuantity units, e.g.
+// 8.50 a unit from 10 units.
+// @Description Quantity price break
+type PriceBreak struct {
+	MinQuantity int         `json:"minQuantity"`
+	Price       money.Money `json:"price"`
+}
+
+// PriceBreaks are the quantity breaks of a price, in any order.
+type PriceBreaks []PriceBreak
+
+// For returns the price of the largest break quantity reaches, if any.
+func (b PriceBreaks) For(quantity int) (money.Money, bool) {
+	best := -1
+	for i, brk := range b {
+		if brk.MinQuantity <= quantity && (best == -1 || brk.MinQuantity > b[best].MinQuantity) {
+			best = i
+		}
+	}
+	if best == -1 {
+		return money.Zero, false
+	}
+	return b[best].Price, true
+}
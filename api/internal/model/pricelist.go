@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// PriceList is a set of negotiated variant prices for some customers, either
+// assigned to them directly or through their customer group. A list applies
+// to orders in its currency placed from ValidFrom until ValidTo; open ends
+// are unbounded. When several lists price a variant, lists assigned to the
+// customer directly win over group lists, then the highest Priority wins.
+// @Description Price list
+type PriceList struct {
+	BaseModel
+	OrgID     uint            `gorm:"not null;index" json:"orgId"`
+	Name      string          `gorm:"type:varchar(100);not null;check:name <> ''" json:"name"`
+	Currency  string          `gorm:"size:3;not null" json:"currency"`
+	ValidFrom *time.Time      `json:"validFrom,omitempty"`
+	ValidTo   *time.Time      `json:"validTo,omitempty"` // exclusive
+	Priority  int             `gorm:"not null;default:0" json:"priority"`
+	Items     []PriceListItem `gorm:"foreignKey:PriceListID;constraint:OnDelete:CASCADE;" json:"items,omitempty"`
+	Customers []Customer      `gorm:"many2many:price_list_customers" json:"customers,omitempty"`
+	Groups    []CustomerGroup `gorm:"many2many:price_list_groups" json:"groups,omitempty"`
+}
+
+// PriceListItem is the price of a variant on a price list.
+// @Description Price list item
+type PriceListItem struct {
+	BaseModel
+	OrgID       uint        `gorm:"not null;index" json:"orgId"`
+	PriceListID uint        `gorm:"not null;uniqueIndex:idx_price_list_variant" json:"priceListId"`
+	VariantID   uint        `gorm:"not null;uniqueIndex:idx_price_list_variant" json:"variantId"`
+	Variant     *Variant    `gorm:"foreignKey:VariantID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"variant,omitempty"`
+	Price       money.Money `gorm:"not null" json:"price"`
+	// PriceBreaks are cheaper prices for larger quantities. Price applies
+	// below the smallest break.
+	PriceBreaks PriceBreaks `gorm:"serializer:json" json:"priceBreaks,omitempty"`
+}
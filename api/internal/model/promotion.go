@@ -0,0 +1,83 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+type PromotionType string
+
+const (
+	PromotionPercentage PromotionType = "percentage"
+	PromotionFixed      PromotionType = "fixed"
+	// PromotionBuyXGetY gives GetQuantity units free for every BuyQuantity
+	// units bought of a variant
+	PromotionBuyXGetY PromotionType = "buy_x_get_y"
+)
+
+// PromotionRule is what a promotion takes off an order.
+type PromotionRule struct {
+	Type PromotionType `gorm:"type:varchar(20)" json:"type,omitempty"`
+	// Amount is a percentage, e.g. 12.50 for 12.5%, or a fixed amount
+	Amount      money.Money `gorm:"type:decimal(12,2);not null;default:0" json:"amount"`
+	BuyQuantity int         `gorm:"not null;default:0" json:"buyQuantity,omitempty"`
+	GetQuantity int         `gorm:"not null;default:0" json:"getQuantity,omitempty"`
+	// MinOrderValue is the order subtotal below which the promotion gives
+	// nothing
+	MinOrderValue money.Money `gorm:"type:decimal(12,2);not null;default:0" json:"minOrderValue"`
+}
+
+// Promotion is a discount an order gets by its Code. Fixed amounts and the
+// minimum order value are in the org base currency. It applies to the
+// variants of ProductIDs and of products in Categories, or to every variant
+// when both are empty.
+// @Description Promotion
+type Promotion struct {
+	BaseModel
+	OrgID uint   `gorm:"not null;uniqueIndex:idx_org_promotion_code" json:"orgId"`
+	Code  string `gorm:"type:varchar(50);not null;uniqueIndex:idx_org_promotion_code;check:code <> ''" json:"code"`
+	Name  string `gorm:"type:varchar(100);not null" json:"name"`
+
+	PromotionRule `gorm:"embedded"`
+
+	ProductIDs []uint   `gorm:"serializer:json" json:"productIds,omitempty"`
+	Categories []string `gorm:"serializer:json" json:"categories,omitempty"`
+
+	// UsageLimit caps the orders that may use the promotion, PerCustomerLimit
+	// the orders of each customer. Nil is no limit.
+	UsageLimit       *int `json:"usageLimit,omitempty"`
+	PerCustomerLimit *int `json:"perCustomerLimit,omitempty"`
+	TimesUsed        int  `gorm:"not null;default:0" json:"timesUsed"`
+
+	StartsAt *time.Time `json:"startsAt,omitempty"`
+	EndsAt   *time.Time `json:"endsAt,omitempty"` // exclusive
+	Active   bool       `gorm:"not null;default:true" json:"active"`
+}
+
+// ActiveAt reports whether the promotion can be used at t.
+func (p *Promotion) ActiveAt(t time.Time) bool {
+	return p.Active && (p.StartsAt == nil || !t.Before(*p.StartsAt)) && (p.EndsAt == nil || t.Before(*p.EndsAt))
+}
+
+// Eligible reports whether the promotion applies to the variants of a
+// product in category.
+func (p *Promotion) Eligible(productID uint, category string) bool {
+	if len(p.ProductIDs) == 0 && len(p.Categories) == 0 {
+		return true
+	}
+	return slices.Contains(p.ProductIDs, productID) || category != "" && slices.Contains(p.Categories, category)
+}
+
+// PromotionRedemption records a promotion used by an order.
+// @Description Promotion redemption
+type PromotionRedemption struct {
+	BaseModel
+	OrgID       uint        `gorm:"not null;index" json:"orgId"`
+	PromotionID uint        `gorm:"not null;uniqueIndex:idx_promotion_order" json:"promotionId"`
+	OrderID     uint        `gorm:"not null;uniqueIndex:idx_promotion_order" json:"orderId"`
+	CustomerID  uint        `gorm:"not null;index" json:"customerId"`
+	Amount      money.Money `gorm:"type:decimal(12,2);not null" json:"amount"` // discount given, in Currency
+	Currency    string      `gorm:"size:3;not null" json:"currency"`
+}
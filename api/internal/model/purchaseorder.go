@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// PurchaseOrderStatus represents the possible statuses of a purchase order
+type PurchaseOrderStatus string
+
+const (
+	// draft, still being put together, its lines can change.
+	PurchaseOrderDraft PurchaseOrderStatus = "draft"
+	// sent, emailed to the supplier, waiting for the goods.
+	PurchaseOrderSent PurchaseOrderStatus = "sent"
+	// partially_received, some of the goods arrived.
+	PurchaseOrderPartiallyReceived PurchaseOrderStatus = "partially_received"
+	// received, every line arrived in full.
+	PurchaseOrderReceived PurchaseOrderStatus = "received"
+	// closed, nothing more is expected, whatever did not arrive is dropped.
+	PurchaseOrderClosed PurchaseOrderStatus = "closed"
+)
+
+// PurchaseOrder orders stock of variants from a supplier. Received goods
+// are added to the stock on hand as receipts.
+// @Description Purchase order response model
+type PurchaseOrder struct {
+	BaseModel
+
+	OrgID      uint      `gorm:"index;not null" json:"orgId"`
+	SupplierID uint      `gorm:"index;not null" json:"supplierId"`
+	Supplier   *Supplier `gorm:"foreignKey:SupplierID" json:"supplier,omitempty"`
+
+	// WarehouseID is where the goods are received, the default warehouse
+	// when nil
+	WarehouseID *uint      `gorm:"index" json:"warehouseId,omitempty"`
+	Warehouse   *Warehouse `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"`
+
+	PurchaseOrderNumber string              `gorm:"uniqueIndex;size:50;not null" json:"purchaseOrderNumber"`
+	Status              PurchaseOrderStatus `gorm:"type:varchar(20);default:'draft';not null;check:status IN ('draft','sent','partially_received','received','closed')" json:"status"`
+	Notes               string              `gorm:"type:text" json:"notes"`
+	ExpectedAt          *time.Time          `json:"expectedAt"` // when the supplier should deliver
+	SentAt              *time.Time          `json:"sentAt"`
+	ReceivedAt          *time.Time          `json:"receivedAt"` // when the last goods arrived
+	ClosedAt            *time.Time          `json:"closedAt"`
+
+	Currency string      `gorm:"size:3;not null" json:"currency"`
+	Total    money.Money `gorm:"type:decimal(12,2);not null" json:"total"`
+
+	Lines []PurchaseOrderLine `gorm:"foreignKey:PurchaseOrderID" json:"lines"`
+}
+
+// PurchaseOrderLine is a quantity of a variant bought at a unit cost.
+type PurchaseOrderLine struct {
+	BaseModel
+
+	OrgID           uint `gorm:"index;not null" json:"orgId"`
+	PurchaseOrderID uint `gorm:"index;not null" json:"purchaseOrderId"`
+	VariantID       uint `gorm:"index;not null" json:"variantId"`
+
+	SKU              string      `gorm:"type:varchar(50)" json:"sku"`
+	Quantity         int         `gorm:"not null;check:quantity > 0" json:"quantity"`
+	ReceivedQuantity int         `gorm:"not null;default:0" json:"receivedQuantity"`
+	UnitCost         money.Money `gorm:"type:decimal(12,2);not null" json:"unitCost"`
+	LineTotal        money.Money `gorm:"type:decimal(12,2);not null" json:"lineTotal"`
+}
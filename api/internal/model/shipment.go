@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// ShipmentStatus represents the possible statuses of a shipment
+type ShipmentStatus string
+
+const (
+	// pending, being packed, quantities are set aside for it.
+	ShipmentPending ShipmentStatus = "pending"
+	// shipped, handed to the carrier.
+	ShipmentShipped ShipmentStatus = "shipped"
+	// delivered, received by the customer.
+	ShipmentDelivered ShipmentStatus = "delivered"
+	// cancelled, never shipped, its quantities can be shipped again.
+	ShipmentCancelled ShipmentStatus = "cancelled"
+)
+
+// Shipment is a part of an order sent to the customer at once. The delivery
+// status of the order is derived from its shipments.
+// @Description Shipment response model
+type Shipment struct {
+	BaseModel
+
+	OrgID   uint   `gorm:"index;not null" json:"orgId"`
+	OrderID uint   `gorm:"index;not null" json:"orderId"`
+	Order   *Order `gorm:"foreignKey:OrderID" json:"order,omitempty"`
+
+	// WarehouseID is where the shipment leaves from, the fulfilment location
+	// of its order
+	WarehouseID *uint      `gorm:"index" json:"warehouseId,omitempty"`
+	Warehouse   *Warehouse `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"`
+
+	ShipmentNumber string         `gorm:"uniqueIndex;size:50;not null" json:"shipmentNumber"`
+	Status         ShipmentStatus `gorm:"type:varchar(20);default:'pending';not null;check:status IN ('pending','shipped','delivered','cancelled')" json:"status"`
+	Carrier        string         `gorm:"type:varchar(100)" json:"carrier"`
+	TrackingNumber string         `gorm:"type:varchar(100)" json:"trackingNumber"`
+	ShippedAt      *time.Time     `json:"shippedAt"`
+	DeliveredAt    *time.Time     `json:"deliveredAt"`
+
+	Lines []ShipmentLine `gorm:"foreignKey:ShipmentID" json:"lines"`
+}
+
+// ShipmentLine is a quantity of an order item in a shipment.
+type ShipmentLine struct {
+	BaseModel
+
+	OrgID       uint   `gorm:"index;not null" json:"orgId"`
+	ShipmentID  uint   `gorm:"index;not null" json:"shipmentId"`
+	OrderItemID uint   `gorm:"index;not null" json:"orderItemId"`
+	VariantID   uint   `gorm:"index;not null" json:"variantId"`
+	SKU         string `gorm:"type:varchar(50)" json:"sku"`
+	Quantity    int    `gorm:"not null" json:"quantity"`
+}
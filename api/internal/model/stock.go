@@ -0,0 +1,70 @@
+package model
+
+// StockLine is a quantity of a single variant to reserve, release or commit.
+type StockLine struct {
+	VariantID   uint
+	WarehouseID *uint // the default warehouse when nil
+	Quantity    int
+}
+
+// StockMovementReason explains why the stock of a variant changed
+type StockMovementReason string
+
+const (
+	// order, stock left the warehouse for an approved order.
+	StockReasonOrder StockMovementReason = "order"
+	// adjustment, stock was corrected by hand, e.g. after a count.
+	StockReasonAdjustment StockMovementReason = "adjustment"
+	// return, stock came back from a cancelled or returned order.
+	StockReasonReturn StockMovementReason = "return"
+	// receipt, stock was received, including the opening stock of a variant.
+	StockReasonReceipt StockMovementReason = "receipt"
+	// transfer, stock left or arrived at a warehouse on a stock transfer.
+	StockReasonTransfer StockMovementReason = "transfer"
+)
+
+// StockMovement is an entry of the stock ledger of a variant. The stock of a
+// variant always equals the sum of the deltas of its movements.
+// @Description Stock movement response model
+type StockMovement struct {
+	BaseModel
+
+	OrgID     uint     `gorm:"index;not null" json:"orgId"`
+	VariantID uint     `gorm:"index;not null" json:"variantId"`
+	Variant   *Variant `gorm:"foreignKey:VariantID" json:"variant,omitempty"`
+
+	// WarehouseID is where the stock moved, nil while the org had no
+	// warehouses
+	WarehouseID *uint `gorm:"index" json:"warehouseId,omitempty"`
+
+	Delta   int                 `gorm:"not null" json:"delta"`   // signed change of the stock on hand
+	Balance int                 `gorm:"not null" json:"balance"` // stock on hand after the movement
+	Reason  StockMovementReason `gorm:"type:varchar(20);not null;check:reason IN ('order','adjustment','return','receipt','transfer')" json:"reason"`
+
+	// ReferenceID points at the record that caused the movement, e.g. the
+	// order for order and return movements, the purchase order for
+	// receipts or the stock transfer for transfers.
+	ReferenceID *uint  `gorm:"index" json:"referenceId,omitempty"`
+	UserID      *uint  `gorm:"index" json:"userId,omitempty"` // nil for system changes
+	Note        string `json:"note"`
+}
+
+// StockLevel is the stock of a variant at a warehouse. The levels of a
+// variant add up to its Stock and Reserved.
+// @Description Stock level response model
+type StockLevel struct {
+	BaseModel
+
+	OrgID       uint       `gorm:"index;not null" json:"orgId"`
+	WarehouseID uint       `gorm:"not null;uniqueIndex:idx_warehouse_variant" json:"warehouseId"`
+	Warehouse   *Warehouse `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"`
+	VariantID   uint       `gorm:"not null;uniqueIndex:idx_warehouse_variant;index" json:"variantId"`
+
+	Stock    int `gorm:"not null;default:0" json:"stock"`
+	Reserved int `gorm:"not null;default:0" json:"reserved"` // held by pending orders fulfilled from the warehouse
+}
+
+// Available returns the stock at the warehouse that can still be reserved.
+func (l *StockLevel) Available() int {
+	return l.Stock - l.Reserved
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// StockTransferStatus represents the possible statuses of a stock transfer
+type StockTransferStatus string
+
+const (
+	// pending, being put together, no stock moved yet.
+	StockTransferPending StockTransferStatus = "pending"
+	// in_transit, shipped, the stock left the source warehouse.
+	StockTransferInTransit StockTransferStatus = "in_transit"
+	// received, the stock is on hand at the destination warehouse.
+	StockTransferReceived StockTransferStatus = "received"
+	// cancelled, never shipped.
+	StockTransferCancelled StockTransferStatus = "cancelled"
+)
+
+// StockTransfer moves stock of variants from one warehouse of the org to
+// another. Both legs are recorded as transfer movements.
+// @Description Stock transfer response model
+type StockTransfer struct {
+	BaseModel
+
+	OrgID           uint       `gorm:"index;not null" json:"orgId"`
+	FromWarehouseID uint       `gorm:"index;not null" json:"fromWarehouseId"`
+	FromWarehouse   *Warehouse `gorm:"foreignKey:FromWarehouseID" json:"fromWarehouse,omitempty"`
+	ToWarehouseID   uint       `gorm:"index;not null" json:"toWarehouseId"`
+	ToWarehouse     *Warehouse `gorm:"foreignKey:ToWarehouseID" json:"toWarehouse,omitempty"`
+
+	TransferNumber string              `gorm:"uniqueIndex;size:50;not null" json:"transferNumber"`
+	Status         StockTransferStatus `gorm:"type:varchar(20);default:'pending';not null;check:status IN ('pending','in_transit','received','cancelled')" json:"status"`
+	Notes          string              `gorm:"type:text" json:"notes"`
+	ShippedAt      *time.Time          `json:"shippedAt"`
+	ReceivedAt     *time.Time          `json:"receivedAt"`
+	CancelledAt    *time.Time          `json:"cancelledAt"`
+
+	Lines []StockTransferLine `gorm:"foreignKey:StockTransferID" json:"lines"`
+}
+
+// StockTransferLine is a quantity of a variant on a stock transfer.
+type StockTransferLine struct {
+	BaseModel
+
+	OrgID           uint   `gorm:"index;not null" json:"orgId"`
+	StockTransferID uint   `gorm:"index;not null" json:"stockTransferId"`
+	VariantID       uint   `gorm:"index;not null" json:"variantId"`
+	SKU             string `gorm:"type:varchar(50)" json:"sku"`
+	Quantity        int    `gorm:"not null;check:quantity > 0" json:"quantity"`
+}
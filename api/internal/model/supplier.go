@@ -0,0 +1,16 @@
+package model
+
+// Supplier is a company the org buys stock from with purchase orders.
+// @Description Supplier response model
+type Supplier struct {
+	BaseModel
+	OrgID       uint     `gorm:"index;not null" json:"orgId"`
+	Name        string   `gorm:"not null;type:varchar(100);check:name <> ''" json:"name"`
+	ContactName string   `gorm:"type:varchar(100)" json:"contactName"`
+	Email       string   `gorm:"not null;type:varchar(100)" json:"email"` // purchase orders are emailed here
+	PhoneNumber string   `gorm:"type:varchar(50)" json:"phoneNumber"`
+	Address     *Address `gorm:"embedded;embeddedPrefix:address_" json:"address"`
+	Currency    string   `gorm:"size:3" json:"currency"` // currency of purchase orders, the org base currency when empty
+
+	PurchaseOrders []*PurchaseOrder `json:"purchaseOrders,omitempty"`
+}
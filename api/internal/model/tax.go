@@ -0,0 +1,47 @@
+package model
+
+import (
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// TaxRate is a tax the org charges, e.g. VAT at 7.5%. A compound tax is
+// charged on the price plus the taxes applied before it, otherwise taxes are
+// charged on the price alone.
+// @Description Tax rate
+type TaxRate struct {
+	BaseModel
+	OrgID    uint    `gorm:"not null;index" json:"orgId"`
+	Name     string  `gorm:"type:varchar(50);not null;check:name <> ''" json:"name"` // printed on invoices, e.g. "VAT"
+	Rate     float64 `gorm:"type:decimal(9,6);not null;check:rate >= 0" json:"rate"` // e.g. 0.075 for 7.5%
+	Compound bool    `gorm:"not null;default:false" json:"compound"`
+	Priority int     `gorm:"not null;default:0" json:"priority"` // taxes of a class apply in ascending priority
+}
+
+// TaxClass is the set of taxes charged on the variants in it, e.g.
+// "Standard" for VAT plus a local levy. A class with a 0% rate is zero rated
+// and still prints the tax on invoices; an exempt class charges no tax at
+// all, whatever its rates.
+// @Description Tax class
+type TaxClass struct {
+	BaseModel
+	OrgID  uint      `gorm:"not null;index" json:"orgId"`
+	Name   string    `gorm:"type:varchar(50);not null;check:name <> ''" json:"name"`
+	Exempt bool      `gorm:"not null;default:false" json:"exempt"`
+	Rates  []TaxRate `gorm:"many2many:tax_class_rates" json:"rates"`
+}
+
+// TaxLine is one tax charged on an order or invoice line. Lines are a
+// snapshot of the rates at the time the order was priced.
+type TaxLine struct {
+	Name     string      `json:"name"`
+	Rate     float64     `json:"rate"`
+	Compound bool        `json:"compound,omitempty"`
+	Amount   money.Money `json:"amount"`
+}
+
+// Percent formats the rate as a percentage, e.g. "7.5%".
+func (t TaxLine) Percent() string {
+	return strconv.FormatFloat(t.Rate*100, 'f', -1, 64) + "%"
+}
@@ -11,6 +11,7 @@ const (
 	RoleOwner  Role = "distributor"
 	RoleAdmin  Role = "admin"
 	RoleViewer Role = "viewer"
+	RoleSales  Role = "sales"
 )
 
 const errInvalidRoleValue = "invalid role value"
@@ -23,7 +24,7 @@ func (r *Role) Scan(value interface{}) error {
 	}
 
 	switch Role(strValue) {
-	case RoleOwner, RoleAdmin, RoleViewer:
+	case RoleOwner, RoleAdmin, RoleViewer, RoleSales:
 		*r = Role(strValue)
 		return nil
 	default:
@@ -34,7 +35,7 @@ func (r *Role) Scan(value interface{}) error {
 // Value implements the driver Valuer interface for database serialization
 func (r Role) Value() (driver.Value, error) {
 	switch r {
-	case RoleOwner, RoleAdmin, RoleViewer:
+	case RoleOwner, RoleAdmin, RoleViewer, RoleSales:
 		return string(r), nil
 	default:
 		return nil, errors.New(errInvalidRoleValue)
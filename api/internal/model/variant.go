@@ -1,15 +1,48 @@
 package model
 
+import "github.com/deveasyclick/openb2b/internal/shared/money"
+
 // Variant represents an variant entity
 // @Description Variant response model
 type Variant struct {
 	BaseModel
-	ProductID uint    `gorm:"index;not null" json:"productId"`
-	Color     string  `json:"color"`
-	Size      string  `json:"size"`
-	Price     float64 `gorm:"not null" json:"price"`
-	Stock     int     `gorm:"not null" json:"stock"`
-	SKU       string  `gorm:"not null;uniqueIndex:idx_org_sku" json:"sku"`
-	OrgID     uint    `gorm:"not null;uniqueIndex:idx_org_sku" json:"orgId"` //needed for sku uniqueness per org
-	TaxRate   float64 `gorm:"not null" json:"taxRate"`
+	ProductID uint        `gorm:"index;not null" json:"productId"`
+	Color     string      `json:"color"`
+	Size      string      `json:"size"`
+	Price     money.Money `gorm:"not null" json:"price"`
+	Stock     int         `gorm:"not null" json:"stock"`
+	Reserved  int         `gorm:"not null;default:0" json:"reserved"` // held by pending orders, always <= Stock
+	SKU       string      `gorm:"not null;uniqueIndex:idx_org_sku" json:"sku"`
+	OrgID     uint        `gorm:"not null;uniqueIndex:idx_org_sku" json:"orgId"` //needed for sku uniqueness per org
+	TaxRate   float64     `gorm:"not null" json:"taxRate"`                       // charged as a single tax when the variant has no tax class
+
+	// AllowBackorder accepts orders for more than is available, the rest
+	// is backordered until the variant is restocked
+	AllowBackorder bool `gorm:"not null;default:false" json:"allowBackorder"`
+
+	TaxClassID *uint     `gorm:"index" json:"taxClassId,omitempty"`
+	TaxClass   *TaxClass `gorm:"foreignKey:TaxClassID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"taxClass,omitempty"`
+
+	// Prices are prices in other currencies than the org base currency, by
+	// currency code. Price is converted at the exchange rate otherwise.
+	Prices map[string]money.Money `gorm:"serializer:json" json:"prices,omitempty"`
+
+	// PriceBreaks are cheaper unit prices for larger quantities, in the
+	// base currency like Price. Price applies below the smallest break.
+	PriceBreaks PriceBreaks `gorm:"serializer:json" json:"priceBreaks,omitempty"`
+
+	// Locations are the stock levels of the variant per warehouse, which add
+	// up to Stock and Reserved. Empty for orgs without warehouses.
+	Locations []StockLevel `gorm:"foreignKey:VariantID" json:"locations,omitempty"`
+}
+
+// PriceIn returns the price of the variant set for currency, if any.
+func (v *Variant) PriceIn(currency string) (money.Money, bool) {
+	price, ok := v.Prices[currency]
+	return price, ok
+}
+
+// Available returns the stock that can still be reserved by new orders.
+func (v *Variant) Available() int {
+	return v.Stock - v.Reserved
 }
@@ -0,0 +1,17 @@
+package model
+
+// Warehouse is a location stock of the org is kept at, e.g. the main store
+// or a satellite depot. Orgs without warehouses only keep stock per variant.
+// @Description Warehouse response model
+type Warehouse struct {
+	BaseModel
+	OrgID   uint     `gorm:"not null;uniqueIndex:idx_org_warehouse_code;uniqueIndex:idx_org_default_warehouse,where:is_default = true" json:"orgId"`
+	Name    string   `gorm:"not null;type:varchar(100);check:name <> ''" json:"name"`
+	Code    string   `gorm:"not null;type:varchar(20);uniqueIndex:idx_org_warehouse_code" json:"code"` // short name, e.g. on pick lists
+	Address *Address `gorm:"embedded;embeddedPrefix:address_" json:"address"`
+
+	// IsDefault marks the warehouse stock is kept at when no other is
+	// given, e.g. for orders without a fulfilment location. The first
+	// warehouse of an org is its default and takes over its stock.
+	IsDefault bool `gorm:"not null;default:false" json:"isDefault"`
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// WebhookDeliveryStatus is the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	// pending, not delivered yet and still being retried.
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// succeeded, the endpoint answered with a 2xx status.
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// failed, every attempt failed. It can still be redelivered by hand.
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookSubscription is an endpoint of an org that domain events of the
+// chosen types are posted to.
+// @Description Webhook subscription response model
+type WebhookSubscription struct {
+	BaseModel
+
+	OrgID       uint     `gorm:"index;not null" json:"orgId"`
+	URL         string   `gorm:"size:2048;not null" json:"url"`
+	Description string   `gorm:"size:255" json:"description"`
+	EventTypes  []string `gorm:"serializer:json;type:text;not null" json:"eventTypes"`
+	Active      bool     `gorm:"not null;default:true" json:"active"`
+	// Secret signs the deliveries, in the Svix "whsec_" format
+	Secret string `gorm:"size:100;not null" json:"secret" audit:"-"`
+}
+
+// Subscribes reports whether the subscription is active and wants events of
+// eventType.
+func (s *WebhookSubscription) Subscribes(eventType string) bool {
+	if !s.Active {
+		return false
+	}
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is the delivery of one event to one webhook subscription,
+// with the outcome of its last attempt.
+// @Description Webhook delivery response model
+type WebhookDelivery struct {
+	BaseModel
+
+	OrgID          uint                 `gorm:"index;not null" json:"orgId"`
+	SubscriptionID uint                 `gorm:"not null;uniqueIndex:idx_webhook_deliveries_subscription_event" json:"subscriptionId"`
+	Subscription   *WebhookSubscription `gorm:"foreignKey:SubscriptionID;constraint:OnDelete:CASCADE" json:"subscription,omitempty"`
+	EventID        uint                 `gorm:"not null;uniqueIndex:idx_webhook_deliveries_subscription_event" json:"eventId"`
+	EventType      string               `gorm:"size:100;not null;index" json:"eventType"`
+	Payload        string               `gorm:"type:text;not null" json:"payload"` // JSON body posted to the endpoint
+
+	Status WebhookDeliveryStatus `gorm:"type:varchar(20);not null;default:'pending';index;check:status IN ('pending','succeeded','failed')" json:"status"`
+	// Attempts counts the attempts since the delivery was last (re)queued
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus int        `json:"responseStatus"`
+	ResponseBody   string     `gorm:"type:text" json:"responseBody"` // truncated
+	LastError      string     `gorm:"type:text" json:"lastError"`
+	LastAttemptAt  *time.Time `json:"lastAttemptAt,omitempty"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+}
@@ -0,0 +1,181 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"name": true}
+
+// For Swagger docs
+type APIResponseAPIKey struct {
+	Code    int          `json:"code"`
+	Message string       `json:"message"`
+	Data    model.APIKey `json:"data"`
+}
+
+type APIKeyHandler struct {
+	service interfaces.APIKeyService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.APIKeyService, appCtx *deps.AppContext) interfaces.APIKeyHandler {
+	return &APIKeyHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List API keys with filtering and pagination
+// @Description  Returns a paginated list of the API keys of the org. Only key prefixes are returned.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        name          query     string  false  "Filter by name"
+// @Success      200           {object}  APIResponseAPIKey
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /api-keys [get]
+// @Security BearerAuth
+func (h *APIKeyHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterAPIKey, h.appCtx.Logger)
+		return
+	}
+
+	apiKeys, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterAPIKey, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.APIKey]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      apiKeys,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create API key
+// @Description Create an API key for machine-to-machine access. The full key is only returned in this response; send it as "Authorization: Bearer <key>". Scopes must be permissions granted to the caller.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAPIKeyDTO true "API key payload"
+// @Success 201 {object} APIResponseAPIKey
+// @Failure      400  {object}  apperrors.APIErrorResponse
+// @Failure      403  {object}  apperrors.APIErrorResponse
+// @Failure      500  {object}  apperrors.APIErrorResponse
+// @Router /api-keys [post]
+// @Security BearerAuth
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreateAPIKeyDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateAPIKey, h.appCtx.Logger)
+		return
+	}
+
+	apiKey, err := h.service.Create(ctx, userFromContext, &req)
+	if err != nil {
+		if errors.Is(err, errScopeNotGranted) {
+			response.WriteJSONErrorV2(w, http.StatusForbidden, nil, apperrors.ErrAPIKeyScopeNotGranted, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateAPIKey, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, apiKey, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get API key
+// @Description Get an API key by ID
+// @Tags api-keys
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200 {object} APIResponseAPIKey
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /api-keys/{id} [get]
+// @Security BearerAuth
+func (h *APIKeyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	apiKey, err := h.service.FindOneWithFields(r.Context(), nil, map[string]any{"id": id}, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrAPIKeyNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindAPIKey, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, apiKey, h.appCtx.Logger)
+}
+
+// Revoke godoc
+// @Summary Revoke API key
+// @Description Revoke an API key. Requests using it are rejected with 401 from then on.
+// @Tags api-keys
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200 {object} APIResponseAPIKey
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /api-keys/{id}/revoke [post]
+// @Security BearerAuth
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	apiKey, err := h.service.Revoke(r.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrAPIKeyNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrRevokeAPIKey, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, apiKey, h.appCtx.Logger)
+}
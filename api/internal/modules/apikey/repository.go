@@ -0,0 +1,58 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.APIKeyRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.APIKey, int64, error) {
+	return pagination.Paginate[model.APIKey](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, apiKey *model.APIKey) error {
+	return r.db.WithContext(ctx).Create(apiKey).Error
+}
+
+func (r *repository) Update(ctx context.Context, apiKey *model.APIKey) error {
+	return r.db.WithContext(ctx).Save(apiKey).Error
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.APIKey, error) {
+	var result model.APIKey
+
+	query := r.db.WithContext(ctx).Model(model.APIKey{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.APIKeyRepository {
+	return &repository{db: tx}
+}
@@ -0,0 +1,109 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/internal/utils/apikeyutil"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+)
+
+// errScopeNotGranted is returned when a key would be granted more than its
+// creator is.
+var errScopeNotGranted = errors.New(apperrors.ErrAPIKeyScopeNotGranted)
+
+type service struct {
+	repo        interfaces.APIKeyRepository
+	userService interfaces.UserService
+}
+
+func NewService(repo interfaces.APIKeyRepository, userService interfaces.UserService) interfaces.APIKeyService {
+	return &service{
+		repo:        repo,
+		userService: userService,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.APIKey, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+// Create creates an API key granted DTO.Scopes, which creator must be granted
+// too. The returned key is the only time the full key is available.
+func (s *service) Create(ctx context.Context, creator *identity.ContextUser, DTO *dto.CreateAPIKeyDTO) (*model.APIKey, error) {
+	granted, err := s.grants(ctx, creator, DTO.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if !granted {
+		return nil, errScopeNotGranted
+	}
+
+	key, prefix, hash, err := apikeyutil.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := DTO.ToModel(creator.Org, creator.ID)
+	apiKey.Prefix = prefix
+	apiKey.SecretHash = hash
+	if err := s.repo.Create(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	apiKey.Key = key
+	return apiKey, nil
+}
+
+// grants reports whether user is granted every one of scopes.
+func (s *service) grants(ctx context.Context, user *identity.ContextUser, scopes []string) (bool, error) {
+	perms := make([]rbac.Permission, 0, len(scopes))
+	for _, scope := range scopes {
+		perms = append(perms, rbac.Permission(scope))
+	}
+
+	if user.APIKeyID != 0 {
+		return rbac.InScopes(user.Scopes, perms...), nil
+	}
+
+	role := model.Role(user.Role)
+	if role == "" {
+		dbUser, err := s.userService.FindByID(ctx, user.ID, nil)
+		if err != nil {
+			return false, err
+		}
+		role = dbUser.Role
+	}
+
+	return rbac.Can(role, perms...), nil
+}
+
+// Revoke stops an API key from authenticating any further requests.
+func (s *service) Revoke(ctx context.Context, ID uint) (*model.APIKey, error) {
+	apiKey, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.RevokedAt != nil {
+		return apiKey, nil
+	}
+
+	now := time.Now()
+	apiKey.RevokedAt = &now
+	if err := s.repo.Update(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.APIKey, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
@@ -0,0 +1,112 @@
+package auditlog
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"entity_type": true, "request_id": true}
+
+// For Swagger docs
+type APIResponseAuditEntry struct {
+	Code    int              `json:"code"`
+	Message string           `json:"message"`
+	Data    model.AuditEntry `json:"data"`
+}
+
+type AuditLogHandler struct {
+	service interfaces.AuditLogService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.AuditLogService, appCtx *deps.AppContext) interfaces.AuditLogHandler {
+	return &AuditLogHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List audit log entries with filtering and pagination
+// @Description  Returns the changes made to the records of the org, newest first unless sorted otherwise. Each entry holds the changed columns with their values before and after.
+// @Tags         audit-logs
+// @Accept       json
+// @Produce      json
+// @Param        page           query     int     false  "Page number (default: 1)"
+// @Param        limit          query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort           query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        search_fields  query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        entity_type    query     string  false  "Filter by table, e.g. 'products'"
+// @Param        entity_id      query     int     false  "Filter by ID of the changed record"
+// @Param        action         query     string  false  "Filter by action (create, update, delete)"
+// @Param        actor_id       query     int     false  "Filter by the user who made the change"
+// @Param        created_at_gte query     string  false  "Filter by changes made at or after this time"
+// @Param        created_at_lte query     string  false  "Filter by changes made at or before this time"
+// @Success      200            {object}  APIResponseAuditEntry
+// @Failure      400            {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500            {object}  apperrors.APIError "Internal server error"
+// @Router       /audit-logs [get]
+// @Security BearerAuth
+func (h *AuditLogHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterAuditLog, h.appCtx.Logger)
+		return
+	}
+	if opts.SortBy == "" {
+		opts.SortBy = "id desc"
+	}
+
+	entries, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterAuditLog, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.AuditEntry]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      entries,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get audit log entry
+// @Description Get an audit log entry by ID
+// @Tags audit-logs
+// @Produce json
+// @Param id path int true "Audit log entry ID"
+// @Success 200 {object} APIResponseAuditEntry
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /audit-logs/{id} [get]
+// @Security BearerAuth
+func (h *AuditLogHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	entry, err := h.service.FindOneWithFields(r.Context(), nil, map[string]any{"id": id}, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrAuditEntryNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindAuditEntry, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, entry, h.appCtx.Logger)
+}
@@ -0,0 +1,45 @@
+package auditlog
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.AuditLogRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.AuditEntry, int64, error) {
+	return pagination.Paginate[model.AuditEntry](ctx, r.db, opts)
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.AuditEntry, error) {
+	var result model.AuditEntry
+
+	query := r.db.WithContext(ctx).Model(model.AuditEntry{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
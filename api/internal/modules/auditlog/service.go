@@ -0,0 +1,27 @@
+package auditlog
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+)
+
+type service struct {
+	repo interfaces.AuditLogRepository
+}
+
+func NewService(repo interfaces.AuditLogRepository) interfaces.AuditLogService {
+	return &service{
+		repo: repo,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.AuditEntry, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.AuditEntry, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
@@ -0,0 +1,73 @@
+package backorder
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+)
+
+// filters maps the query parameters of the report to the columns they
+// filter backorders on.
+var filters = map[string]string{
+	"variant_id":  "order_items.variant_id",
+	"customer_id": "orders.customer_id",
+}
+
+// For Swagger docs
+type APIResponseBackorderReport struct {
+	Code    int                   `json:"code"`
+	Message string                `json:"message"`
+	Data    types.BackorderReport `json:"data"`
+}
+
+type BackorderHandler struct {
+	service interfaces.BackorderService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.BackorderService, appCtx *deps.AppContext) interfaces.BackorderHandler {
+	return &BackorderHandler{service: service, appCtx: appCtx}
+}
+
+// Report godoc
+// @Summary      Backorder report
+// @Description  Totals what pending and approved orders have backordered, by variant and customer, next to the stock of each variant. Backorders are allocated oldest order first when a variant is restocked.
+// @Tags         backorders
+// @Produce      json
+// @Param        variant_id   query     int  false  "Only backorders of this variant"
+// @Param        customer_id  query     int  false  "Only backorders of this customer"
+// @Success      200          {object}  APIResponseBackorderReport
+// @Failure      400          {object}  apperrors.APIErrorResponse
+// @Failure      500          {object}  apperrors.APIErrorResponse
+// @Router       /backorders [get]
+// @Security BearerAuth
+func (h *BackorderHandler) Report(w http.ResponseWriter, r *http.Request) {
+	where := map[string]any{}
+	for param, column := range filters {
+		v := r.URL.Query().Get(param)
+		if v == "" {
+			continue
+		}
+
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, fmt.Sprintf("%s: %s", apperrors.ErrInvalidId, param), h.appCtx.Logger)
+			return
+		}
+		where[column] = uint(id)
+	}
+
+	report, err := h.service.Report(r.Context(), where)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrBackorderReport, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, report, h.appCtx.Logger)
+}
@@ -0,0 +1,87 @@
+package backorder
+
+import (
+	"context"
+	"sort"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+)
+
+type service struct {
+	orderService   interfaces.OrderService
+	productService interfaces.ProductService
+}
+
+func NewService(orderService interfaces.OrderService, productService interfaces.ProductService) interfaces.BackorderService {
+	return &service{
+		orderService:   orderService,
+		productService: productService,
+	}
+}
+
+// Report totals the backorders of open orders matching where by variant and
+// customer, next to the stock of each variant. Lines are sorted by SKU, then
+// by their oldest order.
+func (s *service) Report(ctx context.Context, where map[string]any) (*types.BackorderReport, error) {
+	backorders, err := s.orderService.FindBackorders(ctx, where)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ variantID, customerID uint }
+	report := &types.BackorderReport{Lines: []types.BackorderReportLine{}}
+	lines := map[key]int{}
+	orders := map[key]map[uint]bool{}
+	var variantIDs []uint
+	for _, backorder := range backorders {
+		k := key{backorder.VariantID, backorder.CustomerID}
+		i, ok := lines[k]
+		if !ok {
+			i = len(report.Lines)
+			lines[k] = i
+			orders[k] = map[uint]bool{}
+			report.Lines = append(report.Lines, types.BackorderReportLine{
+				VariantID:    backorder.VariantID,
+				SKU:          backorder.SKU,
+				CustomerID:   backorder.CustomerID,
+				CustomerName: backorder.CustomerFirstName + " " + backorder.CustomerLastName,
+				Oldest:       backorder.OrderedAt, // backorders come oldest first
+			})
+			variantIDs = append(variantIDs, backorder.VariantID)
+		}
+
+		line := &report.Lines[i]
+		line.BackorderedQuantity += backorder.BackorderedQuantity
+		orders[k][backorder.OrderID] = true
+		line.Orders = len(orders[k])
+		report.BackorderedQuantity += backorder.BackorderedQuantity
+	}
+
+	if len(variantIDs) > 0 {
+		variants, err := s.productService.FindVariants(ctx, map[string]any{"id": variantIDs}, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		variantMap := make(map[uint]model.Variant, len(variants))
+		for _, v := range variants {
+			variantMap[v.ID] = v
+		}
+		for i := range report.Lines {
+			v := variantMap[report.Lines[i].VariantID]
+			report.Lines[i].Stock = v.Stock
+			report.Lines[i].Available = v.Available()
+		}
+	}
+
+	sort.SliceStable(report.Lines, func(i, j int) bool {
+		if report.Lines[i].SKU != report.Lines[j].SKU {
+			return report.Lines[i].SKU < report.Lines[j].SKU
+		}
+		return report.Lines[i].Oldest.Before(report.Lines[j].Oldest)
+	})
+
+	return report, nil
+}
@@ -0,0 +1,199 @@
+package creditnote
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/modules/customer"
+	"github.com/deveasyclick/openb2b/internal/modules/invoice"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedCreditNoteSearchFields = map[string]bool{"credit_note_number": true, "reason": true}
+
+// For Swagger docs
+type APIResponseCreditNote struct {
+	Code    int              `json:"code"`
+	Message string           `json:"message"`
+	Data    model.CreditNote `json:"data"`
+}
+
+type CreditNoteHandler struct {
+	service interfaces.CreditNoteService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.CreditNoteService, appCtx *deps.AppContext) interfaces.CreditNoteHandler {
+	return &CreditNoteHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List credit notes with filtering and pagination
+// @Description  Returns a paginated list of credit notes. Supports filtering, sorting, searching, and preloading.
+// @Tags         credit-notes
+// @Accept       json
+// @Produce      json
+// @Param        page               query     int     false  "Page number (default: 1)"
+// @Param        limit              query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort               query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        preloads           query     string  false  "Comma-separated list of relations to preload. relation must start with uppercase. e.g. 'Items,Invoice'"
+// @Param        search_fields      query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        invoice_id         query     int     false  "Filter by invoice"
+// @Param        customer_id        query     int     false  "Filter by customer"
+// @Param        credit_note_number query     string  false  "Filter by credit note number"
+// @Success      200                {object}  APIResponseCreditNote
+// @Failure      400                {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500                {object}  apperrors.APIError "Internal server error"
+// @Router       /credit-notes [get]
+// @Security BearerAuth
+func (h *CreditNoteHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedCreditNoteSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterCreditNote, h.appCtx.Logger)
+		return
+	}
+
+	creditNotes, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterCreditNote, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.CreditNote]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      creditNotes,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create credit note
+// @Description Credit some or all items of an issued invoice and email the credit note to the customer. The invoice balance goes down by the credit note total; anything already paid over the new balance becomes customer credit.
+// @Tags credit-notes
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCreditNoteDTO true "Credit note payload"
+// @Success 201 {object} APIResponseCreditNote
+// @Failure      400  {object}  apperrors.APIErrorResponse
+// @Failure      404  {object}  apperrors.APIErrorResponse
+// @Failure      409  {object}  apperrors.APIErrorResponse
+// @Failure      500  {object}  apperrors.APIErrorResponse
+// @Router /credit-notes [post]
+// @Security BearerAuth
+func (h *CreditNoteHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreateCreditNoteDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateCreditNote, h.appCtx.Logger)
+		return
+	}
+
+	creditNote, err := h.service.Create(ctx, userFromContext.Org, &req)
+	if err != nil {
+		var transitionErr *invoice.TransitionError
+		var stockErr *apperrors.InsufficientStockError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrInvoiceNotFound, h.appCtx.Logger)
+		case errors.Is(err, errInvoiceItemNotFound), errors.Is(err, errQuantityExceeded):
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+		case errors.Is(err, errInvoiceNotIssued), errors.Is(err, errNothingToCredit):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, err.Error(), h.appCtx.Logger)
+		case errors.Is(err, customer.ErrInsufficientCredit):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrInsufficientCredit, h.appCtx.Logger)
+		case errors.As(err, &transitionErr):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, transitionErr.Error(), h.appCtx.Logger)
+		case errors.As(err, &stockErr):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, stockErr.Error(), h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateCreditNote, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, creditNote, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get credit note
+// @Description Get a credit note by ID
+// @Tags credit-notes
+// @Produce json
+// @Param id path int true "Credit note ID"
+// @Success 200 {object} APIResponseCreditNote
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /credit-notes/{id} [get]
+// @Security BearerAuth
+func (h *CreditNoteHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	creditNote, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Items", "Invoice"})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrCreditNoteNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindCreditNote, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, creditNote, h.appCtx.Logger)
+}
+
+// Send godoc
+// @Summary Send credit note
+// @Description Email a credit note to the customer again
+// @Tags credit-notes
+// @Produce json
+// @Param id path int true "Credit note ID"
+// @Success 200 {object} response.APIResponseString
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /credit-notes/{id}/send [post]
+// @Security BearerAuth
+func (h *CreditNoteHandler) Send(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	if err := h.service.Send(r.Context(), uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrCreditNoteNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrSendCreditNote, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, "Credit note emailed", h.appCtx.Logger)
+}
@@ -0,0 +1,79 @@
+package creditnote
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.CreditNoteRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.CreditNote, int64, error) {
+	return pagination.Paginate[model.CreditNote](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, creditNote *model.CreditNote) error {
+	return r.db.WithContext(ctx).Create(creditNote).Error
+}
+
+// CreditedQuantities returns the quantity credited so far per invoice item of
+// an invoice.
+func (r *repository) CreditedQuantities(ctx context.Context, invoiceID uint) (map[uint]int, error) {
+	var rows []struct {
+		InvoiceItemID uint
+		Quantity      int
+	}
+
+	err := r.db.WithContext(ctx).Model(&model.CreditNoteItem{}).
+		Select("credit_note_items.invoice_item_id, SUM(credit_note_items.quantity) AS quantity").
+		Joins("JOIN credit_notes ON credit_notes.id = credit_note_items.credit_note_id AND credit_notes.deleted_at IS NULL").
+		Where("credit_notes.invoice_id = ?", invoiceID).
+		Group("credit_note_items.invoice_item_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	credited := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		credited[row.InvoiceItemID] = row.Quantity
+	}
+	return credited, nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.CreditNote, error) {
+	var result model.CreditNote
+
+	query := r.db.WithContext(ctx).Model(model.CreditNote{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.CreditNoteRepository {
+	return &repository{db: tx}
+}
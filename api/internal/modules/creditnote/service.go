@@ -0,0 +1,246 @@
+package creditnote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/modules/invoice"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/internal/utils/numbergen"
+	"github.com/deveasyclick/openb2b/internal/utils/pdfutil"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+var (
+	errInvoiceNotIssued    = errors.New(apperrors.ErrInvoiceNotIssued)
+	errInvoiceItemNotFound = errors.New(apperrors.ErrInvoiceItemNotFound)
+	errNothingToCredit     = errors.New(apperrors.ErrNothingToCredit)
+	errQuantityExceeded    = errors.New(apperrors.ErrCreditQuantityExceeded)
+)
+
+type service struct {
+	repo           interfaces.CreditNoteRepository
+	invoiceService interfaces.InvoiceService
+	paymentService interfaces.PaymentService
+	orderService   interfaces.OrderService
+	jobs           interfaces.JobQueue
+	appCtx         *deps.AppContext
+	db             *gorm.DB
+}
+
+func NewService(
+	repo interfaces.CreditNoteRepository,
+	invoiceService interfaces.InvoiceService,
+	paymentService interfaces.PaymentService,
+	orderService interfaces.OrderService,
+	appCtx *deps.AppContext,
+) interfaces.CreditNoteService {
+	return &service{
+		repo:           repo,
+		invoiceService: invoiceService,
+		paymentService: paymentService,
+		orderService:   orderService,
+		jobs:           appCtx.Jobs,
+		appCtx:         appCtx,
+		db:             appCtx.DB,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.CreditNote, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+// Create credits some or all of an issued invoice and emails the credit note
+// to the customer. What is owed on the invoice goes down by the credit note
+// total, and whatever was already paid over the new balance becomes customer
+// credit. With DTO.Restock the credited quantities are put back on hand.
+func (s *service) Create(ctx context.Context, orgID uint, DTO *dto.CreateCreditNoteDTO) (*model.CreditNote, error) {
+	var creditNote *model.CreditNote
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		invoiceService := s.invoiceService.WithTx(tx)
+
+		// Locked, so credit notes of the same invoice are created one at a
+		// time and never credit a quantity twice
+		inv, err := invoiceService.FindForUpdate(ctx, DTO.InvoiceID, []string{"Items", "Order"})
+		if err != nil {
+			return err
+		}
+		if !invoice.IsIssued(inv.Status) {
+			return errInvoiceNotIssued
+		}
+
+		credited, err := repo.CreditedQuantities(ctx, inv.ID)
+		if err != nil {
+			return err
+		}
+
+		items, err := creditItems(orgID, inv, credited, DTO.Items)
+		if err != nil {
+			return err
+		}
+
+		creditNote = &model.CreditNote{
+			OrgID:            orgID,
+			InvoiceID:        inv.ID,
+			CustomerID:       inv.Order.CustomerID,
+			CreditNoteNumber: numbergen.Generate("CN"),
+			Reason:           DTO.Reason,
+			IssuedAt:         time.Now(),
+			Restock:          DTO.Restock,
+			Currency:         inv.Currency,
+			Items:            items,
+		}
+		for _, item := range items {
+			creditNote.Subtotal = creditNote.Subtotal.Add(item.UnitPrice.Mul(item.Quantity))
+			creditNote.TaxTotal = creditNote.TaxTotal.Add(item.TaxAmount)
+			creditNote.Total = creditNote.Total.Add(item.LineTotal)
+		}
+
+		if err := repo.Create(ctx, creditNote); err != nil {
+			return err
+		}
+
+		if DTO.Restock {
+			// Returned goods go back to where the order was fulfilled from,
+			// as far as the order deducted them
+			quantities := make(map[uint]int, len(items))
+			for _, item := range items {
+				quantities[item.VariantID] += item.Quantity
+			}
+			if err := s.orderService.WithTx(tx).Restock(ctx, inv.OrderID, quantities); err != nil {
+				return err
+			}
+		}
+
+		if err := invoiceService.ApplyCredit(ctx, inv, inv.AmountCredited.Add(creditNote.Total)); err != nil {
+			return err
+		}
+
+		// Less is owed now, so part of what was paid may become customer credit
+		if err := s.paymentService.WithTx(tx).Settle(ctx, inv.ID); err != nil {
+			return err
+		}
+
+		return s.jobs.WithTx(tx).Enqueue(ctx, types.CreditNoteEmailJobType, types.CreditNoteEmailJob{CreditNoteID: creditNote.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return creditNote, nil
+}
+
+// Send queues a credit note to be emailed to the customer of its invoice.
+func (s *service) Send(ctx context.Context, ID uint) error {
+	creditNote, err := s.repo.FindOneWithFields(ctx, []string{"id"}, map[string]any{"id": ID}, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.jobs.Enqueue(ctx, types.CreditNoteEmailJobType, types.CreditNoteEmailJob{CreditNoteID: creditNote.ID})
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.CreditNote, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.CreditNoteService {
+	return &service{
+		repo:           s.repo.WithTx(tx),
+		invoiceService: s.invoiceService.WithTx(tx),
+		paymentService: s.paymentService.WithTx(tx),
+		orderService:   s.orderService.WithTx(tx),
+		jobs:           s.jobs.WithTx(tx),
+		appCtx:         s.appCtx,
+		db:             tx,
+	}
+}
+
+// creditItems builds the credit note items for the requested quantities of
+// the invoice items. credited holds what was credited per invoice item
+// before. Without requested items, everything not credited yet is credited.
+func creditItems(orgID uint, inv *model.Invoice, credited map[uint]int, requested []dto.CreateCreditNoteItemDTO) ([]model.CreditNoteItem, error) {
+	invoiceItems := make(map[uint]*model.InvoiceItem, len(inv.Items))
+	for _, item := range inv.Items {
+		invoiceItems[item.ID] = item
+	}
+
+	quantities := make(map[uint]int)
+	var order []uint
+	if len(requested) == 0 {
+		for _, item := range inv.Items {
+			if remaining := item.Quantity - credited[item.ID]; remaining > 0 {
+				quantities[item.ID] = remaining
+				order = append(order, item.ID)
+			}
+		}
+		if len(order) == 0 {
+			return nil, errNothingToCredit
+		}
+	} else {
+		for _, r := range requested {
+			if _, ok := invoiceItems[r.InvoiceItemID]; !ok {
+				return nil, fmt.Errorf("%w: %d", errInvoiceItemNotFound, r.InvoiceItemID)
+			}
+			if _, seen := quantities[r.InvoiceItemID]; !seen {
+				order = append(order, r.InvoiceItemID)
+			}
+			quantities[r.InvoiceItemID] += r.Quantity
+		}
+	}
+
+	items := make([]model.CreditNoteItem, 0, len(order))
+	for _, id := range order {
+		item := invoiceItems[id]
+		quantity := quantities[id]
+		if credited[id]+quantity > item.Quantity {
+			return nil, fmt.Errorf("%w: %s (invoiced %d, credited %d, requested %d)",
+				errQuantityExceeded, item.SKU, item.Quantity, credited[id], quantity)
+		}
+
+		items = append(items, model.CreditNoteItem{
+			OrgID:         orgID,
+			InvoiceItemID: item.ID,
+			VariantID:     item.VariantID,
+			SKU:           item.SKU,
+			Quantity:      quantity,
+			UnitPrice:     item.UnitPrice,
+			TaxAmount:     item.TaxAmount.MulFrac(int64(quantity), int64(item.Quantity), money.HalfUp),
+			LineTotal:     item.LineTotal.MulFrac(int64(quantity), int64(item.Quantity), money.HalfUp),
+		})
+	}
+
+	return items, nil
+}
+
+// SendEmail emails a credit note to the customer of its invoice. It runs as
+// the job enqueued by Create and Send.
+func (s *service) SendEmail(ctx context.Context, job types.CreditNoteEmailJob) error {
+	creditNote, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": job.CreditNoteID}, []string{"Items", "Invoice"})
+	if err != nil {
+		return err
+	}
+
+	pdfBytes, err := pdfutil.GenerateCreditNotePDF(creditNote)
+	if err != nil {
+		return fmt.Errorf("generate credit note PDF: %w", err)
+	}
+
+	email := creditNote.Invoice.CustomerEmail
+	if err := s.appCtx.Mailer.SendWithAttachment(email, "Credit Note "+creditNote.CreditNoteNumber, "Please find attached.", "credit-note.pdf", pdfBytes); err != nil {
+		return fmt.Errorf("send credit note email: %w", err)
+	}
+
+	s.appCtx.Logger.Info("credit note email sent", "email", email)
+	return nil
+}
@@ -2,13 +2,21 @@ package customer
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"gorm.io/gorm"
 )
 
+// ErrInsufficientCredit is returned when a credit adjustment would leave a
+// customer with a negative credit balance.
+var ErrInsufficientCredit = errors.New(apperrors.ErrInsufficientCredit)
+
 type repository struct {
 	db *gorm.DB
 }
@@ -20,15 +28,32 @@ func NewRepository(db *gorm.DB) interfaces.CustomerRepository {
 }
 
 func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.Customer, int64, error) {
-	return pagination.Paginate[model.Customer](r.db, opts)
+	return pagination.Paginate[model.Customer](ctx, r.db, opts)
 }
 
 func (r *repository) Create(ctx context.Context, customer *model.Customer) error {
 	return r.db.WithContext(ctx).Create(customer).Error
 }
 
+// Update saves every field of customer, false and empty ones included, but
+// its credit balance.
 func (r *repository) Update(ctx context.Context, customer *model.Customer) error {
-	return r.db.WithContext(ctx).Updates(customer).Error
+	return r.db.WithContext(ctx).Select("*").Omit("credit_balance", "created_at").Updates(customer).Error
+}
+
+// AdjustCredit adds delta to the credit balance of a customer. The balance
+// can't go below zero.
+func (r *repository) AdjustCredit(ctx context.Context, ID uint, delta money.Money) error {
+	res := r.db.WithContext(ctx).Model(&model.Customer{}).
+		Where("id = ? AND credit_balance + ? >= 0", ID, delta).
+		Update("credit_balance", gorm.Expr("credit_balance + ?", delta))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("customer %d: %w", ID, ErrInsufficientCredit)
+	}
+	return nil
 }
 
 func (r *repository) Delete(ctx context.Context, ID uint) error {
@@ -51,6 +76,15 @@ func (r *repository) FindByID(ctx context.Context, ID uint) (*model.Customer, er
 	return &customer, nil
 }
 
+func (r *repository) FindByIDs(ctx context.Context, IDs []uint) ([]model.Customer, error) {
+	var customers []model.Customer
+	err := r.db.WithContext(ctx).Where("id IN ?", IDs).Find(&customers).Error
+	if err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
 func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Customer, error) {
 	var result model.Customer
 
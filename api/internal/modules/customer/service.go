@@ -5,6 +5,7 @@ import (
 
 	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"gorm.io/gorm"
@@ -41,6 +42,15 @@ func (s *service) Update(ctx context.Context, customerId uint, dto *dto.UpdateCu
 	return existingCustomer, nil
 }
 
+// AdjustCredit adds delta to the credit balance of a customer. A negative
+// delta takes credit away.
+func (s *service) AdjustCredit(ctx context.Context, ID uint, delta money.Money) error {
+	if delta.IsZero() {
+		return nil
+	}
+	return s.repo.AdjustCredit(ctx, ID, delta)
+}
+
 func (s *service) Delete(ctx context.Context, ID uint) error {
 	return s.repo.Delete(ctx, ID)
 }
@@ -53,6 +63,15 @@ func (s *service) FindByID(ctx context.Context, ID uint, preloads []string) (*mo
 	return customer, nil
 }
 
+// FindByIDs returns the customers of the org with the given IDs. Unknown IDs
+// are left out.
+func (s *service) FindByIDs(ctx context.Context, IDs []uint) ([]model.Customer, error) {
+	if len(IDs) == 0 {
+		return nil, nil
+	}
+	return s.repo.FindByIDs(ctx, IDs)
+}
+
 func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Customer, error) {
 	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
 }
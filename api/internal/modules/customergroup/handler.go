@@ -0,0 +1,218 @@
+package customergroup
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"name": true}
+
+// For Swagger docs
+type APIResponseCustomerGroup struct {
+	Code    int                 `json:"code"`
+	Message string              `json:"message"`
+	Data    model.CustomerGroup `json:"data"`
+}
+
+type CustomerGroupHandler struct {
+	service interfaces.CustomerGroupService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.CustomerGroupService, appCtx *deps.AppContext) interfaces.CustomerGroupHandler {
+	return &CustomerGroupHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List customer groups with filtering and pagination
+// @Description  Returns a paginated list of the customer groups of the org
+// @Tags         customer-groups
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'name asc'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        name          query     string  false  "Filter by name"
+// @Success      200           {object}  APIResponseCustomerGroup
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /customer-groups [get]
+// @Security BearerAuth
+func (h *CustomerGroupHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterCustomerGroup, h.appCtx.Logger)
+		return
+	}
+
+	groups, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterCustomerGroup, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.CustomerGroup]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      groups,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create customer group
+// @Description Create a group of customers, e.g. wholesale buyers, to assign price lists to
+// @Tags customer-groups
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCustomerGroupDTO true "Customer group payload"
+// @Success 201 {object} APIResponseCustomerGroup
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /customer-groups [post]
+// @Security BearerAuth
+func (h *CustomerGroupHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreateCustomerGroupDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateCustomerGroup, h.appCtx.Logger)
+		return
+	}
+
+	group := req.ToModel(userFromContext.Org)
+	if err := h.service.Create(ctx, group); err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateCustomerGroup, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, group, h.appCtx.Logger)
+}
+
+// Update godoc
+// @Summary Update customer group
+// @Description Update a customer group by ID
+// @Tags customer-groups
+// @Accept json
+// @Produce json
+// @Param id path int true "Customer group ID"
+// @Param request body dto.UpdateCustomerGroupDTO true "Update customer group payload"
+// @Success 200 {object} APIResponseCustomerGroup
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /customer-groups/{id} [patch]
+// @Security BearerAuth
+func (h *CustomerGroupHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.UpdateCustomerGroupDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	group, err := h.service.Update(ctx, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrCustomerGroupNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateCustomerGroup, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, group, h.appCtx.Logger)
+}
+
+// Delete godoc
+// @Summary Delete customer group
+// @Description Delete a customer group by ID. Its customers are left without a group.
+// @Tags customer-groups
+// @Produce json
+// @Param id path int true "Customer group ID"
+// @Success 200 {integer} response.APIResponseInt
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /customer-groups/{id} [delete]
+// @Security BearerAuth
+func (h *CustomerGroupHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	if err := h.service.Delete(ctx, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrCustomerGroupNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrDeleteCustomerGroup, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, id, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get customer group
+// @Description Get a customer group by ID
+// @Tags customer-groups
+// @Produce json
+// @Param id path int true "Customer group ID"
+// @Success 200 {object} APIResponseCustomerGroup
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /customer-groups/{id} [get]
+// @Security BearerAuth
+func (h *CustomerGroupHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	group, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrCustomerGroupNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindCustomerGroup, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, group, h.appCtx.Logger)
+}
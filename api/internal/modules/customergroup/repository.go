@@ -0,0 +1,90 @@
+package customergroup
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.CustomerGroupRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.CustomerGroup, int64, error) {
+	return pagination.Paginate[model.CustomerGroup](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, group *model.CustomerGroup) error {
+	return r.db.WithContext(ctx).Create(group).Error
+}
+
+func (r *repository) Update(ctx context.Context, group *model.CustomerGroup) error {
+	return r.db.WithContext(ctx).Save(group).Error
+}
+
+// Delete removes a customer group. Its customers are left without a group.
+func (r *repository) Delete(ctx context.Context, ID uint) error {
+	res := r.db.WithContext(ctx).Delete(&model.CustomerGroup{}, ID)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *repository) FindByID(ctx context.Context, ID uint) (*model.CustomerGroup, error) {
+	var group model.CustomerGroup
+	err := r.db.WithContext(ctx).First(&group, ID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *repository) FindByIDs(ctx context.Context, IDs []uint) ([]model.CustomerGroup, error) {
+	var groups []model.CustomerGroup
+	err := r.db.WithContext(ctx).Where("id IN ?", IDs).Find(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.CustomerGroup, error) {
+	var result model.CustomerGroup
+
+	query := r.db.WithContext(ctx).Model(model.CustomerGroup{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.CustomerGroupRepository {
+	return &repository{db: tx}
+}
@@ -0,0 +1,62 @@
+package customergroup
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type service struct {
+	repo interfaces.CustomerGroupRepository
+}
+
+func NewService(repo interfaces.CustomerGroupRepository) interfaces.CustomerGroupService {
+	return &service{
+		repo: repo,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.CustomerGroup, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+func (s *service) Create(ctx context.Context, group *model.CustomerGroup) error {
+	return s.repo.Create(ctx, group)
+}
+
+func (s *service) Update(ctx context.Context, ID uint, dto *dto.UpdateCustomerGroupDTO) (*model.CustomerGroup, error) {
+	group, err := s.repo.FindByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	dto.ApplyModel(group)
+	if err := s.repo.Update(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (s *service) Delete(ctx context.Context, ID uint) error {
+	return s.repo.Delete(ctx, ID)
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.CustomerGroup, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+// FindByIDs returns the customer groups of the org with the given IDs. Unknown IDs
+// are left out.
+func (s *service) FindByIDs(ctx context.Context, IDs []uint) ([]model.CustomerGroup, error) {
+	if len(IDs) == 0 {
+		return nil, nil
+	}
+	return s.repo.FindByIDs(ctx, IDs)
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.CustomerGroupService {
+	return &service{repo: s.repo.WithTx(tx)}
+}
@@ -0,0 +1,182 @@
+package exchangerate
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"currency": true, "base_currency": true}
+
+// For Swagger docs
+type APIResponseExchangeRate struct {
+	Code    int                `json:"code"`
+	Message string             `json:"message"`
+	Data    model.ExchangeRate `json:"data"`
+}
+
+type ExchangeRateHandler struct {
+	service interfaces.ExchangeRateService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.ExchangeRateService, appCtx *deps.AppContext) interfaces.ExchangeRateHandler {
+	return &ExchangeRateHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List exchange rates with filtering and pagination
+// @Description  Returns a paginated list of the exchange rates of the org, latest first by default
+// @Tags         exchange-rates
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'effective_at desc'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        currency      query     string  false  "Filter by currency"
+// @Success      200           {object}  APIResponseExchangeRate
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /exchange-rates [get]
+// @Security BearerAuth
+func (h *ExchangeRateHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterExchangeRate, h.appCtx.Logger)
+		return
+	}
+	if r.URL.Query().Get("sort") == "" {
+		opts.SortBy = "effective_at desc, id desc"
+	}
+
+	rates, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterExchangeRate, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.ExchangeRate]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      rates,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create exchange rate
+// @Description Record what one unit of a currency is worth in the base currency of the org. Orders in that currency use the latest rate in effect when they are created.
+// @Tags exchange-rates
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateExchangeRateDTO true "Exchange rate payload"
+// @Success 201 {object} APIResponseExchangeRate
+// @Failure      400  {object}  apperrors.APIErrorResponse
+// @Failure      500  {object}  apperrors.APIErrorResponse
+// @Router /exchange-rates [post]
+// @Security BearerAuth
+func (h *ExchangeRateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreateExchangeRateDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateExchangeRate, h.appCtx.Logger)
+		return
+	}
+
+	rate := req.ToModel(userFromContext.Org)
+	if err := h.service.Create(ctx, rate); err != nil {
+		if errors.Is(err, errSameCurrency) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrSameCurrencyRate, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateExchangeRate, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, rate, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get exchange rate
+// @Description Get an exchange rate by ID
+// @Tags exchange-rates
+// @Produce json
+// @Param id path int true "Exchange rate ID"
+// @Success 200 {object} APIResponseExchangeRate
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /exchange-rates/{id} [get]
+// @Security BearerAuth
+func (h *ExchangeRateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	rate, err := h.service.FindOneWithFields(r.Context(), nil, map[string]any{"id": id}, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrExchangeRateNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindExchangeRate, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, rate, h.appCtx.Logger)
+}
+
+// Delete godoc
+// @Summary Delete exchange rate
+// @Description Delete an exchange rate by ID. Orders created with it keep the rate they recorded.
+// @Tags exchange-rates
+// @Produce json
+// @Param id path int true "Exchange rate ID"
+// @Success 200 {integer} response.APIResponseInt
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /exchange-rates/{id} [delete]
+// @Security BearerAuth
+func (h *ExchangeRateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrExchangeRateNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrDeleteExchangeRate, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, id, h.appCtx.Logger)
+}
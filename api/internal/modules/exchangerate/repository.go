@@ -0,0 +1,83 @@
+package exchangerate
+
+import (
+	"context"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.ExchangeRateRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.ExchangeRate, int64, error) {
+	return pagination.Paginate[model.ExchangeRate](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, rate *model.ExchangeRate) error {
+	return r.db.WithContext(ctx).Create(rate).Error
+}
+
+func (r *repository) Delete(ctx context.Context, ID uint) error {
+	res := r.db.WithContext(ctx).Delete(&model.ExchangeRate{}, ID)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.ExchangeRate, error) {
+	var result model.ExchangeRate
+
+	query := r.db.WithContext(ctx).Model(model.ExchangeRate{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FindEffective returns the latest rate of currency to base that took
+// effect at or before at.
+func (r *repository) FindEffective(ctx context.Context, base string, currency string, at time.Time) (*model.ExchangeRate, error) {
+	var result model.ExchangeRate
+	err := r.db.WithContext(ctx).
+		Where("base_currency = ? AND currency = ? AND effective_at <= ?", base, currency, at).
+		Order("effective_at desc, id desc").
+		First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.ExchangeRateRepository {
+	return &repository{db: tx}
+}
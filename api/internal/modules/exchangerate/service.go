@@ -0,0 +1,90 @@
+package exchangerate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+// ErrNoRate is returned when there is no exchange rate of a currency to the
+// base currency of the org.
+var ErrNoRate = errors.New(apperrors.ErrNoExchangeRate)
+
+// errSameCurrency is returned when creating a rate of the base currency to
+// itself.
+var errSameCurrency = errors.New(apperrors.ErrSameCurrencyRate)
+
+type service struct {
+	repo       interfaces.ExchangeRateRepository
+	orgService interfaces.OrgService
+}
+
+func NewService(repo interfaces.ExchangeRateRepository, orgService interfaces.OrgService) interfaces.ExchangeRateService {
+	return &service{
+		repo:       repo,
+		orgService: orgService,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.ExchangeRate, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+// Create records a rate of rate.Currency to the base currency of the org.
+// It takes effect now unless rate.EffectiveAt is set.
+func (s *service) Create(ctx context.Context, rate *model.ExchangeRate) error {
+	org, err := s.orgService.FindOrg(ctx, rate.OrgID)
+	if err != nil {
+		return err
+	}
+
+	rate.BaseCurrency = org.BaseCurrency
+	if rate.Currency == rate.BaseCurrency {
+		return errSameCurrency
+	}
+	if rate.EffectiveAt.IsZero() {
+		rate.EffectiveAt = time.Now()
+	}
+
+	return s.repo.Create(ctx, rate)
+}
+
+func (s *service) Delete(ctx context.Context, ID uint) error {
+	return s.repo.Delete(ctx, ID)
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.ExchangeRate, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+// Rate returns what one unit of currency was worth in base at time at. A
+// currency is worth exactly one unit of itself.
+func (s *service) Rate(ctx context.Context, base string, currency string, at time.Time) (float64, error) {
+	if currency == base {
+		return 1, nil
+	}
+
+	rate, err := s.repo.FindEffective(ctx, base, currency, at)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("%w: %s to %s", ErrNoRate, currency, base)
+		}
+		return 0, err
+	}
+
+	return rate.Rate, nil
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.ExchangeRateService {
+	return &service{
+		repo:       s.repo.WithTx(tx),
+		orgService: s.orgService.WithTx(tx),
+	}
+}
@@ -81,7 +81,7 @@ func (h *InvoiceHandler) Filter(w http.ResponseWriter, r *http.Request) {
 
 // Create godoc
 // @Summary Create invoices
-// @Description Create a new invoice
+// @Description Create a new invoice for an order, or with shipmentId for what a delivered shipment of the order delivered
 // @Tags invoices
 // @Accept json
 // @Produce json
@@ -90,6 +90,7 @@ func (h *InvoiceHandler) Filter(w http.ResponseWriter, r *http.Request) {
 // @Failure      400  {object}  apperrors.APIErrorResponse
 // @Failure      409  {object}  apperrors.APIErrorResponse
 // @Failure      500  {object}  apperrors.APIErrorResponse
+// @Failure      404  {object}  apperrors.APIErrorResponse
 // @Router /invoices [post]
 // @Security BearerAuth
 func (h *InvoiceHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -108,7 +109,18 @@ func (h *InvoiceHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	invoice, err := h.service.Create(ctx, userFromContext.Org, &req)
 	if err != nil {
-		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateInvoice, h.appCtx.Logger)
+		switch {
+		case errors.Is(err, errOrderNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrOrderNotFound, h.appCtx.Logger)
+		case errors.Is(err, gorm.ErrRecordNotFound) && req.ShipmentID != nil:
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrShipmentNotFound, h.appCtx.Logger)
+		case errors.Is(err, errShipmentNotDelivered):
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+		case errors.Is(err, errShipmentInvoiced):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, err.Error(), h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateInvoice, h.appCtx.Logger)
+		}
 		return
 	}
 
@@ -125,8 +137,10 @@ func (h *InvoiceHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Param request body dto.UpdateInvoiceDTO true "Update invoice payload"
 // @Success 200 {object} APIResponseInvoice
 // @Failure 400  {object}  apperrors.APIErrorResponse
+// @Failure 404  {object}  apperrors.APIErrorResponse
+// @Failure 409  {object}  apperrors.APIErrorResponse
 // @Failure 500  {object}  apperrors.APIErrorResponse
-// @Router /invoices/{id} [patch]
+// @Router /invoices/{id} [put]
 // @Security BearerAuth
 func (h *InvoiceHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -150,6 +164,11 @@ func (h *InvoiceHandler) Update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, errInvoiceLocked) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrInvoiceLocked, h.appCtx.Logger)
+			return
+		}
+
 		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateInvoice, h.appCtx.Logger)
 		return
 	}
@@ -166,6 +185,7 @@ func (h *InvoiceHandler) Update(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {integer} response.APIResponseInt
 // @Failure 404 {object} apperrors.APIErrorResponse
 // @Failure 400  {object}  apperrors.APIErrorResponse
+// @Failure 409  {object}  apperrors.APIErrorResponse
 // @Failure 500  {object}  apperrors.APIErrorResponse
 // @Router /invoices/{id} [delete]
 // @Security BearerAuth
@@ -183,6 +203,11 @@ func (h *InvoiceHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, errInvoiceLocked) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrInvoiceLocked, h.appCtx.Logger)
+			return
+		}
+
 		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrDeleteInvoice, h.appCtx.Logger)
 		return
 	}
@@ -210,7 +235,7 @@ func (h *InvoiceHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	invoice, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Items", "Order"})
+	invoice, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Items", "Charges", "Order"})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrInvoiceNotFound, h.appCtx.Logger)
@@ -224,41 +249,97 @@ func (h *InvoiceHandler) Get(w http.ResponseWriter, r *http.Request) {
 	response.WriteJSONSuccess(w, http.StatusOK, invoice, h.appCtx.Logger)
 }
 
-// Update godoc
+// SendProForma godoc
+// @Summary Send pro forma invoice
+// @Description Email a draft invoice to the customer as a pro forma for review
+// @Tags invoices
+// @Produce json
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} APIResponseInvoice
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /invoices/{id}/send-pro-forma [post]
+// @Security BearerAuth
+func (h *InvoiceHandler) SendProForma(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, model.InvoiceStatusProForma)
+}
+
+// Issue godoc
 // @Summary Issue an invoice
-// @Description Issue an invoice by ID
+// @Description Issue a draft or pro forma invoice and email it to the customer. The invoice can't be changed afterwards.
 // @Tags invoices
-// @Accept json
 // @Produce json
 // @Param id path int true "Invoice ID"
-// @Success 200 {object} response.APIResponseString
-// @Failure 400  {object}  apperrors.APIErrorResponse
-// @Failure 500  {object}  apperrors.APIErrorResponse
+// @Success 200 {object} APIResponseInvoice
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
 // @Router /invoices/{id}/issue [post]
 // @Security BearerAuth
 func (h *InvoiceHandler) Issue(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	idParam := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idParam)
+	h.transition(w, r, model.InvoiceStatusIssued)
+}
+
+// Cancel godoc
+// @Summary Cancel invoice
+// @Description Cancel a draft or pro forma invoice
+// @Tags invoices
+// @Produce json
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} APIResponseInvoice
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /invoices/{id}/cancel [post]
+// @Security BearerAuth
+func (h *InvoiceHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, model.InvoiceStatusCancelled)
+}
+
+// Void godoc
+// @Summary Void invoice
+// @Description Void an issued invoice that nothing was paid on. The invoice is kept for the record.
+// @Tags invoices
+// @Produce json
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} APIResponseInvoice
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /invoices/{id}/void [post]
+// @Security BearerAuth
+func (h *InvoiceHandler) Void(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, model.InvoiceStatusVoid)
+}
+
+func (h *InvoiceHandler) transition(w http.ResponseWriter, r *http.Request, to model.InvoiceStatus) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
 		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
 		return
 	}
 
-	err = h.service.Issue(ctx, uint(id))
+	invoice, err := h.service.Transition(r.Context(), uint(id), to)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrInvoiceNotFound, h.appCtx.Logger)
 			return
 		}
 
-		if errors.Is(err, errors.New(apperrors.ErrInvalidInvoiceStatus)) {
-			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidInvoiceStatus, h.appCtx.Logger)
+		var transitionErr *TransitionError
+		if errors.As(err, &transitionErr) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, transitionErr.Error(), h.appCtx.Logger)
+			return
 		}
 
-		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrIssueInvoice, h.appCtx.Logger)
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrTransitionInvoice, h.appCtx.Logger)
 		return
 	}
 
-	response.WriteJSONSuccess(w, http.StatusOK, "Invoice issued and emailed", h.appCtx.Logger)
+	response.WriteJSONSuccess(w, http.StatusOK, invoice, h.appCtx.Logger)
 }
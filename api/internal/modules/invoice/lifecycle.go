@@ -0,0 +1,117 @@
+package invoice
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+)
+
+// transitions is the invoice lifecycle. Drafts can be sent to the customer as
+// a pro forma for review, issued or cancelled. Once issued the invoice is a
+// legal document: it only moves through the payment statuses or is voided,
+// and corrections go through credit notes. Void is reserved for issued
+// invoices nothing was paid on. Cancelled and void invoices are final, paid
+// invoices only reopen when one of their payments is corrected.
+var transitions = map[model.InvoiceStatus][]model.InvoiceStatus{
+	model.InvoiceStatusDraft: {
+		model.InvoiceStatusProForma,
+		model.InvoiceStatusIssued,
+		model.InvoiceStatusCancelled,
+	},
+	model.InvoiceStatusProForma: {
+		model.InvoiceStatusDraft,
+		model.InvoiceStatusIssued,
+		model.InvoiceStatusCancelled,
+	},
+	model.InvoiceStatusIssued: {
+		model.InvoiceStatusPartiallyPaid,
+		model.InvoiceStatusPaid,
+		model.InvoiceStatusOverdue,
+		model.InvoiceStatusVoid,
+	},
+	model.InvoiceStatusPartiallyPaid: {
+		model.InvoiceStatusIssued,
+		model.InvoiceStatusPaid,
+		model.InvoiceStatusOverdue,
+	},
+	model.InvoiceStatusOverdue: {
+		model.InvoiceStatusPartiallyPaid,
+		model.InvoiceStatusPaid,
+		model.InvoiceStatusVoid,
+	},
+	model.InvoiceStatusPaid: {
+		model.InvoiceStatusIssued,
+		model.InvoiceStatusPartiallyPaid,
+	},
+}
+
+// errInvoiceLocked is returned when an issued invoice would be changed or
+// deleted.
+var errInvoiceLocked = errors.New(apperrors.ErrInvoiceLocked)
+
+// ErrNotPayable is returned when a payment is recorded against an invoice
+// that isn't issued.
+var ErrNotPayable = errors.New(apperrors.ErrInvoiceNotPayable)
+
+// canTransition reports whether an invoice may move from one status to another.
+func canTransition(from, to model.InvoiceStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// editableStatuses are the statuses in which the notes, due date and items
+// of an invoice can still be changed. Invoices are immutable once issued.
+var editableStatuses = []model.InvoiceStatus{model.InvoiceStatusDraft, model.InvoiceStatusProForma}
+
+// isEditable reports whether an invoice in status can still be changed.
+func isEditable(status model.InvoiceStatus) bool {
+	return slices.Contains(editableStatuses, status)
+}
+
+// IsIssued reports whether an invoice was issued and not voided, so payments
+// and credit notes can be recorded against it.
+func IsIssued(status model.InvoiceStatus) bool {
+	switch status {
+	case model.InvoiceStatusIssued,
+		model.InvoiceStatusPartiallyPaid,
+		model.InvoiceStatusOverdue,
+		model.InvoiceStatusPaid:
+		return true
+	default:
+		return false
+	}
+}
+
+// paymentStatus is the status of an issued invoice given what was paid and
+// credited on it. Nothing due means paid, and an overdue invoice stays
+// overdue until something is paid.
+func paymentStatus(invoice *model.Invoice) model.InvoiceStatus {
+	switch {
+	case !invoice.AmountDue.IsPositive():
+		return model.InvoiceStatusPaid
+	case invoice.AmountPaid.IsPositive():
+		return model.InvoiceStatusPartiallyPaid
+	case invoice.Status == model.InvoiceStatusOverdue:
+		return model.InvoiceStatusOverdue
+	default:
+		return model.InvoiceStatusIssued
+	}
+}
+
+// TransitionError is returned when a status change is not allowed by the
+// invoice lifecycle.
+type TransitionError struct {
+	From model.InvoiceStatus
+	To   model.InvoiceStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: cannot move invoice from %s to %s", apperrors.ErrInvalidInvoiceStatus, e.From, e.To)
+}
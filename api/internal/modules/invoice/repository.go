@@ -2,11 +2,13 @@ package invoice
 
 import (
 	"context"
+	"time"
 
 	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repository struct {
@@ -20,15 +22,49 @@ func NewRepository(db *gorm.DB) interfaces.InvoiceRepository {
 }
 
 func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.Invoice, int64, error) {
-	return pagination.Paginate[model.Invoice](r.db, opts)
+	return pagination.Paginate[model.Invoice](ctx, r.db, opts)
 }
 
 func (r *repository) Create(ctx context.Context, invoice *model.Invoice) error {
 	return r.db.WithContext(ctx).Create(invoice).Error
 }
 
-func (r *repository) Update(ctx context.Context, invoice *model.Invoice) error {
-	return r.db.WithContext(ctx).Updates(invoice).Error
+// Update saves the notes and due date of invoice, but only while the stored
+// invoice is still editable. It reports false when the invoice was issued or
+// cancelled first. The status and balance only change through UpdateStatus.
+func (r *repository) Update(ctx context.Context, invoice *model.Invoice) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.Invoice{}).
+		Where("id = ? AND status IN ?", invoice.ID, editableStatuses).
+		Select("notes", "due_date").
+		Updates(map[string]any{
+			"notes":    invoice.Notes,
+			"due_date": invoice.DueDate,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// UpdateStatus saves the status, issue date and balance of invoice, but
+// only while the stored status is still from. It reports false when another
+// request changed the status first.
+func (r *repository) UpdateStatus(ctx context.Context, invoice *model.Invoice, from model.InvoiceStatus) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.Invoice{}).
+		Where("id = ? AND status = ?", invoice.ID, from).
+		Updates(map[string]any{
+			"status":          invoice.Status,
+			"issued_at":       invoice.IssuedAt,
+			"amount_paid":     invoice.AmountPaid,
+			"amount_credited": invoice.AmountCredited,
+			"amount_due":      invoice.AmountDue,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
 }
 
 func (r *repository) Delete(ctx context.Context, ID uint) error {
@@ -51,6 +87,53 @@ func (r *repository) FindByID(ctx context.Context, ID uint) (*model.Invoice, err
 	return &invoice, nil
 }
 
+// Invoiced reports whether an invoice matching where exists that is not
+// cancelled or void.
+func (r *repository) Invoiced(ctx context.Context, where map[string]any) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Invoice{}).
+		Where(where).
+		Where("status NOT IN ?", []model.InvoiceStatus{model.InvoiceStatusCancelled, model.InvoiceStatusVoid}).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// FindForUpdate loads an invoice with preloads and locks it until the
+// transaction ends. It must run inside a transaction.
+func (r *repository) FindForUpdate(ctx context.Context, ID uint, preloads []string) (*model.Invoice, error) {
+	var result model.Invoice
+
+	query := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"})
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	if err := query.First(&result, ID).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FindPastDue returns the invoices that are still owed in full or in part
+// and were due before now.
+func (r *repository) FindPastDue(ctx context.Context, now time.Time) ([]model.Invoice, error) {
+	var invoices []model.Invoice
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", []model.InvoiceStatus{model.InvoiceStatusIssued, model.InvoiceStatusPartiallyPaid}).
+		Where("due_date < ?", now).
+		Find(&invoices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
 func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Invoice, error) {
 	var result model.Invoice
 
@@ -4,27 +4,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
 	"github.com/deveasyclick/openb2b/internal/shared/deps"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/tenant"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
 	"github.com/deveasyclick/openb2b/internal/utils/pdfutil"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"gorm.io/gorm"
 )
 
+var (
+	errOrderNotFound        = errors.New(apperrors.ErrOrderNotFound)
+	errShipmentNotDelivered = errors.New(apperrors.ErrShipmentNotDelivered)
+	errShipmentInvoiced     = errors.New(apperrors.ErrShipmentAlreadyInvoiced)
+)
+
 type service struct {
 	repo   interfaces.InvoiceRepository
 	os     interfaces.OrderService
+	ss     interfaces.ShipmentService
+	events interfaces.Outbox
 	appCtx *deps.AppContext
 }
 
-func NewService(repo interfaces.InvoiceRepository, os interfaces.OrderService, appCtx *deps.AppContext) interfaces.InvoiceService {
+func NewService(repo interfaces.InvoiceRepository, os interfaces.OrderService, ss interfaces.ShipmentService, appCtx *deps.AppContext) interfaces.InvoiceService {
 	return &service{
 		repo:   repo,
 		os:     os,
+		ss:     ss,
+		events: appCtx.Events,
 		appCtx: appCtx,
 	}
 }
@@ -33,13 +47,44 @@ func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.
 	return s.repo.Filter(ctx, opts)
 }
 
+// Create invoices an order, or with dto.ShipmentID only what a delivered
+// shipment of it delivered. The charges of the order go on its first
+// invoice.
 func (s *service) Create(ctx context.Context, orgID uint, dto *dto.CreateInvoiceDTO) (*model.Invoice, error) {
-	order, err := s.os.FindOneWithFields(ctx, nil, map[string]any{"id": dto.OrderID}, []string{"Items", "Customer"})
+	order, err := s.os.FindOneWithFields(ctx, nil, map[string]any{"id": dto.OrderID}, []string{"Items", "Charges", "Customer"})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errOrderNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	invoice := dto.ToModel(orgID, order)
+	if dto.ShipmentID != nil {
+		shipment, err := s.ss.FindOneWithFields(ctx, nil, map[string]any{"id": *dto.ShipmentID, "order_id": order.ID}, []string{"Lines"})
+		if err != nil {
+			return nil, err
+		}
+		if shipment.Status != model.ShipmentDelivered {
+			return nil, errShipmentNotDelivered
+		}
+
+		invoiced, err := s.repo.Invoiced(ctx, map[string]any{"shipment_id": shipment.ID})
+		if err != nil {
+			return nil, err
+		}
+		if invoiced {
+			return nil, errShipmentInvoiced
+		}
+
+		orderInvoiced, err := s.repo.Invoiced(ctx, map[string]any{"order_id": order.ID})
+		if err != nil {
+			return nil, err
+		}
+
+		dto.ApplyShipment(invoice, order, shipment, !orderInvoiced)
+	}
+
 	err = s.repo.Create(ctx, invoice)
 	if err != nil {
 		return nil, err
@@ -47,20 +92,40 @@ func (s *service) Create(ctx context.Context, orgID uint, dto *dto.CreateInvoice
 	return invoice, nil
 }
 
+// Update applies dto to a draft or pro forma invoice. Issued invoices are
+// immutable and return ErrInvoiceLocked.
 func (s *service) Update(ctx context.Context, invoiceId uint, dto *dto.UpdateInvoiceDTO) (*model.Invoice, error) {
 	existingInvoice, err := s.repo.FindByID(ctx, invoiceId)
 	if err != nil {
 		return nil, err
 	}
+	if !isEditable(existingInvoice.Status) {
+		return nil, errInvoiceLocked
+	}
+
 	dto.ApplyModel(existingInvoice)
-	err = s.repo.Update(ctx, existingInvoice)
+	updated, err := s.repo.Update(ctx, existingInvoice)
 	if err != nil {
 		return nil, err
 	}
+	// Issued or cancelled since it was loaded
+	if !updated {
+		return nil, errInvoiceLocked
+	}
 	return existingInvoice, nil
 }
 
+// Delete removes a draft or pro forma invoice. Issued invoices are kept and
+// have to be voided instead.
 func (s *service) Delete(ctx context.Context, ID uint) error {
+	invoice, err := s.repo.FindOneWithFields(ctx, []string{"id", "status"}, map[string]any{"id": ID}, nil)
+	if err != nil {
+		return err
+	}
+	if !isEditable(invoice.Status) {
+		return errInvoiceLocked
+	}
+
 	return s.repo.Delete(ctx, ID)
 }
 
@@ -76,46 +141,155 @@ func (s *service) FindOneWithFields(ctx context.Context, fields []string, where
 	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
 }
 
+// FindForUpdate loads an invoice and locks it until the transaction ends, so
+// changes to its balance run one at a time. It must run inside a transaction.
+func (s *service) FindForUpdate(ctx context.Context, ID uint, preloads []string) (*model.Invoice, error) {
+	return s.repo.FindForUpdate(ctx, ID, preloads)
+}
+
 func (s *service) WithTx(tx *gorm.DB) interfaces.InvoiceService {
-	return &service{repo: s.repo.WithTx(tx)}
+	return &service{
+		repo:   s.repo.WithTx(tx),
+		os:     s.os,
+		ss:     s.ss,
+		events: s.events.WithTx(tx),
+		appCtx: s.appCtx,
+	}
 }
 
-func (s *service) Issue(ctx context.Context, id uint) error {
-	invoice, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Items", "Order"})
+// Transition moves an invoice to status to when the invoice lifecycle allows
+// it. Issuing stamps the issue date. Sending a pro forma and issuing record
+// an event, on which the invoice is emailed to the customer.
+func (s *service) Transition(ctx context.Context, id uint, to model.InvoiceStatus) (*model.Invoice, error) {
+	var invoice *model.Invoice
+	err := s.appCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx).(*service)
+
+		var err error
+		invoice, err = txService.repo.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Items", "Charges", "Order"})
+		if err != nil {
+			return err
+		}
+
+		from := invoice.Status
+		if !canTransition(from, to) {
+			return &TransitionError{From: from, To: to}
+		}
+
+		invoice.Status = to
+		if to == model.InvoiceStatusIssued {
+			invoice.IssuedAt = time.Now()
+		}
+
+		// Guard against a concurrent transition of the same invoice
+		updated, err := txService.repo.UpdateStatus(ctx, invoice, from)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return &TransitionError{From: from, To: to}
+		}
+
+		eventType := ""
+		switch to {
+		case model.InvoiceStatusProForma:
+			eventType = types.InvoiceProFormaSentEventType
+		case model.InvoiceStatusIssued:
+			eventType = types.InvoiceIssuedEventType
+		default:
+			return nil
+		}
+
+		return txService.events.Record(ctx, eventType, invoice.ID, types.InvoiceEvent{
+			InvoiceID:     invoice.ID,
+			InvoiceNumber: invoice.InvoiceNumber,
+			OrderID:       invoice.OrderID,
+			Status:        invoice.Status,
+			Currency:      invoice.Currency,
+			Total:         invoice.Total,
+		})
+	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// ApplyPayments records that paid was received against invoice in total and
+// moves it to issued, partially_paid or paid to match. Anything paid over
+// what is owed is the caller's to credit.
+func (s *service) ApplyPayments(ctx context.Context, invoice *model.Invoice, paid money.Money) error {
+	invoice.AmountPaid = paid
+	return s.settle(ctx, invoice)
+}
+
+// ApplyCredit records that credited was credited on invoice in total by its
+// credit notes. The payments of the invoice have to be applied again
+// afterwards, since less is owed.
+func (s *service) ApplyCredit(ctx context.Context, invoice *model.Invoice, credited money.Money) error {
+	invoice.AmountCredited = money.Min(credited, invoice.Total)
+	return s.settle(ctx, invoice)
+}
+
+// settle recomputes the amount due of an issued invoice and moves it to the
+// status matching its balance.
+func (s *service) settle(ctx context.Context, invoice *model.Invoice) error {
+	if !IsIssued(invoice.Status) {
+		return ErrNotPayable
 	}
 
-	// If not draft, it's an invalid state for issuing
-	if invoice.Status != model.InvoiceStatusDraft {
-		return fmt.Errorf("%w: invoice %d is %s", errors.New(apperrors.ErrInvalidInvoiceStatus), id, invoice.Status)
+	from := invoice.Status
+	owed := invoice.Total.Sub(invoice.AmountCredited)
+	invoice.AmountPaid = money.Min(invoice.AmountPaid, owed)
+	invoice.AmountDue = owed.Sub(invoice.AmountPaid)
+	invoice.Status = paymentStatus(invoice)
+	if invoice.Status != from && !canTransition(from, invoice.Status) {
+		return &TransitionError{From: from, To: invoice.Status}
 	}
 
-	invoice.Status = model.InvoiceStatusIssued
-	err = s.repo.Update(ctx, invoice)
+	updated, err := s.repo.UpdateStatus(ctx, invoice, from)
 	if err != nil {
 		return err
 	}
-
-	//TODO: Move email sending to queue
-	invCopy := *invoice
-	go s.sendInvoiceEmail(&invCopy, s.appCtx.Logger)
+	if !updated {
+		return &TransitionError{From: from, To: invoice.Status}
+	}
 
 	return nil
 }
 
-func (s *service) sendInvoiceEmail(invoice *model.Invoice, logger interfaces.Logger) {
-	defer func() {
-		if r := recover(); r != nil {
-			s.appCtx.Logger.Error("panic in sendInvoiceEmail", "err", r)
+// MarkOverdue moves the issued and partially paid invoices of every org that
+// are past their due date to overdue. An invoice paid meanwhile is left as
+// it is.
+func (s *service) MarkOverdue(ctx context.Context, job types.MarkOverdueInvoicesJob) error {
+	invoices, err := s.repo.FindPastDue(tenant.Bypass(ctx), time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		from := invoice.Status
+		invoice.Status = model.InvoiceStatusOverdue
+		if _, err := s.repo.UpdateStatus(tenant.WithOrg(ctx, invoice.OrgID), &invoice, from); err != nil {
+			return fmt.Errorf("mark invoice %d overdue: %w", invoice.ID, err)
 		}
-	}()
+	}
+
+	return nil
+}
+
+// SendEmail emails an invoice to its customer, as a pro forma for review
+// with proForma.
+func (s *service) SendEmail(ctx context.Context, ID uint, proForma bool) error {
+	invoice, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, []string{"Items", "Charges", "Order"})
+	if err != nil {
+		return err
+	}
 
-	proForma := invoice.Status == model.InvoiceStatusProForma
 	pdfBytes, err := pdfutil.GenerateInvoicePDF(invoice, proForma)
 	if err != nil {
-		logger.Error("failed to generate invoice PDF", "err", err)
-		return
+		return fmt.Errorf("generate invoice PDF: %w", err)
 	}
 
 	subject := "Your Invoice"
@@ -124,9 +298,9 @@ func (s *service) sendInvoiceEmail(invoice *model.Invoice, logger interfaces.Log
 	}
 
 	if err := s.appCtx.Mailer.SendWithAttachment(invoice.CustomerEmail, subject, "Please find attached.", "invoice.pdf", pdfBytes); err != nil {
-		logger.Error("failed to send invoice email", "err", err)
-		return
+		return fmt.Errorf("send invoice email: %w", err)
 	}
 
 	s.appCtx.Logger.Info("invoice email sent", "email", invoice.CustomerEmail)
+	return nil
 }
@@ -7,6 +7,9 @@ import (
 	"strconv"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/modules/exchangerate"
+	"github.com/deveasyclick/openb2b/internal/modules/promotion"
+	"github.com/deveasyclick/openb2b/internal/modules/warehouse"
 	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
 	"github.com/deveasyclick/openb2b/internal/shared/deps"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
@@ -78,13 +81,13 @@ func (h *OrderHandler) Filter(w http.ResponseWriter, r *http.Request) {
 
 // Create godoc
 // @Summary Create orders
-// @Description Create a new order
+// @Description Create a new order. Stock is reserved for its items at its fulfilment location, the default warehouse unless warehouseId is given; items of variants that allow backorders take what is available and backorder the rest.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param request body dto.CreateOrderDTO true "Order payload"
 // @Success 200 {object} APIResponseOrder
-// @Failure      400  {object}  apperrors.APIErrorResponse
+// @Failure      400  {object}  apperrors.APIErrorResponse "Unknown customer, warehouse or charge tax class, no exchange rate for the currency or a promotion code that does not apply"
 // @Failure      409  {object}  apperrors.APIErrorResponse
 // @Failure      500  {object}  apperrors.APIErrorResponse
 // @Router /orders [post]
@@ -105,6 +108,32 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	order, err := h.service.Create(ctx, req, userFromContext.Org)
 	if err != nil {
+		var stockErr *apperrors.InsufficientStockError
+		if errors.As(err, &stockErr) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, stockErr.Error(), h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, exchangerate.ErrNoRate) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+			return
+		}
+		if status := promotionErrorStatus(err); status != 0 {
+			response.WriteJSONErrorV2(w, status, nil, err.Error(), h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, errUnknownTaxClass) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, warehouse.ErrUnknownWarehouse) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrCustomerNotFound, h.appCtx.Logger)
+			return
+		}
+
 		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateOrder, h.appCtx.Logger)
 		return
 	}
@@ -122,6 +151,7 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Param request body dto.UpdateOrderDTO true "Update order payload"
 // @Success 200 {object} APIResponseOrder
 // @Failure 400  {object}  apperrors.APIErrorResponse
+// @Failure 409  {object}  apperrors.APIErrorResponse
 // @Failure 500  {object}  apperrors.APIErrorResponse
 // @Router /orders/{id} [patch]
 // @Security BearerAuth
@@ -140,7 +170,7 @@ func (h *OrderHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get existing order
-	existingOrder, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Items"})
+	existingOrder, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Items", "Charges"})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrOrderNotFound, h.appCtx.Logger)
@@ -151,14 +181,37 @@ func (h *OrderHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Don't update order if it is not in pending status
-	if existingOrder.Status != model.OrderStatusPending {
+	// Items are locked once the order leaves pending
+	if !isEditable(existingOrder.Status) {
 		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, fmt.Sprintf("cannot update %s: %s", apperrors.ErrOrderNotPending, existingOrder.Status), h.appCtx.Logger)
 
 		return
 	}
 
 	if err := h.service.Update(ctx, existingOrder, req); err != nil {
+		var stockErr *apperrors.InsufficientStockError
+		if errors.As(err, &stockErr) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, stockErr.Error(), h.appCtx.Logger)
+			return
+		}
+		if status := promotionErrorStatus(err); status != 0 {
+			response.WriteJSONErrorV2(w, status, nil, err.Error(), h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, errUnknownTaxClass) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, warehouse.ErrUnknownWarehouse) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+			return
+		}
+		// Approved or cancelled since it was loaded
+		if errors.Is(err, errNotPending) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+			return
+		}
+
 		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateOrder, h.appCtx.Logger)
 		return
 	}
@@ -219,7 +272,7 @@ func (h *OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	order, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Items", "Customer"})
+	order, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Items", "Charges", "Customer", "StatusHistory", "Shipments.Lines"})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrOrderNotFound, h.appCtx.Logger)
@@ -232,3 +285,102 @@ func (h *OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	response.WriteJSONSuccess(w, http.StatusOK, order, h.appCtx.Logger)
 }
+
+// Approve godoc
+// @Summary Approve order
+// @Description Approve a pending order. Reserved stock is deducted and the order items are locked.
+// @Tags orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} APIResponseOrder
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /orders/{id}/approve [post]
+// @Security BearerAuth
+func (h *OrderHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, model.OrderStatusApproved)
+}
+
+// Deliver godoc
+// @Summary Deliver order
+// @Description Mark an approved order as delivered
+// @Tags orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} APIResponseOrder
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /orders/{id}/deliver [post]
+// @Security BearerAuth
+func (h *OrderHandler) Deliver(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, model.OrderStatusDelivered)
+}
+
+// Cancel godoc
+// @Summary Cancel order
+// @Description Cancel a pending or approved order. Reserved stock is released, deducted stock that was not shipped is returned and pending shipments are cancelled.
+// @Tags orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} APIResponseOrder
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /orders/{id}/cancel [post]
+// @Security BearerAuth
+func (h *OrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, model.OrderStatusCancelled)
+}
+
+func (h *OrderHandler) transition(w http.ResponseWriter, r *http.Request, to model.OrderStatus) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	order, err := h.service.Transition(r.Context(), uint(id), to)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrOrderNotFound, h.appCtx.Logger)
+			return
+		}
+
+		var transitionErr *TransitionError
+		if errors.As(err, &transitionErr) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, transitionErr.Error(), h.appCtx.Logger)
+			return
+		}
+
+		var stockErr *apperrors.InsufficientStockError
+		if errors.As(err, &stockErr) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, stockErr.Error(), h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrTransitionOrder, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, order, h.appCtx.Logger)
+}
+
+// promotionErrorStatus returns the status of a promotion code the order
+// can't get, or 0 when err is not about the promotion.
+func promotionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, promotion.ErrUsedUp):
+		return http.StatusConflict
+	case errors.Is(err, promotion.ErrUnknown),
+		errors.Is(err, promotion.ErrInactive),
+		errors.Is(err, promotion.ErrNotApplicable),
+		errors.Is(err, promotion.ErrBelowMinOrderValue):
+		return http.StatusBadRequest
+	}
+	return 0
+}
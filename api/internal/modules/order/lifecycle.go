@@ -0,0 +1,66 @@
+package order
+
+import (
+	"fmt"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+)
+
+// transitions is the order lifecycle. An order starts pending, is approved
+// or cancelled, and an approved order is delivered or cancelled. Delivered
+// and cancelled orders are final.
+//
+//	pending ──► approved ──► delivered
+//	   │            │
+//	   └──────► cancelled ◄──┘
+var transitions = map[model.OrderStatus][]model.OrderStatus{
+	model.OrderStatusPending:  {model.OrderStatusApproved, model.OrderStatusCancelled},
+	model.OrderStatusApproved: {model.OrderStatusDelivered, model.OrderStatusCancelled},
+}
+
+// canTransition reports whether an order may move from one status to another.
+func canTransition(from, to model.OrderStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isEditable reports whether the items, discount and delivery of an order
+// can still be changed. Items are locked once the order is approved.
+func isEditable(status model.OrderStatus) bool {
+	return status == model.OrderStatusPending
+}
+
+// deliveryStatusOf derives the delivery status of an order from what its
+// shipments shipped and delivered of items.
+func deliveryStatusOf(items []model.OrderItem) model.DeliveryStatus {
+	delivered, shipped := len(items) > 0, false
+	for _, item := range items {
+		delivered = delivered && item.DeliveredQuantity >= item.Quantity
+		shipped = shipped || item.ShippedQuantity > 0
+	}
+
+	switch {
+	case delivered:
+		return model.DeliveryDelivered
+	case shipped:
+		return model.DeliveryShipped
+	default:
+		return model.DeliveryPending
+	}
+}
+
+// TransitionError is returned when a status change is not allowed by the
+// order lifecycle.
+type TransitionError struct {
+	From model.OrderStatus
+	To   model.OrderStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: cannot move order from %s to %s", apperrors.ErrInvalidOrderTransition, e.From, e.To)
+}
@@ -5,8 +5,10 @@ import (
 
 	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repository struct {
@@ -20,15 +22,34 @@ func NewRepository(database *gorm.DB) interfaces.OrderRepository {
 }
 
 func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.Order, int64, error) {
-	return pagination.Paginate[model.Order](r.db, opts)
+	return pagination.Paginate[model.Order](ctx, r.db, opts)
 }
 
 func (r *repository) Create(ctx context.Context, model *model.Order) error {
 	return r.db.WithContext(ctx).Create(model).Error
 }
 
+// Update saves order with its items, whose amounts are recalculated with the
+// order's. The status is left alone, it only changes through UpdateStatus.
 func (r *repository) Update(ctx context.Context, model *model.Order) error {
-	return r.db.WithContext(ctx).Save(model).Error
+	return r.db.WithContext(ctx).Session(&gorm.Session{FullSaveAssociations: true}).Omit("status").Save(model).Error
+}
+
+// FindForUpdate loads an order with preloads and locks it until the
+// transaction ends. It must run inside a transaction.
+func (r *repository) FindForUpdate(ctx context.Context, ID uint, preloads []string) (*model.Order, error) {
+	var result model.Order
+
+	query := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"})
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	if err := query.First(&result, ID).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
 }
 
 func (r *repository) Delete(ctx context.Context, ID uint) error {
@@ -42,6 +63,107 @@ func (r *repository) Delete(ctx context.Context, ID uint) error {
 	return nil
 }
 
+// DeleteItems permanently removes the items of an order so they can be
+// replaced without hitting the order/variant unique index.
+func (r *repository) DeleteItems(ctx context.Context, orderID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Where("order_id = ?", orderID).Delete(&model.OrderItem{}).Error
+}
+
+// DeleteCharges permanently removes the charges of an order so they can be
+// replaced.
+func (r *repository) DeleteCharges(ctx context.Context, orderID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Where("order_id = ?", orderID).Delete(&model.OrderCharge{}).Error
+}
+
+// AddShippedQuantities adds to the quantities of an order item shipped and
+// delivered.
+func (r *repository) AddShippedQuantities(ctx context.Context, itemID uint, shipped int, delivered int) error {
+	return r.db.WithContext(ctx).Model(&model.OrderItem{}).Where("id = ?", itemID).UpdateColumns(map[string]any{
+		"shipped_quantity":   gorm.Expr("shipped_quantity + ?", shipped),
+		"delivered_quantity": gorm.Expr("delivered_quantity + ?", delivered),
+	}).Error
+}
+
+// AddRestockedQuantity adds to the quantity of an order item put back on
+// hand by credit notes.
+func (r *repository) AddRestockedQuantity(ctx context.Context, itemID uint, restocked int) error {
+	return r.db.WithContext(ctx).Model(&model.OrderItem{}).Where("id = ?", itemID).
+		UpdateColumn("restocked_quantity", gorm.Expr("restocked_quantity + ?", restocked)).Error
+}
+
+// FindBackorders returns the backordered items of pending and approved
+// orders matching where, oldest order first. Keys of where are qualified
+// columns, e.g. order_items.variant_id.
+func (r *repository) FindBackorders(ctx context.Context, where map[string]any) ([]types.Backorder, error) {
+	query := r.db.WithContext(ctx).Model(&model.OrderItem{})
+	// A map would be scoped to order_items, so each column is its own condition
+	for column, value := range where {
+		query = query.Where(column+" = ?", value)
+	}
+
+	var backorders []types.Backorder
+	err := query.
+		Select(`orders.id AS order_id, orders.order_number, orders.status AS order_status, orders.created_at AS ordered_at,
+			orders.customer_id, customers.first_name AS customer_first_name, customers.last_name AS customer_last_name,
+			order_items.id AS order_item_id, order_items.variant_id, order_items.sku, order_items.quantity,
+			order_items.allocated_quantity, order_items.backordered_quantity, orders.warehouse_id`).
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Joins("LEFT JOIN customers ON customers.id = orders.customer_id").
+		Where("order_items.backordered_quantity > 0 AND orders.status IN ?", []model.OrderStatus{model.OrderStatusPending, model.OrderStatusApproved}).
+		Order("orders.created_at, orders.id").
+		Scan(&backorders).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return backorders, nil
+}
+
+// AllocateItem moves qty of an order item from backordered to allocated. It
+// reports false when less than qty is backordered anymore.
+func (r *repository) AllocateItem(ctx context.Context, itemID uint, qty int) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.OrderItem{}).
+		Where("id = ? AND backordered_quantity >= ?", itemID, qty).
+		UpdateColumns(map[string]any{
+			"allocated_quantity":   gorm.Expr("allocated_quantity + ?", qty),
+			"backordered_quantity": gorm.Expr("backordered_quantity - ?", qty),
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// CancelShipments cancels the shipments of an order that have not shipped.
+func (r *repository) CancelShipments(ctx context.Context, orderID uint) error {
+	return r.db.WithContext(ctx).Model(&model.Shipment{}).
+		Where("order_id = ? AND status = ?", orderID, model.ShipmentPending).
+		Update("status", model.ShipmentCancelled).Error
+}
+
+// UpdateStatus saves the status and delivery status of order, but only while
+// the stored status is still from. It reports false when another request
+// changed the status first.
+func (r *repository) UpdateStatus(ctx context.Context, order *model.Order, from model.OrderStatus) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("id = ? AND status = ?", order.ID, from).
+		Updates(map[string]any{
+			"status":          order.Status,
+			"delivery_status": order.Delivery.Status,
+			"delivery_at":     order.Delivery.At,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *repository) CreateStatusHistory(ctx context.Context, history *model.OrderStatusHistory) error {
+	return r.db.WithContext(ctx).Create(history).Error
+}
+
 func (r *repository) FindByID(ctx context.Context, ID uint) (*model.Order, error) {
 	var m model.Order
 	err := r.db.WithContext(ctx).First(&m, ID).Error
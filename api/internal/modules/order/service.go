@@ -2,25 +2,68 @@ package order
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/internal/utils/pricing"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"gorm.io/gorm"
 )
 
+// errUnknownTaxClass is returned when a charge is given a tax class the org
+// does not have.
+var errUnknownTaxClass = errors.New(apperrors.ErrUnknownChargeTaxClass)
+
+// errNotPending is returned when an order that left pending would be changed.
+var errNotPending = errors.New(apperrors.ErrOrderNotPending)
+
 type service struct {
-	repo           interfaces.OrderRepository
-	productService interfaces.ProductService
+	repo                interfaces.OrderRepository
+	productService      interfaces.ProductService
+	customerService     interfaces.CustomerService
+	orgService          interfaces.OrgService
+	exchangeRateService interfaces.ExchangeRateService
+	priceListService    interfaces.PriceListService
+	promotionService    interfaces.PromotionService
+	taxClassService     interfaces.TaxClassService
+	warehouseService    interfaces.WarehouseService
+	events              interfaces.Outbox
+	appCtx              *deps.AppContext
 }
 
 // NewUserService creates a service for orders
-func NewService(repo interfaces.OrderRepository, productService interfaces.ProductService) interfaces.OrderService {
+func NewService(
+	repo interfaces.OrderRepository,
+	productService interfaces.ProductService,
+	customerService interfaces.CustomerService,
+	orgService interfaces.OrgService,
+	exchangeRateService interfaces.ExchangeRateService,
+	priceListService interfaces.PriceListService,
+	promotionService interfaces.PromotionService,
+	taxClassService interfaces.TaxClassService,
+	warehouseService interfaces.WarehouseService,
+	appCtx *deps.AppContext,
+) interfaces.OrderService {
 	return &service{
-		repo:           repo,
-		productService: productService,
+		repo:                repo,
+		productService:      productService,
+		customerService:     customerService,
+		orgService:          orgService,
+		exchangeRateService: exchangeRateService,
+		priceListService:    priceListService,
+		promotionService:    promotionService,
+		taxClassService:     taxClassService,
+		warehouseService:    warehouseService,
+		events:              appCtx.Events,
+		appCtx:              appCtx,
 	}
 }
 
@@ -39,35 +82,392 @@ func (s *service) Create(ctx context.Context, DTO dto.CreateOrderDTO, orgId uint
 		return nil, err
 	}
 
+	org, err := s.orgService.FindOrg(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customerService.FindByID(ctx, DTO.CustomerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, exchangeRate, err := s.currencyOf(ctx, DTO.Currency, org, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	listPrices, err := s.priceListService.Prices(ctx, customer, currency, variantIDs(variantMap), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	taxClasses, err := s.chargeTaxClasses(ctx, DTO.Charges)
+	if err != nil {
+		return nil, err
+	}
+
+	warehouseID, err := s.warehouseService.Location(ctx, DTO.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert DTO to model
-	order := DTO.ToModel(variantMap, orgId)
+	order := DTO.ToModel(variantMap, listPrices, taxClasses, org, customer, currency, exchangeRate)
+	order.WarehouseID = warehouseID
+	if DTO.PromotionCode != "" {
+		if err := s.promotionService.Apply(ctx, &order, DTO.PromotionCode); err != nil {
+			return nil, err
+		}
+	}
 
-	// Persist order
-	if err := s.repo.Create(ctx, &order); err != nil {
+	// Reserve stock and persist order atomically
+	err = s.appCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := allocateStock(ctx, s.productService.WithTx(tx), &order, variantMap); err != nil {
+			return err
+		}
+
+		repo := s.repo.WithTx(tx)
+		if err := repo.Create(ctx, &order); err != nil {
+			return err
+		}
+		if err := s.promotionService.WithTx(tx).Redeem(ctx, &order); err != nil {
+			return err
+		}
+
+		err := repo.CreateStatusHistory(ctx, &model.OrderStatusHistory{
+			OrgID:    order.OrgID,
+			OrderID:  order.ID,
+			ToStatus: order.Status,
+			UserID:   identity.ActorID(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.events.WithTx(tx).Record(ctx, types.OrderCreatedEventType, order.ID, types.OrderCreatedEvent{
+			OrderID:     order.ID,
+			OrderNumber: order.OrderNumber,
+			CustomerID:  order.CustomerID,
+			Status:      order.Status,
+			Total:       order.Total,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return &order, nil
 }
 
+// Update applies DTO to order and moves the stock reservation to match the
+// new items and fulfilment location. order is reloaded with its items and
+// charges and locked first, so a concurrent approval or update can't slip in
+// between; it must still be editable then.
 func (s *service) Update(ctx context.Context, order *model.Order, DTO dto.UpdateOrderDTO) error {
-	if len(DTO.Items) > 0 {
-		variantMap, err := s.getVariantMap(ctx, DTO.Items)
+	return s.appCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx).(*service)
+
+		locked, err := txService.repo.FindForUpdate(ctx, order.ID, []string{"Items", "Charges"})
 		if err != nil {
 			return err
 		}
-		DTO.ApplyModel(order, &variantMap)
+		if !isEditable(locked.Status) {
+			return fmt.Errorf("cannot update %w: %s", errNotPending, locked.Status)
+		}
+		*order = *locked
+
+		return txService.update(ctx, order, DTO)
+	})
+}
+
+// update is Update on the locked order. It must run inside a transaction.
+func (s *service) update(ctx context.Context, order *model.Order, DTO dto.UpdateOrderDTO) error {
+	state := stockStateOf(order.Status)
+	oldLines := allocatedLines(order)
+	itemsChanged := len(DTO.Items) > 0
+	warehouseChanged := DTO.WarehouseID != nil && (order.WarehouseID == nil || *order.WarehouseID != *DTO.WarehouseID)
+	chargesChanged := DTO.Charges != nil || (DTO.Delivery != nil && DTO.Delivery.TransportFare != nil)
+
+	customerChanged := DTO.CustomerID != nil && *DTO.CustomerID != order.CustomerID
+	var customer *model.Customer
+	if customerChanged || itemsChanged {
+		customerID := order.CustomerID
+		if DTO.CustomerID != nil {
+			customerID = *DTO.CustomerID
+		}
+
+		var err error
+		customer, err = s.customerService.FindByID(ctx, customerID, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if customerChanged && customer.TaxExempt != order.TaxExempt {
+		order.TaxExempt = customer.TaxExempt
+		if !itemsChanged {
+			if err := s.retax(ctx, order); err != nil {
+				return err
+			}
+		}
+	}
+
+	taxClasses, err := s.chargeTaxClasses(ctx, DTO.Charges)
+	if err != nil {
+		return err
+	}
+
+	if warehouseChanged {
+		warehouseID, err := s.warehouseService.Location(ctx, DTO.WarehouseID)
+		if err != nil {
+			return err
+		}
+		order.WarehouseID = warehouseID
+	}
+
+	var variantMap map[uint]model.Variant
+	if warehouseChanged && !itemsChanged {
+		// The items stay but are allocated again at the new location
+		items := make([]*dto.CreateOrderItemDTO, len(order.Items))
+		for i, item := range order.Items {
+			items[i] = &dto.CreateOrderItemDTO{VariantID: item.VariantID}
+		}
+		variantMap, err = s.getVariantMap(ctx, items)
+		if err != nil {
+			return err
+		}
+	}
+	if itemsChanged {
+		variantMap, err = s.getVariantMap(ctx, DTO.Items)
+		if err != nil {
+			return err
+		}
+		// New items are priced with the lists of the order customer today
+		listPrices, err := s.priceListService.Prices(ctx, customer, order.Currency, variantIDs(variantMap), time.Now())
+		if err != nil {
+			return err
+		}
+		DTO.ApplyModel(order, &variantMap, listPrices, taxClasses)
 	} else {
-		DTO.ApplyModel(order, nil)
+		DTO.ApplyModel(order, nil, nil, taxClasses)
+	}
+
+	// Changed items are checked against the promotion again
+	code := order.PromotionCode
+	if DTO.PromotionCode != nil {
+		code = *DTO.PromotionCode
+	}
+	switch {
+	case code == "":
+		if order.PromotionID != nil {
+			s.promotionService.Remove(order)
+		}
+	case DTO.PromotionCode != nil || itemsChanged:
+		if err := s.promotionService.Apply(ctx, order, code); err != nil {
+			return err
+		}
+	}
+
+	if itemsChanged || warehouseChanged {
+		// Give back what the old items held, then take stock for the new ones
+		if err := moveStock(ctx, s.productService, order.ID, oldLines, state, stockNone); err != nil {
+			return err
+		}
+		if err := allocateStock(ctx, s.productService, order, variantMap); err != nil {
+			return err
+		}
+	}
+	if itemsChanged {
+		if err := s.repo.DeleteItems(ctx, order.ID); err != nil {
+			return err
+		}
 	}
-	err := s.repo.Update(ctx, order)
+	if chargesChanged {
+		if err := s.repo.DeleteCharges(ctx, order.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.Update(ctx, order); err != nil {
+		return err
+	}
+	if err := s.promotionService.Redeem(ctx, order); err != nil {
+		return err
+	}
+
+	return s.events.Record(ctx, types.OrderUpdatedEventType, order.ID, types.OrderUpdatedEvent{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		Total:       order.Total,
+	})
+}
+
+// Transition moves an order to status to when the order lifecycle allows it.
+// Stock held by the order follows the new status and the change is recorded
+// in the order status history.
+func (s *service) Transition(ctx context.Context, ID uint, to model.OrderStatus) (*model.Order, error) {
+	var order *model.Order
+	err := s.appCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx).(*service)
+
+		var err error
+		// Locked, so the stock moved matches the items, e.g. after a credit
+		// note restocked some
+		order, err = txService.repo.FindForUpdate(ctx, ID, []string{"Items"})
+		if err != nil {
+			return err
+		}
+
+		return txService.transition(ctx, order, to)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// ApplyShipment counts the quantities of shipment, which was just shipped or
+// delivered, on the items of its order and derives the delivery status of
+// the order from them. An order with all of its items delivered is
+// delivered. It must run inside a transaction.
+func (s *service) ApplyShipment(ctx context.Context, shipment *model.Shipment) error {
+	order, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": shipment.OrderID}, []string{"Items"})
 	if err != nil {
 		return err
 	}
 
+	quantities := make(map[uint]int, len(shipment.Lines))
+	for _, line := range shipment.Lines {
+		quantities[line.OrderItemID] += line.Quantity
+	}
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		quantity := quantities[item.ID]
+		if quantity == 0 {
+			continue
+		}
+
+		var shipped, delivered int
+		switch shipment.Status {
+		case model.ShipmentShipped:
+			shipped = quantity
+		case model.ShipmentDelivered:
+			delivered = quantity
+		}
+		if err := s.repo.AddShippedQuantities(ctx, item.ID, shipped, delivered); err != nil {
+			return err
+		}
+		item.ShippedQuantity += shipped
+		item.DeliveredQuantity += delivered
+	}
+
+	// Orders closed meanwhile keep their status
+	if order.Status != model.OrderStatusApproved {
+		return nil
+	}
+
+	status := deliveryStatusOf(order.Items)
+	if status == model.DeliveryDelivered {
+		return s.transition(ctx, order, model.OrderStatusDelivered)
+	}
+	if status == order.Delivery.Status {
+		return nil
+	}
+
+	order.Delivery.Status = status
+	updated, err := s.repo.UpdateStatus(ctx, order, order.Status)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return &TransitionError{From: order.Status, To: order.Status}
+	}
 	return nil
 }
 
+// AllocateBackorders allocates what is available of a variant to its
+// backorders, oldest order first. Pending orders reserve what they are
+// allocated, approved orders have it deducted.
+func (s *service) AllocateBackorders(ctx context.Context, variantID uint) error {
+	return s.appCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		productService := s.productService.WithTx(tx)
+
+		backorders, err := repo.FindBackorders(ctx, map[string]any{"order_items.variant_id": variantID})
+		if err != nil {
+			return err
+		}
+
+		for _, backorder := range backorders {
+			line := model.StockLine{VariantID: variantID, WarehouseID: backorder.WarehouseID, Quantity: backorder.BackorderedQuantity}
+			line.Quantity, err = productService.ReserveAvailable(ctx, line)
+			if err != nil {
+				return err
+			}
+			if line.Quantity == 0 {
+				// Nothing left at its location, later orders may be
+				// fulfilled elsewhere
+				continue
+			}
+
+			if stockStateOf(backorder.OrderStatus) == stockCommitted {
+				if err := productService.CommitStock(ctx, backorder.OrderID, []model.StockLine{line}); err != nil {
+					return err
+				}
+			}
+
+			allocated, err := repo.AllocateItem(ctx, backorder.OrderItemID, line.Quantity)
+			if err != nil {
+				return err
+			}
+			if !allocated {
+				return fmt.Errorf("order %d: backorder of variant %d changed meanwhile", backorder.OrderID, variantID)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Restock puts quantities of the items of an order, by variant, back on hand
+// at its fulfilment location, for goods a credit note took back. Only what
+// the order deducted from the stock and was not restocked before goes back,
+// see restockable. It must run inside a transaction.
+func (s *service) Restock(ctx context.Context, orderID uint, quantities map[uint]int) error {
+	order, err := s.repo.FindForUpdate(ctx, orderID, []string{"Items"})
+	if err != nil {
+		return err
+	}
+
+	var lines []model.StockLine
+	for _, item := range order.Items {
+		quantity := min(quantities[item.VariantID], restockable(order.Status, item))
+		if quantity <= 0 {
+			continue
+		}
+
+		if err := s.repo.AddRestockedQuantity(ctx, item.ID, quantity); err != nil {
+			return err
+		}
+		lines = append(lines, model.StockLine{VariantID: item.VariantID, WarehouseID: order.WarehouseID, Quantity: quantity})
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	return s.productService.ReturnStock(ctx, order.ID, lines)
+}
+
+// FindBackorders returns the backordered items of open orders matching
+// where, oldest order first. Keys of where are qualified by table, e.g.
+// order_items.variant_id or orders.customer_id.
+func (s *service) FindBackorders(ctx context.Context, where map[string]any) ([]types.Backorder, error) {
+	return s.repo.FindBackorders(ctx, where)
+}
+
 func (s *service) FindByID(ctx context.Context, ID uint) (*model.Order, error) {
 	return s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, nil)
 }
@@ -76,8 +476,29 @@ func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.
 	return s.repo.Filter(ctx, opts)
 }
 
+// Delete removes an order and releases the stock reserved by it when it is
+// still pending. Stock of approved or delivered orders stays deducted.
 func (s *service) Delete(ctx context.Context, ID uint) error {
-	return s.repo.Delete(ctx, ID)
+	return s.appCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+
+		order, err := repo.FindOneWithFields(ctx, []string{"id", "status", "warehouse_id"}, map[string]any{"id": ID}, []string{"Items"})
+		if err != nil {
+			return err
+		}
+
+		if stockStateOf(order.Status) == stockReserved {
+			if err := s.productService.WithTx(tx).ReleaseStock(ctx, allocatedLines(order)); err != nil {
+				return err
+			}
+		}
+
+		if err := s.promotionService.WithTx(tx).Release(ctx, order.ID); err != nil {
+			return err
+		}
+
+		return repo.Delete(ctx, ID)
+	})
 }
 
 func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Order, error) {
@@ -85,7 +506,79 @@ func (s *service) FindOneWithFields(ctx context.Context, fields []string, where
 }
 
 func (s *service) WithTx(tx *gorm.DB) interfaces.OrderService {
-	return &service{repo: s.repo.WithTx(tx)}
+	return &service{
+		repo:                s.repo.WithTx(tx),
+		productService:      s.productService.WithTx(tx),
+		customerService:     s.customerService.WithTx(tx),
+		orgService:          s.orgService.WithTx(tx),
+		exchangeRateService: s.exchangeRateService.WithTx(tx),
+		priceListService:    s.priceListService.WithTx(tx),
+		promotionService:    s.promotionService.WithTx(tx),
+		taxClassService:     s.taxClassService.WithTx(tx),
+		warehouseService:    s.warehouseService.WithTx(tx),
+		events:              s.events.WithTx(tx),
+		appCtx:              s.appCtx,
+	}
+}
+
+// transition moves order to status to, see Transition. It must run inside
+// a transaction.
+func (s *service) transition(ctx context.Context, order *model.Order, to model.OrderStatus) error {
+	from := order.Status
+	if !canTransition(from, to) {
+		return &TransitionError{From: from, To: to}
+	}
+
+	order.Status = to
+	switch to {
+	case model.OrderStatusDelivered:
+		now := time.Now()
+		order.Delivery.Status = model.DeliveryDelivered
+		order.Delivery.At = &now
+	case model.OrderStatusCancelled:
+		order.Delivery.Status = model.DeliveryCancelled
+	}
+
+	// Guard against a concurrent transition of the same order
+	updated, err := s.repo.UpdateStatus(ctx, order, from)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return &TransitionError{From: from, To: to}
+	}
+
+	// What was shipped already left for good
+	if err := moveStock(ctx, s.productService, order.ID, unshippedLines(order), stockStateOf(from), stockStateOf(to)); err != nil {
+		return err
+	}
+	// A cancelled order gives its promotion use back and won't ship
+	if to == model.OrderStatusCancelled {
+		if err := s.promotionService.Release(ctx, order.ID); err != nil {
+			return err
+		}
+		if err := s.repo.CancelShipments(ctx, order.ID); err != nil {
+			return err
+		}
+	}
+
+	err = s.repo.CreateStatusHistory(ctx, &model.OrderStatusHistory{
+		OrgID:      order.OrgID,
+		OrderID:    order.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		UserID:     identity.ActorID(ctx),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.events.Record(ctx, types.OrderStatusChangedEventType, order.ID, types.OrderStatusChangedEvent{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		From:        from,
+		To:          to,
+	})
 }
 
 func (s *service) Exists(ctx context.Context, where map[string]any) (bool, error) {
@@ -98,6 +591,92 @@ func (s *service) Exists(ctx context.Context, where map[string]any) (bool, error
 	return p.ID != 0, nil
 }
 
+// currencyOf returns the currency of a new order and what one unit of it is
+// worth in the org base currency now. The currency is the one asked for,
+// else the billing currency of the customer, else the base currency.
+func (s *service) currencyOf(ctx context.Context, currency string, org *model.Org, customer *model.Customer) (string, float64, error) {
+	if currency == "" {
+		currency = customer.Currency
+	}
+	if currency == "" {
+		currency = org.BaseCurrency
+	}
+
+	rate, err := s.exchangeRateService.Rate(ctx, org.BaseCurrency, currency, time.Now())
+	if err != nil {
+		return "", 0, err
+	}
+
+	return currency, rate, nil
+}
+
+// retax takes the taxes of the items of order from their variants, and of
+// its charges from their classes, again, e.g. after the order moved to a
+// customer with another tax status.
+func (s *service) retax(ctx context.Context, order *model.Order) error {
+	items := make([]*dto.CreateOrderItemDTO, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = &dto.CreateOrderItemDTO{VariantID: item.VariantID}
+	}
+
+	variantMap, err := s.getVariantMap(ctx, items)
+	if err != nil {
+		return err
+	}
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		item.Taxes, item.TaxRate = pricing.Taxes(order, variantMap[item.VariantID])
+	}
+
+	var classIDs []uint
+	for _, charge := range order.Charges {
+		if charge.TaxClassID != nil {
+			classIDs = append(classIDs, *charge.TaxClassID)
+		}
+	}
+	classes, err := s.taxClassService.FindByIDs(ctx, classIDs)
+	if err != nil {
+		return err
+	}
+	for _, class := range classes {
+		for i := range order.Charges {
+			charge := &order.Charges[i]
+			if charge.TaxClassID != nil && *charge.TaxClassID == class.ID {
+				charge.Taxes, _ = pricing.ClassTaxes(order, &class)
+			}
+		}
+	}
+	return nil
+}
+
+// chargeTaxClasses returns the tax classes of charges by ID, all of which
+// must exist.
+func (s *service) chargeTaxClasses(ctx context.Context, charges []dto.CreateOrderChargeDTO) (map[uint]model.TaxClass, error) {
+	var IDs []uint
+	for _, charge := range charges {
+		if charge.TaxClassID != nil {
+			IDs = append(IDs, *charge.TaxClassID)
+		}
+	}
+
+	classes, err := s.taxClassService.FindByIDs(ctx, IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	classMap := make(map[uint]model.TaxClass, len(classes))
+	for _, class := range classes {
+		classMap[class.ID] = class
+	}
+	for _, ID := range IDs {
+		if _, ok := classMap[ID]; !ok {
+			return nil, errUnknownTaxClass
+		}
+	}
+	return classMap, nil
+}
+
 func (s *service) getVariantMap(ctx context.Context, items []*dto.CreateOrderItemDTO) (map[uint]model.Variant, error) {
 
 	var variantIds []uint
@@ -106,7 +685,7 @@ func (s *service) getVariantMap(ctx context.Context, items []*dto.CreateOrderIte
 	}
 
 	// Fetch variants
-	variants, err := s.productService.FindVariants(ctx, map[string]any{"id": variantIds}, nil)
+	variants, err := s.productService.FindVariants(ctx, map[string]any{"id": variantIds}, []string{"TaxClass.Rates"})
 	if err != nil {
 		return nil, err
 	}
@@ -126,3 +705,12 @@ func (s *service) getVariantMap(ctx context.Context, items []*dto.CreateOrderIte
 
 	return variantMap, nil
 }
+
+// variantIDs returns the IDs of the variants in variantMap.
+func variantIDs(variantMap map[uint]model.Variant) []uint {
+	IDs := make([]uint, 0, len(variantMap))
+	for ID := range variantMap {
+		IDs = append(IDs, ID)
+	}
+	return IDs
+}
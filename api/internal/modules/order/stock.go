@@ -0,0 +1,143 @@
+package order
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+)
+
+// stockState is what an order holds on the stock of its variants.
+type stockState int
+
+const (
+	// stockNone: nothing is held, e.g. cancelled orders.
+	stockNone stockState = iota
+	// stockReserved: quantities are reserved but still on hand.
+	stockReserved
+	// stockCommitted: quantities are deducted from the stock on hand.
+	stockCommitted
+)
+
+// stockStateOf maps an order status to the stock it holds. Pending orders
+// reserve stock, approval or delivery commits it.
+func stockStateOf(status model.OrderStatus) stockState {
+	switch status {
+	case model.OrderStatusPending:
+		return stockReserved
+	case model.OrderStatusApproved, model.OrderStatusDelivered:
+		return stockCommitted
+	default:
+		return stockNone
+	}
+}
+
+// moveStock moves the lines of an order from one stock state to another. It
+// must run inside a transaction.
+func moveStock(ctx context.Context, ps interfaces.ProductService, orderID uint, lines []model.StockLine, from, to stockState) error {
+	if from == to || len(lines) == 0 {
+		return nil
+	}
+
+	if from == stockReserved && to == stockCommitted {
+		return ps.CommitStock(ctx, orderID, lines)
+	}
+
+	// Undo what is currently held...
+	switch from {
+	case stockReserved:
+		if err := ps.ReleaseStock(ctx, lines); err != nil {
+			return err
+		}
+	case stockCommitted:
+		if err := ps.ReturnStock(ctx, orderID, lines); err != nil {
+			return err
+		}
+	}
+
+	// ...then hold stock for the target state
+	switch to {
+	case stockReserved:
+		return ps.ReserveStock(ctx, lines)
+	case stockCommitted:
+		if err := ps.ReserveStock(ctx, lines); err != nil {
+			return err
+		}
+		return ps.CommitStock(ctx, orderID, lines)
+	}
+
+	return nil
+}
+
+// allocateStock reserves the stock of the items of a pending order at its
+// fulfilment location. Items of variants that allow backorders take what is
+// available and backorder the rest, the others have to be reserved in full.
+// It must run inside a transaction.
+func allocateStock(ctx context.Context, ps interfaces.ProductService, order *model.Order, variantMap map[uint]model.Variant) error {
+	items := order.Items
+	var lines []model.StockLine
+	for _, item := range items {
+		if !variantMap[item.VariantID].AllowBackorder {
+			lines = append(lines, model.StockLine{VariantID: item.VariantID, WarehouseID: order.WarehouseID, Quantity: item.Quantity})
+		}
+	}
+	if err := ps.ReserveStock(ctx, lines); err != nil {
+		return err
+	}
+
+	for i := range items {
+		item := &items[i]
+		item.AllocatedQuantity = item.Quantity
+		if variantMap[item.VariantID].AllowBackorder {
+			reserved, err := ps.ReserveAvailable(ctx, model.StockLine{VariantID: item.VariantID, WarehouseID: order.WarehouseID, Quantity: item.Quantity})
+			if err != nil {
+				return err
+			}
+			item.AllocatedQuantity = reserved
+		}
+		item.BackorderedQuantity = item.Quantity - item.AllocatedQuantity
+	}
+
+	return nil
+}
+
+// allocatedLines returns the quantities the items of order hold of the stock
+// at its fulfilment location.
+func allocatedLines(order *model.Order) []model.StockLine {
+	lines := make([]model.StockLine, 0, len(order.Items))
+	for _, item := range order.Items {
+		if item.AllocatedQuantity > 0 {
+			lines = append(lines, model.StockLine{VariantID: item.VariantID, WarehouseID: order.WarehouseID, Quantity: item.AllocatedQuantity})
+		}
+	}
+	return lines
+}
+
+// unshippedLines returns the quantities the items of order hold of the stock
+// at its fulfilment location that shipments have not shipped. Credit notes
+// restock shipped goods first, what they restocked beyond is not held
+// anymore either.
+func unshippedLines(order *model.Order) []model.StockLine {
+	lines := make([]model.StockLine, 0, len(order.Items))
+	for _, item := range order.Items {
+		if quantity := item.AllocatedQuantity - max(item.ShippedQuantity, item.RestockedQuantity); quantity > 0 {
+			lines = append(lines, model.StockLine{VariantID: item.VariantID, WarehouseID: order.WarehouseID, Quantity: quantity})
+		}
+	}
+	return lines
+}
+
+// restockable returns how much of an item of an order in status can still be
+// put back on hand. Pending orders deducted nothing, approved and delivered
+// ones their allocated quantity, and cancelled ones what shipped before, as
+// cancelling returned the rest.
+func restockable(status model.OrderStatus, item model.OrderItem) int {
+	var deducted int
+	switch {
+	case stockStateOf(status) == stockCommitted:
+		deducted = item.AllocatedQuantity
+	case status == model.OrderStatusCancelled:
+		deducted = item.ShippedQuantity
+	}
+	return max(deducted-item.RestockedQuantity, 0)
+}
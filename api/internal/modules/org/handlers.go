@@ -123,6 +123,11 @@ func (h *OrgHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.belongsToOrg(r, uint(id)) {
+		response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrOrgNotFound, h.appCtx.Logger)
+		return
+	}
+
 	// Get existing org
 	existingOrg, err := h.service.FindOrg(ctx, uint(id))
 	if err != nil {
@@ -165,6 +170,12 @@ func (h *OrgHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
 		return
 	}
+
+	if !h.belongsToOrg(r, uint(id)) {
+		response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrOrgNotFound, h.appCtx.Logger)
+		return
+	}
+
 	if err := h.service.Delete(ctx, uint(id)); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrOrgNotFound, h.appCtx.Logger)
@@ -197,6 +208,11 @@ func (h *OrgHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.belongsToOrg(r, uint(id)) {
+		response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrOrgNotFound, h.appCtx.Logger)
+		return
+	}
+
 	org, err := h.service.FindOrg(ctx, uint(id))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -209,3 +225,15 @@ func (h *OrgHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	response.WriteJSONSuccess(w, http.StatusOK, org, h.appCtx.Logger)
 }
+
+// belongsToOrg reports whether the authenticated user is a member of the org.
+// Orgs are not tenant scoped at the database layer since the org is the tenant
+// itself, so access is checked here. Other orgs are reported as not found.
+func (h *OrgHandler) belongsToOrg(r *http.Request, orgID uint) bool {
+	userFromContext, err := identity.UserFromContext(r.Context())
+	if err != nil {
+		return false
+	}
+
+	return userFromContext.Org == orgID
+}
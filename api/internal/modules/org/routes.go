@@ -1,19 +1,21 @@
 package org
 
 import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"github.com/go-chi/chi"
 )
 
-func RegisterRoutes(router chi.Router, orgHandler interfaces.OrgHandler) {
+func RegisterRoutes(router chi.Router, orgHandler interfaces.OrgHandler, middleware interfaces.Middleware) {
 
 	router.Route("/orgs", func(r chi.Router) {
+		// Any signed in user can create the org they will own.
 		r.Post("/", orgHandler.Create)
 
-		r.Get("/{id}", orgHandler.Get)
+		r.With(middleware.RequirePermission(rbac.OrgsRead)).Get("/{id}", orgHandler.Get)
 
-		r.Patch("/{id}", orgHandler.Update)
+		r.With(middleware.RequirePermission(rbac.OrgsWrite)).Patch("/{id}", orgHandler.Update)
 
-		r.Delete("/{id}", orgHandler.Delete)
+		r.With(middleware.RequirePermission(rbac.OrgsDelete)).Delete("/{id}", orgHandler.Delete)
 	})
 }
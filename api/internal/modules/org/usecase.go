@@ -2,10 +2,10 @@ package org
 
 import (
 	"context"
-	"errors"
 	"strconv"
 
 	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/tenant"
 	"github.com/deveasyclick/openb2b/internal/shared/types"
 	"github.com/deveasyclick/openb2b/pkg/clerk"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
@@ -33,8 +33,10 @@ func NewCreateUseCase(
 	}
 }
 
+// Execute creates an org and assigns its creator to it. The org is set on the
+// Clerk user once the org.created event is relayed, see SetClerkOrg.
 func (uc *createOrgUseCase) Execute(ctx context.Context, input types.CreateOrgInput) error {
-	txErr := uc.appCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return uc.appCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
 		// Wrap services with transactional repos
 		orgServiceTx := uc.orgService.WithTx(tx)
@@ -50,35 +52,19 @@ func (uc *createOrgUseCase) Execute(ctx context.Context, input types.CreateOrgIn
 			return err // rollback
 		}
 
-		return nil // commit
+		// The creator has no org yet, record the event for the new one
+		orgCtx := tenant.WithOrg(ctx, input.Org.ID)
+		return uc.appCtx.Events.WithTx(tx).Record(orgCtx, types.OrgCreatedEventType, input.Org.ID, types.OrgCreatedEvent{
+			OrgID:   input.Org.ID,
+			UserID:  input.User.ID,
+			ClerkID: input.User.ClerkID,
+		})
 	})
+}
 
-	if txErr != nil {
-		return txErr
-	}
-
-	orgID := strconv.FormatUint(uint64(input.Org.ID), 10)
-	err := uc.clerkService.SetOrg(ctx, input.User.ClerkID, orgID)
-	if err != nil {
-		// TODO: Send slerk alerk if this fail or delete org when this fail
-		// TODO: Do this later in  a background worker
-		// Delete org and user org assignment if clerk update failed
-		go func() {
-			if rec := recover(); rec != nil {
-				uc.appCtx.Logger.Error("error recovered from panic", "error", err)
-			}
-			err := uc.orgService.Delete(ctx, input.Org.ID)
-			if err != nil {
-				uc.appCtx.Logger.Error("error deleting org", "error", err)
-			}
-			err = uc.userService.AssignOrg(ctx, input.User.ID, 0)
-			if err != nil {
-				uc.appCtx.Logger.Error("error deleting org", "error", err)
-			}
-		}()
-
-		return errors.New("failed to create workspace, setting custom claim in clerk failed")
-	}
-
-	return nil
+// SetClerkOrg sets a new org on the Clerk user of its creator. It subscribes
+// to org.created, so a failing Clerk call is retried by the job queue.
+func (uc *createOrgUseCase) SetClerkOrg(ctx context.Context, event types.OrgCreatedEvent) error {
+	orgID := strconv.FormatUint(uint64(event.OrgID), 10)
+	return uc.clerkService.SetOrg(ctx, event.ClerkID, orgID)
 }
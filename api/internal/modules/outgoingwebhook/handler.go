@@ -0,0 +1,329 @@
+package outgoingwebhook
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSubscriptionSearchFields = map[string]bool{"url": true, "description": true}
+var allowedDeliverySearchFields = map[string]bool{"event_type": true}
+
+// For Swagger docs
+type APIResponseWebhookSubscription struct {
+	Code    int                       `json:"code"`
+	Message string                    `json:"message"`
+	Data    model.WebhookSubscription `json:"data"`
+}
+
+// For Swagger docs
+type APIResponseWebhookDelivery struct {
+	Code    int                   `json:"code"`
+	Message string                `json:"message"`
+	Data    model.WebhookDelivery `json:"data"`
+}
+
+type OutgoingWebhookHandler struct {
+	service interfaces.OutgoingWebhookService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.OutgoingWebhookService, appCtx *deps.AppContext) interfaces.OutgoingWebhookHandler {
+	return &OutgoingWebhookHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List webhook subscriptions with filtering and pagination
+// @Description  Returns a paginated list of the webhook subscriptions of the org.
+// @Tags         webhook-subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        active        query     bool    false  "Filter by active"
+// @Success      200           {object}  APIResponseWebhookSubscription
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /webhook-subscriptions [get]
+// @Security BearerAuth
+func (h *OutgoingWebhookHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSubscriptionSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterWebhookSubscription, h.appCtx.Logger)
+		return
+	}
+
+	subscriptions, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterWebhookSubscription, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.WebhookSubscription]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      subscriptions,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create webhook subscription
+// @Description Register an https endpoint on a public address for the chosen event types. Redirects are not followed. Deliveries are signed with the returned secret using the Svix headers (svix-id, svix-timestamp, svix-signature).
+// @Tags webhook-subscriptions
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateWebhookSubscriptionDTO true "Webhook subscription payload"
+// @Success 201 {object} APIResponseWebhookSubscription
+// @Failure      400  {object}  apperrors.APIErrorResponse
+// @Failure      500  {object}  apperrors.APIErrorResponse
+// @Router /webhook-subscriptions [post]
+// @Security BearerAuth
+func (h *OutgoingWebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreateWebhookSubscriptionDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateWebhookSubscription, h.appCtx.Logger)
+		return
+	}
+
+	subscription, err := h.service.Create(ctx, userFromContext.Org, &req)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateWebhookSubscription, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, subscription, h.appCtx.Logger)
+}
+
+// Update godoc
+// @Summary Update webhook subscription
+// @Description Change the endpoint or event types of a webhook subscription, or pause it
+// @Tags webhook-subscriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook subscription ID"
+// @Param request body dto.UpdateWebhookSubscriptionDTO true "Update webhook subscription payload"
+// @Success 200 {object} APIResponseWebhookSubscription
+// @Failure 400  {object}  apperrors.APIErrorResponse
+// @Failure 404  {object}  apperrors.APIErrorResponse
+// @Failure 500  {object}  apperrors.APIErrorResponse
+// @Router /webhook-subscriptions/{id} [patch]
+// @Security BearerAuth
+func (h *OutgoingWebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.UpdateWebhookSubscriptionDTO
+	if errors := validator.ValidateRequest(r, &req); len(errors) > 0 {
+		validator.WriteValidationResponse(w, errors)
+		return
+	}
+
+	subscription, err := h.service.Update(ctx, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrWebhookSubscriptionNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateWebhookSubscription, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, subscription, h.appCtx.Logger)
+}
+
+// Delete godoc
+// @Summary Delete webhook subscription
+// @Description Delete a webhook subscription. Its pending deliveries fail.
+// @Tags webhook-subscriptions
+// @Produce json
+// @Param id path int true "Webhook subscription ID"
+// @Success 200 {integer} response.APIResponseInt
+// @Failure 400  {object}  apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500  {object}  apperrors.APIErrorResponse
+// @Router /webhook-subscriptions/{id} [delete]
+// @Security BearerAuth
+func (h *OutgoingWebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrWebhookSubscriptionNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrDeleteWebhookSubscription, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, id, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get webhook subscription
+// @Description Get a webhook subscription by ID
+// @Tags webhook-subscriptions
+// @Produce json
+// @Param id path int true "Webhook subscription ID"
+// @Success 200 {object} APIResponseWebhookSubscription
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /webhook-subscriptions/{id} [get]
+// @Security BearerAuth
+func (h *OutgoingWebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	subscription, err := h.service.FindOneWithFields(r.Context(), nil, map[string]any{"id": id}, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrWebhookSubscriptionNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindWebhookSubscription, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, subscription, h.appCtx.Logger)
+}
+
+// FilterDeliveries godoc
+// @Summary      List webhook deliveries with filtering and pagination
+// @Description  Returns the delivery log of the webhook subscriptions of the org, with the outcome of the last attempt of each delivery.
+// @Tags         webhook-subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        page            query     int     false  "Page number (default: 1)"
+// @Param        limit           query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort            query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        search_fields   query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        subscription_id query     int     false  "Filter by webhook subscription"
+// @Param        event_type      query     string  false  "Filter by event type"
+// @Param        status          query     string  false  "Filter by status (pending, succeeded, failed)"
+// @Success      200             {object}  APIResponseWebhookDelivery
+// @Failure      400             {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500             {object}  apperrors.APIError "Internal server error"
+// @Router       /webhook-deliveries [get]
+// @Security BearerAuth
+func (h *OutgoingWebhookHandler) FilterDeliveries(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedDeliverySearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterWebhookDelivery, h.appCtx.Logger)
+		return
+	}
+
+	deliveries, total, err := h.service.FilterDeliveries(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterWebhookDelivery, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.WebhookDelivery]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      deliveries,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// GetDelivery godoc
+// @Summary Get webhook delivery
+// @Description Get a webhook delivery by ID
+// @Tags webhook-subscriptions
+// @Produce json
+// @Param id path int true "Webhook delivery ID"
+// @Success 200 {object} APIResponseWebhookDelivery
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /webhook-deliveries/{id} [get]
+// @Security BearerAuth
+func (h *OutgoingWebhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	delivery, err := h.service.FindDelivery(r.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrWebhookDeliveryNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindWebhookDelivery, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, delivery, h.appCtx.Logger)
+}
+
+// Redeliver godoc
+// @Summary Redeliver webhook
+// @Description Queue a webhook delivery to be posted again, with the same message ID and a fresh set of attempts
+// @Tags webhook-subscriptions
+// @Produce json
+// @Param id path int true "Webhook delivery ID"
+// @Success 200 {object} APIResponseWebhookDelivery
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /webhook-deliveries/{id}/redeliver [post]
+// @Security BearerAuth
+func (h *OutgoingWebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	delivery, err := h.service.Redeliver(r.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrWebhookDeliveryNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrRedeliverWebhook, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, delivery, h.appCtx.Logger)
+}
@@ -0,0 +1,112 @@
+package outgoingwebhook
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.OutgoingWebhookRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.WebhookSubscription, int64, error) {
+	return pagination.Paginate[model.WebhookSubscription](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, subscription *model.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Create(subscription).Error
+}
+
+func (r *repository) Update(ctx context.Context, subscription *model.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Save(subscription).Error
+}
+
+func (r *repository) Delete(ctx context.Context, ID uint) error {
+	res := r.db.WithContext(ctx).Delete(&model.WebhookSubscription{}, ID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.WebhookSubscription, error) {
+	var result model.WebhookSubscription
+
+	query := r.db.WithContext(ctx).Model(model.WebhookSubscription{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FindActive returns the active subscriptions of the org.
+func (r *repository) FindActive(ctx context.Context) ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	err := r.db.WithContext(ctx).Where("active = ?", true).Order("id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// CreateDelivery stores delivery unless the event was delivered to the
+// subscription before, and reports whether it was stored.
+func (r *repository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Create(delivery)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *repository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Omit("Subscription").Save(delivery).Error
+}
+
+func (r *repository) FindDelivery(ctx context.Context, ID uint, preloads []string) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+
+	query := r.db.WithContext(ctx)
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	if err := query.First(&delivery, ID).Error; err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (r *repository) FilterDeliveries(ctx context.Context, opts pagination.Options) ([]model.WebhookDelivery, int64, error) {
+	return pagination.Paginate[model.WebhookDelivery](ctx, r.db, opts)
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.OutgoingWebhookRepository {
+	return &repository{db: tx}
+}
@@ -0,0 +1,278 @@
+package outgoingwebhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/deveasyclick/openb2b/pkg/svix"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxAttempts is how often a delivery is tried before it fails. The job
+	// queue retries the delivery job at least as often, with exponential
+	// backoff between attempts.
+	maxAttempts = 5
+	// requestTimeout bounds how long an endpoint can take to answer.
+	requestTimeout = 10 * time.Second
+	// maxResponseBody is how much of the answer of an endpoint is logged.
+	maxResponseBody = 1024
+)
+
+type service struct {
+	repo   interfaces.OutgoingWebhookRepository
+	jobs   interfaces.JobQueue
+	client *http.Client
+	appCtx *deps.AppContext
+	db     *gorm.DB
+}
+
+func NewService(repo interfaces.OutgoingWebhookRepository, appCtx *deps.AppContext) interfaces.OutgoingWebhookService {
+	return &service{
+		repo:   repo,
+		jobs:   appCtx.Jobs,
+		client: appCtx.HTTPClient,
+		appCtx: appCtx,
+		db:     appCtx.DB,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.WebhookSubscription, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+// Create registers an endpoint with a new signing secret.
+func (s *service) Create(ctx context.Context, orgID uint, DTO *dto.CreateWebhookSubscriptionDTO) (*model.WebhookSubscription, error) {
+	secret, err := svix.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := DTO.ToModel(orgID, secret)
+	if err := s.repo.Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (s *service) Update(ctx context.Context, ID uint, DTO *dto.UpdateWebhookSubscriptionDTO) (*model.WebhookSubscription, error) {
+	subscription, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	DTO.ApplyModel(subscription)
+	if err := s.repo.Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (s *service) Delete(ctx context.Context, ID uint) error {
+	return s.repo.Delete(ctx, ID)
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.WebhookSubscription, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+func (s *service) FindDelivery(ctx context.Context, ID uint) (*model.WebhookDelivery, error) {
+	return s.repo.FindDelivery(ctx, ID, nil)
+}
+
+func (s *service) FilterDeliveries(ctx context.Context, opts pagination.Options) ([]model.WebhookDelivery, int64, error) {
+	return s.repo.FilterDeliveries(ctx, opts)
+}
+
+// webhookPayload is the body posted to endpoints.
+type webhookPayload struct {
+	Type      string          `json:"type"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Dispatch queues a delivery of event to every active subscription of its
+// org that wants it. It runs as the outbox subscriber of the webhook event
+// types, so an event is dispatched once per subscription even when it runs
+// again.
+func (s *service) Dispatch(ctx context.Context, event *model.OutboxEvent) error {
+	if event.OrgID == nil {
+		return nil
+	}
+
+	subscriptions, err := s.repo.FindActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		Type:      event.Type,
+		Timestamp: event.CreatedAt,
+		Data:      json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		jobs := s.jobs.WithTx(tx)
+
+		for _, subscription := range subscriptions {
+			if !subscription.Subscribes(event.Type) {
+				continue
+			}
+
+			delivery := &model.WebhookDelivery{
+				OrgID:          subscription.OrgID,
+				SubscriptionID: subscription.ID,
+				EventID:        event.ID,
+				EventType:      event.Type,
+				Payload:        string(payload),
+				Status:         model.WebhookDeliveryPending,
+			}
+			created, err := repo.CreateDelivery(ctx, delivery)
+			if err != nil {
+				return err
+			}
+			if !created {
+				continue
+			}
+
+			if err := jobs.Enqueue(ctx, types.WebhookDeliveryJobType, types.WebhookDeliveryJob{DeliveryID: delivery.ID}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Deliver posts a pending delivery to the endpoint of its subscription and
+// logs the outcome on the delivery. A failed attempt returns an error, so
+// the job is retried, until the delivery runs out of attempts.
+func (s *service) Deliver(ctx context.Context, job types.WebhookDeliveryJob) error {
+	delivery, err := s.repo.FindDelivery(ctx, job.DeliveryID, []string{"Subscription"})
+	if err != nil {
+		return err
+	}
+	if delivery.Status != model.WebhookDeliveryPending {
+		return nil
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	var postErr error
+	if delivery.Subscription == nil {
+		postErr = fmt.Errorf("subscription %d was deleted", delivery.SubscriptionID)
+		delivery.Attempts = maxAttempts
+	} else {
+		postErr = s.post(ctx, delivery.Subscription, delivery)
+	}
+
+	switch {
+	case postErr == nil:
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case delivery.Attempts >= maxAttempts:
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.LastError = postErr.Error()
+	default:
+		delivery.LastError = postErr.Error()
+	}
+
+	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return err
+	}
+
+	if delivery.Status == model.WebhookDeliveryPending {
+		return fmt.Errorf("webhook delivery %d failed: %w", delivery.ID, postErr)
+	}
+
+	return nil
+}
+
+// post sends delivery to the endpoint of subscription, signed with the
+// Svix headers, and records the answer on delivery.
+func (s *service) post(ctx context.Context, subscription *model.WebhookSubscription, delivery *model.WebhookDelivery) error {
+	body := []byte(delivery.Payload)
+	// One message ID per event, so endpoints can drop repeated deliveries
+	msgID := fmt.Sprintf("msg_%d", delivery.EventID)
+	timestamp := time.Now()
+
+	signature, err := svix.Sign(subscription.Secret, msgID, timestamp, body)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("svix-id", msgID)
+	req.Header.Set("svix-timestamp", strconv.FormatInt(timestamp.Unix(), 10))
+	req.Header.Set("svix-signature", signature)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		delivery.ResponseStatus = 0
+		delivery.ResponseBody = ""
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	delivery.ResponseStatus = resp.StatusCode
+	delivery.ResponseBody = string(respBody)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint answered %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Redeliver queues a delivery to be posted again, with a fresh set of
+// attempts.
+func (s *service) Redeliver(ctx context.Context, ID uint) (*model.WebhookDelivery, error) {
+	delivery, err := s.repo.FindDelivery(ctx, ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		delivery.Status = model.WebhookDeliveryPending
+		delivery.Attempts = 0
+		if err := s.repo.WithTx(tx).UpdateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+
+		return s.jobs.WithTx(tx).Enqueue(ctx, types.WebhookDeliveryJobType, types.WebhookDeliveryJob{DeliveryID: delivery.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,254 @@
+package payment
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/modules/customer"
+	"github.com/deveasyclick/openb2b/internal/modules/invoice"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedPaymentSearchFields = map[string]bool{"reference": true, "notes": true, "method": true}
+
+// For Swagger docs
+type APIResponsePayment struct {
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    model.Payment `json:"data"`
+}
+
+type PaymentHandler struct {
+	service interfaces.PaymentService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.PaymentService, appCtx *deps.AppContext) interfaces.PaymentHandler {
+	return &PaymentHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List payments with filtering and pagination
+// @Description  Returns a paginated list of payments. Supports filtering, sorting, searching, and preloading.
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'received_at desc'"
+// @Param        preloads      query     string  false  "Comma-separated list of relations to preload. relation must start with uppercase. e.g. 'Invoice,Customer'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        invoice_id    query     int     false  "Filter by invoice"
+// @Param        customer_id   query     int     false  "Filter by customer"
+// @Param        method        query     string  false  "Filter by payment method"
+// @Param        reference     query     string  false  "Filter by reference"
+// @Success      200           {object}  APIResponsePayment
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /payments [get]
+// @Security BearerAuth
+func (h *PaymentHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedPaymentSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterPayment, h.appCtx.Logger)
+		return
+	}
+
+	payments, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterPayment, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.Payment]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      payments,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Record payment
+// @Description Record a payment against an issued invoice. The invoice moves to partially_paid or paid, and anything paid over the amount due is credited to the customer.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param request body dto.CreatePaymentDTO true "Payment payload"
+// @Success 201 {object} APIResponsePayment
+// @Failure      400  {object}  apperrors.APIErrorResponse
+// @Failure      404  {object}  apperrors.APIErrorResponse
+// @Failure      409  {object}  apperrors.APIErrorResponse
+// @Failure      500  {object}  apperrors.APIErrorResponse
+// @Router /payments [post]
+// @Security BearerAuth
+func (h *PaymentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreatePaymentDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreatePayment, h.appCtx.Logger)
+		return
+	}
+
+	payment, err := h.service.Create(ctx, userFromContext.Org, &req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrInvoiceNotFound, h.appCtx.Logger)
+			return
+		}
+
+		h.writeSettleError(w, err, apperrors.ErrCreatePayment)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, payment, h.appCtx.Logger)
+}
+
+// Update godoc
+// @Summary Update payment
+// @Description Correct a recorded payment. The invoice and customer credit are recomputed.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int true "Payment ID"
+// @Param request body dto.UpdatePaymentDTO true "Update payment payload"
+// @Success 200 {object} APIResponsePayment
+// @Failure 400  {object}  apperrors.APIErrorResponse
+// @Failure 404  {object}  apperrors.APIErrorResponse
+// @Failure 409  {object}  apperrors.APIErrorResponse
+// @Failure 500  {object}  apperrors.APIErrorResponse
+// @Router /payments/{id} [patch]
+// @Security BearerAuth
+func (h *PaymentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.UpdatePaymentDTO
+	if errors := validator.ValidateRequest(r, &req); len(errors) > 0 {
+		validator.WriteValidationResponse(w, errors)
+		return
+	}
+
+	payment, err := h.service.Update(ctx, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPaymentNotFound, h.appCtx.Logger)
+			return
+		}
+
+		h.writeSettleError(w, err, apperrors.ErrUpdatePayment)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, payment, h.appCtx.Logger)
+}
+
+// Delete godoc
+// @Summary Delete payment
+// @Description Delete a payment recorded by mistake. The invoice and customer credit are recomputed.
+// @Tags payments
+// @Produce json
+// @Param id path int true "Payment ID"
+// @Success 200 {integer} response.APIResponseInt
+// @Failure 400  {object}  apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409  {object}  apperrors.APIErrorResponse
+// @Failure 500  {object}  apperrors.APIErrorResponse
+// @Router /payments/{id} [delete]
+// @Security BearerAuth
+func (h *PaymentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	if err := h.service.Delete(ctx, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPaymentNotFound, h.appCtx.Logger)
+			return
+		}
+
+		h.writeSettleError(w, err, apperrors.ErrDeletePayment)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, id, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get payment
+// @Description Get a payment by ID
+// @Tags payments
+// @Produce json
+// @Param id path int true "Payment ID"
+// @Success 200 {object} APIResponsePayment
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /payments/{id} [get]
+// @Security BearerAuth
+func (h *PaymentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	payment, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Invoice"})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPaymentNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindPayment, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, payment, h.appCtx.Logger)
+}
+
+// writeSettleError maps the errors of recomputing an invoice after its
+// payments changed to a response.
+func (h *PaymentHandler) writeSettleError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, errCurrencyMismatch):
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrPaymentCurrencyMismatch, h.appCtx.Logger)
+	case errors.Is(err, invoice.ErrNotPayable):
+		response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrInvoiceNotPayable, h.appCtx.Logger)
+	case errors.Is(err, customer.ErrInsufficientCredit):
+		response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrInsufficientCredit, h.appCtx.Logger)
+	default:
+		var transitionErr *invoice.TransitionError
+		if errors.As(err, &transitionErr) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, transitionErr.Error(), h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, fallback, h.appCtx.Logger)
+	}
+}
@@ -0,0 +1,80 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.PaymentRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.Payment, int64, error) {
+	return pagination.Paginate[model.Payment](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, payment *model.Payment) error {
+	return r.db.WithContext(ctx).Create(payment).Error
+}
+
+func (r *repository) Update(ctx context.Context, payment *model.Payment) error {
+	return r.db.WithContext(ctx).Omit("Invoice", "Customer").Save(payment).Error
+}
+
+func (r *repository) Delete(ctx context.Context, ID uint) error {
+	res := r.db.WithContext(ctx).Delete(&model.Payment{}, ID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindByInvoice returns the payments of an invoice in the order they were
+// received.
+func (r *repository) FindByInvoice(ctx context.Context, invoiceID uint) ([]model.Payment, error) {
+	var payments []model.Payment
+	err := r.db.WithContext(ctx).
+		Where("invoice_id = ?", invoiceID).
+		Order("received_at, id").
+		Find(&payments).Error
+	return payments, err
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Payment, error) {
+	var result model.Payment
+
+	query := r.db.WithContext(ctx).Model(model.Payment{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.PaymentRepository {
+	return &repository{db: tx}
+}
@@ -0,0 +1,189 @@
+package payment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+// errCurrencyMismatch is returned when a payment is in another currency than
+// its invoice.
+var errCurrencyMismatch = errors.New(apperrors.ErrPaymentCurrencyMismatch)
+
+type service struct {
+	repo            interfaces.PaymentRepository
+	invoiceService  interfaces.InvoiceService
+	customerService interfaces.CustomerService
+	events          interfaces.Outbox
+	db              *gorm.DB
+}
+
+func NewService(repo interfaces.PaymentRepository, invoiceService interfaces.InvoiceService, customerService interfaces.CustomerService, appCtx *deps.AppContext) interfaces.PaymentService {
+	return &service{
+		repo:            repo,
+		invoiceService:  invoiceService,
+		customerService: customerService,
+		events:          appCtx.Events,
+		db:              appCtx.DB,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.Payment, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+// Create records a payment against an issued invoice.
+func (s *service) Create(ctx context.Context, orgID uint, DTO *dto.CreatePaymentDTO) (*model.Payment, error) {
+	var payment *model.Payment
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx).(*service)
+
+		err := txService.settle(ctx, DTO.InvoiceID, func(repo interfaces.PaymentRepository, invoice *model.Invoice) error {
+			payment = DTO.ToModel(orgID, invoice)
+			if payment.Currency != invoice.Currency {
+				return errCurrencyMismatch
+			}
+			return repo.Create(ctx, payment)
+		})
+		if err != nil {
+			return err
+		}
+
+		return txService.events.Record(ctx, types.PaymentRecordedEventType, payment.ID, types.PaymentRecordedEvent{
+			PaymentID:  payment.ID,
+			InvoiceID:  payment.InvoiceID,
+			CustomerID: payment.CustomerID,
+			Amount:     payment.Amount,
+			Currency:   payment.Currency,
+			Method:     payment.Method,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The credit amount was set while settling
+	return s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": payment.ID}, nil)
+}
+
+// Update corrects a recorded payment.
+func (s *service) Update(ctx context.Context, ID uint, DTO *dto.UpdatePaymentDTO) (*model.Payment, error) {
+	payment, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.settle(ctx, payment.InvoiceID, func(repo interfaces.PaymentRepository, _ *model.Invoice) error {
+		DTO.ApplyModel(payment)
+		return repo.Update(ctx, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, nil)
+}
+
+// Delete removes a payment recorded by mistake.
+func (s *service) Delete(ctx context.Context, ID uint) error {
+	payment, err := s.repo.FindOneWithFields(ctx, []string{"id", "invoice_id"}, map[string]any{"id": ID}, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.settle(ctx, payment.InvoiceID, func(repo interfaces.PaymentRepository, _ *model.Invoice) error {
+		return repo.Delete(ctx, ID)
+	})
+}
+
+// Settle applies the payments of an invoice again, e.g. after a credit note
+// lowered what is owed on it.
+func (s *service) Settle(ctx context.Context, invoiceID uint) error {
+	return s.settle(ctx, invoiceID, func(interfaces.PaymentRepository, *model.Invoice) error { return nil })
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Payment, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.PaymentService {
+	return &service{
+		repo:            s.repo.WithTx(tx),
+		invoiceService:  s.invoiceService.WithTx(tx),
+		customerService: s.customerService.WithTx(tx),
+		events:          s.events.WithTx(tx),
+		db:              tx,
+	}
+}
+
+// settle runs change against the payments of an invoice, then spreads all of
+// its payments over what is owed on the invoice again. The amount paid and
+// status of the invoice and the credit of the customer are updated to match,
+// all in one transaction that holds the lock on the invoice.
+func (s *service) settle(ctx context.Context, invoiceID uint, change func(repo interfaces.PaymentRepository, invoice *model.Invoice) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		invoiceService := s.invoiceService.WithTx(tx)
+
+		// Payments of the same invoice are settled one at a time, or they
+		// would each add up the payments without the other's
+		invoice, err := invoiceService.FindForUpdate(ctx, invoiceID, []string{"Order"})
+		if err != nil {
+			return err
+		}
+
+		before, err := repo.FindByInvoice(ctx, invoiceID)
+		if err != nil {
+			return err
+		}
+
+		if err := change(repo, invoice); err != nil {
+			return err
+		}
+
+		payments, err := repo.FindByInvoice(ctx, invoiceID)
+		if err != nil {
+			return err
+		}
+
+		paid := money.Zero
+		remaining := invoice.Total.Sub(invoice.AmountCredited)
+		for i := range payments {
+			applied := money.Min(payments[i].Amount, money.Max(remaining, money.Zero))
+			remaining = remaining.Sub(applied)
+			paid = paid.Add(applied)
+
+			credit := payments[i].Amount.Sub(applied)
+			if credit != payments[i].CreditAmount {
+				payments[i].CreditAmount = credit
+				if err := repo.Update(ctx, &payments[i]); err != nil {
+					return err
+				}
+			}
+		}
+
+		delta := totalCredit(payments).Sub(totalCredit(before))
+		if err := s.customerService.WithTx(tx).AdjustCredit(ctx, invoice.Order.CustomerID, delta); err != nil {
+			return err
+		}
+
+		return invoiceService.ApplyPayments(ctx, invoice, paid)
+	})
+}
+
+func totalCredit(payments []model.Payment) money.Money {
+	total := money.Zero
+	for _, p := range payments {
+		total = total.Add(p.CreditAmount)
+	}
+	return total
+}
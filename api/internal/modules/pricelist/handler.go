@@ -0,0 +1,235 @@
+package pricelist
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"name": true}
+
+// For Swagger docs
+type APIResponsePriceList struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    model.PriceList `json:"data"`
+}
+
+type PriceListHandler struct {
+	service interfaces.PriceListService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.PriceListService, appCtx *deps.AppContext) interfaces.PriceListHandler {
+	return &PriceListHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List price lists with filtering and pagination
+// @Description  Returns a paginated list of the price lists of the org
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'name asc'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        name          query     string  false  "Filter by name"
+// @Success      200           {object}  APIResponsePriceList
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /price-lists [get]
+// @Security BearerAuth
+func (h *PriceListHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterPriceList, h.appCtx.Logger)
+		return
+	}
+
+	lists, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterPriceList, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.PriceList]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      lists,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create price list
+// @Description Create a list of negotiated variant prices for customers, assigned to them directly or through their customer groups
+// @Tags price-lists
+// @Accept json
+// @Produce json
+// @Param request body dto.CreatePriceListDTO true "Price list payload"
+// @Success 201 {object} APIResponsePriceList
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /price-lists [post]
+// @Security BearerAuth
+func (h *PriceListHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreatePriceListDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreatePriceList, h.appCtx.Logger)
+		return
+	}
+
+	list := req.ToModel(userFromContext.Org)
+	if err := h.service.Create(ctx, list, req.CustomerIDs, req.GroupIDs); err != nil {
+		if errors.Is(err, errUnknownRefs) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrUnknownPriceListRefs, h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, errInvalidPeriod) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidPriceListPeriod, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreatePriceList, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, list, h.appCtx.Logger)
+}
+
+// Update godoc
+// @Summary Update price list
+// @Description Update a price list by ID. Items, customers and groups are replaced when set. Orders already priced keep their prices.
+// @Tags price-lists
+// @Accept json
+// @Produce json
+// @Param id path int true "Price list ID"
+// @Param request body dto.UpdatePriceListDTO true "Update price list payload"
+// @Success 200 {object} APIResponsePriceList
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /price-lists/{id} [patch]
+// @Security BearerAuth
+func (h *PriceListHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.UpdatePriceListDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	list, err := h.service.Update(ctx, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, errUnknownRefs) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrUnknownPriceListRefs, h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, errInvalidPeriod) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidPriceListPeriod, h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPriceListNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdatePriceList, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, list, h.appCtx.Logger)
+}
+
+// Delete godoc
+// @Summary Delete price list
+// @Description Delete a price list by ID. Orders already priced keep their prices.
+// @Tags price-lists
+// @Produce json
+// @Param id path int true "Price list ID"
+// @Success 200 {integer} response.APIResponseInt
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /price-lists/{id} [delete]
+// @Security BearerAuth
+func (h *PriceListHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	if err := h.service.Delete(ctx, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPriceListNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrDeletePriceList, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, id, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get price list
+// @Description Get a price list by ID with its items, customers and groups
+// @Tags price-lists
+// @Produce json
+// @Param id path int true "Price list ID"
+// @Success 200 {object} APIResponsePriceList
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /price-lists/{id} [get]
+// @Security BearerAuth
+func (h *PriceListHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	list, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Items", "Customers", "Groups"})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPriceListNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindPriceList, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, list, h.appCtx.Logger)
+}
@@ -0,0 +1,140 @@
+package pricelist
+
+import (
+	"context"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.PriceListRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.PriceList, int64, error) {
+	return pagination.Paginate[model.PriceList](ctx, r.db, opts)
+}
+
+// Create inserts a list with its items and links it to its customers and
+// groups, which must exist.
+func (r *repository) Create(ctx context.Context, list *model.PriceList) error {
+	return r.db.WithContext(ctx).Create(list).Error
+}
+
+// Update saves the fields of a list and replaces its customers and groups
+// with list.Customers and list.Groups. Its items are replaced with
+// list.Items unless they are nil.
+func (r *repository) Update(ctx context.Context, list *model.PriceList) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(list).Select("name", "currency", "valid_from", "valid_to", "priority").Updates(list).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(list).Association("Customers").Replace(list.Customers); err != nil {
+			return err
+		}
+		if err := tx.Model(list).Association("Groups").Replace(list.Groups); err != nil {
+			return err
+		}
+
+		if list.Items == nil {
+			return nil
+		}
+		// Hard delete so the variants can be priced again
+		if err := tx.Unscoped().Where("price_list_id = ?", list.ID).Delete(&model.PriceListItem{}).Error; err != nil {
+			return err
+		}
+		for i := range list.Items {
+			list.Items[i].PriceListID = list.ID
+		}
+		if len(list.Items) == 0 {
+			return nil
+		}
+		return tx.Create(&list.Items).Error
+	})
+}
+
+func (r *repository) Delete(ctx context.Context, ID uint) error {
+	res := r.db.WithContext(ctx).Delete(&model.PriceList{}, ID)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.PriceList, error) {
+	var result model.PriceList
+
+	query := r.db.WithContext(ctx).Model(model.PriceList{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FindForCustomer returns the lists assigned to a customer that apply at
+// at in currency, highest priority first, with their items for variantIDs.
+func (r *repository) FindForCustomer(ctx context.Context, customerID uint, currency string, variantIDs []uint, at time.Time) ([]model.PriceList, error) {
+	var lists []model.PriceList
+	err := r.applicable(ctx, currency, variantIDs, at).
+		Joins("JOIN price_list_customers ON price_list_customers.price_list_id = price_lists.id").
+		Where("price_list_customers.customer_id = ?", customerID).
+		Find(&lists).Error
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// FindForGroup is FindForCustomer for the lists assigned to a customer
+// group.
+func (r *repository) FindForGroup(ctx context.Context, groupID uint, currency string, variantIDs []uint, at time.Time) ([]model.PriceList, error) {
+	var lists []model.PriceList
+	err := r.applicable(ctx, currency, variantIDs, at).
+		Joins("JOIN price_list_groups ON price_list_groups.price_list_id = price_lists.id").
+		Where("price_list_groups.customer_group_id = ?", groupID).
+		Find(&lists).Error
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+func (r *repository) applicable(ctx context.Context, currency string, variantIDs []uint, at time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Where("price_lists.currency = ?", currency).
+		Where("price_lists.valid_from IS NULL OR price_lists.valid_from <= ?", at).
+		Where("price_lists.valid_to IS NULL OR price_lists.valid_to > ?", at).
+		Preload("Items", "variant_id IN ?", variantIDs).
+		Order("price_lists.priority DESC, price_lists.id DESC")
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.PriceListRepository {
+	return &repository{db: tx}
+}
@@ -0,0 +1,216 @@
+package pricelist
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/utils/pricing"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+// errUnknownRefs is returned when a list refers to customers, groups or
+// variants the org does not have.
+var errUnknownRefs = errors.New(apperrors.ErrUnknownPriceListRefs)
+
+// errInvalidPeriod is returned when a list stops applying before it starts.
+var errInvalidPeriod = errors.New(apperrors.ErrInvalidPriceListPeriod)
+
+type service struct {
+	repo                 interfaces.PriceListRepository
+	orgService           interfaces.OrgService
+	customerService      interfaces.CustomerService
+	customerGroupService interfaces.CustomerGroupService
+	productService       interfaces.ProductService
+}
+
+func NewService(
+	repo interfaces.PriceListRepository,
+	orgService interfaces.OrgService,
+	customerService interfaces.CustomerService,
+	customerGroupService interfaces.CustomerGroupService,
+	productService interfaces.ProductService,
+) interfaces.PriceListService {
+	return &service{
+		repo:                 repo,
+		orgService:           orgService,
+		customerService:      customerService,
+		customerGroupService: customerGroupService,
+		productService:       productService,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.PriceList, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+// Create creates a list assigned to the customers with customerIDs and the
+// groups with groupIDs. It is in the org base currency unless
+// list.Currency is set.
+func (s *service) Create(ctx context.Context, list *model.PriceList, customerIDs []uint, groupIDs []uint) error {
+	if !validPeriod(list) {
+		return errInvalidPeriod
+	}
+	if list.Currency == "" {
+		org, err := s.orgService.FindOrg(ctx, list.OrgID)
+		if err != nil {
+			return err
+		}
+		list.Currency = org.BaseCurrency
+	}
+
+	if err := s.checkVariants(ctx, list.Items); err != nil {
+		return err
+	}
+	var err error
+	if list.Customers, err = s.findCustomers(ctx, customerIDs); err != nil {
+		return err
+	}
+	if list.Groups, err = s.findGroups(ctx, groupIDs); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, list)
+}
+
+func (s *service) Update(ctx context.Context, ID uint, dto *dto.UpdatePriceListDTO) (*model.PriceList, error) {
+	list, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, []string{"Customers", "Groups"})
+	if err != nil {
+		return nil, err
+	}
+
+	dto.ApplyModel(list)
+	if !validPeriod(list) {
+		return nil, errInvalidPeriod
+	}
+	if err := s.checkVariants(ctx, list.Items); err != nil {
+		return nil, err
+	}
+	if dto.CustomerIDs != nil {
+		if list.Customers, err = s.findCustomers(ctx, dto.CustomerIDs); err != nil {
+			return nil, err
+		}
+	}
+	if dto.GroupIDs != nil {
+		if list.Groups, err = s.findGroups(ctx, dto.GroupIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Update(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *service) Delete(ctx context.Context, ID uint) error {
+	return s.repo.Delete(ctx, ID)
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.PriceList, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+// Prices returns the prices of the variants with variantIDs on the lists
+// that apply to customer for an order in currency placed at at. Lists
+// assigned to the customer win over lists of its group, then the highest
+// priority wins, then the newest list.
+func (s *service) Prices(ctx context.Context, customer *model.Customer, currency string, variantIDs []uint, at time.Time) (pricing.ListPrices, error) {
+	if len(variantIDs) == 0 {
+		return nil, nil
+	}
+
+	lists, err := s.repo.FindForCustomer(ctx, customer.ID, currency, variantIDs, at)
+	if err != nil {
+		return nil, err
+	}
+	if customer.CustomerGroupID != nil {
+		groupLists, err := s.repo.FindForGroup(ctx, *customer.CustomerGroupID, currency, variantIDs, at)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, groupLists...)
+	}
+
+	prices := pricing.ListPrices{}
+	for _, list := range lists {
+		for _, item := range list.Items {
+			if _, ok := prices[item.VariantID]; !ok {
+				prices[item.VariantID] = item
+			}
+		}
+	}
+	return prices, nil
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.PriceListService {
+	return &service{
+		repo:                 s.repo.WithTx(tx),
+		orgService:           s.orgService.WithTx(tx),
+		customerService:      s.customerService.WithTx(tx),
+		customerGroupService: s.customerGroupService.WithTx(tx),
+		productService:       s.productService.WithTx(tx),
+	}
+}
+
+// checkVariants checks the variants of items exist.
+func (s *service) checkVariants(ctx context.Context, items []model.PriceListItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	IDs := make([]uint, len(items))
+	for i, item := range items {
+		IDs[i] = item.VariantID
+	}
+	variants, err := s.productService.FindVariants(ctx, map[string]any{"id": IDs}, nil)
+	if err != nil {
+		return err
+	}
+	if len(variants) != len(IDs) {
+		return errUnknownRefs
+	}
+	return nil
+}
+
+// findCustomers returns the customers with IDs, all of which must exist.
+func (s *service) findCustomers(ctx context.Context, IDs []uint) ([]model.Customer, error) {
+	customers, err := s.customerService.FindByIDs(ctx, IDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(customers) != len(unique(IDs)) {
+		return nil, errUnknownRefs
+	}
+	return customers, nil
+}
+
+// findGroups returns the customer groups with IDs, all of which must exist.
+func (s *service) findGroups(ctx context.Context, IDs []uint) ([]model.CustomerGroup, error) {
+	groups, err := s.customerGroupService.FindByIDs(ctx, IDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) != len(unique(IDs)) {
+		return nil, errUnknownRefs
+	}
+	return groups, nil
+}
+
+// validPeriod reports whether a list stops applying after it starts.
+func validPeriod(list *model.PriceList) bool {
+	return list.ValidFrom == nil || list.ValidTo == nil || list.ValidTo.After(*list.ValidFrom)
+}
+
+func unique(IDs []uint) map[uint]bool {
+	set := make(map[uint]bool, len(IDs))
+	for _, ID := range IDs {
+		set[ID] = true
+	}
+	return set
+}
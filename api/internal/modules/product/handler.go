@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/modules/warehouse"
 	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
 	"github.com/deveasyclick/openb2b/internal/shared/deps"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
@@ -20,6 +21,7 @@ import (
 )
 
 var allowedProductSearchFields = map[string]bool{"name": true, "last_name": true, "phone_number": true, "email": true}
+var allowedStockMovementSearchFields = map[string]bool{"reason": true, "note": true}
 
 // For Swagger docs
 type APIResponseProduct struct {
@@ -33,6 +35,12 @@ type APIResponseVariant struct {
 	Message string        `json:"message"`
 	Data    model.Variant `json:"data"`
 }
+type APIResponseStockMovements struct {
+	Code    int                                          `json:"code"`
+	Message string                                       `json:"message"`
+	Data    response.FilterResponse[model.StockMovement] `json:"data"`
+}
+
 type ProductHandler struct {
 	service interfaces.ProductService
 	appCtx  *deps.AppContext
@@ -51,7 +59,7 @@ func NewHandler(service interfaces.ProductService, appCtx *deps.AppContext) inte
 // @Param        page          query     int     false  "Page number (default: 1)"
 // @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
 // @Param        sort          query     string  false  "Sort by field, e.g. 'created_at desc'"
-// @Param        preloads      query     string  false  "Comma-separated list of relations to preload. relation must start with uppercase"
+// @Param        preloads      query     string  false  "Comma-separated list of relations to preload. relation must start with uppercase. e.g. 'Variants.Locations' for stock per warehouse"
 // @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
 // @Param        name          query     string  false  "Filter by product name"
 // @Param        last_name     query     string  false  "Filter by last name"
@@ -164,6 +172,11 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	// Get existing product
 	existingProduct, err := h.service.FindByID(ctx, uint(id))
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrProductNotFound, h.appCtx.Logger)
+			return
+		}
+
 		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateProduct, h.appCtx.Logger)
 		return
 	}
@@ -214,7 +227,7 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 // Get godoc
 // @Summary Get product
-// @Description Get a product by ID
+// @Description Get a product by ID with its variants and their stock per warehouse
 // @Tags products
 // @Produce json
 // @Param id path int true "Product ID"
@@ -232,7 +245,7 @@ func (h *ProductHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	product, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Variants"})
+	product, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Variants.Locations"})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrProductNotFound, h.appCtx.Logger)
@@ -317,6 +330,7 @@ func (h *ProductHandler) CreateVariant(w http.ResponseWriter, r *http.Request) {
 // @Param request body dto.UpdateVariantDTO true "Update variant payload"
 // @Success 200 {object} APIResponseVariant
 // @Failure 400  {object}  apperrors.APIErrorResponse
+// @Failure 409  {object}  apperrors.APIErrorResponse
 // @Failure 500  {object}  apperrors.APIErrorResponse
 // @Router /products/{productId}/variants/{id} [patch]
 // @Security BearerAuth
@@ -345,16 +359,24 @@ func (h *ProductHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrVariantNotFound, h.appCtx.Logger)
+			return
 		}
 
 		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateVariant, h.appCtx.Logger)
 		return
 	}
 
-	// Update only provided fields
-	req.ApplyModel(existingVariant)
+	// Update only provided fields, a new stock is recorded in the stock ledger
+	if err := h.service.UpdateVariant(ctx, existingVariant, req); err != nil {
+		if errors.Is(err, errStockOutOfSync) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrStockBelowReserved, h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, warehouse.ErrUnknownWarehouse) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrWarehouseNotFound, h.appCtx.Logger)
+			return
+		}
 
-	if err := h.service.UpdateVariant(ctx, existingVariant); err != nil {
 		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateVariant, h.appCtx.Logger)
 		return
 	}
@@ -404,7 +426,7 @@ func (h *ProductHandler) DeleteVariant(w http.ResponseWriter, r *http.Request) {
 
 // Get godoc
 // @Summary Get variant
-// @Description Get a variant by product ID and variant ID
+// @Description Get a variant by product ID and variant ID with its stock per warehouse
 // @Tags variants
 // @Produce json
 // @Param productId path int true "Product ID"
@@ -431,9 +453,84 @@ func (h *ProductHandler) GetVariant(w http.ResponseWriter, r *http.Request) {
 
 	variant, err := h.service.FindVariantByID(ctx, uint(productId), uint(id))
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrVariantNotFound, h.appCtx.Logger)
+			return
+		}
+
 		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindVariant, h.appCtx.Logger)
 		return
 	}
 
 	response.WriteJSONSuccess(w, http.StatusOK, variant, h.appCtx.Logger)
 }
+
+// FilterStockMovements godoc
+// @Summary      List stock movements of a variant
+// @Description  Returns the paginated stock ledger of a variant, newest first by default. Supports filtering and sorting.
+// @Tags         variants
+// @Produce      json
+// @Param        productId     path      int     true   "Product ID"
+// @Param        id            path      int     true   "Variant ID"
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        reason        query     string  false  "Filter by reason (order, adjustment, return, receipt, transfer)"
+// @Param        warehouse_id  query     int     false  "Filter by warehouse"
+// @Param        reference_id  query     int     false  "Filter by reference ID"
+// @Success      200           {object}  APIResponseStockMovements
+// @Failure      400           {object}  apperrors.APIErrorResponse
+// @Failure      404           {object}  apperrors.APIErrorResponse
+// @Failure      500           {object}  apperrors.APIErrorResponse
+// @Router       /products/{productId}/variants/{id}/movements [get]
+// @Security BearerAuth
+func (h *ProductHandler) FilterStockMovements(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	productId, err := strconv.ParseUint(chi.URLParam(r, "productId"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, fmt.Sprintf("%s: productId %d", apperrors.ErrInvalidId, productId), h.appCtx.Logger)
+		return
+	}
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, fmt.Sprintf("%s: variantId %d", apperrors.ErrInvalidId, id), h.appCtx.Logger)
+		return
+	}
+
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedStockMovementSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterStockMovement, h.appCtx.Logger)
+		return
+	}
+
+	// Make sure the variant belongs to the product (and the caller's org)
+	if _, err := h.service.FindVariantByID(ctx, uint(productId), uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrVariantNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterStockMovement, h.appCtx.Logger)
+		return
+	}
+
+	opts.Filters = append(opts.Filters, pagination.FilterCondition{Field: "variant_id", Operator: "=", Value: id})
+	if opts.SortBy == "" {
+		opts.SortBy = "id desc"
+	}
+
+	movements, total, err := h.service.FilterStockMovements(ctx, opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterStockMovement, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.StockMovement]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      movements,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
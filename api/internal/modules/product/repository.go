@@ -3,13 +3,21 @@ package product
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// errStockOutOfSync is returned when a stock update would leave a variant
+// with less stock than it has reserved, or a negative reservation.
+var errStockOutOfSync = errors.New(apperrors.ErrStockOutOfSync)
+
 type repository struct {
 	db *gorm.DB
 }
@@ -18,8 +26,15 @@ func NewRepository(db *gorm.DB) interfaces.ProductRepository {
 	return &repository{db: db}
 }
 
-func (r *repository) Create(ctx context.Context, product *model.Product) error {
-	return r.db.WithContext(ctx).Create(product).Error
+// Create saves product with its variants, whose opening stock is received
+// at warehouseID.
+func (r *repository) Create(ctx context.Context, product *model.Product, warehouseID *uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		return createOpeningMovements(ctx, tx, product.Variants, warehouseID)
+	})
 }
 
 func (r *repository) Update(ctx context.Context, product *model.Product) error {
@@ -50,7 +65,7 @@ func (r *repository) FindByID(ctx context.Context, ID uint) (*model.Product, err
 }
 
 func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.Product, int64, error) {
-	return pagination.Paginate[model.Product](r.db, opts)
+	return pagination.Paginate[model.Product](ctx, r.db, opts)
 }
 
 func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Product, error) {
@@ -76,12 +91,21 @@ func (r *repository) FindOneWithFields(ctx context.Context, fields []string, whe
 
 // Variants
 
-func (r *repository) CreateVariant(ctx context.Context, variant *model.Variant) error {
-	return r.db.WithContext(ctx).Create(variant).Error
+// CreateVariant saves variant, whose opening stock is received at
+// warehouseID.
+func (r *repository) CreateVariant(ctx context.Context, variant *model.Variant, warehouseID *uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(variant).Error; err != nil {
+			return err
+		}
+		return createOpeningMovements(ctx, tx, []model.Variant{*variant}, warehouseID)
+	})
 }
 
+// UpdateVariant saves every field but the stock quantities, which only
+// change through MoveStock, ReserveStock and ReleaseStock.
 func (r *repository) UpdateVariant(ctx context.Context, variant *model.Variant) error {
-	return r.db.WithContext(ctx).Save(variant).Error
+	return r.db.WithContext(ctx).Omit("stock", "reserved", "Locations").Save(variant).Error
 }
 
 func (r *repository) DeleteVariant(ctx context.Context, variantID uint, productID uint) error {
@@ -100,10 +124,12 @@ func (r *repository) DeleteVariant(ctx context.Context, variantID uint, productI
 	return nil
 }
 
+// FindVariantByID returns a variant of a product with its stock levels.
 func (r *repository) FindVariantByID(ctx context.Context, variantID uint, productID uint) (*model.Variant, error) {
 	var variant model.Variant
 	if err := r.db.WithContext(ctx).
 		Where("id = ? AND product_id = ?", variantID, productID).
+		Preload("Locations").
 		First(&variant).Error; err != nil {
 		return nil, err
 	}
@@ -143,3 +169,213 @@ func (r *repository) FindVariants(ctx context.Context, where map[string]any, pre
 	}
 	return result, nil
 }
+
+// ReserveStock holds qty units of a variant for a pending order, at
+// warehouseID when the org keeps stock per warehouse. The updates only match
+// while enough stock is available, so concurrent reservations cannot
+// oversell. It reports false when the variant is short.
+func (r *repository) ReserveStock(ctx context.Context, variantID uint, warehouseID *uint, qty int) (bool, error) {
+	var reserved bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if warehouseID != nil {
+			res := tx.Model(&model.StockLevel{}).
+				Where("warehouse_id = ? AND variant_id = ? AND stock - reserved >= ?", *warehouseID, variantID, qty).
+				UpdateColumn("reserved", gorm.Expr("reserved + ?", qty))
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return nil
+			}
+		}
+
+		res := tx.Model(&model.Variant{}).
+			Where("id = ? AND stock - reserved >= ?", variantID, qty).
+			UpdateColumn("reserved", gorm.Expr("reserved + ?", qty))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			if warehouseID != nil {
+				// The level had it, so the variant disagrees with its levels
+				return fmt.Errorf("variant %d: %w", variantID, errStockOutOfSync)
+			}
+			return nil
+		}
+
+		reserved = true
+		return nil
+	})
+
+	return reserved, err
+}
+
+// ReleaseStock gives back qty reserved units of a variant, at warehouseID
+// when the org keeps stock per warehouse.
+func (r *repository) ReleaseStock(ctx context.Context, variantID uint, warehouseID *uint, qty int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if warehouseID != nil {
+			res := tx.Model(&model.StockLevel{}).
+				Where("warehouse_id = ? AND variant_id = ? AND reserved >= ?", *warehouseID, variantID, qty).
+				UpdateColumn("reserved", gorm.Expr("reserved - ?", qty))
+			if err := stockUpdateResult(res, variantID); err != nil {
+				return err
+			}
+		}
+
+		res := tx.Unscoped().Model(&model.Variant{}).
+			Where("id = ? AND reserved >= ?", variantID, qty).
+			UpdateColumn("reserved", gorm.Expr("reserved - ?", qty))
+
+		return stockUpdateResult(res, variantID)
+	})
+}
+
+// MoveStock applies movement.Delta to the stock on hand and reservedDelta to
+// the reserved quantity of a variant, and of its level at movement.WarehouseID
+// when set, and records the movement in the same transaction. The updates
+// only match while the stock stays above the reserved quantity, otherwise
+// errStockOutOfSync is returned.
+func (r *repository) MoveStock(ctx context.Context, movement *model.StockMovement, reservedDelta int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if movement.WarehouseID != nil {
+			if err := moveLevel(tx, movement, reservedDelta); err != nil {
+				return err
+			}
+		}
+
+		res := tx.Unscoped().Model(&model.Variant{}).
+			Where("id = ? AND stock + ? >= reserved + ? AND reserved + ? >= 0",
+				movement.VariantID, movement.Delta, reservedDelta, reservedDelta).
+			UpdateColumns(map[string]any{
+				"stock":    gorm.Expr("stock + ?", movement.Delta),
+				"reserved": gorm.Expr("reserved + ?", reservedDelta),
+			})
+		if err := stockUpdateResult(res, movement.VariantID); err != nil {
+			return err
+		}
+
+		var variant model.Variant
+		if err := tx.Unscoped().Select("id", "org_id", "stock").First(&variant, movement.VariantID).Error; err != nil {
+			return err
+		}
+
+		movement.OrgID = variant.OrgID
+		movement.Balance = variant.Stock
+		if movement.UserID == nil {
+			movement.UserID = identity.ActorID(ctx)
+		}
+
+		return tx.Create(movement).Error
+	})
+}
+
+// FindStockForUpdate returns the stock on hand of a variant, at warehouseID
+// when set, and locks the rows holding it until the transaction ends. The
+// level is locked before the variant, in the order MoveStock updates them.
+func (r *repository) FindStockForUpdate(ctx context.Context, variantID uint, warehouseID *uint) (int, error) {
+	lock := clause.Locking{Strength: "UPDATE"}
+
+	var levels []model.StockLevel
+	if warehouseID != nil {
+		err := r.db.WithContext(ctx).Clauses(lock).
+			Where("warehouse_id = ? AND variant_id = ?", *warehouseID, variantID).
+			Find(&levels).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var variant model.Variant
+	if err := r.db.WithContext(ctx).Clauses(lock).Select("id", "stock").First(&variant, variantID).Error; err != nil {
+		return 0, err
+	}
+
+	switch {
+	case warehouseID == nil:
+		return variant.Stock, nil
+	case len(levels) == 0:
+		// No stock was ever kept there
+		return 0, nil
+	default:
+		return levels[0].Stock, nil
+	}
+}
+
+// FindStockLevels returns the stock levels matching where, by warehouse.
+func (r *repository) FindStockLevels(ctx context.Context, where map[string]any) ([]model.StockLevel, error) {
+	var levels []model.StockLevel
+	if err := r.db.WithContext(ctx).Where(where).Order("warehouse_id").Find(&levels).Error; err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+func (r *repository) FilterStockMovements(ctx context.Context, opts pagination.Options) ([]model.StockMovement, int64, error) {
+	return pagination.Paginate[model.StockMovement](ctx, r.db, opts)
+}
+
+// createOpeningMovements records the initial stock of new variants as
+// receipts so the ledger of a variant always adds up to its stock. With a
+// warehouse, the stock is kept there.
+func createOpeningMovements(ctx context.Context, tx *gorm.DB, variants []model.Variant, warehouseID *uint) error {
+	for _, v := range variants {
+		if warehouseID != nil {
+			level := model.StockLevel{OrgID: v.OrgID, WarehouseID: *warehouseID, VariantID: v.ID, Stock: v.Stock}
+			if err := tx.Create(&level).Error; err != nil {
+				return err
+			}
+		}
+		if v.Stock == 0 {
+			continue
+		}
+
+		movement := model.StockMovement{
+			OrgID:       v.OrgID,
+			VariantID:   v.ID,
+			WarehouseID: warehouseID,
+			Delta:       v.Stock,
+			Balance:     v.Stock,
+			Reason:      model.StockReasonReceipt,
+			UserID:      identity.ActorID(ctx),
+			Note:        "opening stock",
+		}
+		if err := tx.Create(&movement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func stockUpdateResult(res *gorm.DB, variantID uint) error {
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("variant %d: %w", variantID, errStockOutOfSync)
+	}
+
+	return nil
+}
+
+// moveLevel applies movement to the stock level of its variant at
+// movement.WarehouseID, see MoveStock. Levels are created as stock first
+// arrives at a warehouse.
+func moveLevel(tx *gorm.DB, movement *model.StockMovement, reservedDelta int) error {
+	level := model.StockLevel{WarehouseID: *movement.WarehouseID, VariantID: movement.VariantID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&level).Error; err != nil {
+		return err
+	}
+
+	res := tx.Model(&model.StockLevel{}).
+		Where("warehouse_id = ? AND variant_id = ? AND stock + ? >= reserved + ? AND reserved + ? >= 0",
+			*movement.WarehouseID, movement.VariantID, movement.Delta, reservedDelta, reservedDelta).
+		UpdateColumns(map[string]any{
+			"stock":    gorm.Expr("stock + ?", movement.Delta),
+			"reserved": gorm.Expr("reserved + ?", reservedDelta),
+		})
+
+	return stockUpdateResult(res, movement.VariantID)
+}
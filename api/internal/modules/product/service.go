@@ -2,23 +2,42 @@ package product
 
 import (
 	"context"
+	"sort"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"gorm.io/gorm"
 )
 
 type service struct {
-	repo interfaces.ProductRepository
+	repo       interfaces.ProductRepository
+	warehouses interfaces.WarehouseService
+	events     interfaces.Outbox
+	db         *gorm.DB
 }
 
-func NewService(repo interfaces.ProductRepository) interfaces.ProductService {
-	return &service{repo: repo}
+func NewService(repo interfaces.ProductRepository, warehouses interfaces.WarehouseService, appCtx *deps.AppContext) interfaces.ProductService {
+	return &service{
+		repo:       repo,
+		warehouses: warehouses,
+		events:     appCtx.Events,
+		db:         appCtx.DB,
+	}
 }
 
+// Create saves product with its variants. Their opening stock is kept at
+// the default warehouse.
 func (s *service) Create(ctx context.Context, product *model.Product) error {
-	return s.repo.Create(ctx, product)
+	location, err := s.warehouses.Location(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, product, location)
 }
 
 func (s *service) Update(ctx context.Context, product *model.Product) error {
@@ -53,8 +72,14 @@ func (s *service) FindOneWithFields(ctx context.Context, fields []string, where
 
 // variants
 
+// CreateVariant saves variant. Its opening stock is kept at the default
+// warehouse.
 func (s *service) CreateVariant(ctx context.Context, variant *model.Variant) error {
-	return s.repo.CreateVariant(ctx, variant)
+	location, err := s.warehouses.Location(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return s.repo.CreateVariant(ctx, variant, location)
 }
 
 func (s *service) FindVariantByID(ctx context.Context, productID, variantID uint) (*model.Variant, error) {
@@ -65,8 +90,22 @@ func (s *service) DeleteVariant(ctx context.Context, productID, variantID uint)
 	return s.repo.DeleteVariant(ctx, variantID, productID)
 }
 
-func (s *service) UpdateVariant(ctx context.Context, variant *model.Variant) error {
-	return s.repo.UpdateVariant(ctx, variant)
+// UpdateVariant applies DTO to variant. A new stock is recorded as a stock
+// adjustment in the same transaction, so the variant and its stock are saved
+// together or not at all.
+func (s *service) UpdateVariant(ctx context.Context, variant *model.Variant, DTO dto.UpdateVariantDTO) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txService := s.WithTx(tx).(*service)
+
+		if DTO.Stock != nil {
+			if err := txService.adjustStock(ctx, variant, DTO.WarehouseID, *DTO.Stock, ""); err != nil {
+				return err
+			}
+		}
+
+		DTO.ApplyModel(variant)
+		return txService.repo.UpdateVariant(ctx, variant)
+	})
 }
 
 func (s *service) CheckVariantExists(ctx context.Context, sku string) (bool, error) {
@@ -74,9 +113,376 @@ func (s *service) CheckVariantExists(ctx context.Context, sku string) (bool, err
 }
 
 func (s *service) WithTx(tx *gorm.DB) interfaces.ProductService {
-	return &service{repo: s.repo.WithTx(tx)}
+	return &service{
+		repo:       s.repo.WithTx(tx),
+		warehouses: s.warehouses.WithTx(tx),
+		events:     s.events.WithTx(tx),
+		db:         tx,
+	}
 }
 
 func (s *service) FindVariants(ctx context.Context, where map[string]any, preloads []string) ([]model.Variant, error) {
 	return s.repo.FindVariants(ctx, where, preloads)
 }
+
+// ReserveStock reserves every line or reports all the variants that are
+// short with an *apperrors.InsufficientStockError. It must run inside a
+// transaction so reservations made before a shortage are rolled back.
+func (s *service) ReserveStock(ctx context.Context, lines []model.StockLine) error {
+	located, err := s.locateStockLines(ctx, lines)
+	if err != nil {
+		return err
+	}
+
+	var short []model.StockLine
+	for _, line := range located {
+		ok, err := s.repo.ReserveStock(ctx, line.VariantID, line.WarehouseID, line.Quantity)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			short = append(short, line)
+		}
+	}
+
+	if len(short) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(short))
+	for _, line := range short {
+		ids = append(ids, line.VariantID)
+	}
+
+	variants, err := s.repo.FindVariants(ctx, map[string]any{"id": ids}, nil)
+	if err != nil {
+		return err
+	}
+
+	variantMap := make(map[uint]model.Variant, len(variants))
+	for _, v := range variants {
+		variantMap[v.ID] = v
+	}
+
+	stockErr := &apperrors.InsufficientStockError{}
+	for _, line := range short {
+		v := variantMap[line.VariantID]
+		available, err := s.available(ctx, &v, line.WarehouseID)
+		if err != nil {
+			return err
+		}
+		stockErr.Shortages = append(stockErr.Shortages, apperrors.StockShortage{
+			VariantID: line.VariantID,
+			SKU:       v.SKU,
+			Requested: line.Quantity,
+			Available: available,
+		})
+	}
+
+	return stockErr
+}
+
+// ReserveAvailable reserves as much of line as is available and returns the
+// quantity reserved. It must run inside a transaction.
+func (s *service) ReserveAvailable(ctx context.Context, line model.StockLine) (int, error) {
+	location, err := s.warehouses.Location(ctx, line.WarehouseID)
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		variants, err := s.repo.FindVariants(ctx, map[string]any{"id": line.VariantID}, nil)
+		if err != nil {
+			return 0, err
+		}
+		if len(variants) == 0 {
+			return 0, gorm.ErrRecordNotFound
+		}
+
+		available, err := s.available(ctx, &variants[0], location)
+		if err != nil {
+			return 0, err
+		}
+		qty := min(line.Quantity, available)
+		if qty <= 0 {
+			return 0, nil
+		}
+
+		ok, err := s.repo.ReserveStock(ctx, line.VariantID, location, qty)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return qty, nil
+		}
+		// Another order reserved some of it meanwhile, look again
+	}
+}
+
+// ReleaseStock gives back the reservations held for lines.
+func (s *service) ReleaseStock(ctx context.Context, lines []model.StockLine) error {
+	located, err := s.locateStockLines(ctx, lines)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range located {
+		if err := s.repo.ReleaseStock(ctx, line.VariantID, line.WarehouseID, line.Quantity); err != nil {
+			return err
+		}
+		if err := s.replenished(ctx, line.VariantID, line.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CommitStock deducts the lines reserved by an order from the stock on hand.
+func (s *service) CommitStock(ctx context.Context, orderID uint, lines []model.StockLine) error {
+	return s.takeStock(ctx, model.StockReasonOrder, orderID, lines)
+}
+
+// ReturnStock puts lines previously committed by an order back on hand.
+func (s *service) ReturnStock(ctx context.Context, orderID uint, lines []model.StockLine) error {
+	return s.putStock(ctx, model.StockReasonReturn, orderID, lines)
+}
+
+// ReceiveStock adds lines received on a purchase order to the stock on hand.
+func (s *service) ReceiveStock(ctx context.Context, purchaseOrderID uint, lines []model.StockLine) error {
+	return s.putStock(ctx, model.StockReasonReceipt, purchaseOrderID, lines)
+}
+
+// TransferOut takes lines shipped on a stock transfer off the stock on hand
+// of their warehouse. Only available stock can leave, shortages are
+// reported with an *apperrors.InsufficientStockError. It must run inside a
+// transaction.
+func (s *service) TransferOut(ctx context.Context, transferID uint, lines []model.StockLine) error {
+	if err := s.ReserveStock(ctx, lines); err != nil {
+		return err
+	}
+	return s.takeStock(ctx, model.StockReasonTransfer, transferID, lines)
+}
+
+// TransferIn adds lines that arrived on a stock transfer to the stock on
+// hand of their warehouse.
+func (s *service) TransferIn(ctx context.Context, transferID uint, lines []model.StockLine) error {
+	return s.putStock(ctx, model.StockReasonTransfer, transferID, lines)
+}
+
+// AdjustStock sets the stock on hand of variant at a warehouse, the default
+// one when warehouseID is nil, to stock and records the difference as a
+// manual adjustment. The difference is taken from the stock stored when the
+// adjustment runs, not from variant, which may be stale by then.
+func (s *service) AdjustStock(ctx context.Context, variant *model.Variant, warehouseID *uint, stock int, note string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.WithTx(tx).(*service).adjustStock(ctx, variant, warehouseID, stock, note)
+	})
+}
+
+// adjustStock is AdjustStock. It must run inside a transaction.
+func (s *service) adjustStock(ctx context.Context, variant *model.Variant, warehouseID *uint, stock int, note string) error {
+	location, err := s.warehouses.Location(ctx, warehouseID)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.repo.FindStockForUpdate(ctx, variant.ID, location)
+	if err != nil {
+		return err
+	}
+
+	delta := stock - current
+	if delta == 0 {
+		return nil
+	}
+
+	movement := &model.StockMovement{
+		VariantID:   variant.ID,
+		WarehouseID: location,
+		Delta:       delta,
+		Reason:      model.StockReasonAdjustment,
+		Note:        note,
+	}
+	if err := s.moveStock(ctx, movement, 0); err != nil {
+		return err
+	}
+
+	variant.Stock = movement.Balance
+	if location == nil {
+		return nil
+	}
+	variant.Locations, err = s.repo.FindStockLevels(ctx, map[string]any{"variant_id": variant.ID})
+	return err
+}
+
+// takeStock deducts reserved lines from the stock on hand, recording the
+// movements for reason and referenceID.
+func (s *service) takeStock(ctx context.Context, reason model.StockMovementReason, referenceID uint, lines []model.StockLine) error {
+	located, err := s.locateStockLines(ctx, lines)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range located {
+		movement := &model.StockMovement{
+			VariantID:   line.VariantID,
+			WarehouseID: line.WarehouseID,
+			Delta:       -line.Quantity,
+			Reason:      reason,
+			ReferenceID: &referenceID,
+		}
+		if err := s.repo.MoveStock(ctx, movement, -line.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putStock adds lines to the stock on hand, recording the movements for
+// reason and referenceID.
+func (s *service) putStock(ctx context.Context, reason model.StockMovementReason, referenceID uint, lines []model.StockLine) error {
+	located, err := s.locateStockLines(ctx, lines)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range located {
+		movement := &model.StockMovement{
+			VariantID:   line.VariantID,
+			WarehouseID: line.WarehouseID,
+			Delta:       line.Quantity,
+			Reason:      reason,
+			ReferenceID: &referenceID,
+		}
+		if err := s.moveStock(ctx, movement, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveStock records movement, see ProductRepository.MoveStock. Stock coming
+// in is announced as replenished.
+func (s *service) moveStock(ctx context.Context, movement *model.StockMovement, reservedDelta int) error {
+	if err := s.repo.MoveStock(ctx, movement, reservedDelta); err != nil {
+		return err
+	}
+	if movement.Delta <= 0 {
+		return nil
+	}
+	return s.replenished(ctx, movement.VariantID, movement.Delta)
+}
+
+// replenished records that qty more of a variant became available, on which
+// its backorders are allocated.
+func (s *service) replenished(ctx context.Context, variantID uint, qty int) error {
+	return s.events.Record(ctx, types.StockReplenishedEventType, variantID, types.StockReplenishedEvent{
+		VariantID: variantID,
+		Quantity:  qty,
+	})
+}
+
+func (s *service) FilterStockMovements(ctx context.Context, opts pagination.Options) ([]model.StockMovement, int64, error) {
+	return s.repo.FilterStockMovements(ctx, opts)
+}
+
+// available returns what can still be reserved of variant at warehouseID,
+// or of the variant as a whole for orgs without warehouses.
+func (s *service) available(ctx context.Context, variant *model.Variant, warehouseID *uint) (int, error) {
+	if warehouseID == nil {
+		return variant.Available(), nil
+	}
+
+	level, err := s.stockLevel(ctx, variant.ID, *warehouseID)
+	if err != nil {
+		return 0, err
+	}
+	return level.Available(), nil
+}
+
+// stockLevel returns the stock level of a variant at a warehouse, an empty
+// one when no stock was ever kept there.
+func (s *service) stockLevel(ctx context.Context, variantID uint, warehouseID uint) (*model.StockLevel, error) {
+	levels, err := s.repo.FindStockLevels(ctx, map[string]any{"variant_id": variantID, "warehouse_id": warehouseID})
+	if err != nil {
+		return nil, err
+	}
+	if len(levels) == 0 {
+		return &model.StockLevel{WarehouseID: warehouseID, VariantID: variantID}, nil
+	}
+	return &levels[0], nil
+}
+
+// locateStockLines sets the warehouse every line is kept at, see
+// WarehouseService.Location, and merges the lines with mergeStockLines.
+func (s *service) locateStockLines(ctx context.Context, lines []model.StockLine) ([]model.StockLine, error) {
+	located := make([]model.StockLine, 0, len(lines))
+	locations := make(map[uint]*uint) // by requested warehouse, 0 for the default one
+	for _, line := range lines {
+		var requested uint
+		if line.WarehouseID != nil {
+			requested = *line.WarehouseID
+		}
+
+		location, ok := locations[requested]
+		if !ok {
+			var err error
+			location, err = s.warehouses.Location(ctx, line.WarehouseID)
+			if err != nil {
+				return nil, err
+			}
+			locations[requested] = location
+		}
+
+		line.WarehouseID = location
+		located = append(located, line)
+	}
+
+	return mergeStockLines(located), nil
+}
+
+// stockKey identifies the stock of a variant at a warehouse, 0 for orgs
+// without warehouses.
+type stockKey struct {
+	variantID   uint
+	warehouseID uint
+}
+
+// mergeStockLines sums the quantities per variant and warehouse and sorts
+// the result by variant ID so concurrent transactions always lock rows in
+// the same order.
+func mergeStockLines(lines []model.StockLine) []model.StockLine {
+	totals := make(map[stockKey]int, len(lines))
+	for _, line := range lines {
+		key := stockKey{variantID: line.VariantID}
+		if line.WarehouseID != nil {
+			key.warehouseID = *line.WarehouseID
+		}
+		totals[key] += line.Quantity
+	}
+
+	keys := make([]stockKey, 0, len(totals))
+	for key, qty := range totals {
+		if qty > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].variantID != keys[j].variantID {
+			return keys[i].variantID < keys[j].variantID
+		}
+		return keys[i].warehouseID < keys[j].warehouseID
+	})
+
+	merged := make([]model.StockLine, 0, len(keys))
+	for _, key := range keys {
+		line := model.StockLine{VariantID: key.variantID, Quantity: totals[key]}
+		if key.warehouseID != 0 {
+			warehouseID := key.warehouseID
+			line.WarehouseID = &warehouseID
+		}
+		merged = append(merged, line)
+	}
+
+	return merged
+}
@@ -0,0 +1,294 @@
+package promotion
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"code": true, "name": true, "type": true, "active": true}
+
+var allowedRedemptionSearchFields = map[string]bool{"order_id": true, "customer_id": true}
+
+// For Swagger docs
+type APIResponsePromotion struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    model.Promotion `json:"data"`
+}
+
+type APIResponsePromotionRedemptions struct {
+	Code    int                                                `json:"code"`
+	Message string                                             `json:"message"`
+	Data    response.FilterResponse[model.PromotionRedemption] `json:"data"`
+}
+
+type PromotionHandler struct {
+	service interfaces.PromotionService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.PromotionService, appCtx *deps.AppContext) interfaces.PromotionHandler {
+	return &PromotionHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List promotions with filtering and pagination
+// @Description  Returns a paginated list of the promotions of the org
+// @Tags         promotions
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'code asc'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        code          query     string  false  "Filter by code"
+// @Param        name          query     string  false  "Filter by name"
+// @Param        type          query     string  false  "Filter by type (percentage, fixed, buy_x_get_y)"
+// @Param        active        query     bool    false  "Filter by active"
+// @Success      200           {object}  APIResponsePromotion
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /promotions [get]
+// @Security BearerAuth
+func (h *PromotionHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterPromotion, h.appCtx.Logger)
+		return
+	}
+
+	promotions, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterPromotion, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.Promotion]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      promotions,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create promotion
+// @Description Create a promotion that orders get by its code: a percentage or fixed amount off, or buy X get Y free, optionally limited to some products or categories, a minimum order value, a number of uses and a validity window
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param request body dto.CreatePromotionDTO true "Promotion payload"
+// @Success 201 {object} APIResponsePromotion
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse "Code already used"
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /promotions [post]
+// @Security BearerAuth
+func (h *PromotionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreatePromotionDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreatePromotion, h.appCtx.Logger)
+		return
+	}
+
+	promotion := req.ToModel(userFromContext.Org)
+	if err := h.service.Create(ctx, promotion); err != nil {
+		if errors.Is(err, errCodeExists) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrPromotionCodeExists, h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, errInvalidPeriod) || errors.Is(err, errInvalidPercentage) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreatePromotion, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, promotion, h.appCtx.Logger)
+}
+
+// Update godoc
+// @Summary Update promotion
+// @Description Update a promotion by ID. Its code and type can't change. Orders that used it keep the discount they got.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Param request body dto.UpdatePromotionDTO true "Update promotion payload"
+// @Success 200 {object} APIResponsePromotion
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /promotions/{id} [patch]
+// @Security BearerAuth
+func (h *PromotionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.UpdatePromotionDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	promotion, err := h.service.Update(ctx, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, errInvalidPeriod) || errors.Is(err, errInvalidPercentage) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPromotionNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdatePromotion, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, promotion, h.appCtx.Logger)
+}
+
+// Delete godoc
+// @Summary Delete promotion
+// @Description Delete a promotion by ID. Orders that used it keep the discount they got; its code can't be used again.
+// @Tags promotions
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {integer} response.APIResponseInt
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /promotions/{id} [delete]
+// @Security BearerAuth
+func (h *PromotionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	if err := h.service.Delete(ctx, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPromotionNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrDeletePromotion, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, id, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get promotion
+// @Description Get a promotion by ID
+// @Tags promotions
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} APIResponsePromotion
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /promotions/{id} [get]
+// @Security BearerAuth
+func (h *PromotionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	promotion, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPromotionNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindPromotion, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, promotion, h.appCtx.Logger)
+}
+
+// Redemptions godoc
+// @Summary      List redemptions of a promotion
+// @Description  Returns the paginated orders that used a promotion with the discount they got, newest first by default
+// @Tags         promotions
+// @Produce      json
+// @Param        id            path      int     true   "Promotion ID"
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        order_id      query     int     false  "Filter by order ID"
+// @Param        customer_id   query     int     false  "Filter by customer ID"
+// @Success      200           {object}  APIResponsePromotionRedemptions
+// @Failure      400           {object}  apperrors.APIErrorResponse
+// @Failure      404           {object}  apperrors.APIErrorResponse
+// @Failure      500           {object}  apperrors.APIErrorResponse
+// @Router       /promotions/{id}/redemptions [get]
+// @Security BearerAuth
+func (h *PromotionHandler) Redemptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedRedemptionSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterPromotionRedemption, h.appCtx.Logger)
+		return
+	}
+
+	redemptions, total, err := h.service.Redemptions(ctx, uint(id), opts)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPromotionNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterPromotionRedemption, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.PromotionRedemption]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      redemptions,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
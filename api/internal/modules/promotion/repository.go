@@ -0,0 +1,182 @@
+package promotion
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.PromotionRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.Promotion, int64, error) {
+	return pagination.Paginate[model.Promotion](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, promotion *model.Promotion) error {
+	return r.db.WithContext(ctx).Create(promotion).Error
+}
+
+// Update saves the editable fields of a promotion. TimesUsed is left alone
+// as orders may be using the promotion meanwhile.
+func (r *repository) Update(ctx context.Context, promotion *model.Promotion) error {
+	return r.db.WithContext(ctx).Model(promotion).Select(
+		"name", "amount", "buy_quantity", "get_quantity", "min_order_value",
+		"product_ids", "categories", "usage_limit", "per_customer_limit",
+		"starts_at", "ends_at", "active",
+	).Updates(promotion).Error
+}
+
+func (r *repository) Delete(ctx context.Context, ID uint) error {
+	res := r.db.WithContext(ctx).Delete(&model.Promotion{}, ID)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// FindByID returns a promotion even when it was deleted, for the orders
+// that still use it.
+func (r *repository) FindByID(ctx context.Context, ID uint) (*model.Promotion, error) {
+	var promotion model.Promotion
+	err := r.db.WithContext(ctx).Unscoped().First(&promotion, ID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// FindForUpdate returns a promotion like FindByID and locks it until the
+// transaction ends. It must run inside a transaction.
+func (r *repository) FindForUpdate(ctx context.Context, ID uint) (*model.Promotion, error) {
+	var promotion model.Promotion
+	err := r.db.WithContext(ctx).Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&promotion, ID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Promotion, error) {
+	var result model.Promotion
+
+	query := r.db.WithContext(ctx).Model(model.Promotion{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CodeExists reports whether the org has a promotion with code, deleted or
+// not. Codes are not reused so orders keep pointing at one promotion.
+func (r *repository) CodeExists(ctx context.Context, code string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.Promotion{}).Where("code = ?", code).Count(&count).Error
+	return count > 0, err
+}
+
+// ProductCategories returns the categories of the products with
+// productIDs.
+func (r *repository) ProductCategories(ctx context.Context, productIDs []uint) (map[uint]string, error) {
+	var products []model.Product
+	err := r.db.WithContext(ctx).Select("id", "category").Where("id IN ?", productIDs).Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[uint]string, len(products))
+	for _, p := range products {
+		categories[p.ID] = p.Category
+	}
+	return categories, nil
+}
+
+// Use counts one more use of a promotion, but only while it is under its
+// usage limit. It reports false when the promotion is used up.
+func (r *repository) Use(ctx context.Context, ID uint) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.Promotion{}).
+		Where("id = ? AND (usage_limit IS NULL OR times_used < usage_limit)", ID).
+		UpdateColumn("times_used", gorm.Expr("times_used + 1"))
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// Unuse gives back a use of a promotion.
+func (r *repository) Unuse(ctx context.Context, ID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&model.Promotion{}).
+		Where("id = ? AND times_used > 0", ID).
+		UpdateColumn("times_used", gorm.Expr("times_used - 1")).Error
+}
+
+// FindRedemption returns the redemption of an order.
+func (r *repository) FindRedemption(ctx context.Context, orderID uint) (*model.PromotionRedemption, error) {
+	var redemption model.PromotionRedemption
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&redemption).Error
+	if err != nil {
+		return nil, err
+	}
+	return &redemption, nil
+}
+
+// CountRedemptions counts the orders of a customer, other than the order
+// with exceptOrderID, that used a promotion.
+func (r *repository) CountRedemptions(ctx context.Context, promotionID uint, customerID uint, exceptOrderID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.PromotionRedemption{}).
+		Where("promotion_id = ? AND customer_id = ? AND order_id <> ?", promotionID, customerID, exceptOrderID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *repository) CreateRedemption(ctx context.Context, redemption *model.PromotionRedemption) error {
+	return r.db.WithContext(ctx).Create(redemption).Error
+}
+
+func (r *repository) UpdateRedemption(ctx context.Context, redemption *model.PromotionRedemption) error {
+	return r.db.WithContext(ctx).Save(redemption).Error
+}
+
+// DeleteRedemption permanently removes a redemption so the order can use
+// the promotion again.
+func (r *repository) DeleteRedemption(ctx context.Context, ID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&model.PromotionRedemption{}, ID).Error
+}
+
+func (r *repository) FilterRedemptions(ctx context.Context, opts pagination.Options) ([]model.PromotionRedemption, int64, error) {
+	return pagination.Paginate[model.PromotionRedemption](ctx, r.db, opts)
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.PromotionRepository {
+	return &repository{db: tx}
+}
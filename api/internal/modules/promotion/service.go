@@ -0,0 +1,340 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/utils/ordertotals"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnknown is returned when an order is given a code the org has no
+	// promotion for.
+	ErrUnknown = errors.New(apperrors.ErrUnknownPromotion)
+
+	// ErrInactive is returned when a promotion is switched off or outside
+	// its validity window.
+	ErrInactive = errors.New(apperrors.ErrPromotionInactive)
+
+	// ErrUsedUp is returned when a promotion reached its usage limit, or
+	// its limit for the customer of the order.
+	ErrUsedUp = errors.New(apperrors.ErrPromotionUsedUp)
+
+	// ErrNotApplicable is returned when no item of an order is eligible for
+	// a promotion.
+	ErrNotApplicable = errors.New(apperrors.ErrPromotionNotApplicable)
+
+	// ErrBelowMinOrderValue is returned when an order is below the minimum
+	// order value of a promotion.
+	ErrBelowMinOrderValue = errors.New(apperrors.ErrPromotionMinOrderValue)
+)
+
+// errCodeExists is returned when a promotion is created with a code the org
+// used before.
+var errCodeExists = errors.New(apperrors.ErrPromotionCodeExists)
+
+// errInvalidPeriod is returned when a promotion ends before it starts.
+var errInvalidPeriod = errors.New(apperrors.ErrInvalidPromotionPeriod)
+
+// errInvalidPercentage is returned when a percentage promotion takes off
+// more than 100%.
+var errInvalidPercentage = errors.New(apperrors.ErrInvalidPromotionPercentage)
+
+type service struct {
+	repo interfaces.PromotionRepository
+}
+
+func NewService(repo interfaces.PromotionRepository) interfaces.PromotionService {
+	return &service{
+		repo: repo,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.Promotion, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+func (s *service) Create(ctx context.Context, promotion *model.Promotion) error {
+	if err := validate(promotion); err != nil {
+		return err
+	}
+
+	exists, err := s.repo.CodeExists(ctx, promotion.Code)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errCodeExists
+	}
+
+	return s.repo.Create(ctx, promotion)
+}
+
+func (s *service) Update(ctx context.Context, ID uint, dto *dto.UpdatePromotionDTO) (*model.Promotion, error) {
+	promotion, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dto.ApplyModel(promotion)
+	if err := validate(promotion); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (s *service) Delete(ctx context.Context, ID uint) error {
+	return s.repo.Delete(ctx, ID)
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Promotion, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+// Redemptions returns the orders that used a promotion, newest first by
+// default.
+func (s *service) Redemptions(ctx context.Context, promotionID uint, opts pagination.Options) ([]model.PromotionRedemption, int64, error) {
+	if _, err := s.repo.FindOneWithFields(ctx, []string{"id"}, map[string]any{"id": promotionID}, nil); err != nil {
+		return nil, 0, err
+	}
+
+	opts.Filters = append(opts.Filters, pagination.FilterCondition{Field: "promotion_id", Operator: "=", Value: promotionID})
+	if opts.SortBy == "" {
+		opts.SortBy = "id desc"
+	}
+	return s.repo.FilterRedemptions(ctx, opts)
+}
+
+// Apply gives order the promotion with code and recalculates its totals.
+// A code new to the order must be active, under its usage limits and
+// eligible for the order. A code the order already has is kept with the
+// rule it got, so editing the order does not lose it.
+func (s *service) Apply(ctx context.Context, order *model.Order, code string) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	if order.PromotionID != nil && order.PromotionCode == code {
+		promotion, err := s.repo.FindByID(ctx, *order.PromotionID)
+		if err != nil {
+			return err
+		}
+		if _, err := s.markItems(ctx, order, promotion); err != nil {
+			return err
+		}
+		ordertotals.Calculate(order)
+		return nil
+	}
+
+	promotion, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"code": code}, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUnknown
+		}
+		return err
+	}
+	if !promotion.ActiveAt(time.Now()) {
+		return ErrInactive
+	}
+	if promotion.UsageLimit != nil && promotion.TimesUsed >= *promotion.UsageLimit {
+		return ErrUsedUp
+	}
+	if err := s.checkCustomerLimit(ctx, promotion, order); err != nil {
+		return err
+	}
+
+	eligible, err := s.markItems(ctx, order, promotion)
+	if err != nil {
+		return err
+	}
+	if !eligible {
+		s.Remove(order)
+		return ErrNotApplicable
+	}
+
+	order.PromotionID = &promotion.ID
+	order.PromotionCode = promotion.Code
+	order.Promotion = ruleIn(promotion.PromotionRule, order.ExchangeRate)
+	ordertotals.Calculate(order)
+
+	if order.Subtotal.LessThan(order.Promotion.MinOrderValue) {
+		s.Remove(order)
+		return ErrBelowMinOrderValue
+	}
+	return nil
+}
+
+// Remove takes the promotion off order and recalculates its totals.
+func (s *service) Remove(order *model.Order) {
+	order.PromotionID = nil
+	order.PromotionCode = ""
+	order.Promotion = model.PromotionRule{}
+	for i := range order.Items {
+		order.Items[i].Promoted = false
+	}
+	ordertotals.Calculate(order)
+}
+
+// Redeem records the promotion of a saved order, counting a use of it the
+// first time. The redemption of a promotion the order no longer has is
+// released. It must run inside a transaction.
+func (s *service) Redeem(ctx context.Context, order *model.Order) error {
+	redemption, err := s.repo.FindRedemption(ctx, order.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if redemption != nil && (order.PromotionID == nil || *order.PromotionID != redemption.PromotionID) {
+		if err := s.release(ctx, redemption); err != nil {
+			return err
+		}
+		redemption = nil
+	}
+	if order.PromotionID == nil {
+		return nil
+	}
+
+	if redemption == nil || redemption.CustomerID != order.CustomerID {
+		// Locked, so concurrent orders of a customer are counted one at a
+		// time and can't both take the customer's last use
+		promotion, err := s.repo.FindForUpdate(ctx, *order.PromotionID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkCustomerLimit(ctx, promotion, order); err != nil {
+			return err
+		}
+	}
+
+	if redemption == nil {
+		// Guard against concurrent orders taking the last use
+		used, err := s.repo.Use(ctx, *order.PromotionID)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrUsedUp
+		}
+		redemption = &model.PromotionRedemption{
+			OrgID:       order.OrgID,
+			PromotionID: *order.PromotionID,
+			OrderID:     order.ID,
+		}
+	}
+
+	redemption.CustomerID = order.CustomerID
+	redemption.Amount = order.PromotionDiscount
+	redemption.Currency = order.Currency
+
+	if redemption.ID == 0 {
+		return s.repo.CreateRedemption(ctx, redemption)
+	}
+	return s.repo.UpdateRedemption(ctx, redemption)
+}
+
+// Release gives back the use of a promotion by an order, e.g. when the
+// order is cancelled.
+func (s *service) Release(ctx context.Context, orderID uint) error {
+	redemption, err := s.repo.FindRedemption(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return s.release(ctx, redemption)
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.PromotionService {
+	return &service{
+		repo: s.repo.WithTx(tx),
+	}
+}
+
+func (s *service) release(ctx context.Context, redemption *model.PromotionRedemption) error {
+	if err := s.repo.DeleteRedemption(ctx, redemption.ID); err != nil {
+		return err
+	}
+	return s.repo.Unuse(ctx, redemption.PromotionID)
+}
+
+// checkCustomerLimit checks the customer of order may use promotion once
+// more. promotion must be locked, so no other redemption by the customer is
+// recorded between the count and the redemption of order.
+func (s *service) checkCustomerLimit(ctx context.Context, promotion *model.Promotion, order *model.Order) error {
+	if promotion.PerCustomerLimit == nil {
+		return nil
+	}
+
+	count, err := s.repo.CountRedemptions(ctx, promotion.ID, order.CustomerID, order.ID)
+	if err != nil {
+		return err
+	}
+	if count >= int64(*promotion.PerCustomerLimit) {
+		return ErrUsedUp
+	}
+	return nil
+}
+
+// markItems marks the items of order eligible for promotion and reports
+// whether there is any.
+func (s *service) markItems(ctx context.Context, order *model.Order, promotion *model.Promotion) (bool, error) {
+	var categories map[uint]string
+	if len(promotion.Categories) > 0 {
+		productIDs := make([]uint, len(order.Items))
+		for i, item := range order.Items {
+			productIDs[i] = item.ProductID
+		}
+
+		var err error
+		categories, err = s.repo.ProductCategories(ctx, productIDs)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	eligible := false
+	for i := range order.Items {
+		item := &order.Items[i]
+		item.Promoted = promotion.Eligible(item.ProductID, categories[item.ProductID])
+		eligible = eligible || item.Promoted
+	}
+	return eligible, nil
+}
+
+// ruleIn returns rule with its amounts, which are in the org base currency,
+// converted to a currency worth exchangeRate units of it.
+func ruleIn(rule model.PromotionRule, exchangeRate float64) model.PromotionRule {
+	if exchangeRate <= 0 || exchangeRate == 1 {
+		return rule
+	}
+
+	if rule.Type == model.PromotionFixed {
+		rule.Amount = rule.Amount.DivRate(exchangeRate, money.HalfUp)
+	}
+	rule.MinOrderValue = rule.MinOrderValue.DivRate(exchangeRate, money.HalfUp)
+	return rule
+}
+
+// validate checks a promotion takes off at most 100% and ends after it
+// starts.
+func validate(promotion *model.Promotion) error {
+	if promotion.Type == model.PromotionPercentage && promotion.Amount.GreaterThan(money.FromInt(100)) {
+		return errInvalidPercentage
+	}
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		return errInvalidPeriod
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package purchaseorder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"purchase_order_number": true, "notes": true}
+
+// For Swagger docs
+type APIResponsePurchaseOrder struct {
+	Code    int                 `json:"code"`
+	Message string              `json:"message"`
+	Data    model.PurchaseOrder `json:"data"`
+}
+
+type PurchaseOrderHandler struct {
+	service interfaces.PurchaseOrderService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.PurchaseOrderService, appCtx *deps.AppContext) interfaces.PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List purchase orders with filtering and pagination
+// @Description  Returns a paginated list of purchase orders. Supports filtering, sorting, searching, and preloading.
+// @Tags         purchase-orders
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        preloads      query     string  false  "Comma-separated list of relations to preload. relation must start with uppercase. e.g. 'Lines,Supplier'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        supplier_id   query     int     false  "Filter by supplier"
+// @Param        status        query     string  false  "Filter by status (draft, sent, partially_received, received, closed)"
+// @Success      200           {object}  APIResponsePurchaseOrder
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /purchase-orders [get]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterPurchaseOrder, h.appCtx.Logger)
+		return
+	}
+
+	pos, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterPurchaseOrder, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.PurchaseOrder]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      pos,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create purchase order
+// @Description Create a draft purchase order of variants at cost prices from a supplier
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param request body dto.CreatePurchaseOrderDTO true "Purchase order payload"
+// @Success 201 {object} APIResponsePurchaseOrder
+// @Failure 400 {object} apperrors.APIErrorResponse "Unknown supplier or variants"
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /purchase-orders [post]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreatePurchaseOrderDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreatePurchaseOrder, h.appCtx.Logger)
+		return
+	}
+
+	po := req.ToModel(userFromContext.Org)
+	if err := h.service.Create(ctx, po); err != nil {
+		switch {
+		case errors.Is(err, errUnknownRefs), errors.Is(err, errNoLines):
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreatePurchaseOrder, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, po, h.appCtx.Logger)
+}
+
+// Update godoc
+// @Summary Update purchase order
+// @Description Update the notes, expected date and lines of a draft purchase order
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param request body dto.UpdatePurchaseOrderDTO true "Update purchase order payload"
+// @Success 200 {object} APIResponsePurchaseOrder
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse "Purchase order is not a draft"
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /purchase-orders/{id} [patch]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.UpdatePurchaseOrderDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	po, err := h.service.Update(ctx, uint(id), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPurchaseOrderNotFound, h.appCtx.Logger)
+		case errors.Is(err, errLocked):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrPurchaseOrderLocked, h.appCtx.Logger)
+		case errors.Is(err, errUnknownRefs), errors.Is(err, errNoLines):
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdatePurchaseOrder, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, po, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get purchase order
+// @Description Get a purchase order by ID with its lines and supplier
+// @Tags purchase-orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} APIResponsePurchaseOrder
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /purchase-orders/{id} [get]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	po, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Lines", "Supplier"})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPurchaseOrderNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindPurchaseOrder, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, po, h.appCtx.Logger)
+}
+
+// Send godoc
+// @Summary Send purchase order
+// @Description Mark a draft purchase order as sent and email it to the supplier as a PDF
+// @Tags purchase-orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} APIResponsePurchaseOrder
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /purchase-orders/{id}/send [post]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) Send(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.Send)
+}
+
+// Close godoc
+// @Summary Close purchase order
+// @Description Close a purchase order. Whatever did not arrive yet is no longer expected.
+// @Tags purchase-orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} APIResponsePurchaseOrder
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /purchase-orders/{id}/close [post]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) Close(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.Close)
+}
+
+// Receive godoc
+// @Summary Receive goods
+// @Description Record goods that arrived for a sent purchase order and add them to the stock on hand. Without lines, everything still expected is received.
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param request body dto.ReceivePurchaseOrderDTO true "Goods receipt payload"
+// @Success 200 {object} APIResponsePurchaseOrder
+// @Failure 400 {object} apperrors.APIErrorResponse "Unknown line or more than is still expected"
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse "Purchase order not sent or fully received"
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /purchase-orders/{id}/receive [post]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.ReceivePurchaseOrderDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	po, err := h.service.Receive(ctx, uint(id), req.Lines)
+	if err != nil {
+		var transitionErr *TransitionError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPurchaseOrderNotFound, h.appCtx.Logger)
+		case errors.Is(err, errLineNotFound), errors.Is(err, errQuantityExceeded):
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+		case errors.Is(err, errNotReceivable), errors.Is(err, errNothingToReceive):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, err.Error(), h.appCtx.Logger)
+		case errors.As(err, &transitionErr):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, transitionErr.Error(), h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrReceivePurchaseOrder, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, po, h.appCtx.Logger)
+}
+
+func (h *PurchaseOrderHandler) transition(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, ID uint) (*model.PurchaseOrder, error)) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	po, err := apply(r.Context(), uint(id))
+	if err != nil {
+		var transitionErr *TransitionError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrPurchaseOrderNotFound, h.appCtx.Logger)
+		case errors.As(err, &transitionErr):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, transitionErr.Error(), h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrTransitionPurchaseOrder, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, po, h.appCtx.Logger)
+}
@@ -0,0 +1,55 @@
+package purchaseorder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+)
+
+// transitions is the purchase order lifecycle. A purchase order starts as a
+// draft and is sent to the supplier. Receiving goods moves it to partially
+// received until every line arrived in full. It can be closed at any point,
+// after which nothing more is expected.
+//
+//	draft ──► sent ──► partially_received ──► received
+//	  │         │               │                 │
+//	  └─────────┴───────────────┴──► closed ◄─────┘
+var transitions = map[model.PurchaseOrderStatus][]model.PurchaseOrderStatus{
+	model.PurchaseOrderDraft:             {model.PurchaseOrderSent, model.PurchaseOrderClosed},
+	model.PurchaseOrderSent:              {model.PurchaseOrderPartiallyReceived, model.PurchaseOrderReceived, model.PurchaseOrderClosed},
+	model.PurchaseOrderPartiallyReceived: {model.PurchaseOrderReceived, model.PurchaseOrderClosed},
+	model.PurchaseOrderReceived:          {model.PurchaseOrderClosed},
+}
+
+// errLocked is returned when a purchase order that is no longer a draft
+// would be changed.
+var errLocked = errors.New(apperrors.ErrPurchaseOrderLocked)
+
+// canTransition reports whether a purchase order may move from one status to
+// another.
+func canTransition(from, to model.PurchaseOrderStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isReceivable reports whether goods can be received for a purchase order.
+func isReceivable(status model.PurchaseOrderStatus) bool {
+	return status == model.PurchaseOrderSent || status == model.PurchaseOrderPartiallyReceived
+}
+
+// TransitionError is returned when a status change is not allowed by the
+// purchase order lifecycle.
+type TransitionError struct {
+	From model.PurchaseOrderStatus
+	To   model.PurchaseOrderStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: cannot move purchase order from %s to %s", apperrors.ErrInvalidPurchaseOrderTransition, e.From, e.To)
+}
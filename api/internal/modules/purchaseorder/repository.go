@@ -0,0 +1,108 @@
+package purchaseorder
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.PurchaseOrderRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.PurchaseOrder, int64, error) {
+	return pagination.Paginate[model.PurchaseOrder](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, po *model.PurchaseOrder) error {
+	return r.db.WithContext(ctx).Create(po).Error
+}
+
+// Update saves the notes, expected date and total of a purchase order. Its
+// lines are replaced when set.
+func (r *repository) Update(ctx context.Context, po *model.PurchaseOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(po).Select("notes", "expected_at", "total").Updates(po).Error
+		if err != nil {
+			return err
+		}
+
+		if po.Lines == nil {
+			return nil
+		}
+		if err := tx.Unscoped().Where("purchase_order_id = ?", po.ID).Delete(&model.PurchaseOrderLine{}).Error; err != nil {
+			return err
+		}
+		for i := range po.Lines {
+			po.Lines[i].PurchaseOrderID = po.ID
+		}
+		return tx.Create(&po.Lines).Error
+	})
+}
+
+// UpdateStatus saves the status and timestamps of po, but only while the
+// stored status is still from. It reports false when another request
+// changed the status first.
+func (r *repository) UpdateStatus(ctx context.Context, po *model.PurchaseOrder, from model.PurchaseOrderStatus) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.PurchaseOrder{}).
+		Where("id = ? AND status = ?", po.ID, from).
+		Updates(map[string]any{
+			"status":      po.Status,
+			"sent_at":     po.SentAt,
+			"received_at": po.ReceivedAt,
+			"closed_at":   po.ClosedAt,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// ReceiveLine adds qty to the received quantity of a purchase order line. It
+// reports false when that would receive more than the line ordered.
+func (r *repository) ReceiveLine(ctx context.Context, lineID uint, qty int) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.PurchaseOrderLine{}).
+		Where("id = ? AND received_quantity + ? <= quantity", lineID, qty).
+		Update("received_quantity", gorm.Expr("received_quantity + ?", qty))
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.PurchaseOrder, error) {
+	var result model.PurchaseOrder
+
+	query := r.db.WithContext(ctx).Model(model.PurchaseOrder{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.PurchaseOrderRepository {
+	return &repository{db: tx}
+}
@@ -0,0 +1,379 @@
+package purchaseorder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/modules/warehouse"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/internal/utils/numbergen"
+	"github.com/deveasyclick/openb2b/internal/utils/pdfutil"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+var (
+	errNoLines          = errors.New(apperrors.ErrPurchaseOrderNoLines)
+	errUnknownRefs      = errors.New(apperrors.ErrUnknownPurchaseOrderRefs)
+	errNotReceivable    = errors.New(apperrors.ErrPurchaseOrderNotReceivable)
+	errLineNotFound     = errors.New(apperrors.ErrPurchaseOrderLineNotFound)
+	errQuantityExceeded = errors.New(apperrors.ErrReceiptQuantityExceeded)
+	errNothingToReceive = errors.New(apperrors.ErrNothingToReceive)
+)
+
+type service struct {
+	repo             interfaces.PurchaseOrderRepository
+	supplierService  interfaces.SupplierService
+	productService   interfaces.ProductService
+	warehouseService interfaces.WarehouseService
+	orgService       interfaces.OrgService
+	jobs             interfaces.JobQueue
+	appCtx           *deps.AppContext
+	db               *gorm.DB
+}
+
+func NewService(
+	repo interfaces.PurchaseOrderRepository,
+	supplierService interfaces.SupplierService,
+	productService interfaces.ProductService,
+	warehouseService interfaces.WarehouseService,
+	orgService interfaces.OrgService,
+	appCtx *deps.AppContext,
+) interfaces.PurchaseOrderService {
+	return &service{
+		repo:             repo,
+		supplierService:  supplierService,
+		productService:   productService,
+		warehouseService: warehouseService,
+		orgService:       orgService,
+		jobs:             appCtx.Jobs,
+		appCtx:           appCtx,
+		db:               appCtx.DB,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.PurchaseOrder, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+// Create saves a draft purchase order with the supplier. Its currency
+// defaults to the currency of the supplier, then to the org base currency,
+// and the goods are received at the default warehouse unless it names one.
+func (s *service) Create(ctx context.Context, po *model.PurchaseOrder) error {
+	supplier, err := s.supplierService.FindOneWithFields(ctx, []string{"id", "currency"}, map[string]any{"id": po.SupplierID}, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errUnknownRefs
+		}
+		return err
+	}
+
+	po.WarehouseID, err = s.warehouseService.Location(ctx, po.WarehouseID)
+	if err != nil {
+		if errors.Is(err, warehouse.ErrUnknownWarehouse) {
+			return errUnknownRefs
+		}
+		return err
+	}
+
+	if po.Currency == "" {
+		po.Currency = supplier.Currency
+	}
+	if po.Currency == "" {
+		org, err := s.orgService.FindOrg(ctx, po.OrgID)
+		if err != nil {
+			return err
+		}
+		po.Currency = org.BaseCurrency
+	}
+
+	if err := s.prepareLines(ctx, po); err != nil {
+		return err
+	}
+	po.PurchaseOrderNumber = numbergen.Generate("PO")
+
+	return s.repo.Create(ctx, po)
+}
+
+// Update changes the notes, expected date and lines of a draft purchase
+// order.
+func (s *service) Update(ctx context.Context, ID uint, dto *dto.UpdatePurchaseOrderDTO) (*model.PurchaseOrder, error) {
+	po, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if po.Status != model.PurchaseOrderDraft {
+		return nil, errLocked
+	}
+
+	dto.ApplyModel(po)
+	if po.Lines != nil {
+		if err := s.prepareLines(ctx, po); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.Update(ctx, po); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, []string{"Lines"})
+}
+
+// Send marks a draft purchase order as sent and emails it to the supplier.
+func (s *service) Send(ctx context.Context, ID uint) (*model.PurchaseOrder, error) {
+	return s.transition(ctx, ID, model.PurchaseOrderSent)
+}
+
+// Close marks a purchase order as done. Whatever did not arrive yet is no
+// longer expected.
+func (s *service) Close(ctx context.Context, ID uint) (*model.PurchaseOrder, error) {
+	return s.transition(ctx, ID, model.PurchaseOrderClosed)
+}
+
+func (s *service) transition(ctx context.Context, ID uint, to model.PurchaseOrderStatus) (*model.PurchaseOrder, error) {
+	var po *model.PurchaseOrder
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+
+		var err error
+		po, err = repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, []string{"Lines"})
+		if err != nil {
+			return err
+		}
+
+		from := po.Status
+		if !canTransition(from, to) {
+			return &TransitionError{From: from, To: to}
+		}
+
+		now := time.Now()
+		switch to {
+		case model.PurchaseOrderSent:
+			po.SentAt = &now
+		case model.PurchaseOrderClosed:
+			po.ClosedAt = &now
+		}
+		po.Status = to
+
+		// Guard against a concurrent transition of the same purchase order
+		updated, err := repo.UpdateStatus(ctx, po, from)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return &TransitionError{From: from, To: to}
+		}
+
+		if to != model.PurchaseOrderSent {
+			return nil
+		}
+		return s.jobs.WithTx(tx).Enqueue(ctx, types.PurchaseOrderEmailJobType, types.PurchaseOrderEmailJob{PurchaseOrderID: po.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return po, nil
+}
+
+// Receive records goods that arrived for a sent purchase order and adds
+// them to the stock on hand. Without lines, everything still expected is
+// received. The purchase order is received once every line arrived in full.
+func (s *service) Receive(ctx context.Context, ID uint, lines []dto.ReceivePurchaseOrderLineDTO) (*model.PurchaseOrder, error) {
+	var po *model.PurchaseOrder
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+
+		var err error
+		po, err = repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, []string{"Lines"})
+		if err != nil {
+			return err
+		}
+		from := po.Status
+		if !isReceivable(from) {
+			return errNotReceivable
+		}
+
+		receipt, err := receiptLines(po, lines)
+		if err != nil {
+			return err
+		}
+
+		stock := make([]model.StockLine, 0, len(receipt))
+		for _, r := range receipt {
+			// Guard against receiving the same goods twice concurrently
+			received, err := repo.ReceiveLine(ctx, r.line.ID, r.quantity)
+			if err != nil {
+				return err
+			}
+			if !received {
+				return fmt.Errorf("%w: %s", errQuantityExceeded, r.line.SKU)
+			}
+			stock = append(stock, model.StockLine{VariantID: r.line.VariantID, WarehouseID: po.WarehouseID, Quantity: r.quantity})
+		}
+		if err := s.productService.WithTx(tx).ReceiveStock(ctx, po.ID, stock); err != nil {
+			return err
+		}
+
+		po, err = repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, []string{"Lines"})
+		if err != nil {
+			return err
+		}
+		po.Status = model.PurchaseOrderReceived
+		for _, line := range po.Lines {
+			if line.ReceivedQuantity < line.Quantity {
+				po.Status = model.PurchaseOrderPartiallyReceived
+				break
+			}
+		}
+		now := time.Now()
+		po.ReceivedAt = &now
+
+		updated, err := repo.UpdateStatus(ctx, po, from)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return &TransitionError{From: from, To: po.Status}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return po, nil
+}
+
+// SendEmail emails a purchase order to its supplier. It runs as the job
+// enqueued by Send.
+func (s *service) SendEmail(ctx context.Context, job types.PurchaseOrderEmailJob) error {
+	po, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": job.PurchaseOrderID}, []string{"Lines", "Supplier"})
+	if err != nil {
+		return err
+	}
+
+	pdfBytes, err := pdfutil.GeneratePurchaseOrderPDF(po)
+	if err != nil {
+		return fmt.Errorf("generate purchase order PDF: %w", err)
+	}
+
+	email := po.Supplier.Email
+	if err := s.appCtx.Mailer.SendWithAttachment(email, "Purchase Order "+po.PurchaseOrderNumber, "Please find attached.", "purchase-order.pdf", pdfBytes); err != nil {
+		return fmt.Errorf("send purchase order email: %w", err)
+	}
+
+	s.appCtx.Logger.Info("purchase order email sent", "email", email)
+	return nil
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.PurchaseOrder, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.PurchaseOrderService {
+	return &service{
+		repo:             s.repo.WithTx(tx),
+		supplierService:  s.supplierService.WithTx(tx),
+		productService:   s.productService.WithTx(tx),
+		warehouseService: s.warehouseService.WithTx(tx),
+		orgService:       s.orgService,
+		jobs:             s.jobs.WithTx(tx),
+		appCtx:           s.appCtx,
+		db:               tx,
+	}
+}
+
+// prepareLines checks that the variants of the lines of po exist, copies
+// their SKUs and totals the purchase order.
+func (s *service) prepareLines(ctx context.Context, po *model.PurchaseOrder) error {
+	if len(po.Lines) == 0 {
+		return errNoLines
+	}
+
+	IDs := make([]uint, len(po.Lines))
+	for i, line := range po.Lines {
+		IDs[i] = line.VariantID
+	}
+	variants, err := s.productService.FindVariants(ctx, map[string]any{"id": IDs}, nil)
+	if err != nil {
+		return err
+	}
+	if len(variants) != len(IDs) {
+		return errUnknownRefs
+	}
+
+	skus := make(map[uint]string, len(variants))
+	for _, v := range variants {
+		skus[v.ID] = v.SKU
+	}
+	totals := make([]money.Money, len(po.Lines))
+	for i := range po.Lines {
+		po.Lines[i].SKU = skus[po.Lines[i].VariantID]
+		totals[i] = po.Lines[i].LineTotal
+	}
+	po.Total = money.Sum(totals...)
+
+	return nil
+}
+
+// receiptLine is a quantity of a purchase order line that arrived.
+type receiptLine struct {
+	line     model.PurchaseOrderLine
+	quantity int
+}
+
+// receiptLines returns the requested quantities of the lines of po. Without
+// requested lines, everything still expected is received.
+func receiptLines(po *model.PurchaseOrder, requested []dto.ReceivePurchaseOrderLineDTO) ([]receiptLine, error) {
+	lines := make(map[uint]model.PurchaseOrderLine, len(po.Lines))
+	for _, line := range po.Lines {
+		lines[line.ID] = line
+	}
+
+	quantities := make(map[uint]int)
+	var lineIDs []uint
+	if len(requested) == 0 {
+		for _, line := range po.Lines {
+			if remaining := line.Quantity - line.ReceivedQuantity; remaining > 0 {
+				quantities[line.ID] = remaining
+				lineIDs = append(lineIDs, line.ID)
+			}
+		}
+		if len(lineIDs) == 0 {
+			return nil, errNothingToReceive
+		}
+	} else {
+		for _, r := range requested {
+			if _, ok := lines[r.LineID]; !ok {
+				return nil, fmt.Errorf("%w: %d", errLineNotFound, r.LineID)
+			}
+			if _, seen := quantities[r.LineID]; !seen {
+				lineIDs = append(lineIDs, r.LineID)
+			}
+			quantities[r.LineID] += r.Quantity
+		}
+	}
+
+	receipt := make([]receiptLine, 0, len(lineIDs))
+	for _, ID := range lineIDs {
+		line := lines[ID]
+		quantity := quantities[ID]
+		if line.ReceivedQuantity+quantity > line.Quantity {
+			return nil, fmt.Errorf("%w: %s (ordered %d, received %d, requested %d)",
+				errQuantityExceeded, line.SKU, line.Quantity, line.ReceivedQuantity, quantity)
+		}
+
+		receipt = append(receipt, receiptLine{line: line, quantity: quantity})
+	}
+
+	return receipt, nil
+}
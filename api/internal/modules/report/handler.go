@@ -0,0 +1,92 @@
+package report
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+)
+
+var errInvalidPeriod = errors.New(apperrors.ErrInvalidReportPeriod)
+
+// For Swagger docs
+type APIResponseSalesReport struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Data    types.SalesReport `json:"data"`
+}
+
+type ReportHandler struct {
+	service interfaces.ReportService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.ReportService, appCtx *deps.AppContext) interfaces.ReportHandler {
+	return &ReportHandler{service: service, appCtx: appCtx}
+}
+
+// Sales godoc
+// @Summary      Sales report
+// @Description  Totals the invoices issued in a period, per currency and in the org base currency. Each invoice is converted at the exchange rate of its order. The period defaults to the current month.
+// @Tags         reports
+// @Produce      json
+// @Param        from  query     string  false  "First day of the period, e.g. 2026-01-01"
+// @Param        to    query     string  false  "Last day of the period, included, e.g. 2026-01-31"
+// @Success      200   {object}  APIResponseSalesReport
+// @Failure      400   {object}  apperrors.APIErrorResponse "Invalid period"
+// @Failure      500   {object}  apperrors.APIErrorResponse
+// @Router       /reports/sales [get]
+// @Security BearerAuth
+func (h *ReportHandler) Sales(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	from, to, err := parsePeriod(r, time.Now().UTC())
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidReportPeriod, h.appCtx.Logger)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrSalesReport, h.appCtx.Logger)
+		return
+	}
+
+	report, err := h.service.Sales(ctx, userFromContext.Org, from, to)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrSalesReport, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, report, h.appCtx.Logger)
+}
+
+// parsePeriod reads the from and to dates of the request, both included,
+// and returns the period as [from, to+1 day).
+func parsePeriod(r *http.Request, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := today.AddDate(0, 0, 1-today.Day())
+	to := today
+
+	var err error
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = time.Parse(time.DateOnly, v); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = time.Parse(time.DateOnly, v); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errInvalidPeriod
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
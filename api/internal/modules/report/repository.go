@@ -0,0 +1,44 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+// issued are the statuses of invoices that count as sales
+var issued = []model.InvoiceStatus{
+	model.InvoiceStatusIssued,
+	model.InvoiceStatusPartiallyPaid,
+	model.InvoiceStatusOverdue,
+	model.InvoiceStatusPaid,
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.ReportRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+// FindIssuedInvoices returns the amounts of the invoices issued from from
+// until to, voided ones excluded.
+func (r *repository) FindIssuedInvoices(ctx context.Context, from time.Time, to time.Time) ([]model.Invoice, error) {
+	var invoices []model.Invoice
+	err := r.db.WithContext(ctx).
+		Select("id", "currency", "exchange_rate", "total", "amount_paid", "amount_credited", "amount_due").
+		Where("status IN ? AND issued_at >= ? AND issued_at < ?", issued, from, to).
+		Order("currency").
+		Find(&invoices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
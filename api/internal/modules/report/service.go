@@ -0,0 +1,85 @@
+package report
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+)
+
+type service struct {
+	repo       interfaces.ReportRepository
+	orgService interfaces.OrgService
+}
+
+func NewService(repo interfaces.ReportRepository, orgService interfaces.OrgService) interfaces.ReportService {
+	return &service{
+		repo:       repo,
+		orgService: orgService,
+	}
+}
+
+// Sales totals the invoices of the org issued from from until to, by
+// currency and in the org base currency.
+func (s *service) Sales(ctx context.Context, orgID uint, from time.Time, to time.Time) (*types.SalesReport, error) {
+	org, err := s.orgService.FindOrg(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := s.repo.FindIssuedInvoices(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &types.SalesReport{
+		BaseCurrency: org.BaseCurrency,
+		From:         from,
+		To:           to,
+		Currencies:   []types.CurrencySales{},
+	}
+	byCurrency := map[string]*types.CurrencySales{}
+	for _, invoice := range invoices {
+		sales, ok := byCurrency[invoice.Currency]
+		if !ok {
+			sales = &types.CurrencySales{Currency: invoice.Currency}
+			byCurrency[invoice.Currency] = sales
+		}
+
+		sales.Invoices++
+		sales.Total = sales.Total.Add(invoice.Total)
+		sales.AmountPaid = sales.AmountPaid.Add(invoice.AmountPaid)
+		sales.AmountCredited = sales.AmountCredited.Add(invoice.AmountCredited)
+		sales.AmountDue = sales.AmountDue.Add(invoice.AmountDue)
+
+		total := toBase(invoice.Total, invoice.ExchangeRate)
+		sales.BaseTotal = sales.BaseTotal.Add(total)
+
+		report.Invoices++
+		report.Total = report.Total.Add(total)
+		report.AmountPaid = report.AmountPaid.Add(toBase(invoice.AmountPaid, invoice.ExchangeRate))
+		report.AmountCredited = report.AmountCredited.Add(toBase(invoice.AmountCredited, invoice.ExchangeRate))
+		report.AmountDue = report.AmountDue.Add(toBase(invoice.AmountDue, invoice.ExchangeRate))
+	}
+
+	for _, sales := range byCurrency {
+		report.Currencies = append(report.Currencies, *sales)
+	}
+	sort.Slice(report.Currencies, func(i, j int) bool {
+		return report.Currencies[i].Currency < report.Currencies[j].Currency
+	})
+
+	return report, nil
+}
+
+// toBase converts amount to the base currency at rate, the base currency
+// units one unit of its currency is worth.
+func toBase(amount money.Money, rate float64) money.Money {
+	if rate <= 0 {
+		return amount
+	}
+	return amount.MulRate(rate, money.HalfUp)
+}
@@ -0,0 +1,283 @@
+package shipment
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/modules/order"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"shipment_number": true, "carrier": true, "tracking_number": true}
+
+// For Swagger docs
+type APIResponseShipment struct {
+	Code    int            `json:"code"`
+	Message string         `json:"message"`
+	Data    model.Shipment `json:"data"`
+}
+
+type ShipmentHandler struct {
+	service interfaces.ShipmentService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.ShipmentService, appCtx *deps.AppContext) interfaces.ShipmentHandler {
+	return &ShipmentHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List shipments with filtering and pagination
+// @Description  Returns a paginated list of shipments. Supports filtering, sorting, searching, and preloading.
+// @Tags         shipments
+// @Accept       json
+// @Produce      json
+// @Param        page            query     int     false  "Page number (default: 1)"
+// @Param        limit           query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort            query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        preloads        query     string  false  "Comma-separated list of relations to preload. relation must start with uppercase. e.g. 'Lines,Order'"
+// @Param        search_fields   query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        order_id        query     int     false  "Filter by order"
+// @Param        status          query     string  false  "Filter by status (pending, shipped, delivered, cancelled)"
+// @Param        tracking_number query     string  false  "Filter by tracking number"
+// @Success      200             {object}  APIResponseShipment
+// @Failure      400             {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500             {object}  apperrors.APIError "Internal server error"
+// @Router       /shipments [get]
+// @Security BearerAuth
+func (h *ShipmentHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterShipment, h.appCtx.Logger)
+		return
+	}
+
+	shipments, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterShipment, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.Shipment]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      shipments,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create shipment
+// @Description Set aside quantities of the items of an approved order for a pending shipment. Without lines, everything not on another shipment yet is shipped.
+// @Tags shipments
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateShipmentDTO true "Shipment payload"
+// @Success 201 {object} APIResponseShipment
+// @Failure 400 {object} apperrors.APIErrorResponse "Unknown order item or more than is left to ship"
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse "Order not approved or fully shipped"
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /shipments [post]
+// @Security BearerAuth
+func (h *ShipmentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreateShipmentDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateShipment, h.appCtx.Logger)
+		return
+	}
+
+	shipment := req.ToModel(userFromContext.Org)
+	if err := h.service.Create(ctx, shipment, req.Lines); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrOrderNotFound, h.appCtx.Logger)
+		case errors.Is(err, errOrderItemNotFound), errors.Is(err, errQuantityExceeded):
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+		case errors.Is(err, errOrderNotApproved), errors.Is(err, errNothingToShip):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, err.Error(), h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateShipment, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, shipment, h.appCtx.Logger)
+}
+
+// Update godoc
+// @Summary Update shipment
+// @Description Update the carrier and tracking number of a shipment that is not delivered or cancelled
+// @Tags shipments
+// @Accept json
+// @Produce json
+// @Param id path int true "Shipment ID"
+// @Param request body dto.UpdateShipmentDTO true "Update shipment payload"
+// @Success 200 {object} APIResponseShipment
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /shipments/{id} [patch]
+// @Security BearerAuth
+func (h *ShipmentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.UpdateShipmentDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	shipment, err := h.service.Update(ctx, uint(id), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrShipmentNotFound, h.appCtx.Logger)
+		case errors.Is(err, errLocked):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrShipmentLocked, h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateShipment, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, shipment, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get shipment
+// @Description Get a shipment by ID with its lines
+// @Tags shipments
+// @Produce json
+// @Param id path int true "Shipment ID"
+// @Success 200 {object} APIResponseShipment
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /shipments/{id} [get]
+// @Security BearerAuth
+func (h *ShipmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	shipment, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Lines"})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrShipmentNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindShipment, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, shipment, h.appCtx.Logger)
+}
+
+// Ship godoc
+// @Summary Ship shipment
+// @Description Hand a pending shipment to the carrier. Its quantities count as shipped on the order.
+// @Tags shipments
+// @Produce json
+// @Param id path int true "Shipment ID"
+// @Success 200 {object} APIResponseShipment
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /shipments/{id}/ship [post]
+// @Security BearerAuth
+func (h *ShipmentHandler) Ship(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, model.ShipmentShipped)
+}
+
+// Deliver godoc
+// @Summary Deliver shipment
+// @Description Mark a shipped shipment as delivered. Its quantities count as delivered on the order, which is delivered once all of its items are.
+// @Tags shipments
+// @Produce json
+// @Param id path int true "Shipment ID"
+// @Success 200 {object} APIResponseShipment
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /shipments/{id}/deliver [post]
+// @Security BearerAuth
+func (h *ShipmentHandler) Deliver(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, model.ShipmentDelivered)
+}
+
+// Cancel godoc
+// @Summary Cancel shipment
+// @Description Cancel a pending shipment. Its quantities can be shipped again.
+// @Tags shipments
+// @Produce json
+// @Param id path int true "Shipment ID"
+// @Success 200 {object} APIResponseShipment
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /shipments/{id}/cancel [post]
+// @Security BearerAuth
+func (h *ShipmentHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, model.ShipmentCancelled)
+}
+
+func (h *ShipmentHandler) transition(w http.ResponseWriter, r *http.Request, to model.ShipmentStatus) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	shipment, err := h.service.Transition(r.Context(), uint(id), to)
+	if err != nil {
+		var transitionErr *TransitionError
+		var orderTransitionErr *order.TransitionError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrShipmentNotFound, h.appCtx.Logger)
+		case errors.As(err, &transitionErr):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, transitionErr.Error(), h.appCtx.Logger)
+		case errors.As(err, &orderTransitionErr):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, orderTransitionErr.Error(), h.appCtx.Logger)
+		case errors.Is(err, errOrderNotApproved):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, err.Error(), h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrTransitionShipment, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, shipment, h.appCtx.Logger)
+}
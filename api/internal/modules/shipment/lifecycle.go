@@ -0,0 +1,53 @@
+package shipment
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+)
+
+// transitions is the shipment lifecycle. A shipment starts pending, ships
+// and is delivered. Only pending shipments can be cancelled; delivered and
+// cancelled shipments are final.
+//
+//	pending ──► shipped ──► delivered
+//	   │
+//	   └──────► cancelled
+var transitions = map[model.ShipmentStatus][]model.ShipmentStatus{
+	model.ShipmentPending: {model.ShipmentShipped, model.ShipmentCancelled},
+	model.ShipmentShipped: {model.ShipmentDelivered},
+}
+
+// errLocked is returned when a delivered or cancelled shipment would be
+// changed.
+var errLocked = errors.New(apperrors.ErrShipmentLocked)
+
+// canTransition reports whether a shipment may move from one status to
+// another.
+func canTransition(from, to model.ShipmentStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isEditable reports whether the carrier and tracking number of a shipment
+// can still be changed.
+func isEditable(status model.ShipmentStatus) bool {
+	return status == model.ShipmentPending || status == model.ShipmentShipped
+}
+
+// TransitionError is returned when a status change is not allowed by the
+// shipment lifecycle.
+type TransitionError struct {
+	From model.ShipmentStatus
+	To   model.ShipmentStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: cannot move shipment from %s to %s", apperrors.ErrInvalidShipmentTransition, e.From, e.To)
+}
@@ -0,0 +1,102 @@
+package shipment
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.ShipmentRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.Shipment, int64, error) {
+	return pagination.Paginate[model.Shipment](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, shipment *model.Shipment) error {
+	return r.db.WithContext(ctx).Create(shipment).Error
+}
+
+// Update saves the carrier and tracking number of a shipment.
+func (r *repository) Update(ctx context.Context, shipment *model.Shipment) error {
+	return r.db.WithContext(ctx).Model(shipment).Select("carrier", "tracking_number").Updates(shipment).Error
+}
+
+// UpdateStatus saves the status and timestamps of shipment, but only while
+// the stored status is still from. It reports false when another request
+// changed the status first.
+func (r *repository) UpdateStatus(ctx context.Context, shipment *model.Shipment, from model.ShipmentStatus) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.Shipment{}).
+		Where("id = ? AND status = ?", shipment.ID, from).
+		Updates(map[string]any{
+			"status":       shipment.Status,
+			"shipped_at":   shipment.ShippedAt,
+			"delivered_at": shipment.DeliveredAt,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// ShippingQuantities returns the quantities of the items of an order that
+// are on shipments which were not cancelled, by order item.
+func (r *repository) ShippingQuantities(ctx context.Context, orderID uint) (map[uint]int, error) {
+	var rows []struct {
+		OrderItemID uint
+		Quantity    int
+	}
+
+	err := r.db.WithContext(ctx).Model(&model.ShipmentLine{}).
+		Select("shipment_lines.order_item_id, SUM(shipment_lines.quantity) AS quantity").
+		Joins("JOIN shipments ON shipments.id = shipment_lines.shipment_id AND shipments.deleted_at IS NULL").
+		Where("shipments.order_id = ? AND shipments.status <> ?", orderID, model.ShipmentCancelled).
+		Group("shipment_lines.order_item_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		quantities[row.OrderItemID] = row.Quantity
+	}
+	return quantities, nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Shipment, error) {
+	var result model.Shipment
+
+	query := r.db.WithContext(ctx).Model(model.Shipment{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.ShipmentRepository {
+	return &repository{db: tx}
+}
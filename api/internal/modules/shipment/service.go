@@ -0,0 +1,215 @@
+package shipment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/utils/numbergen"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+var (
+	errOrderNotApproved  = errors.New(apperrors.ErrShipmentOrderNotApproved)
+	errOrderItemNotFound = errors.New(apperrors.ErrShipmentOrderItemNotFound)
+	errQuantityExceeded  = errors.New(apperrors.ErrShipmentQuantityExceeded)
+	errNothingToShip     = errors.New(apperrors.ErrNothingToShip)
+)
+
+type service struct {
+	repo         interfaces.ShipmentRepository
+	orderService interfaces.OrderService
+	appCtx       *deps.AppContext
+}
+
+func NewService(repo interfaces.ShipmentRepository, orderService interfaces.OrderService, appCtx *deps.AppContext) interfaces.ShipmentService {
+	return &service{
+		repo:         repo,
+		orderService: orderService,
+		appCtx:       appCtx,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.Shipment, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+// Create sets aside the requested quantities of the items of an approved
+// order for a new pending shipment. Only what the items are allocated of
+// the stock can ship, backorders wait. Without lines, everything not on
+// another shipment yet is shipped.
+func (s *service) Create(ctx context.Context, shipment *model.Shipment, lines []dto.CreateShipmentLineDTO) error {
+	return s.appCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+
+		order, err := s.orderService.WithTx(tx).FindOneWithFields(ctx, nil, map[string]any{"id": shipment.OrderID}, []string{"Items"})
+		if err != nil {
+			return err
+		}
+		if order.Status != model.OrderStatusApproved {
+			return errOrderNotApproved
+		}
+
+		onShipments, err := repo.ShippingQuantities(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		shipment.Lines, err = shipmentLines(order, onShipments, lines)
+		if err != nil {
+			return err
+		}
+		shipment.WarehouseID = order.WarehouseID
+		shipment.ShipmentNumber = numbergen.Generate("SHP")
+
+		return repo.Create(ctx, shipment)
+	})
+}
+
+// Update changes the carrier and tracking number of a shipment that is not
+// delivered or cancelled.
+func (s *service) Update(ctx context.Context, ID uint, dto *dto.UpdateShipmentDTO) (*model.Shipment, error) {
+	shipment, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, []string{"Lines"})
+	if err != nil {
+		return nil, err
+	}
+	if !isEditable(shipment.Status) {
+		return nil, errLocked
+	}
+
+	dto.ApplyModel(shipment)
+	if err := s.repo.Update(ctx, shipment); err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// Transition moves a shipment to status to when the shipment lifecycle
+// allows it. Shipping and delivering count the quantities of the shipment
+// on its order, whose delivery status follows.
+func (s *service) Transition(ctx context.Context, ID uint, to model.ShipmentStatus) (*model.Shipment, error) {
+	var shipment *model.Shipment
+	err := s.appCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		orderService := s.orderService.WithTx(tx)
+
+		var err error
+		shipment, err = repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, []string{"Lines"})
+		if err != nil {
+			return err
+		}
+
+		from := shipment.Status
+		if !canTransition(from, to) {
+			return &TransitionError{From: from, To: to}
+		}
+
+		now := time.Now()
+		switch to {
+		case model.ShipmentShipped:
+			order, err := orderService.FindOneWithFields(ctx, []string{"id", "status"}, map[string]any{"id": shipment.OrderID}, nil)
+			if err != nil {
+				return err
+			}
+			if order.Status != model.OrderStatusApproved {
+				return errOrderNotApproved
+			}
+			shipment.ShippedAt = &now
+		case model.ShipmentDelivered:
+			shipment.DeliveredAt = &now
+		}
+		shipment.Status = to
+
+		// Guard against a concurrent transition of the same shipment
+		updated, err := repo.UpdateStatus(ctx, shipment, from)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return &TransitionError{From: from, To: to}
+		}
+
+		if to == model.ShipmentCancelled {
+			return nil
+		}
+		return orderService.ApplyShipment(ctx, shipment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shipment, nil
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Shipment, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.ShipmentService {
+	return &service{
+		repo:         s.repo.WithTx(tx),
+		orderService: s.orderService.WithTx(tx),
+		appCtx:       s.appCtx,
+	}
+}
+
+// shipmentLines builds the lines of a shipment of order for the requested
+// quantities of its items. onShipments holds what other shipments hold per
+// order item. Without requested lines, everything left is shipped.
+func shipmentLines(order *model.Order, onShipments map[uint]int, requested []dto.CreateShipmentLineDTO) ([]model.ShipmentLine, error) {
+	items := make(map[uint]model.OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		items[item.ID] = item
+	}
+
+	quantities := make(map[uint]int)
+	var itemIDs []uint
+	if len(requested) == 0 {
+		for _, item := range order.Items {
+			if remaining := item.AllocatedQuantity - onShipments[item.ID]; remaining > 0 {
+				quantities[item.ID] = remaining
+				itemIDs = append(itemIDs, item.ID)
+			}
+		}
+		if len(itemIDs) == 0 {
+			return nil, errNothingToShip
+		}
+	} else {
+		for _, r := range requested {
+			if _, ok := items[r.OrderItemID]; !ok {
+				return nil, fmt.Errorf("%w: %d", errOrderItemNotFound, r.OrderItemID)
+			}
+			if _, seen := quantities[r.OrderItemID]; !seen {
+				itemIDs = append(itemIDs, r.OrderItemID)
+			}
+			quantities[r.OrderItemID] += r.Quantity
+		}
+	}
+
+	lines := make([]model.ShipmentLine, 0, len(itemIDs))
+	for _, ID := range itemIDs {
+		item := items[ID]
+		quantity := quantities[ID]
+		if onShipments[ID]+quantity > item.AllocatedQuantity {
+			return nil, fmt.Errorf("%w: %s (allocated %d, on shipments %d, requested %d)",
+				errQuantityExceeded, item.SKU, item.AllocatedQuantity, onShipments[ID], quantity)
+		}
+
+		lines = append(lines, model.ShipmentLine{
+			OrgID:       order.OrgID,
+			OrderItemID: item.ID,
+			VariantID:   item.VariantID,
+			SKU:         item.SKU,
+			Quantity:    quantity,
+		})
+	}
+
+	return lines, nil
+}
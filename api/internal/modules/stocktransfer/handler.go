@@ -0,0 +1,230 @@
+package stocktransfer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"transfer_number": true, "notes": true}
+
+// For Swagger docs
+type APIResponseStockTransfer struct {
+	Code    int                 `json:"code"`
+	Message string              `json:"message"`
+	Data    model.StockTransfer `json:"data"`
+}
+
+type StockTransferHandler struct {
+	service interfaces.StockTransferService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.StockTransferService, appCtx *deps.AppContext) interfaces.StockTransferHandler {
+	return &StockTransferHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List stock transfers with filtering and pagination
+// @Description  Returns a paginated list of stock transfers. Supports filtering, sorting, searching, and preloading.
+// @Tags         stock-transfers
+// @Accept       json
+// @Produce      json
+// @Param        page              query     int     false  "Page number (default: 1)"
+// @Param        limit             query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort              query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        preloads          query     string  false  "Comma-separated list of relations to preload. relation must start with uppercase. e.g. 'Lines,FromWarehouse,ToWarehouse'"
+// @Param        search_fields     query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        from_warehouse_id query     int     false  "Filter by source warehouse"
+// @Param        to_warehouse_id   query     int     false  "Filter by destination warehouse"
+// @Param        status            query     string  false  "Filter by status (pending, in_transit, received, cancelled)"
+// @Success      200               {object}  APIResponseStockTransfer
+// @Failure      400               {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500               {object}  apperrors.APIError "Internal server error"
+// @Router       /stock-transfers [get]
+// @Security BearerAuth
+func (h *StockTransferHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterStockTransfer, h.appCtx.Logger)
+		return
+	}
+
+	transfers, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterStockTransfer, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.StockTransfer]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      transfers,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create stock transfer
+// @Description Create a pending transfer of variants from one warehouse to another. No stock moves until it is shipped.
+// @Tags stock-transfers
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateStockTransferDTO true "Stock transfer payload"
+// @Success 201 {object} APIResponseStockTransfer
+// @Failure 400 {object} apperrors.APIErrorResponse "Unknown warehouses or variants"
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /stock-transfers [post]
+// @Security BearerAuth
+func (h *StockTransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreateStockTransferDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateStockTransfer, h.appCtx.Logger)
+		return
+	}
+
+	transfer := req.ToModel(userFromContext.Org)
+	if err := h.service.Create(ctx, transfer); err != nil {
+		if errors.Is(err, errUnknownRefs) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, err.Error(), h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateStockTransfer, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, transfer, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get stock transfer
+// @Description Get a stock transfer by ID with its lines and warehouses
+// @Tags stock-transfers
+// @Produce json
+// @Param id path int true "Stock transfer ID"
+// @Success 200 {object} APIResponseStockTransfer
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /stock-transfers/{id} [get]
+// @Security BearerAuth
+func (h *StockTransferHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	transfer, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Lines", "FromWarehouse", "ToWarehouse"})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrStockTransferNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindStockTransfer, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, transfer, h.appCtx.Logger)
+}
+
+// Ship godoc
+// @Summary Ship stock transfer
+// @Description Ship a pending stock transfer. Its lines are taken off the stock on hand of the source warehouse, which must have them available.
+// @Tags stock-transfers
+// @Produce json
+// @Param id path int true "Stock transfer ID"
+// @Success 200 {object} APIResponseStockTransfer
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse "Not pending or not enough stock available"
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /stock-transfers/{id}/ship [post]
+// @Security BearerAuth
+func (h *StockTransferHandler) Ship(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.Ship)
+}
+
+// Receive godoc
+// @Summary Receive stock transfer
+// @Description Receive a stock transfer in transit. Its lines are put on hand at the destination warehouse.
+// @Tags stock-transfers
+// @Produce json
+// @Param id path int true "Stock transfer ID"
+// @Success 200 {object} APIResponseStockTransfer
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /stock-transfers/{id}/receive [post]
+// @Security BearerAuth
+func (h *StockTransferHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.Receive)
+}
+
+// Cancel godoc
+// @Summary Cancel stock transfer
+// @Description Cancel a stock transfer that did not ship yet
+// @Tags stock-transfers
+// @Produce json
+// @Param id path int true "Stock transfer ID"
+// @Success 200 {object} APIResponseStockTransfer
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /stock-transfers/{id}/cancel [post]
+// @Security BearerAuth
+func (h *StockTransferHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.service.Cancel)
+}
+
+func (h *StockTransferHandler) transition(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, ID uint) (*model.StockTransfer, error)) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	transfer, err := apply(r.Context(), uint(id))
+	if err != nil {
+		var transitionErr *TransitionError
+		var stockErr *apperrors.InsufficientStockError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrStockTransferNotFound, h.appCtx.Logger)
+		case errors.As(err, &transitionErr):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, transitionErr.Error(), h.appCtx.Logger)
+		case errors.As(err, &stockErr):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, stockErr.Error(), h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrTransitionStockTransfer, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, transfer, h.appCtx.Logger)
+}
@@ -0,0 +1,42 @@
+package stocktransfer
+
+import (
+	"fmt"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+)
+
+// transitions is the stock transfer lifecycle. Shipping takes the stock off
+// the source warehouse and receiving puts it on hand at the destination.
+// Only transfers that did not ship yet can be cancelled.
+//
+//	pending ──► in_transit ──► received
+//	   │
+//	   └──► cancelled
+var transitions = map[model.StockTransferStatus][]model.StockTransferStatus{
+	model.StockTransferPending:   {model.StockTransferInTransit, model.StockTransferCancelled},
+	model.StockTransferInTransit: {model.StockTransferReceived},
+}
+
+// canTransition reports whether a stock transfer may move from one status to
+// another.
+func canTransition(from, to model.StockTransferStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionError is returned when a status change is not allowed by the
+// stock transfer lifecycle.
+type TransitionError struct {
+	From model.StockTransferStatus
+	To   model.StockTransferStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: cannot move stock transfer from %s to %s", apperrors.ErrInvalidStockTransferTransition, e.From, e.To)
+}
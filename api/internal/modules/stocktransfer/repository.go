@@ -0,0 +1,73 @@
+package stocktransfer
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.StockTransferRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.StockTransfer, int64, error) {
+	return pagination.Paginate[model.StockTransfer](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, transfer *model.StockTransfer) error {
+	return r.db.WithContext(ctx).Create(transfer).Error
+}
+
+// UpdateStatus saves the status and timestamps of transfer, but only while
+// the stored status is still from. It reports false when another request
+// changed the status first.
+func (r *repository) UpdateStatus(ctx context.Context, transfer *model.StockTransfer, from model.StockTransferStatus) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&model.StockTransfer{}).
+		Where("id = ? AND status = ?", transfer.ID, from).
+		Updates(map[string]any{
+			"status":       transfer.Status,
+			"shipped_at":   transfer.ShippedAt,
+			"received_at":  transfer.ReceivedAt,
+			"cancelled_at": transfer.CancelledAt,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.StockTransfer, error) {
+	var result model.StockTransfer
+
+	query := r.db.WithContext(ctx).Model(model.StockTransfer{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.StockTransferRepository {
+	return &repository{db: tx}
+}
@@ -0,0 +1,171 @@
+package stocktransfer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/modules/warehouse"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/utils/numbergen"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+var errUnknownRefs = errors.New(apperrors.ErrUnknownStockTransferRefs)
+
+type service struct {
+	repo             interfaces.StockTransferRepository
+	warehouseService interfaces.WarehouseService
+	productService   interfaces.ProductService
+	db               *gorm.DB
+}
+
+func NewService(
+	repo interfaces.StockTransferRepository,
+	warehouseService interfaces.WarehouseService,
+	productService interfaces.ProductService,
+	appCtx *deps.AppContext,
+) interfaces.StockTransferService {
+	return &service{
+		repo:             repo,
+		warehouseService: warehouseService,
+		productService:   productService,
+		db:               appCtx.DB,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.StockTransfer, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+// Create saves a pending stock transfer between two warehouses of the org.
+// No stock moves until it is shipped.
+func (s *service) Create(ctx context.Context, transfer *model.StockTransfer) error {
+	for _, ID := range []uint{transfer.FromWarehouseID, transfer.ToWarehouseID} {
+		if _, err := s.warehouseService.Location(ctx, &ID); err != nil {
+			if errors.Is(err, warehouse.ErrUnknownWarehouse) {
+				return errUnknownRefs
+			}
+			return err
+		}
+	}
+
+	IDs := make([]uint, len(transfer.Lines))
+	for i, line := range transfer.Lines {
+		IDs[i] = line.VariantID
+	}
+	variants, err := s.productService.FindVariants(ctx, map[string]any{"id": IDs}, nil)
+	if err != nil {
+		return err
+	}
+	if len(variants) != len(IDs) {
+		return errUnknownRefs
+	}
+
+	skus := make(map[uint]string, len(variants))
+	for _, v := range variants {
+		skus[v.ID] = v.SKU
+	}
+	for i := range transfer.Lines {
+		transfer.Lines[i].SKU = skus[transfer.Lines[i].VariantID]
+	}
+	transfer.TransferNumber = numbergen.Generate("TRF")
+
+	return s.repo.Create(ctx, transfer)
+}
+
+// Ship takes the lines of a pending transfer off the stock on hand of its
+// source warehouse. Only available stock can leave, shortages are reported
+// with an *apperrors.InsufficientStockError.
+func (s *service) Ship(ctx context.Context, ID uint) (*model.StockTransfer, error) {
+	return s.transition(ctx, ID, model.StockTransferInTransit)
+}
+
+// Receive puts the lines of a transfer in transit on hand at its destination
+// warehouse.
+func (s *service) Receive(ctx context.Context, ID uint) (*model.StockTransfer, error) {
+	return s.transition(ctx, ID, model.StockTransferReceived)
+}
+
+// Cancel drops a transfer that did not ship yet.
+func (s *service) Cancel(ctx context.Context, ID uint) (*model.StockTransfer, error) {
+	return s.transition(ctx, ID, model.StockTransferCancelled)
+}
+
+func (s *service) transition(ctx context.Context, ID uint, to model.StockTransferStatus) (*model.StockTransfer, error) {
+	var transfer *model.StockTransfer
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		productService := s.productService.WithTx(tx)
+
+		var err error
+		transfer, err = repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, []string{"Lines"})
+		if err != nil {
+			return err
+		}
+
+		from := transfer.Status
+		if !canTransition(from, to) {
+			return &TransitionError{From: from, To: to}
+		}
+
+		now := time.Now()
+		switch to {
+		case model.StockTransferInTransit:
+			transfer.ShippedAt = &now
+		case model.StockTransferReceived:
+			transfer.ReceivedAt = &now
+		case model.StockTransferCancelled:
+			transfer.CancelledAt = &now
+		}
+		transfer.Status = to
+
+		// Guard against moving the same stock twice concurrently
+		updated, err := repo.UpdateStatus(ctx, transfer, from)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return &TransitionError{From: from, To: to}
+		}
+
+		switch to {
+		case model.StockTransferInTransit:
+			return productService.TransferOut(ctx, transfer.ID, stockLines(transfer.Lines, transfer.FromWarehouseID))
+		case model.StockTransferReceived:
+			return productService.TransferIn(ctx, transfer.ID, stockLines(transfer.Lines, transfer.ToWarehouseID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.StockTransfer, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.StockTransferService {
+	return &service{
+		repo:             s.repo.WithTx(tx),
+		warehouseService: s.warehouseService.WithTx(tx),
+		productService:   s.productService.WithTx(tx),
+		db:               tx,
+	}
+}
+
+// stockLines returns the lines of a transfer as stock at warehouseID.
+func stockLines(lines []model.StockTransferLine, warehouseID uint) []model.StockLine {
+	stock := make([]model.StockLine, len(lines))
+	for i, line := range lines {
+		stock[i] = model.StockLine{VariantID: line.VariantID, WarehouseID: &warehouseID, Quantity: line.Quantity}
+	}
+	return stock
+}
@@ -0,0 +1,223 @@
+package supplier
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"name": true, "contact_name": true, "email": true, "phone_number": true}
+
+// For Swagger docs
+type APIResponseSupplier struct {
+	Code    int            `json:"code"`
+	Message string         `json:"message"`
+	Data    model.Supplier `json:"data"`
+}
+
+type SupplierHandler struct {
+	service interfaces.SupplierService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.SupplierService, appCtx *deps.AppContext) interfaces.SupplierHandler {
+	return &SupplierHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List suppliers with filtering and pagination
+// @Description  Returns a paginated list of suppliers. Supports filtering, sorting, searching, and preloading.
+// @Tags         suppliers
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        preloads      query     string  false  "Comma-separated list of relations to preload. relation must start with uppercase. e.g. 'PurchaseOrders'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        name          query     string  false  "Filter by name"
+// @Param        email         query     string  false  "Filter by email"
+// @Success      200           {object}  APIResponseSupplier
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /suppliers [get]
+// @Security BearerAuth
+func (h *SupplierHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterSupplier, h.appCtx.Logger)
+		return
+	}
+
+	suppliers, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterSupplier, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.Supplier]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      suppliers,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create supplier
+// @Description Create a new supplier to place purchase orders with
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateSupplierDTO true "Supplier payload"
+// @Success 201 {object} APIResponseSupplier
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /suppliers [post]
+// @Security BearerAuth
+func (h *SupplierHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreateSupplierDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateSupplier, h.appCtx.Logger)
+		return
+	}
+
+	supplier := req.ToModel(userFromContext.Org)
+	if err := h.service.Create(ctx, supplier); err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateSupplier, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, supplier, h.appCtx.Logger)
+}
+
+// Update godoc
+// @Summary Update supplier
+// @Description Update an existing supplier by ID
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Param request body dto.UpdateSupplierDTO true "Update supplier payload"
+// @Success 200 {object} APIResponseSupplier
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /suppliers/{id} [patch]
+// @Security BearerAuth
+func (h *SupplierHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.UpdateSupplierDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	supplier, err := h.service.Update(ctx, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrSupplierNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateSupplier, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, supplier, h.appCtx.Logger)
+}
+
+// Delete godoc
+// @Summary Delete supplier
+// @Description Delete a supplier by ID. Suppliers with purchase orders can't be deleted.
+// @Tags suppliers
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Success 200 {integer} response.APIResponseInt
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /suppliers/{id} [delete]
+// @Security BearerAuth
+func (h *SupplierHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	if err := h.service.Delete(ctx, uint(id)); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrSupplierNotFound, h.appCtx.Logger)
+		case errors.Is(err, errHasPurchaseOrders):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrSupplierHasPurchaseOrders, h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrDeleteSupplier, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, id, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get supplier
+// @Description Get a supplier by ID
+// @Tags suppliers
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} APIResponseSupplier
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /suppliers/{id} [get]
+// @Security BearerAuth
+func (h *SupplierHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	supplier, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrSupplierNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindSupplier, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, supplier, h.appCtx.Logger)
+}
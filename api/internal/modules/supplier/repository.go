@@ -0,0 +1,81 @@
+package supplier
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.SupplierRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.Supplier, int64, error) {
+	return pagination.Paginate[model.Supplier](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, supplier *model.Supplier) error {
+	return r.db.WithContext(ctx).Create(supplier).Error
+}
+
+// Update saves every field of supplier, false and empty ones included.
+func (r *repository) Update(ctx context.Context, supplier *model.Supplier) error {
+	return r.db.WithContext(ctx).Select("*").Omit("created_at").Updates(supplier).Error
+}
+
+func (r *repository) Delete(ctx context.Context, ID uint) error {
+	res := r.db.WithContext(ctx).Delete(&model.Supplier{}, ID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// HasPurchaseOrders reports whether any purchase order was placed with a
+// supplier.
+func (r *repository) HasPurchaseOrders(ctx context.Context, ID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.PurchaseOrder{}).Where("supplier_id = ?", ID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Supplier, error) {
+	var result model.Supplier
+
+	query := r.db.WithContext(ctx).Model(model.Supplier{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.SupplierRepository {
+	return &repository{db: tx}
+}
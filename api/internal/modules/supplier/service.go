@@ -0,0 +1,68 @@
+package supplier
+
+import (
+	"context"
+	"errors"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+// errHasPurchaseOrders is returned when a supplier that was ordered from
+// would be deleted.
+var errHasPurchaseOrders = errors.New(apperrors.ErrSupplierHasPurchaseOrders)
+
+type service struct {
+	repo interfaces.SupplierRepository
+}
+
+func NewService(repo interfaces.SupplierRepository) interfaces.SupplierService {
+	return &service{
+		repo: repo,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.Supplier, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+func (s *service) Create(ctx context.Context, supplier *model.Supplier) error {
+	return s.repo.Create(ctx, supplier)
+}
+
+func (s *service) Update(ctx context.Context, ID uint, dto *dto.UpdateSupplierDTO) (*model.Supplier, error) {
+	supplier, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	dto.ApplyModel(supplier)
+	if err := s.repo.Update(ctx, supplier); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// Delete removes a supplier. Suppliers with purchase orders are kept so the
+// orders still show who they were placed with.
+func (s *service) Delete(ctx context.Context, ID uint) error {
+	ordered, err := s.repo.HasPurchaseOrders(ctx, ID)
+	if err != nil {
+		return err
+	}
+	if ordered {
+		return errHasPurchaseOrders
+	}
+	return s.repo.Delete(ctx, ID)
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Supplier, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.SupplierService {
+	return &service{repo: s.repo.WithTx(tx)}
+}
@@ -0,0 +1,232 @@
+package taxclass
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"name": true}
+
+// For Swagger docs
+type APIResponseTaxClass struct {
+	Code    int            `json:"code"`
+	Message string         `json:"message"`
+	Data    model.TaxClass `json:"data"`
+}
+
+type TaxClassHandler struct {
+	service interfaces.TaxClassService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.TaxClassService, appCtx *deps.AppContext) interfaces.TaxClassHandler {
+	return &TaxClassHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List tax classes with filtering and pagination
+// @Description  Returns a paginated list of the tax classes of the org with their rates
+// @Tags         tax-classes
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'name asc'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        name          query     string  false  "Filter by name"
+// @Success      200           {object}  APIResponseTaxClass
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /tax-classes [get]
+// @Security BearerAuth
+func (h *TaxClassHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterTaxClass, h.appCtx.Logger)
+		return
+	}
+
+	classes, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterTaxClass, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.TaxClass]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      classes,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create tax class
+// @Description Create a set of taxes charged on the variants in it. An exempt class charges no tax; a class with a 0% rate is zero rated.
+// @Tags tax-classes
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateTaxClassDTO true "Tax class payload"
+// @Success 201 {object} APIResponseTaxClass
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /tax-classes [post]
+// @Security BearerAuth
+func (h *TaxClassHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreateTaxClassDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateTaxClass, h.appCtx.Logger)
+		return
+	}
+
+	class := req.ToModel(userFromContext.Org)
+	if err := h.service.Create(ctx, class, req.RateIDs); err != nil {
+		if errors.Is(err, errUnknownRates) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrUnknownTaxRates, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateTaxClass, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, class, h.appCtx.Logger)
+}
+
+// Update godoc
+// @Summary Update tax class
+// @Description Update a tax class by ID. Orders already priced keep the taxes they were priced with.
+// @Tags tax-classes
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax class ID"
+// @Param request body dto.UpdateTaxClassDTO true "Update tax class payload"
+// @Success 200 {object} APIResponseTaxClass
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /tax-classes/{id} [patch]
+// @Security BearerAuth
+func (h *TaxClassHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.UpdateTaxClassDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	class, err := h.service.Update(ctx, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, errUnknownRates) {
+			response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrUnknownTaxRates, h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrTaxClassNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateTaxClass, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, class, h.appCtx.Logger)
+}
+
+// Delete godoc
+// @Summary Delete tax class
+// @Description Delete a tax class by ID. Classes with variants in them can't be deleted.
+// @Tags tax-classes
+// @Produce json
+// @Param id path int true "Tax class ID"
+// @Success 200 {integer} response.APIResponseInt
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /tax-classes/{id} [delete]
+// @Security BearerAuth
+func (h *TaxClassHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	if err := h.service.Delete(ctx, uint(id)); err != nil {
+		if errors.Is(err, errInUse) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrTaxClassInUse, h.appCtx.Logger)
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrTaxClassNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrDeleteTaxClass, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, id, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get tax class
+// @Description Get a tax class by ID with its rates
+// @Tags tax-classes
+// @Produce json
+// @Param id path int true "Tax class ID"
+// @Success 200 {object} APIResponseTaxClass
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /tax-classes/{id} [get]
+// @Security BearerAuth
+func (h *TaxClassHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	class, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, []string{"Rates"})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrTaxClassNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindTaxClass, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, class, h.appCtx.Logger)
+}
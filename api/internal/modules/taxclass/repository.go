@@ -0,0 +1,99 @@
+package taxclass
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.TaxClassRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.TaxClass, int64, error) {
+	return pagination.Paginate[model.TaxClass](ctx, r.db, opts)
+}
+
+// Create inserts a class and links it to its rates, which must exist.
+func (r *repository) Create(ctx context.Context, class *model.TaxClass) error {
+	return r.db.WithContext(ctx).Create(class).Error
+}
+
+// Update saves the fields of a class and replaces its rates with
+// class.Rates.
+func (r *repository) Update(ctx context.Context, class *model.TaxClass) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(class).Select("name", "exempt").Updates(class).Error; err != nil {
+			return err
+		}
+		return tx.Model(class).Association("Rates").Replace(class.Rates)
+	})
+}
+
+func (r *repository) Delete(ctx context.Context, ID uint) error {
+	res := r.db.WithContext(ctx).Delete(&model.TaxClass{}, ID)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// InUse reports whether a variant is in the class.
+func (r *repository) InUse(ctx context.Context, ID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Variant{}).Where("tax_class_id = ?", ID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindByIDs returns the classes with IDs, with their rates.
+func (r *repository) FindByIDs(ctx context.Context, IDs []uint) ([]model.TaxClass, error) {
+	var classes []model.TaxClass
+	err := r.db.WithContext(ctx).Preload("Rates").Where("id IN ?", IDs).Find(&classes).Error
+	if err != nil {
+		return nil, err
+	}
+	return classes, nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.TaxClass, error) {
+	var result model.TaxClass
+
+	query := r.db.WithContext(ctx).Model(model.TaxClass{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.TaxClassRepository {
+	return &repository{db: tx}
+}
@@ -0,0 +1,112 @@
+package taxclass
+
+import (
+	"context"
+	"errors"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+var (
+	errUnknownRates = errors.New(apperrors.ErrUnknownTaxRates)
+	errInUse        = errors.New(apperrors.ErrTaxClassInUse)
+)
+
+type service struct {
+	repo           interfaces.TaxClassRepository
+	taxRateService interfaces.TaxRateService
+}
+
+func NewService(repo interfaces.TaxClassRepository, taxRateService interfaces.TaxRateService) interfaces.TaxClassService {
+	return &service{
+		repo:           repo,
+		taxRateService: taxRateService,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.TaxClass, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+// Create creates a class charging the rates with rateIDs.
+func (s *service) Create(ctx context.Context, class *model.TaxClass, rateIDs []uint) error {
+	rates, err := s.findRates(ctx, rateIDs)
+	if err != nil {
+		return err
+	}
+	class.Rates = rates
+
+	return s.repo.Create(ctx, class)
+}
+
+func (s *service) Update(ctx context.Context, ID uint, dto *dto.UpdateTaxClassDTO) (*model.TaxClass, error) {
+	class, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, []string{"Rates"})
+	if err != nil {
+		return nil, err
+	}
+
+	dto.ApplyModel(class)
+	if dto.RateIDs != nil {
+		if class.Rates, err = s.findRates(ctx, dto.RateIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Update(ctx, class); err != nil {
+		return nil, err
+	}
+	return class, nil
+}
+
+// Delete removes a class no variant is in.
+func (s *service) Delete(ctx context.Context, ID uint) error {
+	inUse, err := s.repo.InUse(ctx, ID)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errInUse
+	}
+
+	return s.repo.Delete(ctx, ID)
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.TaxClass, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+// FindByIDs returns the tax classes of the org with the given IDs, with
+// their rates. Unknown IDs are left out.
+func (s *service) FindByIDs(ctx context.Context, IDs []uint) ([]model.TaxClass, error) {
+	if len(IDs) == 0 {
+		return nil, nil
+	}
+	return s.repo.FindByIDs(ctx, IDs)
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.TaxClassService {
+	return &service{repo: s.repo.WithTx(tx), taxRateService: s.taxRateService.WithTx(tx)}
+}
+
+// findRates returns the rates with IDs, all of which must exist.
+func (s *service) findRates(ctx context.Context, IDs []uint) ([]model.TaxRate, error) {
+	unique := make(map[uint]bool, len(IDs))
+	for _, ID := range IDs {
+		unique[ID] = true
+	}
+
+	rates, err := s.taxRateService.FindByIDs(ctx, IDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) != len(unique) {
+		return nil, errUnknownRates
+	}
+
+	return rates, nil
+}
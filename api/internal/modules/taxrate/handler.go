@@ -0,0 +1,218 @@
+package taxrate
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"name": true}
+
+// For Swagger docs
+type APIResponseTaxRate struct {
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    model.TaxRate `json:"data"`
+}
+
+type TaxRateHandler struct {
+	service interfaces.TaxRateService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.TaxRateService, appCtx *deps.AppContext) interfaces.TaxRateHandler {
+	return &TaxRateHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List tax rates with filtering and pagination
+// @Description  Returns a paginated list of the tax rates of the org
+// @Tags         tax-rates
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'priority asc'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        name          query     string  false  "Filter by name"
+// @Success      200           {object}  APIResponseTaxRate
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /tax-rates [get]
+// @Security BearerAuth
+func (h *TaxRateHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterTaxRate, h.appCtx.Logger)
+		return
+	}
+
+	rates, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterTaxRate, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.TaxRate]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      rates,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create tax rate
+// @Description Create a tax the org charges, e.g. VAT at 7.5%
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateTaxRateDTO true "Tax rate payload"
+// @Success 201 {object} APIResponseTaxRate
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /tax-rates [post]
+// @Security BearerAuth
+func (h *TaxRateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreateTaxRateDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateTaxRate, h.appCtx.Logger)
+		return
+	}
+
+	rate := req.ToModel(userFromContext.Org)
+	if err := h.service.Create(ctx, rate); err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateTaxRate, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, rate, h.appCtx.Logger)
+}
+
+// Update godoc
+// @Summary Update tax rate
+// @Description Update a tax rate by ID. Orders already priced keep the rate they were priced at.
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Param request body dto.UpdateTaxRateDTO true "Update tax rate payload"
+// @Success 200 {object} APIResponseTaxRate
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /tax-rates/{id} [patch]
+// @Security BearerAuth
+func (h *TaxRateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.UpdateTaxRateDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	rate, err := h.service.Update(ctx, uint(id), &req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrTaxRateNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateTaxRate, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, rate, h.appCtx.Logger)
+}
+
+// Delete godoc
+// @Summary Delete tax rate
+// @Description Delete a tax rate by ID. It is no longer charged by the classes it was in.
+// @Tags tax-rates
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Success 200 {integer} response.APIResponseInt
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /tax-rates/{id} [delete]
+// @Security BearerAuth
+func (h *TaxRateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	if err := h.service.Delete(ctx, uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrTaxRateNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrDeleteTaxRate, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, id, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get tax rate
+// @Description Get a tax rate by ID
+// @Tags tax-rates
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Success 200 {object} APIResponseTaxRate
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /tax-rates/{id} [get]
+// @Security BearerAuth
+func (h *TaxRateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	rate, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrTaxRateNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindTaxRate, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, rate, h.appCtx.Logger)
+}
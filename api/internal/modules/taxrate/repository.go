@@ -0,0 +1,90 @@
+package taxrate
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.TaxRateRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.TaxRate, int64, error) {
+	return pagination.Paginate[model.TaxRate](ctx, r.db, opts)
+}
+
+func (r *repository) Create(ctx context.Context, rate *model.TaxRate) error {
+	return r.db.WithContext(ctx).Create(rate).Error
+}
+
+func (r *repository) Update(ctx context.Context, rate *model.TaxRate) error {
+	return r.db.WithContext(ctx).Save(rate).Error
+}
+
+// Delete removes a tax rate. It drops out of the classes it was in.
+func (r *repository) Delete(ctx context.Context, ID uint) error {
+	res := r.db.WithContext(ctx).Delete(&model.TaxRate{}, ID)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *repository) FindByID(ctx context.Context, ID uint) (*model.TaxRate, error) {
+	var rate model.TaxRate
+	err := r.db.WithContext(ctx).First(&rate, ID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *repository) FindByIDs(ctx context.Context, IDs []uint) ([]model.TaxRate, error) {
+	var rates []model.TaxRate
+	err := r.db.WithContext(ctx).Where("id IN ?", IDs).Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.TaxRate, error) {
+	var result model.TaxRate
+
+	query := r.db.WithContext(ctx).Model(model.TaxRate{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.TaxRateRepository {
+	return &repository{db: tx}
+}
@@ -0,0 +1,231 @@
+package warehouse
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/deps"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/validator"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+var allowedSearchFields = map[string]bool{"name": true, "code": true}
+
+// For Swagger docs
+type APIResponseWarehouse struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    model.Warehouse `json:"data"`
+}
+
+type WarehouseHandler struct {
+	service interfaces.WarehouseService
+	appCtx  *deps.AppContext
+}
+
+func NewHandler(service interfaces.WarehouseService, appCtx *deps.AppContext) interfaces.WarehouseHandler {
+	return &WarehouseHandler{service: service, appCtx: appCtx}
+}
+
+// Filter godoc
+// @Summary      List warehouses with filtering and pagination
+// @Description  Returns a paginated list of warehouses. Supports filtering, sorting and searching.
+// @Tags         warehouses
+// @Accept       json
+// @Produce      json
+// @Param        page          query     int     false  "Page number (default: 1)"
+// @Param        limit         query     int     false  "Number of items per page (default: 20, max: 100)"
+// @Param        sort          query     string  false  "Sort by field, e.g. 'created_at desc'"
+// @Param        search_fields query     string  false  "Comma-separated list of fields to search (must be allowed)"
+// @Param        name          query     string  false  "Filter by name"
+// @Param        code          query     string  false  "Filter by code"
+// @Success      200           {object}  APIResponseWarehouse
+// @Failure      400           {object}  apperrors.APIError "Invalid filter parameters"
+// @Failure      500           {object}  apperrors.APIError "Internal server error"
+// @Router       /warehouses [get]
+// @Security BearerAuth
+func (h *WarehouseHandler) Filter(w http.ResponseWriter, r *http.Request) {
+	opts, err := pagination.ParsePaginationOptions(r.URL.Query(), allowedSearchFields)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrFilterWarehouse, h.appCtx.Logger)
+		return
+	}
+
+	warehouses, total, err := h.service.Filter(r.Context(), opts)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFilterWarehouse, h.appCtx.Logger)
+		return
+	}
+
+	resp := response.FilterResponse[model.Warehouse]{
+		Pagination: pagination.BuildPagination(total, opts),
+		Items:      warehouses,
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, resp, h.appCtx.Logger)
+}
+
+// Create godoc
+// @Summary Create warehouse
+// @Description Create a new warehouse to keep stock at. The first warehouse of the org becomes its default and takes over the stock of every variant.
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateWarehouseDTO true "Warehouse payload"
+// @Success 201 {object} APIResponseWarehouse
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse "Code already exists"
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /warehouses [post]
+// @Security BearerAuth
+func (h *WarehouseHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req dto.CreateWarehouseDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	userFromContext, err := identity.UserFromContext(ctx)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateWarehouse, h.appCtx.Logger)
+		return
+	}
+
+	warehouse := req.ToModel(userFromContext.Org)
+	if err := h.service.Create(ctx, warehouse); err != nil {
+		if errors.Is(err, errCodeTaken) {
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrWarehouseCodeTaken, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrCreateWarehouse, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusCreated, warehouse, h.appCtx.Logger)
+}
+
+// Update godoc
+// @Summary Update warehouse
+// @Description Update the name, code and address of a warehouse by ID
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Param request body dto.UpdateWarehouseDTO true "Update warehouse payload"
+// @Success 200 {object} APIResponseWarehouse
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse "Code already exists"
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /warehouses/{id} [patch]
+// @Security BearerAuth
+func (h *WarehouseHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	var req dto.UpdateWarehouseDTO
+	if errs := validator.ValidateRequest(r, &req); len(errs) > 0 {
+		validator.WriteValidationResponse(w, errs)
+		return
+	}
+
+	warehouse, err := h.service.Update(ctx, uint(id), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrWarehouseNotFound, h.appCtx.Logger)
+		case errors.Is(err, errCodeTaken):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, apperrors.ErrWarehouseCodeTaken, h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrUpdateWarehouse, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, warehouse, h.appCtx.Logger)
+}
+
+// Delete godoc
+// @Summary Delete warehouse
+// @Description Delete a warehouse by ID. The default warehouse and warehouses that hold stock or have open orders, purchase orders or transfers can't be deleted.
+// @Tags warehouses
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Success 200 {integer} response.APIResponseInt
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 409 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /warehouses/{id} [delete]
+// @Security BearerAuth
+func (h *WarehouseHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	if err := h.service.Delete(ctx, uint(id)); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrWarehouseNotFound, h.appCtx.Logger)
+		case errors.Is(err, errDefault), errors.Is(err, errInUse):
+			response.WriteJSONErrorV2(w, http.StatusConflict, nil, err.Error(), h.appCtx.Logger)
+		default:
+			response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrDeleteWarehouse, h.appCtx.Logger)
+		}
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, id, h.appCtx.Logger)
+}
+
+// Get godoc
+// @Summary Get warehouse
+// @Description Get a warehouse by ID
+// @Tags warehouses
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Success 200 {object} APIResponseWarehouse
+// @Failure 400 {object} apperrors.APIErrorResponse
+// @Failure 404 {object} apperrors.APIErrorResponse
+// @Failure 500 {object} apperrors.APIErrorResponse
+// @Router /warehouses/{id} [get]
+// @Security BearerAuth
+func (h *WarehouseHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.WriteJSONErrorV2(w, http.StatusBadRequest, nil, apperrors.ErrInvalidId, h.appCtx.Logger)
+		return
+	}
+
+	warehouse, err := h.service.FindOneWithFields(ctx, nil, map[string]any{"id": id}, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.WriteJSONErrorV2(w, http.StatusNotFound, nil, apperrors.ErrWarehouseNotFound, h.appCtx.Logger)
+			return
+		}
+
+		response.WriteJSONErrorV2(w, http.StatusInternalServerError, err, apperrors.ErrFindWarehouse, h.appCtx.Logger)
+		return
+	}
+
+	response.WriteJSONSuccess(w, http.StatusOK, warehouse, h.appCtx.Logger)
+}
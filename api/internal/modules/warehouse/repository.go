@@ -0,0 +1,122 @@
+package warehouse
+
+import (
+	"context"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) interfaces.WarehouseRepository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Filter(ctx context.Context, opts pagination.Options) ([]model.Warehouse, int64, error) {
+	return pagination.Paginate[model.Warehouse](ctx, r.db, opts)
+}
+
+// Create saves warehouse. The first warehouse of an org becomes its default
+// and takes over the stock of every variant, so the stock levels of a
+// variant always add up to its stock.
+func (r *repository) Create(ctx context.Context, warehouse *model.Warehouse) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.Warehouse{}).Count(&count).Error; err != nil {
+			return err
+		}
+
+		warehouse.IsDefault = count == 0
+		if err := tx.Create(warehouse).Error; err != nil {
+			return err
+		}
+		if !warehouse.IsDefault {
+			return nil
+		}
+
+		now := time.Now()
+		return tx.Exec(`INSERT INTO stock_levels (org_id, warehouse_id, variant_id, stock, reserved, created_at, updated_at)
+			SELECT org_id, ?, id, stock, reserved, ?, ? FROM variants WHERE org_id = ?`,
+			warehouse.ID, now, now, warehouse.OrgID).Error
+	})
+}
+
+// Update saves the name, code and address of warehouse. Which warehouse is
+// the default never changes.
+func (r *repository) Update(ctx context.Context, warehouse *model.Warehouse) error {
+	return r.db.WithContext(ctx).Select("*").Omit("created_at", "is_default").Updates(warehouse).Error
+}
+
+// Delete removes a warehouse with its stock levels, which must be empty.
+func (r *repository) Delete(ctx context.Context, ID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&model.Warehouse{}, ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Unscoped().Where("warehouse_id = ?", ID).Delete(&model.StockLevel{}).Error
+	})
+}
+
+// InUse reports whether a warehouse holds stock or open orders, purchase
+// orders or stock transfers still move stock through it.
+func (r *repository) InUse(ctx context.Context, ID uint) (bool, error) {
+	db := r.db.WithContext(ctx)
+	queries := []*gorm.DB{
+		db.Model(&model.StockLevel{}).Where("warehouse_id = ? AND (stock <> 0 OR reserved <> 0)", ID),
+		db.Model(&model.Order{}).Where("warehouse_id = ? AND status IN ?", ID,
+			[]model.OrderStatus{model.OrderStatusPending, model.OrderStatusApproved}),
+		db.Model(&model.PurchaseOrder{}).Where("warehouse_id = ? AND status IN ?", ID,
+			[]model.PurchaseOrderStatus{model.PurchaseOrderDraft, model.PurchaseOrderSent, model.PurchaseOrderPartiallyReceived}),
+		db.Model(&model.StockTransfer{}).Where("(from_warehouse_id = ? OR to_warehouse_id = ?) AND status IN ?", ID, ID,
+			[]model.StockTransferStatus{model.StockTransferPending, model.StockTransferInTransit}),
+	}
+
+	for _, query := range queries {
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *repository) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Warehouse, error) {
+	var result model.Warehouse
+
+	query := r.db.WithContext(ctx).Model(model.Warehouse{}).Select(fields)
+
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	err := query.First(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// WithTx returns a new repository with the given transaction
+func (r *repository) WithTx(tx *gorm.DB) interfaces.WarehouseRepository {
+	return &repository{db: tx}
+}
@@ -0,0 +1,128 @@
+package warehouse
+
+import (
+	"context"
+	"errors"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+)
+
+// ErrUnknownWarehouse is returned by Location when the org has no warehouse
+// with the given ID.
+var ErrUnknownWarehouse = errors.New(apperrors.ErrWarehouseNotFound)
+
+var (
+	errCodeTaken = errors.New(apperrors.ErrWarehouseCodeTaken)
+	errDefault   = errors.New(apperrors.ErrDeleteDefaultWarehouse)
+	errInUse     = errors.New(apperrors.ErrWarehouseInUse)
+)
+
+type service struct {
+	repo interfaces.WarehouseRepository
+}
+
+func NewService(repo interfaces.WarehouseRepository) interfaces.WarehouseService {
+	return &service{
+		repo: repo,
+	}
+}
+
+func (s *service) Filter(ctx context.Context, opts pagination.Options) ([]model.Warehouse, int64, error) {
+	return s.repo.Filter(ctx, opts)
+}
+
+// Create saves a warehouse with a code the org does not use yet. The first
+// warehouse of an org becomes its default.
+func (s *service) Create(ctx context.Context, warehouse *model.Warehouse) error {
+	if err := s.checkCode(ctx, warehouse.Code, 0); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, warehouse)
+}
+
+func (s *service) Update(ctx context.Context, ID uint, dto *dto.UpdateWarehouseDTO) (*model.Warehouse, error) {
+	warehouse, err := s.repo.FindOneWithFields(ctx, nil, map[string]any{"id": ID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	dto.ApplyModel(warehouse)
+	if err := s.checkCode(ctx, warehouse.Code, warehouse.ID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, warehouse); err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+// Delete removes a warehouse. The default warehouse and warehouses stock is
+// held at or still moving through are kept.
+func (s *service) Delete(ctx context.Context, ID uint) error {
+	warehouse, err := s.repo.FindOneWithFields(ctx, []string{"id", "is_default"}, map[string]any{"id": ID}, nil)
+	if err != nil {
+		return err
+	}
+	if warehouse.IsDefault {
+		return errDefault
+	}
+
+	inUse, err := s.repo.InUse(ctx, ID)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errInUse
+	}
+	return s.repo.Delete(ctx, ID)
+}
+
+// Location returns the warehouse stock is kept at for ID: ID itself when the
+// org has that warehouse, its default warehouse when ID is nil. It returns
+// nil for orgs without warehouses, whose stock is only kept per variant.
+func (s *service) Location(ctx context.Context, ID *uint) (*uint, error) {
+	where := map[string]any{"is_default": true}
+	if ID != nil {
+		where = map[string]any{"id": *ID}
+	}
+
+	warehouse, err := s.repo.FindOneWithFields(ctx, []string{"id"}, where, nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if ID != nil {
+			return nil, ErrUnknownWarehouse
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &warehouse.ID, nil
+}
+
+func (s *service) FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Warehouse, error) {
+	return s.repo.FindOneWithFields(ctx, fields, where, preloads)
+}
+
+func (s *service) WithTx(tx *gorm.DB) interfaces.WarehouseService {
+	return &service{repo: s.repo.WithTx(tx)}
+}
+
+// checkCode returns errCodeTaken when another warehouse than ID has code.
+func (s *service) checkCode(ctx context.Context, code string, ID uint) error {
+	existing, err := s.repo.FindOneWithFields(ctx, []string{"id"}, map[string]any{"code": code}, nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != ID {
+		return errCodeTaken
+	}
+	return nil
+}
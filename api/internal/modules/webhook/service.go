@@ -122,13 +122,11 @@ func (s *service) createUser(ctx context.Context, data map[string]interface{}) e
 
 	err = s.userService.Create(ctx, user)
 	if err != nil {
-		// TODO: Move this later to a background worker
-		go func(ctx context.Context, clerkId string) {
-			cleanupError := s.clerkService.DeleteUser(ctx, user.ClerkID)
-			if cleanupError != nil {
-				s.appCtx.Logger.Error("error deleting user from clerk", "error", cleanupError, "user", user.ID)
-			}
-		}(ctx, user.ClerkID)
+		// Don't leave a Clerk user behind that has no local user
+		cleanupErr := s.appCtx.Jobs.Enqueue(ctx, types.DeleteClerkUserJobType, types.DeleteClerkUserJob{ClerkID: user.ClerkID})
+		if cleanupErr != nil {
+			s.appCtx.Logger.Error("error queueing clerk user deletion", "error", cleanupErr, "clerkId", user.ClerkID)
+		}
 		return err
 	}
 	s.appCtx.Logger.Info("User created", "user ID", user.ID)
@@ -0,0 +1,239 @@
+// Package outbox is a transactional outbox for domain events.
+//
+// Services record events with the outbox of the transaction that makes the
+// change, so an event is stored if and only if the change commits. The
+// relay then publishes committed events by queueing one job per subscriber
+// of the event type, so a failing subscriber is retried on its own and
+// never loses or repeats the event for the others.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/jobs"
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/tenant"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Options tune how events are relayed.
+type Options struct {
+	// PollInterval is how long the relay waits before looking for events
+	// again when there were none.
+	PollInterval time.Duration
+	// BatchSize is the number of events published per transaction.
+	BatchSize int
+}
+
+// DefaultOptions are the options used by New.
+var DefaultOptions = Options{
+	PollInterval: time.Second,
+	BatchSize:    100,
+}
+
+// Outbox records domain events and relays them to their subscribers.
+type Outbox struct {
+	db     *gorm.DB
+	queue  interfaces.JobQueue
+	logger interfaces.Logger
+	opts   Options
+	// subscribers lists the subscriber names per event type
+	subscribers map[string][]string
+	mu          *sync.RWMutex
+
+	// set by Start
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+}
+
+// New creates an outbox with DefaultOptions. Subscribers run as jobs of
+// queue.
+func New(db *gorm.DB, queue interfaces.JobQueue, logger interfaces.Logger) *Outbox {
+	return NewWithOptions(db, queue, logger, DefaultOptions)
+}
+
+func NewWithOptions(db *gorm.DB, queue interfaces.JobQueue, logger interfaces.Logger, opts Options) *Outbox {
+	return &Outbox{
+		db:          db,
+		queue:       queue,
+		logger:      logger,
+		opts:        opts,
+		subscribers: make(map[string][]string),
+		mu:          &sync.RWMutex{},
+		wg:          &sync.WaitGroup{},
+	}
+}
+
+// eventJob is the payload of the job delivering an event to a subscriber.
+type eventJob struct {
+	EventID uint `json:"eventId"`
+}
+
+// jobType is the job type delivering events of eventType to subscriber.
+func jobType(eventType string, subscriber string) string {
+	return "event:" + eventType + ":" + subscriber
+}
+
+// Typed adapts a handler taking a decoded event payload of type T to an
+// EventHandler.
+func Typed[T any](fn func(ctx context.Context, payload T) error) interfaces.EventHandler {
+	return func(ctx context.Context, event *model.OutboxEvent) error {
+		var payload T
+		if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+			return fmt.Errorf("decode event payload: %w", err)
+		}
+		return fn(ctx, payload)
+	}
+}
+
+func (o *Outbox) Subscribe(eventType string, subscriber string, handler interfaces.EventHandler) {
+	o.mu.Lock()
+	o.subscribers[eventType] = append(o.subscribers[eventType], subscriber)
+	o.mu.Unlock()
+
+	o.queue.Handle(jobType(eventType, subscriber), jobs.Typed(func(ctx context.Context, job eventJob) error {
+		var event model.OutboxEvent
+		if err := o.db.WithContext(ctx).First(&event, job.EventID).Error; err != nil {
+			return err
+		}
+		return handler(ctx, &event)
+	}))
+}
+
+func (o *Outbox) Record(ctx context.Context, eventType string, aggregateID uint, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode event payload: %w", err)
+	}
+
+	event := &model.OutboxEvent{
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     string(data),
+	}
+	if orgID, err := tenant.OrgFromContext(ctx); err == nil {
+		event.OrgID = &orgID
+	}
+
+	return o.db.WithContext(ctx).Create(event).Error
+}
+
+func (o *Outbox) WithTx(tx *gorm.DB) interfaces.Outbox {
+	txOutbox := *o
+	txOutbox.db = tx
+	return &txOutbox
+}
+
+// Start runs the relay in the background until Stop is called.
+func (o *Outbox) Start(ctx context.Context) {
+	ctx, o.cancel = context.WithCancel(ctx)
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		o.relay(ctx)
+	}()
+}
+
+// Stop stops the relay, waiting for the batch being published to commit or
+// for ctx to be done.
+func (o *Outbox) Stop(ctx context.Context) error {
+	if o.cancel != nil {
+		o.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		o.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (o *Outbox) relay(ctx context.Context) {
+	for {
+		published, err := o.RelayOnce(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			o.logger.Error("failed to relay events", "err", err)
+		}
+		if published > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(o.opts.PollInterval):
+		}
+	}
+}
+
+// RelayOnce publishes the next batch of unpublished events, oldest first,
+// and returns how many were published. Events without subscribers are
+// marked published too.
+func (o *Outbox) RelayOnce(ctx context.Context) (int, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	var events []model.OutboxEvent
+	err := o.db.WithContext(tenant.Bypass(context.WithoutCancel(ctx))).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL").
+			Order("id").
+			Limit(o.opts.BatchSize).
+			Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		queue := o.queue.WithTx(tx)
+		ids := make([]uint, 0, len(events))
+		for _, event := range events {
+			if err := o.publish(tx.Statement.Context, queue, &event); err != nil {
+				return err
+			}
+			ids = append(ids, event.ID)
+		}
+
+		return tx.Model(&model.OutboxEvent{}).Where("id IN ?", ids).Update("published_at", time.Now()).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+// publish queues the jobs delivering event to its subscribers.
+func (o *Outbox) publish(ctx context.Context, queue interfaces.JobQueue, event *model.OutboxEvent) error {
+	// Subscribers run for the org of the event
+	if event.OrgID != nil {
+		ctx = tenant.WithOrg(ctx, *event.OrgID)
+	}
+
+	o.mu.RLock()
+	subscribers := o.subscribers[event.Type]
+	o.mu.RUnlock()
+
+	for _, subscriber := range subscribers {
+		if err := queue.Enqueue(ctx, jobType(event.Type, subscriber), eventJob{EventID: event.ID}); err != nil {
+			return fmt.Errorf("queue event %d for %s: %w", event.ID, subscriber, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/jobs"
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/tenant"
+	"github.com/deveasyclick/openb2b/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type orderCreated struct {
+	OrderID uint `json:"orderId"`
+}
+
+func setupOutbox(t *testing.T) (*Outbox, *jobs.Queue, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// Every connection to :memory: opens a database of its own
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&model.Job{}, &model.OutboxEvent{}))
+	require.NoError(t, tenant.Register(db))
+
+	log := logger.New("test")
+	queue := jobs.New(db, log)
+	return New(db, queue, log), queue, db
+}
+
+func runJobs(t *testing.T, queue *jobs.Queue) {
+	t.Helper()
+	for {
+		ran, err := queue.RunOnce(context.Background())
+		require.NoError(t, err)
+		if !ran {
+			return
+		}
+	}
+}
+
+func TestEventIsDeliveredToEverySubscriber(t *testing.T) {
+	o, queue, db := setupOutbox(t)
+
+	var mailed, hooked []uint
+	var mailedOrg uint
+	o.Subscribe("order.created", "mailer", Typed(func(ctx context.Context, e orderCreated) error {
+		mailed = append(mailed, e.OrderID)
+		mailedOrg, _ = tenant.OrgFromContext(ctx)
+		return nil
+	}))
+	o.Subscribe("order.created", "webhooks", func(ctx context.Context, event *model.OutboxEvent) error {
+		hooked = append(hooked, event.AggregateID)
+		return nil
+	})
+
+	ctx := tenant.WithOrg(context.Background(), 3)
+	require.NoError(t, o.Record(ctx, "order.created", 42, orderCreated{OrderID: 42}))
+	require.NoError(t, o.Record(ctx, "order.deleted", 42, nil)) // no subscribers
+
+	// Nothing runs before the relay publishes the event
+	runJobs(t, queue)
+	assert.Empty(t, mailed)
+
+	published, err := o.RelayOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	runJobs(t, queue)
+	assert.Equal(t, []uint{42}, mailed)
+	assert.Equal(t, uint(3), mailedOrg)
+	assert.Equal(t, []uint{42}, hooked)
+
+	// Published events are not published again
+	published, err = o.RelayOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+
+	var unpublished int64
+	system := db.WithContext(tenant.Bypass(context.Background()))
+	require.NoError(t, system.Model(&model.OutboxEvent{}).Where("published_at IS NULL").Count(&unpublished).Error)
+	assert.Equal(t, int64(0), unpublished)
+}
+
+func TestFailingSubscriberDoesNotAffectOthers(t *testing.T) {
+	o, queue, db := setupOutbox(t)
+
+	delivered := 0
+	o.Subscribe("invoice.issued", "mailer", func(ctx context.Context, event *model.OutboxEvent) error {
+		return errors.New("smtp unavailable")
+	})
+	o.Subscribe("invoice.issued", "webhooks", func(ctx context.Context, event *model.OutboxEvent) error {
+		delivered++
+		return nil
+	})
+
+	require.NoError(t, o.Record(context.Background(), "invoice.issued", 1, nil))
+	_, err := o.RelayOnce(context.Background())
+	require.NoError(t, err)
+	runJobs(t, queue)
+	assert.Equal(t, 1, delivered)
+
+	var job model.Job
+	system := db.WithContext(tenant.Bypass(context.Background()))
+	require.NoError(t, system.Where("type = ?", "event:invoice.issued:mailer").First(&job).Error)
+	assert.Equal(t, model.JobStatusPending, job.Status) // retried later
+	assert.Equal(t, "smtp unavailable", job.LastError)
+}
+
+func TestRolledBackEventIsNotPublished(t *testing.T) {
+	o, queue, _ := setupOutbox(t)
+
+	delivered := 0
+	o.Subscribe("payment.recorded", "mailer", func(ctx context.Context, event *model.OutboxEvent) error {
+		delivered++
+		return nil
+	})
+
+	err := o.db.WithContext(context.Background()).Transaction(func(tx *gorm.DB) error {
+		require.NoError(t, o.WithTx(tx).Record(context.Background(), "payment.recorded", 1, nil))
+		return errors.New("rollback")
+	})
+	assert.Error(t, err)
+
+	published, err := o.RelayOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+	runJobs(t, queue)
+	assert.Equal(t, 0, delivered)
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerAPIKeyRoutes(router chi.Router, handler interfaces.APIKeyHandler, middleware interfaces.Middleware) {
+	router.Route("/api-keys", func(r chi.Router) {
+		r.Use(middleware.RequirePermission(rbac.APIKeysManage))
+
+		r.Get("/", handler.Filter)
+
+		r.Post("/", handler.Create)
+
+		r.Get("/{id}", handler.Get)
+
+		r.Post("/{id}/revoke", handler.Revoke)
+	})
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerAuditLogRoutes(router chi.Router, handler interfaces.AuditLogHandler, middleware interfaces.Middleware) {
+	router.Route("/audit-logs", func(r chi.Router) {
+		r.Use(middleware.RequirePermission(rbac.AuditLogsRead))
+
+		r.Get("/", handler.Filter)
+
+		r.Get("/{id}", handler.Get)
+	})
+}
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerBackorderRoutes(router chi.Router, handler interfaces.BackorderHandler, middleware interfaces.Middleware) {
+	router.With(middleware.RequirePermission(rbac.OrdersRead)).Get("/backorders", handler.Report)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerCreditNoteRoutes(router chi.Router, handler interfaces.CreditNoteHandler, middleware interfaces.Middleware) {
+	read := middleware.RequirePermission(rbac.InvoicesRead)
+	issue := middleware.RequirePermission(rbac.InvoicesIssue)
+
+	router.Route("/credit-notes", func(r chi.Router) {
+		r.With(read).Get("/", handler.Filter)
+
+		r.With(issue).Post("/", handler.Create)
+
+		r.With(read).Get("/{id}", handler.Get)
+
+		r.With(issue).Post("/{id}/send", handler.Send)
+	})
+}
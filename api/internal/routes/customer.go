@@ -1,20 +1,24 @@
 package routes
 
 import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"github.com/go-chi/chi"
 )
 
-func registerCustomerRoutes(router chi.Router, handler interfaces.CustomerHandler) {
+func registerCustomerRoutes(router chi.Router, handler interfaces.CustomerHandler, middleware interfaces.Middleware) {
+	read := middleware.RequirePermission(rbac.CustomersRead)
+	write := middleware.RequirePermission(rbac.CustomersWrite)
+
 	router.Route("/customers", func(r chi.Router) {
-		r.Get("/", handler.Filter)
+		r.With(read).Get("/", handler.Filter)
 
-		r.Post("/", handler.Create)
+		r.With(write).Post("/", handler.Create)
 
-		r.Get("/{id}", handler.Get)
+		r.With(read).Get("/{id}", handler.Get)
 
-		r.Patch("/{id}", handler.Update)
+		r.With(write).Patch("/{id}", handler.Update)
 
-		r.Delete("/{id}", handler.Delete)
+		r.With(write).Delete("/{id}", handler.Delete)
 	})
 }
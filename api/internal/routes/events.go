@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/outbox"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+)
+
+// registerEventSubscribers subscribes the in-process side effects of domain
+// events to the outbox.
+func registerEventSubscribers(events interfaces.Outbox, orderService interfaces.OrderService, invoiceService interfaces.InvoiceService, outgoingWebhookService interfaces.OutgoingWebhookService, createOrgUseCase interfaces.CreateOrgUseCase) {
+	events.Subscribe(types.StockReplenishedEventType, "backorders", outbox.Typed(func(ctx context.Context, event types.StockReplenishedEvent) error {
+		return orderService.AllocateBackorders(ctx, event.VariantID)
+	}))
+
+	sendInvoice := outbox.Typed(func(ctx context.Context, event types.InvoiceEvent) error {
+		return invoiceService.SendEmail(ctx, event.InvoiceID, event.Status == model.InvoiceStatusProForma)
+	})
+	events.Subscribe(types.InvoiceProFormaSentEventType, "mailer", sendInvoice)
+	events.Subscribe(types.InvoiceIssuedEventType, "mailer", sendInvoice)
+
+	events.Subscribe(types.OrgCreatedEventType, "clerk", outbox.Typed(createOrgUseCase.SetClerkOrg))
+
+	for _, eventType := range types.WebhookEventTypes {
+		events.Subscribe(eventType, "webhooks", outgoingWebhookService.Dispatch)
+	}
+}
@@ -1,22 +1,30 @@
 package routes
 
 import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"github.com/go-chi/chi"
 )
 
-func registerInvoiceRoutes(router chi.Router, handler interfaces.InvoiceHandler) {
+func registerInvoiceRoutes(router chi.Router, handler interfaces.InvoiceHandler, middleware interfaces.Middleware) {
+	read := middleware.RequirePermission(rbac.InvoicesRead)
+	write := middleware.RequirePermission(rbac.InvoicesWrite)
+	issue := middleware.RequirePermission(rbac.InvoicesIssue)
+
 	router.Route("/invoices", func(r chi.Router) {
-		r.Get("/", handler.Filter)
+		r.With(read).Get("/", handler.Filter)
 
-		r.Post("/", handler.Create)
+		r.With(write).Post("/", handler.Create)
 
 		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", handler.Get)
-			r.Put("/", handler.Update)
-			r.Delete("/", handler.Delete)
+			r.With(read).Get("/", handler.Get)
+			r.With(write).Put("/", handler.Update)
+			r.With(write).Delete("/", handler.Delete)
 
-			r.Post("/issue", handler.Issue)
+			r.With(write).Post("/send-pro-forma", handler.SendProForma)
+			r.With(issue).Post("/issue", handler.Issue)
+			r.With(write).Post("/cancel", handler.Cancel)
+			r.With(issue).Post("/void", handler.Void)
 		})
 	})
 }
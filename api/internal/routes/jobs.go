@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"context"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/jobs"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/pkg/clerk"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+)
+
+// markOverdueInterval is how often invoices past their due date are marked
+// overdue.
+const markOverdueInterval = time.Hour
+
+// registerJobHandlers registers the handlers of the background jobs the
+// modules enqueue and schedules the recurring ones.
+func registerJobHandlers(
+	ctx context.Context,
+	queue interfaces.JobQueue,
+	invoiceService interfaces.InvoiceService,
+	creditNoteService interfaces.CreditNoteService,
+	purchaseOrderService interfaces.PurchaseOrderService,
+	outgoingWebhookService interfaces.OutgoingWebhookService,
+	clerkService clerk.Service,
+) error {
+	queue.Handle(types.CreditNoteEmailJobType, jobs.Typed(creditNoteService.SendEmail))
+	queue.Handle(types.PurchaseOrderEmailJobType, jobs.Typed(purchaseOrderService.SendEmail))
+	queue.Handle(types.WebhookDeliveryJobType, jobs.Typed(outgoingWebhookService.Deliver))
+	queue.Handle(types.DeleteClerkUserJobType, jobs.Typed(func(ctx context.Context, job types.DeleteClerkUserJob) error {
+		return clerkService.DeleteUser(ctx, job.ClerkID)
+	}))
+	queue.Handle(types.MarkOverdueInvoicesJobType, jobs.Typed(invoiceService.MarkOverdue))
+
+	return queue.Schedule(ctx, types.MarkOverdueInvoicesJobType, markOverdueInterval)
+}
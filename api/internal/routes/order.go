@@ -1,22 +1,33 @@
 package routes
 
 import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"github.com/go-chi/chi"
 )
 
-func registerOrderRoutes(router chi.Router, orderHandler interfaces.OrderHandler) {
+func registerOrderRoutes(router chi.Router, orderHandler interfaces.OrderHandler, middleware interfaces.Middleware) {
+	read := middleware.RequirePermission(rbac.OrdersRead)
+	write := middleware.RequirePermission(rbac.OrdersWrite)
+	approve := middleware.RequirePermission(rbac.OrdersApprove)
+	fulfil := middleware.RequirePermission(rbac.OrdersFulfil)
 
 	router.Route("/orders", func(r chi.Router) {
-		r.Get("/", orderHandler.Filter)
+		r.With(read).Get("/", orderHandler.Filter)
 
-		r.Post("/", orderHandler.Create)
+		r.With(write).Post("/", orderHandler.Create)
 
-		r.Get("/{id}", orderHandler.Get)
+		r.With(read).Get("/{id}", orderHandler.Get)
 
-		r.Patch("/{id}", orderHandler.Update)
+		r.With(write).Patch("/{id}", orderHandler.Update)
 
-		r.Delete("/{id}", orderHandler.Delete)
+		r.With(write).Delete("/{id}", orderHandler.Delete)
+
+		r.With(approve).Post("/{id}/approve", orderHandler.Approve)
+
+		r.With(fulfil).Post("/{id}/deliver", orderHandler.Deliver)
+
+		r.With(write).Post("/{id}/cancel", orderHandler.Cancel)
 
 	})
 }
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerOutgoingWebhookRoutes(router chi.Router, handler interfaces.OutgoingWebhookHandler, middleware interfaces.Middleware) {
+	// Subscriptions carry their signing secret, so reading them is managing them
+	manage := middleware.RequirePermission(rbac.WebhooksManage)
+
+	router.Route("/webhook-subscriptions", func(r chi.Router) {
+		r.Use(manage)
+
+		r.Get("/", handler.Filter)
+
+		r.Post("/", handler.Create)
+
+		r.Get("/{id}", handler.Get)
+
+		r.Patch("/{id}", handler.Update)
+
+		r.Delete("/{id}", handler.Delete)
+	})
+
+	router.Route("/webhook-deliveries", func(r chi.Router) {
+		r.Use(manage)
+
+		r.Get("/", handler.FilterDeliveries)
+
+		r.Get("/{id}", handler.GetDelivery)
+
+		r.Post("/{id}/redeliver", handler.Redeliver)
+	})
+}
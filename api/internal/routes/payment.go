@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerPaymentRoutes(router chi.Router, handler interfaces.PaymentHandler, middleware interfaces.Middleware) {
+	read := middleware.RequirePermission(rbac.PaymentsRead)
+	write := middleware.RequirePermission(rbac.PaymentsWrite)
+
+	router.Route("/payments", func(r chi.Router) {
+		r.With(read).Get("/", handler.Filter)
+
+		r.With(write).Post("/", handler.Create)
+
+		r.With(read).Get("/{id}", handler.Get)
+
+		r.With(write).Patch("/{id}", handler.Update)
+
+		r.With(write).Delete("/{id}", handler.Delete)
+	})
+}
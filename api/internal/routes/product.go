@@ -1,28 +1,32 @@
 package routes
 
 import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"github.com/go-chi/chi"
 )
 
-func registerProductRoutes(router chi.Router, productHandler interfaces.ProductHandler) {
+func registerProductRoutes(router chi.Router, productHandler interfaces.ProductHandler, middleware interfaces.Middleware) {
+	read := middleware.RequirePermission(rbac.ProductsRead)
+	write := middleware.RequirePermission(rbac.ProductsWrite)
 
 	router.Route("/products", func(r chi.Router) {
-		r.Get("/", productHandler.Filter)
+		r.With(read).Get("/", productHandler.Filter)
 
-		r.Post("/", productHandler.Create)
+		r.With(write).Post("/", productHandler.Create)
 
-		r.Get("/{id}", productHandler.Get)
+		r.With(read).Get("/{id}", productHandler.Get)
 
-		r.Patch("/{id}", productHandler.Update)
+		r.With(write).Patch("/{id}", productHandler.Update)
 
-		r.Delete("/{id}", productHandler.Delete)
+		r.With(write).Delete("/{id}", productHandler.Delete)
 
 		r.Route("/{productId}/variants", func(r chi.Router) {
-			r.Post("/", productHandler.CreateVariant)
-			r.Patch("/{id}", productHandler.UpdateVariant)
-			r.Delete("/{id}", productHandler.DeleteVariant)
-			r.Get("/{id}", productHandler.GetVariant)
+			r.With(write).Post("/", productHandler.CreateVariant)
+			r.With(write).Patch("/{id}", productHandler.UpdateVariant)
+			r.With(write).Delete("/{id}", productHandler.DeleteVariant)
+			r.With(read).Get("/{id}", productHandler.GetVariant)
+			r.With(read).Get("/{id}/movements", productHandler.FilterStockMovements)
 		})
 
 	})
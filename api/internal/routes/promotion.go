@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerPromotionRoutes(router chi.Router, handler interfaces.PromotionHandler, middleware interfaces.Middleware) {
+	read := middleware.RequirePermission(rbac.ProductsRead)
+	write := middleware.RequirePermission(rbac.ProductsWrite)
+
+	router.Route("/promotions", func(r chi.Router) {
+		r.With(read).Get("/", handler.Filter)
+
+		r.With(write).Post("/", handler.Create)
+
+		r.With(read).Get("/{id}", handler.Get)
+
+		r.With(write).Patch("/{id}", handler.Update)
+
+		r.With(write).Delete("/{id}", handler.Delete)
+
+		r.With(read).Get("/{id}/redemptions", handler.Redemptions)
+	})
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerPurchaseOrderRoutes(router chi.Router, handler interfaces.PurchaseOrderHandler, middleware interfaces.Middleware) {
+	read := middleware.RequirePermission(rbac.ProductsRead)
+	write := middleware.RequirePermission(rbac.ProductsWrite)
+
+	router.Route("/purchase-orders", func(r chi.Router) {
+		r.With(read).Get("/", handler.Filter)
+
+		r.With(write).Post("/", handler.Create)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.With(read).Get("/", handler.Get)
+			r.With(write).Patch("/", handler.Update)
+
+			r.With(write).Post("/send", handler.Send)
+			r.With(write).Post("/receive", handler.Receive)
+			r.With(write).Post("/close", handler.Close)
+		})
+	})
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerReportRoutes(router chi.Router, handler interfaces.ReportHandler, middleware interfaces.Middleware) {
+	router.Route("/reports", func(r chi.Router) {
+		r.Use(middleware.RequirePermission(rbac.ReportsRead))
+
+		r.Get("/sales", handler.Sales)
+	})
+}
@@ -1,18 +1,38 @@
 package routes
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"time"
 
 	"github.com/deveasyclick/openb2b/docs"
+	"github.com/deveasyclick/openb2b/internal/modules/apikey"
+	"github.com/deveasyclick/openb2b/internal/modules/auditlog"
+	"github.com/deveasyclick/openb2b/internal/modules/backorder"
+	"github.com/deveasyclick/openb2b/internal/modules/creditnote"
 	"github.com/deveasyclick/openb2b/internal/modules/customer"
+	"github.com/deveasyclick/openb2b/internal/modules/customergroup"
+	"github.com/deveasyclick/openb2b/internal/modules/exchangerate"
 	"github.com/deveasyclick/openb2b/internal/modules/invoice"
 	"github.com/deveasyclick/openb2b/internal/modules/order"
 	"github.com/deveasyclick/openb2b/internal/modules/org"
+	"github.com/deveasyclick/openb2b/internal/modules/outgoingwebhook"
+	"github.com/deveasyclick/openb2b/internal/modules/payment"
+	"github.com/deveasyclick/openb2b/internal/modules/pricelist"
 	"github.com/deveasyclick/openb2b/internal/modules/product"
+	"github.com/deveasyclick/openb2b/internal/modules/promotion"
+	"github.com/deveasyclick/openb2b/internal/modules/purchaseorder"
+	"github.com/deveasyclick/openb2b/internal/modules/report"
+	"github.com/deveasyclick/openb2b/internal/modules/shipment"
+	"github.com/deveasyclick/openb2b/internal/modules/stocktransfer"
+	"github.com/deveasyclick/openb2b/internal/modules/supplier"
+	"github.com/deveasyclick/openb2b/internal/modules/taxclass"
+	"github.com/deveasyclick/openb2b/internal/modules/taxrate"
 	"github.com/deveasyclick/openb2b/internal/modules/user"
+	"github.com/deveasyclick/openb2b/internal/modules/warehouse"
 	"github.com/deveasyclick/openb2b/internal/modules/webhook"
+	"github.com/deveasyclick/openb2b/internal/shared/audit"
 	"github.com/deveasyclick/openb2b/internal/shared/deps"
 	"github.com/deveasyclick/openb2b/pkg/clerk"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
@@ -26,6 +46,7 @@ import (
 func Register(r chi.Router, appCtx *deps.AppContext, middleware interfaces.Middleware, clerkService clerk.Service) {
 	r.Use(chiMiddleware.RequestID) // Adds a unique request ID
 	r.Use(chiMiddleware.RealIP)    // Gets the real IP from X-Forwarded-For
+	r.Use(audit.RequestInfo)       // Keeps the IP for the audit log
 	r.Use(chiMiddleware.Logger)
 	r.Use(chiMiddleware.Recoverer)
 	// Enable rate limiter of 100 requests per minute per IP
@@ -36,8 +57,8 @@ func Register(r chi.Router, appCtx *deps.AppContext, middleware interfaces.Middl
 		// AllowedOrigins:   []string{"https://foo.com"}, // Use this to allow specific origin hosts
 		AllowedOrigins:   []string{"http://localhost:3000", "http://localhost:5173"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Idempotency-Key"},
+		ExposedHeaders:   []string{"Link", "Idempotent-Replayed"},
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
@@ -57,25 +78,120 @@ func Register(r chi.Router, appCtx *deps.AppContext, middleware interfaces.Middl
 	createOrgUseCase := org.NewCreateUseCase(orgService, userService, clerkService, appCtx)
 	orgHandler := org.NewHandler(orgService, createOrgUseCase, appCtx)
 
+	// Warehouse
+	warehouseRepository := warehouse.NewRepository(appCtx.DB)
+	warehouseService := warehouse.NewService(warehouseRepository)
+	warehouseHandler := warehouse.NewHandler(warehouseService, appCtx)
+
 	// Product
 	productRepository := product.NewRepository(appCtx.DB)
-	productService := product.NewService(productRepository)
+	productService := product.NewService(productRepository, warehouseService, appCtx)
 	productHandler := product.NewHandler(productService, appCtx)
 
-	// Order
-	orderRepository := order.NewRepository(appCtx.DB)
-	orderService := order.NewService(orderRepository, productService)
-	orderHandler := order.NewHandler(orderService, appCtx)
-
 	// Customer
 	customerRepository := customer.NewRepository(appCtx.DB)
 	customerService := customer.NewService(customerRepository)
 	customerHandler := customer.NewHandler(customerService, appCtx)
 
+	// Customer group
+	customerGroupRepository := customergroup.NewRepository(appCtx.DB)
+	customerGroupService := customergroup.NewService(customerGroupRepository)
+	customerGroupHandler := customergroup.NewHandler(customerGroupService, appCtx)
+
+	// Tax
+	taxRateRepository := taxrate.NewRepository(appCtx.DB)
+	taxRateService := taxrate.NewService(taxRateRepository)
+	taxRateHandler := taxrate.NewHandler(taxRateService, appCtx)
+	taxClassRepository := taxclass.NewRepository(appCtx.DB)
+	taxClassService := taxclass.NewService(taxClassRepository, taxRateService)
+	taxClassHandler := taxclass.NewHandler(taxClassService, appCtx)
+
+	// Price list
+	priceListRepository := pricelist.NewRepository(appCtx.DB)
+	priceListService := pricelist.NewService(priceListRepository, orgService, customerService, customerGroupService, productService)
+	priceListHandler := pricelist.NewHandler(priceListService, appCtx)
+
+	// Promotion
+	promotionRepository := promotion.NewRepository(appCtx.DB)
+	promotionService := promotion.NewService(promotionRepository)
+	promotionHandler := promotion.NewHandler(promotionService, appCtx)
+
+	// Supplier
+	supplierRepository := supplier.NewRepository(appCtx.DB)
+	supplierService := supplier.NewService(supplierRepository)
+	supplierHandler := supplier.NewHandler(supplierService, appCtx)
+
+	// Purchase order
+	purchaseOrderRepository := purchaseorder.NewRepository(appCtx.DB)
+	purchaseOrderService := purchaseorder.NewService(purchaseOrderRepository, supplierService, productService, warehouseService, orgService, appCtx)
+	purchaseOrderHandler := purchaseorder.NewHandler(purchaseOrderService, appCtx)
+
+	// Stock transfer
+	stockTransferRepository := stocktransfer.NewRepository(appCtx.DB)
+	stockTransferService := stocktransfer.NewService(stockTransferRepository, warehouseService, productService, appCtx)
+	stockTransferHandler := stocktransfer.NewHandler(stockTransferService, appCtx)
+
+	// Exchange rate
+	exchangeRateRepository := exchangerate.NewRepository(appCtx.DB)
+	exchangeRateService := exchangerate.NewService(exchangeRateRepository, orgService)
+	exchangeRateHandler := exchangerate.NewHandler(exchangeRateService, appCtx)
+
+	// Order
+	orderRepository := order.NewRepository(appCtx.DB)
+	orderService := order.NewService(orderRepository, productService, customerService, orgService, exchangeRateService, priceListService, promotionService, taxClassService, warehouseService, appCtx)
+	orderHandler := order.NewHandler(orderService, appCtx)
+
+	// Backorder
+	backorderService := backorder.NewService(orderService, productService)
+	backorderHandler := backorder.NewHandler(backorderService, appCtx)
+
+	// Shipment
+	shipmentRepository := shipment.NewRepository(appCtx.DB)
+	shipmentService := shipment.NewService(shipmentRepository, orderService, appCtx)
+	shipmentHandler := shipment.NewHandler(shipmentService, appCtx)
+
 	// Invoice
 	invoiceRepository := invoice.NewRepository(appCtx.DB)
-	invoiceService := invoice.NewService(invoiceRepository, orderService, appCtx)
+	invoiceService := invoice.NewService(invoiceRepository, orderService, shipmentService, appCtx)
 	invoiceHandler := invoice.NewHandler(invoiceService, appCtx)
+
+	// Payment
+	paymentRepository := payment.NewRepository(appCtx.DB)
+	paymentService := payment.NewService(paymentRepository, invoiceService, customerService, appCtx)
+	paymentHandler := payment.NewHandler(paymentService, appCtx)
+
+	// Credit note
+	creditNoteRepository := creditnote.NewRepository(appCtx.DB)
+	creditNoteService := creditnote.NewService(creditNoteRepository, invoiceService, paymentService, orderService, appCtx)
+	creditNoteHandler := creditnote.NewHandler(creditNoteService, appCtx)
+
+	// Outgoing webhook
+	outgoingWebhookRepository := outgoingwebhook.NewRepository(appCtx.DB)
+	outgoingWebhookService := outgoingwebhook.NewService(outgoingWebhookRepository, appCtx)
+	outgoingWebhookHandler := outgoingwebhook.NewHandler(outgoingWebhookService, appCtx)
+
+	// API key
+	apiKeyRepository := apikey.NewRepository(appCtx.DB)
+	apiKeyService := apikey.NewService(apiKeyRepository, userService)
+	apiKeyHandler := apikey.NewHandler(apiKeyService, appCtx)
+
+	// Audit log
+	auditLogRepository := auditlog.NewRepository(appCtx.DB)
+	auditLogService := auditlog.NewService(auditLogRepository)
+	auditLogHandler := auditlog.NewHandler(auditLogService, appCtx)
+
+	// Report
+	reportRepository := report.NewRepository(appCtx.DB)
+	reportService := report.NewService(reportRepository, orgService)
+	reportHandler := report.NewHandler(reportService, appCtx)
+
+	err := registerJobHandlers(context.Background(), appCtx.Jobs, invoiceService, creditNoteService, purchaseOrderService, outgoingWebhookService, clerkService)
+	if err != nil {
+		// Scheduled again on the next start
+		appCtx.Logger.Error("failed to schedule jobs", "error", err)
+	}
+	registerEventSubscribers(appCtx.Events, orderService, invoiceService, outgoingWebhookService, createOrgUseCase)
+
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Use(chiMiddleware.SetHeader("Content-Type", "application/json"))
 
@@ -87,13 +203,32 @@ func Register(r chi.Router, appCtx *deps.AppContext, middleware interfaces.Middl
 
 		// Private routes
 		r.Group(func(r chi.Router) {
-			r.Use(middleware.ValidateJWT())
-			org.RegisterRoutes(r, orgHandler)
+			r.Use(middleware.Authenticate())
+			r.Use(middleware.Idempotent())
+			org.RegisterRoutes(r, orgHandler, middleware)
 			registerUserRoutes(r, userHandler)
-			registerProductRoutes(r, productHandler)
-			registerOrderRoutes(r, orderHandler)
-			registerCustomerRoutes(r, customerHandler)
-			registerInvoiceRoutes(r, invoiceHandler)
+			registerProductRoutes(r, productHandler, middleware)
+			registerOrderRoutes(r, orderHandler, middleware)
+			registerCustomerRoutes(r, customerHandler, middleware)
+			registerInvoiceRoutes(r, invoiceHandler, middleware)
+			registerPaymentRoutes(r, paymentHandler, middleware)
+			registerCreditNoteRoutes(r, creditNoteHandler, middleware)
+			registerOutgoingWebhookRoutes(r, outgoingWebhookHandler, middleware)
+			registerAPIKeyRoutes(r, apiKeyHandler, middleware)
+			registerAuditLogRoutes(r, auditLogHandler, middleware)
+			registerExchangeRateRoutes(r, exchangeRateHandler, middleware)
+			registerReportRoutes(r, reportHandler, middleware)
+			registerTaxRateRoutes(r, taxRateHandler, middleware)
+			registerTaxClassRoutes(r, taxClassHandler, middleware)
+			registerCustomerGroupRoutes(r, customerGroupHandler, middleware)
+			registerPriceListRoutes(r, priceListHandler, middleware)
+			registerPromotionRoutes(r, promotionHandler, middleware)
+			registerShipmentRoutes(r, shipmentHandler, middleware)
+			registerBackorderRoutes(r, backorderHandler, middleware)
+			registerSupplierRoutes(r, supplierHandler, middleware)
+			registerPurchaseOrderRoutes(r, purchaseOrderHandler, middleware)
+			registerWarehouseRoutes(r, warehouseHandler, middleware)
+			registerStockTransferRoutes(r, stockTransferHandler, middleware)
 		})
 	})
 
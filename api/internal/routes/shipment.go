@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerShipmentRoutes(router chi.Router, handler interfaces.ShipmentHandler, middleware interfaces.Middleware) {
+	read := middleware.RequirePermission(rbac.OrdersRead)
+	write := middleware.RequirePermission(rbac.OrdersWrite)
+	fulfil := middleware.RequirePermission(rbac.OrdersFulfil)
+
+	router.Route("/shipments", func(r chi.Router) {
+		r.With(read).Get("/", handler.Filter)
+
+		r.With(write).Post("/", handler.Create)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.With(read).Get("/", handler.Get)
+			r.With(write).Patch("/", handler.Update)
+
+			r.With(fulfil).Post("/ship", handler.Ship)
+			r.With(fulfil).Post("/deliver", handler.Deliver)
+			r.With(write).Post("/cancel", handler.Cancel)
+		})
+	})
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerStockTransferRoutes(router chi.Router, handler interfaces.StockTransferHandler, middleware interfaces.Middleware) {
+	read := middleware.RequirePermission(rbac.ProductsRead)
+	write := middleware.RequirePermission(rbac.ProductsWrite)
+
+	router.Route("/stock-transfers", func(r chi.Router) {
+		r.With(read).Get("/", handler.Filter)
+
+		r.With(write).Post("/", handler.Create)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.With(read).Get("/", handler.Get)
+
+			r.With(write).Post("/ship", handler.Ship)
+			r.With(write).Post("/receive", handler.Receive)
+			r.With(write).Post("/cancel", handler.Cancel)
+		})
+	})
+}
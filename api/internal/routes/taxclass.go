@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
+	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/go-chi/chi"
+)
+
+func registerTaxClassRoutes(router chi.Router, handler interfaces.TaxClassHandler, middleware interfaces.Middleware) {
+	read := middleware.RequirePermission(rbac.OrgsRead)
+	write := middleware.RequirePermission(rbac.OrgsWrite)
+
+	router.Route("/tax-classes", func(r chi.Router) {
+		r.With(read).Get("/", handler.Filter)
+
+		r.With(write).Post("/", handler.Create)
+
+		r.With(read).Get("/{id}", handler.Get)
+
+		r.With(write).Patch("/{id}", handler.Update)
+
+		r.With(write).Delete("/{id}", handler.Delete)
+	})
+}
@@ -1,5 +1,10 @@
 package apperrors
 
+import (
+	"fmt"
+	"strings"
+)
+
 type ValidationError struct {
 	Field string `json:"field"`
 	Tag   string `json:"tag"`
@@ -36,3 +41,26 @@ func (e *APIError) ToResponse() APIErrorResponse {
 		Message: e.Message,
 	}
 }
+
+// StockShortage describes a variant that doesn't have enough stock available.
+type StockShortage struct {
+	VariantID uint   `json:"variantId"`
+	SKU       string `json:"sku"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+}
+
+// InsufficientStockError is returned when one or more variants can't cover
+// the requested quantity.
+type InsufficientStockError struct {
+	Shortages []StockShortage
+}
+
+func (e *InsufficientStockError) Error() string {
+	parts := make([]string, 0, len(e.Shortages))
+	for _, s := range e.Shortages {
+		parts = append(parts, fmt.Sprintf("%s (requested %d, available %d)", s.SKU, s.Requested, s.Available))
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInsufficientStock, strings.Join(parts, ", "))
+}
@@ -8,13 +8,22 @@ const (
 	ErrInvalidFilter      = "invalid filter"
 	ErrDecodeRequestBody  = "failed to decode request body"
 
+	// Tenant
+	ErrMissingTenant  = "missing org in request context"
+	ErrTenantMismatch = "record belongs to another org"
+
+	// Access control
+	ErrForbidden   = "you do not have permission to perform this action"
+	ErrResolveRole = "error resolving user role"
+
 	// Customer
-	ErrCustomerNotFound = "customer not found"
-	ErrUpdateCustomer   = "error updating customer"
-	ErrDeleteCustomer   = "error deleting customer"
-	ErrFindCustomer     = "error finding customer"
-	ErrCreateCustomer   = "error creating customer"
-	ErrFilterCustomer   = "error filtering customers"
+	ErrCustomerNotFound   = "customer not found"
+	ErrUpdateCustomer     = "error updating customer"
+	ErrDeleteCustomer     = "error deleting customer"
+	ErrFindCustomer       = "error finding customer"
+	ErrCreateCustomer     = "error creating customer"
+	ErrFilterCustomer     = "error filtering customers"
+	ErrInsufficientCredit = "insufficient customer credit"
 
 	// Org
 	ErrOrgNotFound      = "org not found"
@@ -51,6 +60,12 @@ const (
 	ErrFindVariant          = "error finding variant"
 	ErrVariantNotFound      = "variant not found"
 
+	// Stock
+	ErrInsufficientStock   = "insufficient stock"
+	ErrStockOutOfSync      = "stock reservation out of sync"
+	ErrStockBelowReserved  = "stock cannot be lower than the reserved quantity"
+	ErrFilterStockMovement = "error filtering stock movements"
+
 	// Product
 	ErrOrderAlreadyExists = "order already exists"
 	ErrCreateOrder        = "error creating order"
@@ -61,6 +76,10 @@ const (
 	ErrFilterOrder        = "error filtering orders"
 	ErrOrderNotPending    = "order not in pending"
 
+	ErrInvalidOrderTransition = "invalid order status transition"
+	ErrTransitionOrder        = "error changing order status"
+	ErrUnknownChargeTaxClass  = "unknown tax class for order charge"
+
 	// Invoice
 	ErrInvoiceAlreadyExists = "invoice already exists"
 	ErrCreateInvoice        = "error creating invoice"
@@ -69,9 +88,191 @@ const (
 	ErrFindInvoice          = "error finding invoice"
 	ErrInvoiceNotFound      = "invoice not found"
 	ErrFilterInvoice        = "error filtering invoices"
-	ErrIssueInvoice         = "error issuing invoice"
 	ErrInvalidInvoiceStatus = "invalid invoice status"
+	ErrInvoiceLocked        = "invoice cannot be changed once issued"
+	ErrTransitionInvoice    = "error changing invoice status"
+	ErrInvoiceNotPayable    = "payments can only be recorded against issued invoices"
+
+	// Credit note
+	ErrCreateCreditNote       = "error creating credit note"
+	ErrFindCreditNote         = "error finding credit note"
+	ErrCreditNoteNotFound     = "credit note not found"
+	ErrFilterCreditNote       = "error filtering credit notes"
+	ErrSendCreditNote         = "error sending credit note"
+	ErrInvoiceNotIssued       = "credit notes can only be raised against issued invoices"
+	ErrInvoiceItemNotFound    = "invoice item not found on invoice"
+	ErrNothingToCredit        = "everything on the invoice has been credited already"
+	ErrCreditQuantityExceeded = "credit quantity exceeds the invoiced quantity"
+
+	// Payment
+	ErrCreatePayment           = "error creating payment"
+	ErrUpdatePayment           = "error updating payment"
+	ErrDeletePayment           = "error deleting payment"
+	ErrFindPayment             = "error finding payment"
+	ErrPaymentNotFound         = "payment not found"
+	ErrFilterPayment           = "error filtering payments"
+	ErrPaymentCurrencyMismatch = "payment currency must match the invoice currency"
 
 	// Webhook
 	ErrEmailNotFoundInClerkWebhook = "email not found in clerk webhook"
+
+	// Webhook subscription
+	ErrCreateWebhookSubscription   = "error creating webhook subscription"
+	ErrUpdateWebhookSubscription   = "error updating webhook subscription"
+	ErrDeleteWebhookSubscription   = "error deleting webhook subscription"
+	ErrFindWebhookSubscription     = "error finding webhook subscription"
+	ErrWebhookSubscriptionNotFound = "webhook subscription not found"
+	ErrFilterWebhookSubscription   = "error filtering webhook subscriptions"
+	ErrFindWebhookDelivery         = "error finding webhook delivery"
+	ErrWebhookDeliveryNotFound     = "webhook delivery not found"
+	ErrFilterWebhookDelivery       = "error filtering webhook deliveries"
+	ErrRedeliverWebhook            = "error redelivering webhook"
+
+	// API key
+	ErrCreateAPIKey          = "error creating api key"
+	ErrRevokeAPIKey          = "error revoking api key"
+	ErrFindAPIKey            = "error finding api key"
+	ErrAPIKeyNotFound        = "api key not found"
+	ErrFilterAPIKey          = "error filtering api keys"
+	ErrAPIKeyScopeNotGranted = "api key scopes must be permissions you are granted yourself"
+
+	// Idempotency
+	ErrIdempotencyKeyTooLong        = "idempotency key must be at most 255 characters"
+	ErrIdempotencyKeyReused         = "idempotency key was already used for a different request"
+	ErrIdempotencyRequestInProgress = "a request with this idempotency key is still in progress"
+	ErrCheckIdempotencyKey          = "error checking idempotency key"
+
+	// Audit log
+	ErrFilterAuditLog     = "error filtering audit logs"
+	ErrFindAuditEntry     = "error finding audit log entry"
+	ErrAuditEntryNotFound = "audit log entry not found"
+
+	// Exchange rate
+	ErrCreateExchangeRate   = "error creating exchange rate"
+	ErrDeleteExchangeRate   = "error deleting exchange rate"
+	ErrFindExchangeRate     = "error finding exchange rate"
+	ErrExchangeRateNotFound = "exchange rate not found"
+	ErrFilterExchangeRate   = "error filtering exchange rates"
+	ErrNoExchangeRate       = "no exchange rate to the base currency"
+	ErrSameCurrencyRate     = "exchange rate currency must differ from the base currency"
+
+	// Report
+	ErrInvalidReportPeriod = "invalid report period"
+	ErrSalesReport         = "error building sales report"
+
+	// Tax
+	ErrCreateTaxRate    = "error creating tax rate"
+	ErrUpdateTaxRate    = "error updating tax rate"
+	ErrDeleteTaxRate    = "error deleting tax rate"
+	ErrFindTaxRate      = "error finding tax rate"
+	ErrTaxRateNotFound  = "tax rate not found"
+	ErrFilterTaxRate    = "error filtering tax rates"
+	ErrCreateTaxClass   = "error creating tax class"
+	ErrUpdateTaxClass   = "error updating tax class"
+	ErrDeleteTaxClass   = "error deleting tax class"
+	ErrFindTaxClass     = "error finding tax class"
+	ErrTaxClassNotFound = "tax class not found"
+	ErrFilterTaxClass   = "error filtering tax classes"
+	ErrTaxClassInUse    = "tax class is used by variants"
+	ErrUnknownTaxRates  = "one or more tax rates do not exist"
+
+	// Customer group
+	ErrCreateCustomerGroup   = "error creating customer group"
+	ErrUpdateCustomerGroup   = "error updating customer group"
+	ErrDeleteCustomerGroup   = "error deleting customer group"
+	ErrFindCustomerGroup     = "error finding customer group"
+	ErrCustomerGroupNotFound = "customer group not found"
+	ErrFilterCustomerGroup   = "error filtering customer groups"
+
+	// Price list
+	ErrCreatePriceList        = "error creating price list"
+	ErrUpdatePriceList        = "error updating price list"
+	ErrDeletePriceList        = "error deleting price list"
+	ErrFindPriceList          = "error finding price list"
+	ErrPriceListNotFound      = "price list not found"
+	ErrFilterPriceList        = "error filtering price lists"
+	ErrUnknownPriceListRefs   = "price list refers to customers, groups or variants that do not exist"
+	ErrInvalidPriceListPeriod = "price list must stop applying after it starts"
+
+	// Promotion
+	ErrCreatePromotion            = "error creating promotion"
+	ErrUpdatePromotion            = "error updating promotion"
+	ErrDeletePromotion            = "error deleting promotion"
+	ErrFindPromotion              = "error finding promotion"
+	ErrPromotionNotFound          = "promotion not found"
+	ErrFilterPromotion            = "error filtering promotions"
+	ErrFilterPromotionRedemption  = "error filtering promotion redemptions"
+	ErrPromotionCodeExists        = "promotion code already exists"
+	ErrInvalidPromotionPeriod     = "promotion must end after it starts"
+	ErrInvalidPromotionPercentage = "promotion percentage must be at most 100"
+	ErrUnknownPromotion           = "unknown promotion code"
+	ErrPromotionInactive          = "promotion is not active"
+	ErrPromotionUsedUp            = "promotion has reached its usage limit"
+	ErrPromotionNotApplicable     = "promotion does not apply to any item of the order"
+	ErrPromotionMinOrderValue     = "order is below the minimum order value of the promotion"
+
+	// Shipment
+	ErrCreateShipment            = "error creating shipment"
+	ErrUpdateShipment            = "error updating shipment"
+	ErrFindShipment              = "error finding shipment"
+	ErrShipmentNotFound          = "shipment not found"
+	ErrFilterShipment            = "error filtering shipments"
+	ErrTransitionShipment        = "error changing shipment status"
+	ErrInvalidShipmentTransition = "invalid shipment status transition"
+	ErrShipmentLocked            = "shipment cannot be changed once delivered or cancelled"
+	ErrShipmentOrderNotApproved  = "only approved orders can be shipped"
+	ErrShipmentOrderItemNotFound = "order item not found on order"
+	ErrShipmentQuantityExceeded  = "shipment quantity exceeds what is left to ship"
+	ErrNothingToShip             = "nothing on the order is left to ship, the rest is shipped or backordered"
+	ErrShipmentNotDelivered      = "only delivered shipments of the order can be invoiced"
+	ErrShipmentAlreadyInvoiced   = "shipment has been invoiced already"
+
+	// Backorder
+	ErrBackorderReport = "error building backorder report"
+
+	// Supplier
+	ErrCreateSupplier            = "error creating supplier"
+	ErrUpdateSupplier            = "error updating supplier"
+	ErrDeleteSupplier            = "error deleting supplier"
+	ErrFindSupplier              = "error finding supplier"
+	ErrSupplierNotFound          = "supplier not found"
+	ErrFilterSupplier            = "error filtering suppliers"
+	ErrSupplierHasPurchaseOrders = "supplier has purchase orders and cannot be deleted"
+
+	// Purchase order
+	ErrCreatePurchaseOrder            = "error creating purchase order"
+	ErrUpdatePurchaseOrder            = "error updating purchase order"
+	ErrFindPurchaseOrder              = "error finding purchase order"
+	ErrPurchaseOrderNotFound          = "purchase order not found"
+	ErrFilterPurchaseOrder            = "error filtering purchase orders"
+	ErrTransitionPurchaseOrder        = "error changing purchase order status"
+	ErrReceivePurchaseOrder           = "error receiving purchase order"
+	ErrInvalidPurchaseOrderTransition = "invalid purchase order status transition"
+	ErrPurchaseOrderLocked            = "only draft purchase orders can be changed"
+	ErrPurchaseOrderNoLines           = "purchase order must have at least one line"
+	ErrUnknownPurchaseOrderRefs       = "purchase order refers to a supplier, warehouse or variants that do not exist"
+	ErrPurchaseOrderNotReceivable     = "only sent or partially received purchase orders can be received"
+	ErrPurchaseOrderLineNotFound      = "line not found on purchase order"
+	ErrReceiptQuantityExceeded        = "received quantity exceeds what is still expected"
+	ErrNothingToReceive               = "nothing on the purchase order is still expected"
+
+	// Warehouse
+	ErrCreateWarehouse        = "error creating warehouse"
+	ErrUpdateWarehouse        = "error updating warehouse"
+	ErrDeleteWarehouse        = "error deleting warehouse"
+	ErrFindWarehouse          = "error finding warehouse"
+	ErrWarehouseNotFound      = "warehouse not found"
+	ErrFilterWarehouse        = "error filtering warehouses"
+	ErrWarehouseCodeTaken     = "warehouse code already exists"
+	ErrDeleteDefaultWarehouse = "the default warehouse cannot be deleted"
+	ErrWarehouseInUse         = "warehouse holds stock or has open orders or transfers and cannot be deleted"
+
+	// Stock transfer
+	ErrCreateStockTransfer            = "error creating stock transfer"
+	ErrFindStockTransfer              = "error finding stock transfer"
+	ErrStockTransferNotFound          = "stock transfer not found"
+	ErrFilterStockTransfer            = "error filtering stock transfers"
+	ErrTransitionStockTransfer        = "error changing stock transfer status"
+	ErrInvalidStockTransferTransition = "invalid stock transfer status transition"
+	ErrUnknownStockTransferRefs       = "stock transfer refers to warehouses or variants that do not exist"
 )
@@ -0,0 +1,435 @@
+// Package audit records every change made through GORM to the audit log.
+//
+// Register installs GORM callbacks that, after each create, update and
+// delete, store a model.AuditEntry per affected row in the same transaction
+// as the change. An entry holds the columns that changed with their values
+// before and after, the user from identity.UserFromContext, and the request
+// ID and IP that RequestInfo puts in the request context.
+//
+// Rows that belong to no org, and the tables of the job queue, outbox and
+// other plumbing, are not audited. Raw SQL statements are not seen by the
+// callbacks either. Fields tagged `audit:"-"`, such as secrets, are never
+// stored in an entry.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"reflect"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/tenant"
+	chiMiddleware "github.com/go-chi/chi/middleware"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type contextKey string
+
+const (
+	ipKey contextKey = "auditIP"
+
+	beforeKey = "audit:before"
+)
+
+// skipped are the models whose changes are not audited.
+var skipped = typesOf(
+	&model.AuditEntry{},
+	&model.Job{},
+	&model.OutboxEvent{},
+	&model.WebhookDelivery{},
+	&model.IdempotencyKey{},
+)
+
+// ignoredColumns are bookkeeping columns whose changes alone are not worth
+// an entry.
+var ignoredColumns = map[string]bool{
+	"created_at":   true,
+	"updated_at":   true,
+	"deleted_at":   true,
+	"last_used_at": true,
+}
+
+func typesOf(models ...any) map[reflect.Type]bool {
+	types := make(map[reflect.Type]bool, len(models))
+	for _, m := range models {
+		types[reflect.TypeOf(m).Elem()] = true
+	}
+	return types
+}
+
+// RequestInfo is an HTTP middleware that puts the IP of the client in the
+// request context for the audit log. It must run after chi's RealIP.
+func RequestInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ipKey, ip)))
+	})
+}
+
+// Register installs the audit callbacks on db.
+func Register(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("audit:before_create", loadUpserted); err != nil {
+		return err
+	}
+	if err := cb.Create().After("gorm:create").Register("audit:create", afterCreate); err != nil {
+		return err
+	}
+	if err := cb.Update().After("tenant:update").Before("gorm:update").Register("audit:before_update", loadBefore); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("audit:update", afterUpdate); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("tenant:delete").Before("gorm:delete").Register("audit:before_delete", loadBefore); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("audit:delete", afterDelete)
+}
+
+func audited(db *gorm.DB) bool {
+	stmt := db.Statement
+	return stmt.Schema != nil && stmt.Schema.PrioritizedPrimaryField != nil && !skipped[stmt.Schema.ModelType]
+}
+
+// loadUpserted keeps the stored rows of a create whose rows already have a
+// primary key. Saving associations, as an order update does with its items,
+// upserts existing rows through the create callbacks, and these rows are
+// diffed against what was stored instead of being recorded as created.
+func loadUpserted(db *gorm.DB) {
+	if db.Error != nil || !audited(db) {
+		return
+	}
+
+	stmt := db.Statement
+	pk := stmt.Schema.PrioritizedPrimaryField
+	var ids []any
+	eachRow(stmt.ReflectValue, func(rv reflect.Value) {
+		if id, isZero := pk.ValueOf(stmt.Context, rv); !isZero {
+			ids = append(ids, id)
+		}
+	})
+	if len(ids) == 0 {
+		return
+	}
+
+	rows := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Unscoped().
+		Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Values: ids}).
+		Find(rows.Interface()).Error
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	db.InstanceSet(beforeKey, rows.Elem())
+}
+
+func afterCreate(db *gorm.DB) {
+	if db.Error != nil || !audited(db) {
+		return
+	}
+
+	stmt := db.Statement
+	pk := stmt.Schema.PrioritizedPrimaryField
+	storedByID := make(map[any]reflect.Value)
+	if before, ok := beforeRows(db); ok {
+		eachRow(before, func(rv reflect.Value) {
+			id, _ := pk.ValueOf(stmt.Context, rv)
+			storedByID[id] = rv
+		})
+	}
+
+	var entries []model.AuditEntry
+	eachRow(stmt.ReflectValue, func(rv reflect.Value) {
+		action := model.AuditActionCreate
+		var before map[string]any
+		id, _ := pk.ValueOf(stmt.Context, rv)
+		if stored, ok := storedByID[id]; ok {
+			action = model.AuditActionUpdate
+			before = columns(stmt, stored)
+		}
+
+		changes := diff(before, columns(stmt, rv))
+		if len(changes) == 0 {
+			return
+		}
+		if entry := newEntry(stmt, rv, action, changes); entry != nil {
+			entries = append(entries, *entry)
+		}
+	})
+
+	save(db, entries)
+}
+
+// loadBefore keeps the rows an update or delete is about to change, to diff
+// them against afterwards.
+func loadBefore(db *gorm.DB) {
+	if db.Error != nil || !audited(db) {
+		return
+	}
+
+	stmt := db.Statement
+	query := db.Session(&gorm.Session{NewDB: true}).Model(stmt.Model)
+	hasWhere := false
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			query = query.Clauses(clause.Where{Exprs: where.Exprs})
+			hasWhere = true
+		}
+	}
+	// Updates and deletes of a loaded row are narrowed to it by primary key
+	// only once GORM builds the statement
+	if stmt.ReflectValue.Kind() == reflect.Struct {
+		pk := stmt.Schema.PrioritizedPrimaryField
+		if value, isZero := pk.ValueOf(stmt.Context, stmt.ReflectValue); !isZero {
+			query = query.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Value: value})
+			hasWhere = true
+		}
+	}
+	// GORM rejects the statement itself
+	if !hasWhere {
+		return
+	}
+
+	rows := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+	if err := query.Find(rows.Interface()).Error; err != nil {
+		db.AddError(err)
+		return
+	}
+
+	db.InstanceSet(beforeKey, rows.Elem())
+}
+
+func afterUpdate(db *gorm.DB) {
+	before, ok := beforeRows(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+
+	stmt := db.Statement
+	pk := stmt.Schema.PrioritizedPrimaryField
+	ids := make([]any, 0, before.Len())
+	eachRow(before, func(rv reflect.Value) {
+		id, _ := pk.ValueOf(stmt.Context, rv)
+		ids = append(ids, id)
+	})
+	if len(ids) == 0 {
+		return
+	}
+
+	after := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Unscoped().
+		Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Values: ids}).
+		Find(after.Interface()).Error
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	afterByID := make(map[any]reflect.Value, after.Elem().Len())
+	eachRow(after.Elem(), func(rv reflect.Value) {
+		id, _ := pk.ValueOf(stmt.Context, rv)
+		afterByID[id] = rv
+	})
+
+	var entries []model.AuditEntry
+	eachRow(before, func(rv reflect.Value) {
+		id, _ := pk.ValueOf(stmt.Context, rv)
+		updated, ok := afterByID[id]
+		if !ok {
+			return
+		}
+
+		changes := diff(columns(stmt, rv), columns(stmt, updated))
+		if len(changes) == 0 {
+			return
+		}
+		if entry := newEntry(stmt, updated, model.AuditActionUpdate, changes); entry != nil {
+			entries = append(entries, *entry)
+		}
+	})
+
+	save(db, entries)
+}
+
+func afterDelete(db *gorm.DB) {
+	before, ok := beforeRows(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+
+	var entries []model.AuditEntry
+	eachRow(before, func(rv reflect.Value) {
+		changes := diff(columns(db.Statement, rv), nil)
+		if entry := newEntry(db.Statement, rv, model.AuditActionDelete, changes); entry != nil {
+			entries = append(entries, *entry)
+		}
+	})
+
+	save(db, entries)
+}
+
+func beforeRows(db *gorm.DB) (reflect.Value, bool) {
+	if db.Error != nil {
+		return reflect.Value{}, false
+	}
+
+	value, ok := db.InstanceGet(beforeKey)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	rows, ok := value.(reflect.Value)
+	return rows, ok
+}
+
+// newEntry builds the entry of a change to row rv. It returns nil for rows
+// of no org.
+func newEntry(stmt *gorm.Statement, rv reflect.Value, action model.AuditAction, changes map[string]model.AuditChange) *model.AuditEntry {
+	ctx := stmt.Context
+
+	orgID := rowOrg(stmt, rv)
+	if orgID == 0 {
+		return nil
+	}
+
+	id, _ := stmt.Schema.PrioritizedPrimaryField.ValueOf(ctx, rv)
+	entityID, ok := id.(uint)
+	if !ok {
+		return nil
+	}
+
+	entry := &model.AuditEntry{
+		OrgID:      orgID,
+		EntityType: stmt.Schema.Table,
+		EntityID:   entityID,
+		Action:     action,
+		Changes:    changes,
+		RequestID:  chiMiddleware.GetReqID(ctx),
+	}
+	entry.IP, _ = ctx.Value(ipKey).(string)
+
+	if user, err := identity.UserFromContext(ctx); err == nil && user.ID != 0 {
+		actorID := user.ID
+		entry.ActorID = &actorID
+		if user.APIKeyID != 0 {
+			apiKeyID := user.APIKeyID
+			entry.APIKeyID = &apiKeyID
+		}
+	}
+
+	return entry
+}
+
+// rowOrg returns the org of row rv, falling back to the org of the context
+// for models that don't carry one.
+func rowOrg(stmt *gorm.Statement, rv reflect.Value) uint {
+	if field := stmt.Schema.LookUpField("OrgID"); field != nil {
+		value, isZero := field.ValueOf(stmt.Context, rv)
+		if !isZero {
+			switch org := value.(type) {
+			case uint:
+				return org
+			case *uint:
+				if org != nil {
+					return *org
+				}
+			}
+		}
+	}
+
+	if stmt.Schema.ModelType == reflect.TypeOf(model.Org{}) {
+		id, _ := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, rv)
+		org, _ := id.(uint)
+		return org
+	}
+
+	org, _ := tenant.OrgFromContext(stmt.Context)
+	return org
+}
+
+// columns returns the column values of row rv. Fields tagged `audit:"-"`
+// and fields hidden from JSON are left out.
+func columns(stmt *gorm.Statement, rv reflect.Value) map[string]any {
+	values := make(map[string]any, len(stmt.Schema.DBNames))
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || !field.Readable || field.Tag.Get("audit") == "-" || field.Tag.Get("json") == "-" {
+			continue
+		}
+		// ValueOf wraps serialized fields, while ReflectValueOf allocates
+		// nil embedded structs
+		if field.Serializer != nil {
+			values[field.DBName] = field.ReflectValueOf(stmt.Context, rv).Interface()
+			continue
+		}
+		values[field.DBName], _ = field.ValueOf(stmt.Context, rv)
+	}
+	return values
+}
+
+// diff returns the columns whose values differ between before and after.
+// Either may be nil, for creates and deletes.
+func diff(before map[string]any, after map[string]any) map[string]model.AuditChange {
+	changes := make(map[string]model.AuditChange)
+	add := func(column string) {
+		if _, seen := changes[column]; seen || ignoredColumns[column] {
+			return
+		}
+
+		old, _ := json.Marshal(before[column])
+		updated, _ := json.Marshal(after[column])
+		if string(old) != string(updated) {
+			changes[column] = model.AuditChange{Before: before[column], After: after[column]}
+		}
+	}
+
+	for column := range before {
+		add(column)
+	}
+	for column := range after {
+		add(column)
+	}
+	return changes
+}
+
+func eachRow(rv reflect.Value, fn func(reflect.Value)) {
+	rv = reflect.Indirect(rv)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			row := reflect.Indirect(rv.Index(i))
+			if row.Kind() == reflect.Struct {
+				fn(row)
+			}
+		}
+	case reflect.Struct:
+		fn(rv)
+	}
+}
+
+// save stores entries in the transaction of the change they record.
+func save(db *gorm.DB, entries []model.AuditEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	// Entries carry their org, which may differ from the context's when the
+	// change was made by the system
+	system := tenant.Bypass(db.Statement.Context)
+	err := db.Session(&gorm.Session{NewDB: true, Context: system}).Create(&entries).Error
+	if err != nil {
+		db.AddError(err)
+	}
+}
@@ -5,6 +5,8 @@
 package deps
 
 import (
+	"net/http"
+
 	"github.com/deveasyclick/openb2b/internal/config"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
 	"gorm.io/gorm"
@@ -29,6 +31,18 @@ type AppContext struct {
 
 	// Mailer sends emails (invoices, reminders, notifications).
 	Mailer interfaces.Mailer
+
+	// Jobs enqueues background jobs (emails, cleanups) for the job worker.
+	Jobs interfaces.JobQueue
+
+	// Events records domain events in the transaction of the change they
+	// describe, for the outbox relay to publish.
+	Events interfaces.Outbox
+
+	// HTTPClient sends requests to addresses orgs configure, e.g. webhook
+	// endpoints. Use safehttp.NewClient, which keeps them off the internal
+	// network.
+	HTTPClient *http.Client
 }
 
 // NewAppContext creates and returns a new AppContext instance with the
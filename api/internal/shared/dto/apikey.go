@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+)
+
+// CreateAPIKeyDTO creates an API key for an integration of the org
+type CreateAPIKeyDTO struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// Scopes are permissions, e.g. "orders:read", granted to the key
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,required"`
+	// ExpiresAt defaults to never
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" validate:"omitempty,gt"`
+}
+
+// ToModel converts CreateAPIKeyDTO to an APIKey created by userID
+func (dto *CreateAPIKeyDTO) ToModel(orgID uint, userID uint) *model.APIKey {
+	return &model.APIKey{
+		OrgID:       orgID,
+		Name:        dto.Name,
+		Scopes:      dto.Scopes,
+		ExpiresAt:   dto.ExpiresAt,
+		CreatedByID: userID,
+	}
+}
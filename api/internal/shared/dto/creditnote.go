@@ -0,0 +1,17 @@
+package dto
+
+// CreateCreditNoteItemDTO credits a quantity of an invoice item
+type CreateCreditNoteItemDTO struct {
+	InvoiceItemID uint `json:"invoiceItemId" validate:"required"`
+	Quantity      int  `json:"quantity" validate:"required,gt=0"`
+}
+
+// CreateCreditNoteDTO represents incoming API payload to credit an issued
+// invoice. Without items, everything not credited yet is credited.
+type CreateCreditNoteDTO struct {
+	InvoiceID uint                      `json:"invoiceId" validate:"required"`
+	Reason    string                    `json:"reason" validate:"required,max=500"`
+	Items     []CreateCreditNoteItemDTO `json:"items,omitempty" validate:"omitempty,dive"`
+	// Restock puts the credited quantities back on hand, e.g. for returned goods.
+	Restock bool `json:"restock,omitempty"`
+}
@@ -10,6 +10,10 @@ type CreateCustomerDTO struct {
 	Email       string           `json:"email,omitempty"`
 	Address     *AddressOptional `json:"address,omitempty"`
 	Company     string           `json:"company,omitempty"`
+	Currency    string           `json:"currency,omitempty" validate:"omitempty,len=3,uppercase"` // billing currency, defaults to the org base currency
+	TaxExempt   bool             `json:"taxExempt,omitempty"`
+	// CustomerGroupID puts the customer in a group, for price lists
+	CustomerGroupID *uint `json:"customerGroupId,omitempty" validate:"omitempty,gt=0"`
 }
 
 // ToModel converts CreateCustomerDTO to a Customer model
@@ -20,7 +24,11 @@ func (dto *CreateCustomerDTO) ToModel(orgID uint) *model.Customer {
 		PhoneNumber: dto.PhoneNumber,
 		Email:       dto.Email,
 		Company:     dto.Company,
+		Currency:    dto.Currency,
+		TaxExempt:   dto.TaxExempt,
 		OrgID:       orgID,
+
+		CustomerGroupID: dto.CustomerGroupID,
 	}
 
 	if dto.Address != nil {
@@ -35,6 +43,10 @@ type UpdateCustomerDTO struct {
 	Email     *string          `json:"email" validate:"omitempty,max=100"`
 	Company   *string          `json:"company" validate:"omitempty,max=100"`
 	Address   *AddressOptional `json:"address,omitempty"`
+	Currency  *string          `json:"currency" validate:"omitempty,len=3,uppercase"`
+	TaxExempt *bool            `json:"taxExempt"`
+	// CustomerGroupID moves the customer to a group, 0 removes it
+	CustomerGroupID *uint `json:"customerGroupId"`
 }
 
 // ApplyModel updates an existing Customer model with DTO values
@@ -57,4 +69,20 @@ func (dto *UpdateCustomerDTO) ApplyModel(c *model.Customer) {
 	if dto.Email != nil {
 		c.Email = *dto.Email
 	}
+
+	if dto.Currency != nil {
+		c.Currency = *dto.Currency
+	}
+
+	if dto.TaxExempt != nil {
+		c.TaxExempt = *dto.TaxExempt
+	}
+
+	if dto.CustomerGroupID != nil {
+		if *dto.CustomerGroupID == 0 {
+			c.CustomerGroupID = nil
+		} else {
+			c.CustomerGroupID = dto.CustomerGroupID
+		}
+	}
 }
@@ -0,0 +1,28 @@
+package dto
+
+import "github.com/deveasyclick/openb2b/internal/model"
+
+// CreateCustomerGroupDTO represents incoming API data to create a customer
+// group
+type CreateCustomerGroupDTO struct {
+	Name string `json:"name" validate:"required,max=100" example:"Wholesale"`
+}
+
+// ToModel converts CreateCustomerGroupDTO to a CustomerGroup of the org
+func (dto *CreateCustomerGroupDTO) ToModel(orgID uint) *model.CustomerGroup {
+	return &model.CustomerGroup{
+		OrgID: orgID,
+		Name:  dto.Name,
+	}
+}
+
+type UpdateCustomerGroupDTO struct {
+	Name *string `json:"name" validate:"omitempty,max=100"`
+}
+
+// ApplyModel updates an existing CustomerGroup with DTO values
+func (dto *UpdateCustomerGroupDTO) ApplyModel(group *model.CustomerGroup) {
+	if dto.Name != nil {
+		group.Name = *dto.Name
+	}
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+)
+
+// CreateExchangeRateDTO records what one unit of a currency is worth in the
+// base currency of the org
+type CreateExchangeRateDTO struct {
+	Currency string  `json:"currency" validate:"required,len=3,uppercase" example:"USD"`
+	Rate     float64 `json:"rate" validate:"required,gt=0" example:"1550.25"`
+	// EffectiveAt defaults to now
+	EffectiveAt *time.Time `json:"effectiveAt,omitempty" validate:"omitempty"`
+}
+
+// ToModel converts CreateExchangeRateDTO to an ExchangeRate of the org
+func (dto *CreateExchangeRateDTO) ToModel(orgID uint) *model.ExchangeRate {
+	rate := &model.ExchangeRate{
+		OrgID:    orgID,
+		Currency: dto.Currency,
+		Rate:     dto.Rate,
+	}
+	if dto.EffectiveAt != nil {
+		rate.EffectiveAt = *dto.EffectiveAt
+	}
+	return rate
+}
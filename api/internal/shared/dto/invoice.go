@@ -4,12 +4,16 @@ import (
 	"time"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/deveasyclick/openb2b/internal/utils/numbergen"
 )
 
 type CreateInvoiceDTO struct {
-	OrderID uint   `json:"orderId" validate:"required"`
-	Notes   string `json:"notes,omitempty"`
+	OrderID uint `json:"orderId" validate:"required"`
+	// ShipmentID invoices only what a delivered shipment of the order
+	// delivered instead of the whole order
+	ShipmentID *uint  `json:"shipmentId,omitempty"`
+	Notes      string `json:"notes,omitempty"`
 }
 
 // CreateInvoiceDTO represents incoming API payload to create an invoice
@@ -30,10 +34,16 @@ func (d *CreateInvoiceDTO) ToModel(
 		CustomerAddress: order.Customer.Address,
 
 		// Snapshot financial data
-		Subtotal:      order.Subtotal,
-		TaxTotal:      order.TaxTotal,
-		DiscountTotal: order.DiscountTotal,
-		Total:         order.Total,
+		Currency:         order.Currency,
+		ExchangeRate:     order.ExchangeRate,
+		PricesIncludeTax: order.PricesIncludeTax,
+		TaxExempt:        order.TaxExempt,
+		Subtotal:         order.Subtotal,
+		ChargeTotal:      order.ChargeTotal,
+		TaxTotal:         order.TaxTotal,
+		DiscountTotal:    order.DiscountTotal,
+		Total:            order.Total,
+		AmountDue:        order.Total,
 	}
 
 	// Copy order items into invoice items
@@ -46,28 +56,103 @@ func (d *CreateInvoiceDTO) ToModel(
 			Quantity:  oi.Quantity,
 			UnitPrice: oi.UnitPrice,
 			TaxAmount: oi.TaxAmount,
+			Taxes:     oi.Taxes,
 			LineTotal: oi.Total,
-			Subtotal:  float64(oi.Quantity) * oi.UnitPrice,
+			Subtotal:  oi.UnitPrice.Mul(oi.Quantity),
 			SKU:       oi.SKU,
 		}
 	}
 
+	// Charges are invoiced as lines of their own
+	inv.Charges = make([]model.InvoiceCharge, len(order.Charges))
+	for i, oc := range order.Charges {
+		inv.Charges[i] = model.InvoiceCharge{
+			OrgID:       orgID,
+			Type:        oc.Type,
+			Description: oc.Description,
+			Amount:      oc.Amount,
+			TaxAmount:   oc.TaxAmount,
+			Taxes:       oc.Taxes,
+			Total:       oc.Total,
+		}
+	}
+
 	return inv
 }
 
-// UpdateInvoiceDTO allows updating certain fields (e.g., status, notes)
+// ApplyShipment narrows inv, made by ToModel for order, down to the
+// quantities shipment delivered. Tax and line totals are taken pro rata
+// from the order items, the charges only stay with withCharges.
+func (d *CreateInvoiceDTO) ApplyShipment(inv *model.Invoice, order *model.Order, shipment *model.Shipment, withCharges bool) {
+	orderItems := make(map[uint]model.OrderItem, len(order.Items))
+	for _, oi := range order.Items {
+		orderItems[oi.ID] = oi
+	}
+
+	inv.ShipmentID = &shipment.ID
+	inv.Subtotal = money.Zero
+	inv.TaxTotal = money.Zero
+	inv.DiscountTotal = money.Zero
+	inv.Total = money.Zero
+
+	inv.Items = make([]*model.InvoiceItem, 0, len(shipment.Lines))
+	for _, line := range shipment.Lines {
+		oi, ok := orderItems[line.OrderItemID]
+		if !ok || oi.Quantity == 0 {
+			continue
+		}
+		share := func(m money.Money) money.Money {
+			return m.MulFrac(int64(line.Quantity), int64(oi.Quantity), money.HalfUp)
+		}
+
+		taxes := make([]model.TaxLine, len(oi.Taxes))
+		for i, tax := range oi.Taxes {
+			tax.Amount = share(tax.Amount)
+			taxes[i] = tax
+		}
+
+		item := &model.InvoiceItem{
+			OrgID:     inv.OrgID,
+			VariantID: oi.VariantID,
+			Notes:     oi.Notes,
+			Quantity:  line.Quantity,
+			UnitPrice: oi.UnitPrice,
+			TaxAmount: share(oi.TaxAmount),
+			Taxes:     taxes,
+			LineTotal: share(oi.Total),
+			Subtotal:  oi.UnitPrice.Mul(line.Quantity),
+			SKU:       oi.SKU,
+		}
+		inv.Items = append(inv.Items, item)
+
+		inv.Subtotal = inv.Subtotal.Add(item.Subtotal)
+		inv.TaxTotal = inv.TaxTotal.Add(item.TaxAmount)
+		inv.DiscountTotal = inv.DiscountTotal.Add(share(money.Sum(oi.AppliedDiscount, oi.AppliedPromotionDiscount, oi.AppliedOrderDiscount)))
+		inv.Total = inv.Total.Add(item.LineTotal)
+	}
+
+	if !withCharges {
+		inv.Charges = nil
+		inv.ChargeTotal = money.Zero
+	}
+	for _, charge := range inv.Charges {
+		inv.TaxTotal = inv.TaxTotal.Add(charge.TaxAmount)
+		inv.Total = inv.Total.Add(charge.Total)
+	}
+	inv.AmountDue = inv.Total
+}
+
+// UpdateInvoiceDTO allows updating certain fields (e.g., notes) of a draft or
+// pro forma invoice. The status only changes through the invoice transition
+// endpoints.
 type UpdateInvoiceDTO struct {
-	Status *string `json:"status,omitempty" validate:"omitempty,oneof=draft issued paid partially_paid cancelled"`
-	Notes  *string `json:"notes,omitempty"`
+	Notes *string `json:"notes,omitempty"`
 	// Allow optional update of DueDate
 	DueDate *time.Time `json:"dueDate,omitempty"`
 }
 
 // ApplyModel updates allowed fields on an Invoice
 func (dto *UpdateInvoiceDTO) ApplyModel(inv *model.Invoice) {
-	if dto.Status != nil {
-		inv.Status = model.InvoiceStatus(*dto.Status)
-	}
 	if dto.Notes != nil {
 		inv.Notes = *dto.Notes
 	}
@@ -4,8 +4,10 @@ import (
 	"time"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/deveasyclick/openb2b/internal/utils/numbergen"
 	"github.com/deveasyclick/openb2b/internal/utils/ordertotals"
+	"github.com/deveasyclick/openb2b/internal/utils/pricing"
 )
 
 // CreateOrderItemDTO represents incoming data for creating an order item
@@ -16,41 +18,116 @@ type CreateOrderItemDTO struct {
 	Notes     string                `json:"notes" validate:"omitempty"`
 }
 
-// ToModel converts CreateOrderItemDTO to a fully initialized OrderItem
+// ToModel converts CreateOrderItemDTO to a fully initialized OrderItem of order, priced in its currency
+// from the price lists of the customer, else from the variant
 // Order items won'te be created separately, they are created when the order is created so we don't need to calculate totals at item level
-func (i *CreateOrderItemDTO) ToModel(orgID uint, variant model.Variant) model.OrderItem {
+func (i *CreateOrderItemDTO) ToModel(order *model.Order, variant model.Variant, listPrices pricing.ListPrices) model.OrderItem {
+	unitPrice, priceListID := pricing.UnitPrice(order, variant, i.Quantity, listPrices)
+	taxes, taxRate := pricing.Taxes(order, variant)
 
 	return model.OrderItem{
-		OrgID:     orgID,
-		ProductID: variant.ProductID,
-		Notes:     i.Notes,
-		Quantity:  i.Quantity,
-		VariantID: i.VariantID,
-		UnitPrice: variant.Price,
-		SKU:       variant.SKU,
+		OrgID:       order.OrgID,
+		ProductID:   variant.ProductID,
+		Notes:       i.Notes,
+		Quantity:    i.Quantity,
+		VariantID:   i.VariantID,
+		UnitPrice:   unitPrice,
+		PriceListID: priceListID,
+		SKU:         variant.SKU,
 		Discount: model.DiscountInfo{
 			Type:   i.Discount.Type,
 			Amount: i.Discount.Amount,
 		},
-		TaxRate: variant.TaxRate,
+		TaxRate: taxRate,
+		Taxes:   taxes,
 	}
 }
 
 type CreateDeliveryInfoDTO struct {
-	Address       AddressRequired `json:"address" validate:"required"`
-	TransportFare float64         `json:"transportFare" validate:"min=0"`
+	Address AddressRequired `json:"address" validate:"required"`
+	// TransportFare is charged as an untaxed delivery charge, unless the
+	// order has delivery charges already
+	TransportFare money.Money `json:"transportFare" validate:"min=0"`
 }
 
 func (d *CreateDeliveryInfoDTO) ToModel() model.DeliveryInfo {
 	return model.DeliveryInfo{
-		Address:       d.Address.ToModel(),
-		TransportFare: d.TransportFare,
+		Address: d.Address.ToModel(),
 	}
 }
 
+// CreateOrderChargeDTO represents incoming data for a charge of an order
+// besides its items
+type CreateOrderChargeDTO struct {
+	Type        model.ChargeType `json:"type" validate:"required,oneof=delivery handling packaging"`
+	Description string           `json:"description" validate:"omitempty,max=255" example:"Express delivery"`
+	Amount      money.Money      `json:"amount" validate:"min=0"`
+	// TaxClassID makes the charge taxable with the rates of the class
+	TaxClassID *uint `json:"taxClassId,omitempty" validate:"omitempty,gt=0"`
+}
+
+// ToModel converts CreateOrderChargeDTO to a charge of order, taxed with its
+// class in taxClasses
+func (c *CreateOrderChargeDTO) ToModel(order *model.Order, taxClasses map[uint]model.TaxClass) model.OrderCharge {
+	charge := model.OrderCharge{
+		OrgID:       order.OrgID,
+		Type:        c.Type,
+		Description: c.Description,
+		Amount:      c.Amount,
+		TaxClassID:  c.TaxClassID,
+	}
+	if c.TaxClassID != nil {
+		class := taxClasses[*c.TaxClassID]
+		charge.Taxes, _ = pricing.ClassTaxes(order, &class)
+	}
+	return charge
+}
+
+// setTransportFare makes fare the delivery charge of order, keeping the tax
+// class of the delivery charge it replaces. No fare removes it.
+func setTransportFare(order *model.Order, fare money.Money) {
+	charges := make([]model.OrderCharge, 0, len(order.Charges)+1)
+	var delivery *model.OrderCharge
+	for _, charge := range order.Charges {
+		if charge.Type == model.ChargeDelivery {
+			if delivery == nil {
+				delivery = &charge
+			}
+			continue
+		}
+		charges = append(charges, charge)
+	}
+
+	if fare.IsPositive() {
+		charge := model.OrderCharge{OrgID: order.OrgID, Type: model.ChargeDelivery, Amount: fare}
+		if delivery != nil {
+			charge.Description = delivery.Description
+			charge.TaxClassID = delivery.TaxClassID
+			charge.Taxes = delivery.Taxes
+		}
+		charges = append(charges, charge)
+	}
+
+	// Charges are saved anew
+	for i := range charges {
+		charges[i].ID = 0
+	}
+	order.Charges = charges
+}
+
+// hasDeliveryCharge reports whether order has a delivery charge.
+func hasDeliveryCharge(order *model.Order) bool {
+	for _, charge := range order.Charges {
+		if charge.Type == model.ChargeDelivery {
+			return true
+		}
+	}
+	return false
+}
+
 type CreateDiscountInfoDTO struct {
 	Type   model.DiscountType `json:"type" validate:"required,oneof=percentage fixed"`
-	Amount float64            `json:"amount" validate:"min=0"`
+	Amount money.Money        `json:"amount" validate:"min=0"`
 }
 
 func (di *CreateDiscountInfoDTO) ToModel() model.DiscountInfo {
@@ -66,18 +143,36 @@ type CreateOrderDTO struct {
 	Delivery   CreateDeliveryInfoDTO `json:"delivery" validate:"required"`
 	Notes      string                `json:"notes" validate:"omitempty,max=1000"`
 	Discount   CreateDiscountInfoDTO `json:"discount" validate:"omitempty"`
+	// Currency defaults to the billing currency of the customer, then to the
+	// org base currency
+	Currency string `json:"currency,omitempty" validate:"omitempty,len=3,uppercase" example:"USD"`
+	// Charges are charged besides the items, e.g. delivery and packaging
+	Charges []CreateOrderChargeDTO `json:"charges" validate:"omitempty,dive"`
+	// PromotionCode applies a promotion of the org to the order
+	PromotionCode string `json:"promotionCode,omitempty" validate:"omitempty,max=50" example:"SPRING10"`
+	// WarehouseID is the fulfilment location stock is taken from, defaults
+	// to the default warehouse of the org
+	WarehouseID *uint `json:"warehouseId,omitempty" validate:"omitempty,gt=0"`
 }
 
-func (dto *CreateOrderDTO) ToModel(variantMap map[uint]model.Variant, orgID uint) model.Order {
+// ToModel converts CreateOrderDTO to an order of org for customer in
+// currency, which is worth exchangeRate units of the org base currency.
+// listPrices are the prices of the price lists that apply to the order and
+// taxClasses the tax classes of its charges.
+func (dto *CreateOrderDTO) ToModel(variantMap map[uint]model.Variant, listPrices pricing.ListPrices, taxClasses map[uint]model.TaxClass, org *model.Org, customer *model.Customer, currency string, exchangeRate float64) model.Order {
 	order := model.Order{
-		OrderNumber: numbergen.Generate("ORD"),
-		CustomerID:  dto.CustomerID,
-		OrgID:       orgID,
-		Delivery:    dto.Delivery.ToModel(),
-		Notes:       dto.Notes,
-		Discount:    dto.Discount.ToModel(),
-		Status:      model.OrderStatusPending,
-		Items:       make([]model.OrderItem, 0, len(dto.Items)),
+		OrderNumber:      numbergen.Generate("ORD"),
+		CustomerID:       dto.CustomerID,
+		OrgID:            org.ID,
+		Currency:         currency,
+		ExchangeRate:     exchangeRate,
+		PricesIncludeTax: org.PricesIncludeTax,
+		TaxExempt:        customer.TaxExempt,
+		Delivery:         dto.Delivery.ToModel(),
+		Notes:            dto.Notes,
+		Discount:         dto.Discount.ToModel(),
+		Status:           model.OrderStatusPending,
+		Items:            make([]model.OrderItem, 0, len(dto.Items)),
 	}
 
 	// Convert each DTO item to OrderItem model
@@ -87,10 +182,18 @@ func (dto *CreateOrderDTO) ToModel(variantMap map[uint]model.Variant, orgID uint
 			continue // skip invalid variants
 		}
 
-		orderItem := itemDTO.ToModel(orgID, variant)
+		orderItem := itemDTO.ToModel(&order, variant, listPrices)
 		order.Items = append(order.Items, orderItem)
 	}
 
+	order.Charges = make([]model.OrderCharge, 0, len(dto.Charges))
+	for _, chargeDTO := range dto.Charges {
+		order.Charges = append(order.Charges, chargeDTO.ToModel(&order, taxClasses))
+	}
+	if dto.Delivery.TransportFare.IsPositive() && !hasDeliveryCharge(&order) {
+		setTransportFare(&order, dto.Delivery.TransportFare)
+	}
+
 	// After items are added, calculate totals including applied order-level discount
 	ordertotals.Calculate(&order)
 
@@ -101,19 +204,27 @@ func (dto *CreateOrderDTO) ToModel(variantMap map[uint]model.Variant, orgID uint
 // UPDATE DTOs
 //
 
+// UpdateOrderDTO edits a pending order. The status changes through the
+// transition endpoints only.
 type UpdateOrderDTO struct {
-	Status     *model.OrderStatus     `json:"status" validate:"omitempty,oneof=pending approved delivered cancelled"`
 	Notes      *string                `json:"notes" validate:"omitempty,max=1000"`
 	Discount   *CreateDiscountInfoDTO `json:"discount" validate:"omitempty"`
 	Items      []*CreateOrderItemDTO  `json:"items" validate:"omitempty,dive"`
 	Delivery   *UpdateDeliveryInfoDTO `json:"deliver" validate:"omitempty"`
 	CustomerID *uint                  `json:"customerId" validate:"omitempty"`
+	// Charges replace those of the order, when set. [] removes them.
+	Charges []CreateOrderChargeDTO `json:"charges" validate:"omitempty,dive"`
+	// PromotionCode replaces the promotion of the order, "" removes it
+	PromotionCode *string `json:"promotionCode" validate:"omitempty,max=50"`
+	// WarehouseID moves the fulfilment location, and the stock held for the
+	// order, to another warehouse
+	WarehouseID *uint `json:"warehouseId" validate:"omitempty,gt=0"`
 }
 
-func (dto *UpdateOrderDTO) ApplyModel(order *model.Order, variantMap *map[uint]model.Variant) {
-	if dto.Status != nil {
-		order.Status = *dto.Status
-	}
+// ApplyModel updates order with DTO values. New items are priced from
+// listPrices, the prices of the price lists that apply to the order, and
+// new charges taxed with their classes in taxClasses.
+func (dto *UpdateOrderDTO) ApplyModel(order *model.Order, variantMap *map[uint]model.Variant, listPrices pricing.ListPrices, taxClasses map[uint]model.TaxClass) {
 	if dto.Notes != nil {
 		order.Notes = *dto.Notes
 	}
@@ -125,6 +236,20 @@ func (dto *UpdateOrderDTO) ApplyModel(order *model.Order, variantMap *map[uint]m
 		dto.Delivery.ApplyModel(&order.Delivery)
 	}
 
+	// Orders from before charges keep their fare as a delivery charge
+	if order.Delivery.TransportFare.IsPositive() && !hasDeliveryCharge(order) {
+		setTransportFare(order, order.Delivery.TransportFare)
+	}
+	if dto.Charges != nil {
+		order.Charges = make([]model.OrderCharge, 0, len(dto.Charges))
+		for _, charge := range dto.Charges {
+			order.Charges = append(order.Charges, charge.ToModel(order, taxClasses))
+		}
+	}
+	if dto.Delivery != nil && dto.Delivery.TransportFare != nil {
+		setTransportFare(order, *dto.Delivery.TransportFare)
+	}
+
 	if dto.CustomerID != nil {
 		order.CustomerID = *dto.CustomerID
 	}
@@ -136,7 +261,7 @@ func (dto *UpdateOrderDTO) ApplyModel(order *model.Order, variantMap *map[uint]m
 			if !ok {
 				continue // or handle error
 			}
-			order.Items = append(order.Items, item.ToModel(order.OrgID, v))
+			order.Items = append(order.Items, item.ToModel(order, v, listPrices))
 		}
 	}
 
@@ -144,8 +269,9 @@ func (dto *UpdateOrderDTO) ApplyModel(order *model.Order, variantMap *map[uint]m
 }
 
 type UpdateDeliveryInfoDTO struct {
-	Address       *model.Address        `json:"address" validate:"omitempty"`
-	TransportFare *float64              `json:"transportFare" validate:"omitempty,min=0"`
+	Address *model.Address `json:"address" validate:"omitempty"`
+	// TransportFare replaces the delivery charge of the order, 0 removes it
+	TransportFare *money.Money          `json:"transportFare" validate:"omitempty,min=0"`
 	Status        *model.DeliveryStatus `json:"status" validate:"omitempty,oneof=pending shipped delivered cancelled"`
 	Date          *time.Time            `json:"date" validate:"omitempty,datetime"`
 }
@@ -154,9 +280,6 @@ func (dto *UpdateDeliveryInfoDTO) ApplyModel(delivery *model.DeliveryInfo) {
 	if dto.Address != nil {
 		delivery.Address = dto.Address
 	}
-	if dto.TransportFare != nil {
-		delivery.TransportFare = *dto.TransportFare
-	}
 	if dto.Status != nil {
 		delivery.Status = *dto.Status
 	}
@@ -34,6 +34,16 @@ type CreateOrgDTO struct {
 	Phone string `json:"phone" validate:"required,min=10,max=50" example:"+1-202-555-0199"`
 
 	Address AddressRequired `json:"address"`
+
+	// Currency of variant prices and reports, as an ISO 4217 code
+	// Required: false
+	// Default: NGN
+	BaseCurrency string `json:"baseCurrency" validate:"omitempty,len=3,uppercase" example:"NGN"`
+
+	// Whether variant prices include tax
+	// Required: false
+	// Default: false
+	PricesIncludeTax bool `json:"pricesIncludeTax" example:"false"`
 }
 
 // UpdateOrgDTO represents the payload for updating an organization
@@ -59,10 +69,18 @@ type UpdateOrgDTO struct {
 
 	// Address of the organization
 	Address AddressOptional `json:"address"`
+
+	// Currency of variant prices and reports. Exchange rates are kept per
+	// base currency, so rates to the new one have to be added.
+	BaseCurrency string `json:"baseCurrency" validate:"omitempty,len=3,uppercase" example:"NGN"`
+
+	// Whether variant prices include tax. Orders already placed keep the
+	// setting they were priced with.
+	PricesIncludeTax *bool `json:"pricesIncludeTax" example:"false"`
 }
 
 func (dto *CreateOrgDTO) ToModel() *model.Org {
-	return &model.Org{
+	org := &model.Org{
 		Name:             dto.Name,
 		Logo:             dto.Logo,
 		OrganizationName: dto.OrganizationName,
@@ -70,7 +88,13 @@ func (dto *CreateOrgDTO) ToModel() *model.Org {
 		Email:            dto.Email,
 		Phone:            dto.Phone,
 		Address:          dto.Address.ToModel(),
+		BaseCurrency:     dto.BaseCurrency,
+		PricesIncludeTax: dto.PricesIncludeTax,
+	}
+	if org.BaseCurrency == "" {
+		org.BaseCurrency = model.DefaultCurrency
 	}
+	return org
 }
 
 func (dto *UpdateOrgDTO) ApplyModel(org *model.Org) {
@@ -90,5 +114,11 @@ func (dto *UpdateOrgDTO) ApplyModel(org *model.Org) {
 	if dto.Phone != "" {
 		org.Phone = dto.Phone
 	}
+	if dto.BaseCurrency != "" {
+		org.BaseCurrency = dto.BaseCurrency
+	}
+	if dto.PricesIncludeTax != nil {
+		org.PricesIncludeTax = *dto.PricesIncludeTax
+	}
 	dto.Address.ApplyModel(org.Address)
 }
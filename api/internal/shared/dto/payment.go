@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// CreatePaymentDTO records money received against an invoice
+type CreatePaymentDTO struct {
+	InvoiceID uint                `json:"invoiceId" validate:"required"`
+	Amount    money.Money         `json:"amount" validate:"required,gt=0"`
+	Currency  string              `json:"currency,omitempty" validate:"omitempty,len=3"` // defaults to the invoice currency
+	Method    model.PaymentMethod `json:"method" validate:"required,oneof=cash bank_transfer card"`
+	Reference string              `json:"reference,omitempty" validate:"max=100"`
+	// ReceivedAt defaults to now
+	ReceivedAt *time.Time `json:"receivedAt,omitempty"`
+	Notes      string     `json:"notes,omitempty"`
+}
+
+// ToModel converts CreatePaymentDTO to a Payment of invoice
+func (dto *CreatePaymentDTO) ToModel(orgID uint, invoice *model.Invoice) *model.Payment {
+	payment := &model.Payment{
+		OrgID:      orgID,
+		InvoiceID:  invoice.ID,
+		CustomerID: invoice.Order.CustomerID,
+		Amount:     dto.Amount,
+		Currency:   dto.Currency,
+		Method:     dto.Method,
+		Reference:  dto.Reference,
+		ReceivedAt: time.Now(),
+		Notes:      dto.Notes,
+	}
+
+	if payment.Currency == "" {
+		payment.Currency = invoice.Currency
+	}
+	if dto.ReceivedAt != nil {
+		payment.ReceivedAt = *dto.ReceivedAt
+	}
+	return payment
+}
+
+// UpdatePaymentDTO corrects a recorded payment. The invoice and currency of a
+// payment can't be changed, delete and record it again instead.
+type UpdatePaymentDTO struct {
+	Amount     *money.Money         `json:"amount,omitempty" validate:"omitempty,gt=0"`
+	Method     *model.PaymentMethod `json:"method,omitempty" validate:"omitempty,oneof=cash bank_transfer card"`
+	Reference  *string              `json:"reference,omitempty" validate:"omitempty,max=100"`
+	ReceivedAt *time.Time           `json:"receivedAt,omitempty"`
+	Notes      *string              `json:"notes,omitempty"`
+}
+
+// ApplyModel updates an existing Payment with DTO values
+func (dto *UpdatePaymentDTO) ApplyModel(p *model.Payment) {
+	if dto.Amount != nil {
+		p.Amount = *dto.Amount
+	}
+	if dto.Method != nil {
+		p.Method = *dto.Method
+	}
+	if dto.Reference != nil {
+		p.Reference = *dto.Reference
+	}
+	if dto.ReceivedAt != nil {
+		p.ReceivedAt = *dto.ReceivedAt
+	}
+	if dto.Notes != nil {
+		p.Notes = *dto.Notes
+	}
+}
@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// PriceListItemDTO is the price of a variant on a price list
+type PriceListItemDTO struct {
+	VariantID uint        `json:"variantId" validate:"required,gt=0"`
+	Price     money.Money `json:"price" validate:"required,gt=0"`
+	// PriceBreaks are prices from larger quantities
+	PriceBreaks []PriceBreakDTO `json:"priceBreaks,omitempty" validate:"omitempty,unique=MinQuantity,dive"`
+}
+
+func (dto *PriceListItemDTO) ToModel(orgID uint) model.PriceListItem {
+	return model.PriceListItem{
+		OrgID:     orgID,
+		VariantID: dto.VariantID,
+		Price:     dto.Price,
+
+		PriceBreaks: priceBreaks(dto.PriceBreaks),
+	}
+}
+
+// CreatePriceListDTO represents incoming API data to create a price list
+type CreatePriceListDTO struct {
+	Name string `json:"name" validate:"required,max=100" example:"Wholesale 2026"`
+	// Currency of the prices, defaults to the org base currency
+	Currency  string     `json:"currency,omitempty" validate:"omitempty,len=3,uppercase"`
+	ValidFrom *time.Time `json:"validFrom,omitempty"`
+	// ValidTo is when the list stops applying
+	ValidTo *time.Time `json:"validTo,omitempty"`
+	// Priority ranks lists of the same kind that price a variant, highest first
+	Priority    int                `json:"priority" validate:"min=0"`
+	Items       []PriceListItemDTO `json:"items" validate:"omitempty,unique=VariantID,dive"`
+	CustomerIDs []uint             `json:"customerIds" validate:"omitempty,dive,gt=0"`
+	GroupIDs    []uint             `json:"groupIds" validate:"omitempty,dive,gt=0"`
+}
+
+// ToModel converts CreatePriceListDTO to a PriceList of the org with its
+// items. The customers and groups are linked by the service.
+func (dto *CreatePriceListDTO) ToModel(orgID uint) *model.PriceList {
+	list := &model.PriceList{
+		OrgID:     orgID,
+		Name:      dto.Name,
+		Currency:  dto.Currency,
+		ValidFrom: dto.ValidFrom,
+		ValidTo:   dto.ValidTo,
+		Priority:  dto.Priority,
+	}
+	for _, item := range dto.Items {
+		list.Items = append(list.Items, item.ToModel(orgID))
+	}
+	return list
+}
+
+type UpdatePriceListDTO struct {
+	Name      *string    `json:"name" validate:"omitempty,max=100"`
+	Currency  *string    `json:"currency" validate:"omitempty,len=3,uppercase"`
+	ValidFrom *time.Time `json:"validFrom"`
+	ValidTo   *time.Time `json:"validTo"`
+	Priority  *int       `json:"priority" validate:"omitempty,min=0"`
+	// Items, CustomerIDs and GroupIDs replace those of the list, when set
+	Items       []PriceListItemDTO `json:"items" validate:"omitempty,unique=VariantID,dive"`
+	CustomerIDs []uint             `json:"customerIds" validate:"omitempty,dive,gt=0"`
+	GroupIDs    []uint             `json:"groupIds" validate:"omitempty,dive,gt=0"`
+}
+
+// ApplyModel updates the fields and items of an existing PriceList with DTO
+// values. The customers and groups are replaced by the service.
+func (dto *UpdatePriceListDTO) ApplyModel(list *model.PriceList) {
+	if dto.Name != nil {
+		list.Name = *dto.Name
+	}
+	if dto.Currency != nil {
+		list.Currency = *dto.Currency
+	}
+	if dto.ValidFrom != nil {
+		list.ValidFrom = dto.ValidFrom
+	}
+	if dto.ValidTo != nil {
+		list.ValidTo = dto.ValidTo
+	}
+	if dto.Priority != nil {
+		list.Priority = *dto.Priority
+	}
+	if dto.Items != nil {
+		list.Items = []model.PriceListItem{}
+		for _, item := range dto.Items {
+			list.Items = append(list.Items, item.ToModel(list.OrgID))
+		}
+	}
+}
@@ -1,25 +1,59 @@
 package dto
 
-import "github.com/deveasyclick/openb2b/internal/model"
+import (
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// PriceBreakDTO is a unit price for buying at least MinQuantity units
+type PriceBreakDTO struct {
+	MinQuantity int         `json:"minQuantity" validate:"required,min=2" example:"10"`
+	Price       money.Money `json:"price" validate:"required,gt=0"`
+}
+
+// priceBreaks converts breaks to model PriceBreaks, nil for nil.
+func priceBreaks(breaks []PriceBreakDTO) model.PriceBreaks {
+	if breaks == nil {
+		return nil
+	}
+	out := make(model.PriceBreaks, len(breaks))
+	for i, b := range breaks {
+		out[i] = model.PriceBreak{MinQuantity: b.MinQuantity, Price: b.Price}
+	}
+	return out
+}
 
 type CreateProductVariantDTO struct {
-	SKU     string  `json:"sku" validate:"required,min=2,max=50"`
-	Color   string  `json:"color" validate:"omitempty,min=1,max=30"`
-	Size    string  `json:"size" validate:"omitempty,min=1,max=30"`
-	Price   float64 `json:"price" validate:"required,gt=0"`
-	Stock   int     `json:"stock" validate:"required,min=0"`
-	TaxRate float64 `json:"taxRate" validate:"omitempty,min=0,max=1"`
+	SKU     string      `json:"sku" validate:"required,min=2,max=50"`
+	Color   string      `json:"color" validate:"omitempty,min=1,max=30"`
+	Size    string      `json:"size" validate:"omitempty,min=1,max=30"`
+	Price   money.Money `json:"price" validate:"required,gt=0"`
+	Stock   int         `json:"stock" validate:"required,min=0"`
+	TaxRate float64     `json:"taxRate" validate:"omitempty,min=0,max=1"`
+	// TaxClassID sets the taxes charged on the variant, instead of TaxRate
+	TaxClassID *uint `json:"taxClassId,omitempty" validate:"omitempty,gt=0"`
+	// Prices in other currencies than the org base currency, by currency code
+	Prices map[string]money.Money `json:"prices,omitempty" validate:"omitempty,dive,keys,len=3,uppercase,endkeys,gt=0"`
+	// PriceBreaks are unit prices from larger quantities, in the base currency
+	PriceBreaks []PriceBreakDTO `json:"priceBreaks,omitempty" validate:"omitempty,unique=MinQuantity,dive"`
+	// AllowBackorder accepts orders for more than is in stock
+	AllowBackorder bool `json:"allowBackorder,omitempty"`
 }
 
 func (v *CreateProductVariantDTO) ToModel(orgID uint) model.Variant {
 	return model.Variant{
-		SKU:     v.SKU,
-		Color:   v.Color,
-		Size:    v.Size,
-		Price:   v.Price,
-		Stock:   v.Stock,
-		TaxRate: v.TaxRate,
-		OrgID:   orgID,
+		SKU:        v.SKU,
+		Color:      v.Color,
+		Size:       v.Size,
+		Price:      v.Price,
+		Stock:      v.Stock,
+		TaxRate:    v.TaxRate,
+		TaxClassID: v.TaxClassID,
+		OrgID:      orgID,
+		Prices:     v.Prices,
+
+		PriceBreaks:    priceBreaks(v.PriceBreaks),
+		AllowBackorder: v.AllowBackorder,
 	}
 }
 
@@ -73,11 +107,22 @@ func (dto *UpdateProductDTO) ApplyModel(product *model.Product) {
 }
 
 type UpdateVariantDTO struct {
-	Color   *string  `json:"color" validate:"omitempty,min=1,max=30"`
-	Size    *string  `json:"size" validate:"omitempty,min=1,max=30"`
-	Price   *float64 `json:"price" validate:"omitempty,gt=0"`
-	Stock   *int     `json:"stock" validate:"omitempty,min=0"`
-	TaxRate *float64 `json:"taxRate" validate:"omitempty,min=0,max=1"`
+	Color   *string      `json:"color" validate:"omitempty,min=1,max=30"`
+	Size    *string      `json:"size" validate:"omitempty,min=1,max=30"`
+	Price   *money.Money `json:"price" validate:"omitempty,gt=0"`
+	Stock   *int         `json:"stock" validate:"omitempty,min=0"` // recorded as a stock adjustment, not applied by ApplyModel
+	TaxRate *float64     `json:"taxRate" validate:"omitempty,min=0,max=1"`
+	// WarehouseID is the warehouse Stock is set at, the default one when
+	// not set
+	WarehouseID *uint `json:"warehouseId" validate:"omitempty,gt=0"`
+	// TaxClassID sets the tax class of the variant, 0 removes it
+	TaxClassID *uint `json:"taxClassId"`
+	// Prices replaces the prices in other currencies, when set
+	Prices map[string]money.Money `json:"prices" validate:"omitempty,dive,keys,len=3,uppercase,endkeys,gt=0"`
+	// PriceBreaks replaces the quantity breaks, when set
+	PriceBreaks []PriceBreakDTO `json:"priceBreaks" validate:"omitempty,unique=MinQuantity,dive"`
+	// AllowBackorder accepts orders for more than is in stock
+	AllowBackorder *bool `json:"allowBackorder"`
 }
 
 func (dto *UpdateVariantDTO) ApplyModel(variant *model.Variant) {
@@ -90,11 +135,23 @@ func (dto *UpdateVariantDTO) ApplyModel(variant *model.Variant) {
 	if dto.Price != nil {
 		variant.Price = *dto.Price
 	}
-	if dto.Stock != nil {
-		variant.Stock = *dto.Stock
-	}
-
 	if dto.TaxRate != nil {
 		variant.TaxRate = *dto.TaxRate
 	}
+	if dto.TaxClassID != nil {
+		if *dto.TaxClassID == 0 {
+			variant.TaxClassID = nil
+		} else {
+			variant.TaxClassID = dto.TaxClassID
+		}
+	}
+	if dto.Prices != nil {
+		variant.Prices = dto.Prices
+	}
+	if dto.PriceBreaks != nil {
+		variant.PriceBreaks = priceBreaks(dto.PriceBreaks)
+	}
+	if dto.AllowBackorder != nil {
+		variant.AllowBackorder = *dto.AllowBackorder
+	}
 }
@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// CreatePromotionDTO represents incoming API data to create a promotion
+type CreatePromotionDTO struct {
+	// Code is what customers enter, matched without case
+	Code string              `json:"code" validate:"required,max=50" example:"SPRING10"`
+	Name string              `json:"name" validate:"required,max=100" example:"Spring sale"`
+	Type model.PromotionType `json:"type" validate:"required,oneof=percentage fixed buy_x_get_y"`
+	// Amount is a percentage, e.g. 12.50 for 12.5%, or a fixed amount in the
+	// org base currency. Buy X get Y promotions have none.
+	Amount        money.Money `json:"amount" validate:"required_unless=Type buy_x_get_y,min=0"`
+	BuyQuantity   int         `json:"buyQuantity" validate:"required_if=Type buy_x_get_y,min=0"`
+	GetQuantity   int         `json:"getQuantity" validate:"required_if=Type buy_x_get_y,min=0"`
+	MinOrderValue money.Money `json:"minOrderValue" validate:"min=0"`
+	// ProductIDs and Categories limit the promotion to some products
+	ProductIDs       []uint     `json:"productIds" validate:"omitempty,dive,gt=0"`
+	Categories       []string   `json:"categories" validate:"omitempty,dive,required,max=50"`
+	UsageLimit       *int       `json:"usageLimit" validate:"omitempty,min=1"`
+	PerCustomerLimit *int       `json:"perCustomerLimit" validate:"omitempty,min=1"`
+	StartsAt         *time.Time `json:"startsAt,omitempty"`
+	EndsAt           *time.Time `json:"endsAt,omitempty"`
+	Active           *bool      `json:"active"` // defaults to true
+}
+
+// ToModel converts CreatePromotionDTO to a Promotion of the org
+func (dto *CreatePromotionDTO) ToModel(orgID uint) *model.Promotion {
+	promotion := &model.Promotion{
+		OrgID: orgID,
+		Code:  strings.ToUpper(dto.Code),
+		Name:  dto.Name,
+		PromotionRule: model.PromotionRule{
+			Type:          dto.Type,
+			Amount:        dto.Amount,
+			BuyQuantity:   dto.BuyQuantity,
+			GetQuantity:   dto.GetQuantity,
+			MinOrderValue: dto.MinOrderValue,
+		},
+		ProductIDs:       dto.ProductIDs,
+		Categories:       dto.Categories,
+		UsageLimit:       dto.UsageLimit,
+		PerCustomerLimit: dto.PerCustomerLimit,
+		StartsAt:         dto.StartsAt,
+		EndsAt:           dto.EndsAt,
+		Active:           true,
+	}
+	if dto.Active != nil {
+		promotion.Active = *dto.Active
+	}
+	return promotion
+}
+
+// UpdatePromotionDTO edits a promotion. The type and code can't change;
+// orders that used the promotion keep the rule they got.
+type UpdatePromotionDTO struct {
+	Name          *string      `json:"name" validate:"omitempty,max=100"`
+	Amount        *money.Money `json:"amount" validate:"omitempty,min=0"`
+	BuyQuantity   *int         `json:"buyQuantity" validate:"omitempty,min=1"`
+	GetQuantity   *int         `json:"getQuantity" validate:"omitempty,min=1"`
+	MinOrderValue *money.Money `json:"minOrderValue" validate:"omitempty,min=0"`
+	// ProductIDs and Categories replace those of the promotion, when set
+	ProductIDs []uint   `json:"productIds" validate:"omitempty,dive,gt=0"`
+	Categories []string `json:"categories" validate:"omitempty,dive,required,max=50"`
+	// UsageLimit and PerCustomerLimit are replaced, 0 removes them
+	UsageLimit       *int       `json:"usageLimit" validate:"omitempty,min=0"`
+	PerCustomerLimit *int       `json:"perCustomerLimit" validate:"omitempty,min=0"`
+	StartsAt         *time.Time `json:"startsAt"`
+	EndsAt           *time.Time `json:"endsAt"`
+	Active           *bool      `json:"active"`
+}
+
+// ApplyModel updates an existing Promotion with DTO values
+func (dto *UpdatePromotionDTO) ApplyModel(promotion *model.Promotion) {
+	if dto.Name != nil {
+		promotion.Name = *dto.Name
+	}
+	if dto.Amount != nil {
+		promotion.Amount = *dto.Amount
+	}
+	if dto.BuyQuantity != nil {
+		promotion.BuyQuantity = *dto.BuyQuantity
+	}
+	if dto.GetQuantity != nil {
+		promotion.GetQuantity = *dto.GetQuantity
+	}
+	if dto.MinOrderValue != nil {
+		promotion.MinOrderValue = *dto.MinOrderValue
+	}
+	if dto.ProductIDs != nil {
+		promotion.ProductIDs = dto.ProductIDs
+	}
+	if dto.Categories != nil {
+		promotion.Categories = dto.Categories
+	}
+	if dto.UsageLimit != nil {
+		promotion.UsageLimit = limit(*dto.UsageLimit)
+	}
+	if dto.PerCustomerLimit != nil {
+		promotion.PerCustomerLimit = limit(*dto.PerCustomerLimit)
+	}
+	if dto.StartsAt != nil {
+		promotion.StartsAt = dto.StartsAt
+	}
+	if dto.EndsAt != nil {
+		promotion.EndsAt = dto.EndsAt
+	}
+	if dto.Active != nil {
+		promotion.Active = *dto.Active
+	}
+}
+
+// limit returns n as a usage limit, nil for 0.
+func limit(n int) *int {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// PurchaseOrderLineDTO is a quantity of a variant bought at a unit cost
+type PurchaseOrderLineDTO struct {
+	VariantID uint        `json:"variantId" validate:"required,gt=0"`
+	Quantity  int         `json:"quantity" validate:"required,gt=0"`
+	UnitCost  money.Money `json:"unitCost" validate:"gte=0"` // cost price per unit, in the currency of the purchase order
+}
+
+// ToModel converts PurchaseOrderLineDTO to a line of the org. Its SKU is
+// set by the purchase order service.
+func (dto *PurchaseOrderLineDTO) ToModel(orgID uint) model.PurchaseOrderLine {
+	return model.PurchaseOrderLine{
+		OrgID:     orgID,
+		VariantID: dto.VariantID,
+		Quantity:  dto.Quantity,
+		UnitCost:  dto.UnitCost,
+		LineTotal: dto.UnitCost.Mul(dto.Quantity),
+	}
+}
+
+// CreatePurchaseOrderDTO represents incoming API data to create a draft
+// purchase order
+type CreatePurchaseOrderDTO struct {
+	SupplierID uint       `json:"supplierId" validate:"required,gt=0"`
+	Notes      string     `json:"notes,omitempty"`
+	ExpectedAt *time.Time `json:"expectedAt,omitempty"`
+	// Warehouse the goods are received at, defaults to the default warehouse
+	WarehouseID *uint `json:"warehouseId,omitempty" validate:"omitempty,gt=0"`
+	// Currency of the unit costs, defaults to the currency of the supplier,
+	// then to the org base currency
+	Currency string                 `json:"currency,omitempty" validate:"omitempty,len=3,uppercase"`
+	Lines    []PurchaseOrderLineDTO `json:"lines" validate:"required,min=1,unique=VariantID,dive"`
+}
+
+// ToModel converts CreatePurchaseOrderDTO to a draft PurchaseOrder of the org
+// with its lines
+func (dto *CreatePurchaseOrderDTO) ToModel(orgID uint) *model.PurchaseOrder {
+	po := &model.PurchaseOrder{
+		OrgID:       orgID,
+		SupplierID:  dto.SupplierID,
+		WarehouseID: dto.WarehouseID,
+		Status:      model.PurchaseOrderDraft,
+		Notes:       dto.Notes,
+		ExpectedAt:  dto.ExpectedAt,
+		Currency:    dto.Currency,
+	}
+	for _, line := range dto.Lines {
+		po.Lines = append(po.Lines, line.ToModel(orgID))
+	}
+	return po
+}
+
+// UpdatePurchaseOrderDTO edits a draft purchase order
+type UpdatePurchaseOrderDTO struct {
+	Notes      *string    `json:"notes"`
+	ExpectedAt *time.Time `json:"expectedAt"`
+	// Lines replace those of the purchase order, when set
+	Lines []PurchaseOrderLineDTO `json:"lines" validate:"omitempty,unique=VariantID,dive"`
+}
+
+// ApplyModel updates po with DTO values
+func (dto *UpdatePurchaseOrderDTO) ApplyModel(po *model.PurchaseOrder) {
+	if dto.Notes != nil {
+		po.Notes = *dto.Notes
+	}
+	if dto.ExpectedAt != nil {
+		po.ExpectedAt = dto.ExpectedAt
+	}
+	if dto.Lines != nil {
+		po.Lines = []model.PurchaseOrderLine{}
+		for _, line := range dto.Lines {
+			po.Lines = append(po.Lines, line.ToModel(po.OrgID))
+		}
+	}
+}
+
+// ReceivePurchaseOrderDTO records goods that arrived for a purchase order
+type ReceivePurchaseOrderDTO struct {
+	// Lines are the quantities received per purchase order line. Without
+	// lines, everything still expected is received.
+	Lines []ReceivePurchaseOrderLineDTO `json:"lines" validate:"omitempty,dive"`
+}
+
+type ReceivePurchaseOrderLineDTO struct {
+	LineID   uint `json:"lineId" validate:"required"`
+	Quantity int  `json:"quantity" validate:"required,gt=0"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "github.com/deveasyclick/openb2b/internal/model"
+
+// CreateShipmentDTO represents incoming API data to ship part or all of an
+// approved order
+type CreateShipmentDTO struct {
+	OrderID        uint   `json:"orderId" validate:"required"`
+	Carrier        string `json:"carrier" validate:"omitempty,max=100" example:"DHL"`
+	TrackingNumber string `json:"trackingNumber" validate:"omitempty,max=100"`
+	// Lines are the quantities of order items to ship. Without lines,
+	// everything left to ship is shipped.
+	Lines []CreateShipmentLineDTO `json:"lines" validate:"omitempty,dive"`
+}
+
+type CreateShipmentLineDTO struct {
+	OrderItemID uint `json:"orderItemId" validate:"required"`
+	Quantity    int  `json:"quantity" validate:"required,gt=0"`
+}
+
+// ToModel converts CreateShipmentDTO to a pending shipment of the org. Its
+// lines are set by the shipment service.
+func (dto *CreateShipmentDTO) ToModel(orgID uint) *model.Shipment {
+	return &model.Shipment{
+		OrgID:          orgID,
+		OrderID:        dto.OrderID,
+		Status:         model.ShipmentPending,
+		Carrier:        dto.Carrier,
+		TrackingNumber: dto.TrackingNumber,
+	}
+}
+
+// UpdateShipmentDTO edits the carrier and tracking number of a shipment
+type UpdateShipmentDTO struct {
+	Carrier        *string `json:"carrier" validate:"omitempty,max=100"`
+	TrackingNumber *string `json:"trackingNumber" validate:"omitempty,max=100"`
+}
+
+// ApplyModel updates shipment with DTO values
+func (dto *UpdateShipmentDTO) ApplyModel(shipment *model.Shipment) {
+	if dto.Carrier != nil {
+		shipment.Carrier = *dto.Carrier
+	}
+	if dto.TrackingNumber != nil {
+		shipment.TrackingNumber = *dto.TrackingNumber
+	}
+}
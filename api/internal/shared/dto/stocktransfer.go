@@ -0,0 +1,39 @@
+package dto
+
+import "github.com/deveasyclick/openb2b/internal/model"
+
+// StockTransferLineDTO is a quantity of a variant to move
+type StockTransferLineDTO struct {
+	VariantID uint `json:"variantId" validate:"required,gt=0"`
+	Quantity  int  `json:"quantity" validate:"required,gt=0"`
+}
+
+// CreateStockTransferDTO represents incoming API data to create a pending
+// stock transfer between two warehouses
+type CreateStockTransferDTO struct {
+	FromWarehouseID uint                   `json:"fromWarehouseId" validate:"required,gt=0"`
+	ToWarehouseID   uint                   `json:"toWarehouseId" validate:"required,gt=0,nefield=FromWarehouseID"`
+	Notes           string                 `json:"notes,omitempty"`
+	Lines           []StockTransferLineDTO `json:"lines" validate:"required,min=1,unique=VariantID,dive"`
+}
+
+// ToModel converts CreateStockTransferDTO to a pending StockTransfer of the
+// org with its lines. The SKUs of the lines are set by the stock transfer
+// service.
+func (dto *CreateStockTransferDTO) ToModel(orgID uint) *model.StockTransfer {
+	transfer := &model.StockTransfer{
+		OrgID:           orgID,
+		FromWarehouseID: dto.FromWarehouseID,
+		ToWarehouseID:   dto.ToWarehouseID,
+		Status:          model.StockTransferPending,
+		Notes:           dto.Notes,
+	}
+	for _, line := range dto.Lines {
+		transfer.Lines = append(transfer.Lines, model.StockTransferLine{
+			OrgID:     orgID,
+			VariantID: line.VariantID,
+			Quantity:  line.Quantity,
+		})
+	}
+	return transfer
+}
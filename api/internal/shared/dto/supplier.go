@@ -0,0 +1,66 @@
+package dto
+
+import "github.com/deveasyclick/openb2b/internal/model"
+
+// CreateSupplierDTO represents incoming API data to create a supplier
+type CreateSupplierDTO struct {
+	Name        string           `json:"name" validate:"required,max=100" example:"Acme Wholesale"`
+	ContactName string           `json:"contactName,omitempty" validate:"omitempty,max=100"`
+	Email       string           `json:"email" validate:"required,email,max=100"` // purchase orders are emailed here
+	PhoneNumber string           `json:"phoneNumber,omitempty" validate:"omitempty,max=50"`
+	Address     *AddressOptional `json:"address,omitempty"`
+	Currency    string           `json:"currency,omitempty" validate:"omitempty,len=3,uppercase"` // currency of purchase orders, defaults to the org base currency
+}
+
+// ToModel converts CreateSupplierDTO to a Supplier of the org
+func (dto *CreateSupplierDTO) ToModel(orgID uint) *model.Supplier {
+	supplier := &model.Supplier{
+		OrgID:       orgID,
+		Name:        dto.Name,
+		ContactName: dto.ContactName,
+		Email:       dto.Email,
+		PhoneNumber: dto.PhoneNumber,
+		Currency:    dto.Currency,
+	}
+
+	if dto.Address != nil {
+		supplier.Address = dto.Address.ToModel()
+	}
+	return supplier
+}
+
+type UpdateSupplierDTO struct {
+	Name        *string          `json:"name" validate:"omitempty,max=100"`
+	ContactName *string          `json:"contactName" validate:"omitempty,max=100"`
+	Email       *string          `json:"email" validate:"omitempty,email,max=100"`
+	PhoneNumber *string          `json:"phoneNumber" validate:"omitempty,max=50"`
+	Address     *AddressOptional `json:"address,omitempty"`
+	Currency    *string          `json:"currency" validate:"omitempty,len=3,uppercase"`
+}
+
+// ApplyModel updates an existing Supplier model with DTO values
+func (dto *UpdateSupplierDTO) ApplyModel(s *model.Supplier) {
+	if dto.Name != nil {
+		s.Name = *dto.Name
+	}
+	if dto.ContactName != nil {
+		s.ContactName = *dto.ContactName
+	}
+	if dto.Email != nil {
+		s.Email = *dto.Email
+	}
+	if dto.PhoneNumber != nil {
+		s.PhoneNumber = *dto.PhoneNumber
+	}
+
+	if dto.Address != nil {
+		if s.Address == nil {
+			s.Address = &model.Address{}
+		}
+		dto.Address.ApplyModel(s.Address)
+	}
+
+	if dto.Currency != nil {
+		s.Currency = *dto.Currency
+	}
+}
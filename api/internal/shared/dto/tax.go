@@ -0,0 +1,83 @@
+package dto
+
+import "github.com/deveasyclick/openb2b/internal/model"
+
+// CreateTaxRateDTO represents incoming API data to create a tax rate
+type CreateTaxRateDTO struct {
+	Name string  `json:"name" validate:"required,max=50" example:"VAT"`
+	Rate float64 `json:"rate" validate:"min=0,max=1" example:"0.075"`
+	// Compound taxes are charged on the price plus the taxes before them
+	Compound bool `json:"compound"`
+	Priority int  `json:"priority" validate:"min=0"`
+}
+
+// ToModel converts CreateTaxRateDTO to a TaxRate of the org
+func (dto *CreateTaxRateDTO) ToModel(orgID uint) *model.TaxRate {
+	return &model.TaxRate{
+		OrgID:    orgID,
+		Name:     dto.Name,
+		Rate:     dto.Rate,
+		Compound: dto.Compound,
+		Priority: dto.Priority,
+	}
+}
+
+type UpdateTaxRateDTO struct {
+	Name     *string  `json:"name" validate:"omitempty,max=50"`
+	Rate     *float64 `json:"rate" validate:"omitempty,min=0,max=1"`
+	Compound *bool    `json:"compound"`
+	Priority *int     `json:"priority" validate:"omitempty,min=0"`
+}
+
+// ApplyModel updates an existing TaxRate with DTO values. Orders priced
+// before keep the rates they were priced at.
+func (dto *UpdateTaxRateDTO) ApplyModel(rate *model.TaxRate) {
+	if dto.Name != nil {
+		rate.Name = *dto.Name
+	}
+	if dto.Rate != nil {
+		rate.Rate = *dto.Rate
+	}
+	if dto.Compound != nil {
+		rate.Compound = *dto.Compound
+	}
+	if dto.Priority != nil {
+		rate.Priority = *dto.Priority
+	}
+}
+
+// CreateTaxClassDTO represents incoming API data to create a tax class
+type CreateTaxClassDTO struct {
+	Name string `json:"name" validate:"required,max=50" example:"Standard"`
+	// Exempt classes charge no tax
+	Exempt  bool   `json:"exempt"`
+	RateIDs []uint `json:"rateIds" validate:"omitempty,dive,gt=0"`
+}
+
+// ToModel converts CreateTaxClassDTO to a TaxClass of the org, without its
+// rates
+func (dto *CreateTaxClassDTO) ToModel(orgID uint) *model.TaxClass {
+	return &model.TaxClass{
+		OrgID:  orgID,
+		Name:   dto.Name,
+		Exempt: dto.Exempt,
+	}
+}
+
+type UpdateTaxClassDTO struct {
+	Name   *string `json:"name" validate:"omitempty,max=50"`
+	Exempt *bool   `json:"exempt"`
+	// RateIDs replaces the rates of the class, when set
+	RateIDs []uint `json:"rateIds" validate:"omitempty,dive,gt=0"`
+}
+
+// ApplyModel updates the fields of an existing TaxClass with DTO values.
+// The rates are replaced by the service.
+func (dto *UpdateTaxClassDTO) ApplyModel(class *model.TaxClass) {
+	if dto.Name != nil {
+		class.Name = *dto.Name
+	}
+	if dto.Exempt != nil {
+		class.Exempt = *dto.Exempt
+	}
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "github.com/deveasyclick/openb2b/internal/model"
+
+// CreateWarehouseDTO represents incoming API data to create a warehouse
+type CreateWarehouseDTO struct {
+	Name    string           `json:"name" validate:"required,max=100" example:"Main store"`
+	Code    string           `json:"code" validate:"required,max=20" example:"MAIN"`
+	Address *AddressOptional `json:"address,omitempty"`
+}
+
+// ToModel converts CreateWarehouseDTO to a Warehouse of the org
+func (dto *CreateWarehouseDTO) ToModel(orgID uint) *model.Warehouse {
+	warehouse := &model.Warehouse{
+		OrgID: orgID,
+		Name:  dto.Name,
+		Code:  dto.Code,
+	}
+
+	if dto.Address != nil {
+		warehouse.Address = dto.Address.ToModel()
+	}
+	return warehouse
+}
+
+type UpdateWarehouseDTO struct {
+	Name    *string          `json:"name" validate:"omitempty,max=100"`
+	Code    *string          `json:"code" validate:"omitempty,min=1,max=20"`
+	Address *AddressOptional `json:"address,omitempty"`
+}
+
+// ApplyModel updates an existing Warehouse model with DTO values
+func (dto *UpdateWarehouseDTO) ApplyModel(w *model.Warehouse) {
+	if dto.Name != nil {
+		w.Name = *dto.Name
+	}
+	if dto.Code != nil {
+		w.Code = *dto.Code
+	}
+
+	if dto.Address != nil {
+		if w.Address == nil {
+			w.Address = &model.Address{}
+		}
+		dto.Address.ApplyModel(w.Address)
+	}
+}
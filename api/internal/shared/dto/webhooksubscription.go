@@ -0,0 +1,48 @@
+package dto
+
+import "github.com/deveasyclick/openb2b/internal/model"
+
+// CreateWebhookSubscriptionDTO registers an endpoint for domain events
+type CreateWebhookSubscriptionDTO struct {
+	URL         string   `json:"url" validate:"required,url,startswith=https://,max=2048"`
+	Description string   `json:"description,omitempty" validate:"max=255"`
+	EventTypes  []string `json:"eventTypes" validate:"required,min=1,dive,webhook_event"`
+}
+
+// ToModel converts CreateWebhookSubscriptionDTO to a WebhookSubscription
+// signed with secret
+func (dto *CreateWebhookSubscriptionDTO) ToModel(orgID uint, secret string) *model.WebhookSubscription {
+	return &model.WebhookSubscription{
+		OrgID:       orgID,
+		URL:         dto.URL,
+		Description: dto.Description,
+		EventTypes:  dto.EventTypes,
+		Active:      true,
+		Secret:      secret,
+	}
+}
+
+// UpdateWebhookSubscriptionDTO changes a webhook subscription. Inactive
+// subscriptions receive no deliveries.
+type UpdateWebhookSubscriptionDTO struct {
+	URL         *string   `json:"url,omitempty" validate:"omitempty,url,startswith=https://,max=2048"`
+	Description *string   `json:"description,omitempty" validate:"omitempty,max=255"`
+	EventTypes  *[]string `json:"eventTypes,omitempty" validate:"omitempty,min=1,dive,webhook_event"`
+	Active      *bool     `json:"active,omitempty"`
+}
+
+// ApplyModel updates an existing WebhookSubscription with DTO values
+func (dto *UpdateWebhookSubscriptionDTO) ApplyModel(s *model.WebhookSubscription) {
+	if dto.URL != nil {
+		s.URL = *dto.URL
+	}
+	if dto.Description != nil {
+		s.Description = *dto.Description
+	}
+	if dto.EventTypes != nil {
+		s.EventTypes = *dto.EventTypes
+	}
+	if dto.Active != nil {
+		s.Active = *dto.Active
+	}
+}
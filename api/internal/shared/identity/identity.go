@@ -11,6 +11,7 @@ import (
 type CustomSessionClaims struct {
 	OrgID   string `json:"org_id,omitempty"`
 	UserID  string `json:"user_id,omitempty"`
+	Role    string `json:"role,omitempty"`
 	ClerkId string
 }
 
@@ -18,6 +19,22 @@ type ContextUser struct {
 	ID      uint
 	Org     uint
 	ClerkID string
+	Role    string
+
+	// APIKeyID is set when the request was authenticated with an API key.
+	// ID is then the user who created the key, and Scopes replace Role.
+	APIKeyID uint
+	Scopes   []string
+}
+
+type contextKey string
+
+const userKey contextKey = "contextUser"
+
+// WithUser returns a copy of ctx authenticated as user. UserFromContext
+// returns user for it instead of reading the Clerk session.
+func WithUser(ctx context.Context, user *ContextUser) context.Context {
+	return context.WithValue(ctx, userKey, user)
 }
 
 func GetCustomClaims(ctx context.Context) (*CustomSessionClaims, error) {
@@ -33,6 +50,7 @@ func GetCustomClaims(ctx context.Context) (*CustomSessionClaims, error) {
 	return &CustomSessionClaims{
 		UserID:  customClaims.UserID,
 		OrgID:   customClaims.OrgID,
+		Role:    customClaims.Role,
 		ClerkId: claims.Subject,
 	}, nil
 }
@@ -46,6 +64,10 @@ func ClerkIDFromContext(ctx context.Context) (string, bool) {
 }
 
 func UserFromContext(ctx context.Context) (*ContextUser, error) {
+	if user, ok := ctx.Value(userKey).(*ContextUser); ok && user != nil {
+		return user, nil
+	}
+
 	claims, err := GetCustomClaims(ctx)
 	if err != nil {
 		return nil, err
@@ -68,7 +90,18 @@ func UserFromContext(ctx context.Context) (*ContextUser, error) {
 		ID:      userID,
 		ClerkID: claims.ClerkId,
 		Org:     orgId,
+		Role:    claims.Role,
 	}
 
 	return user, nil
 }
+
+// ActorID returns the ID of the authenticated user, or nil when ctx doesn't
+// carry one, e.g. in background jobs. It is meant for "changed by" columns.
+func ActorID(ctx context.Context) *uint {
+	user, err := UserFromContext(ctx)
+	if err != nil || user.ID == 0 {
+		return nil
+	}
+	return &user.ID
+}
@@ -144,6 +144,14 @@ func TestUserFromContext(t *testing.T) {
 			}),
 			want: &ContextUser{ID: 10, Org: 20},
 		},
+		{
+			name: "user set on context wins over claims",
+			ctx: WithUser(fakeClaimsCtx("user-x", &CustomSessionClaims{
+				UserID: "10",
+				OrgID:  "20",
+			}), &ContextUser{ID: 3, Org: 4, APIKeyID: 5}),
+			want: &ContextUser{ID: 3, Org: 4},
+		},
 	}
 
 	for _, tt := range tests {
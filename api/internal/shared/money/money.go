@@ -0,0 +1,419 @@
+// Package money is an exact decimal type for amounts of money.
+//
+// A Money is a whole number of minor units (cents), so adding and
+// subtracting amounts never drifts. Multiplying by rates, percentages and
+// fractions is done exactly and rounded once, with an explicit RoundingMode.
+// Amounts are stored in decimal(12,2) columns and written to JSON as numbers
+// with two decimals, e.g. 1234.50.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimals of an amount.
+const Scale = 2
+
+// unit is the number of minor units in a major unit
+const unit = 100
+
+// ErrInvalid is returned when parsing something that is not an amount.
+var ErrInvalid = errors.New("invalid amount of money")
+
+// RoundingMode is how a result that falls between two cents is rounded.
+type RoundingMode int
+
+const (
+	// HalfUp rounds to the nearest cent, halves away from zero. It is the
+	// mode used for prices, discounts and taxes.
+	HalfUp RoundingMode = iota
+	// HalfEven rounds to the nearest cent, halves to the even cent.
+	HalfEven
+	// Down rounds toward zero.
+	Down
+	// Up rounds away from zero.
+	Up
+)
+
+// Money is an amount of money in cents. The zero value is 0.00.
+type Money struct {
+	cents int64
+}
+
+// Zero is 0.00.
+var Zero = Money{}
+
+// FromCents returns the amount of cents.
+func FromCents(cents int64) Money {
+	return Money{cents: cents}
+}
+
+// FromInt returns a whole amount, e.g. FromInt(12) is 12.00.
+func FromInt(units int64) Money {
+	return Money{cents: units * unit}
+}
+
+// FromFloat returns f rounded to cents with mode. It is meant for values
+// that are floats already, e.g. from a spreadsheet, not for arithmetic.
+func FromFloat(f float64, mode RoundingMode) Money {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	if !ok {
+		return Zero
+	}
+	return fromRat(r, mode)
+}
+
+// Parse parses a decimal amount with at most two decimals, e.g. "-12.5".
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" && fraction == "" || len(fraction) > Scale {
+		return Zero, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	fraction += strings.Repeat("0", Scale-len(fraction))
+
+	if whole == "" {
+		whole = "0"
+	}
+	units, err := strconv.ParseUint(whole, 10, 63)
+	if err != nil {
+		return Zero, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	cents, err := strconv.ParseUint(fraction, 10, 63)
+	if err != nil {
+		return Zero, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	if units > math.MaxInt64/unit {
+		return Zero, fmt.Errorf("%w: %q is too large", ErrInvalid, s)
+	}
+
+	m := Money{cents: int64(units)*unit + int64(cents)}
+	if negative {
+		return m.Neg(), nil
+	}
+	return m, nil
+}
+
+// MustParse is like Parse but panics on invalid amounts. It is meant for
+// constants and tests.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Cents returns the amount in cents.
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+// Float64 returns the amount as a float, for display and interop only.
+func (m Money) Float64() float64 {
+	return float64(m.cents) / unit
+}
+
+// String formats the amount with two decimals, e.g. "-12.50".
+func (m Money) String() string {
+	sign := ""
+	cents := m.cents
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/unit, cents%unit)
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{cents: m.cents + o.cents}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{cents: m.cents - o.cents}
+}
+
+func (m Money) Neg() Money {
+	return Money{cents: -m.cents}
+}
+
+// Mul returns the amount times n, e.g. a unit price times a quantity.
+func (m Money) Mul(n int) Money {
+	return Money{cents: m.cents * int64(n)}
+}
+
+// MulFrac returns the amount times num/den, rounded with mode. It panics
+// when den is 0.
+func (m Money) MulFrac(num int64, den int64, mode RoundingMode) Money {
+	return m.mulRat(big.NewRat(num, den), mode)
+}
+
+// MulRate returns the amount times rate, e.g. a tax rate of 0.075, rounded
+// with mode. The rate is taken as the decimal it prints as, so 0.075 is
+// exactly 75/1000.
+func (m Money) MulRate(rate float64, mode RoundingMode) Money {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+	if !ok {
+		return Zero
+	}
+	return m.mulRat(r, mode)
+}
+
+// DivRate returns the amount divided by rate, e.g. a price in a base
+// currency converted at an exchange rate, rounded with mode. It panics when
+// rate is 0.
+func (m Money) DivRate(rate float64, mode RoundingMode) Money {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+	if !ok {
+		return Zero
+	}
+	return m.mulRat(r.Inv(r), mode)
+}
+
+// Percent returns pct percent of the amount, rounded with mode. pct is an
+// amount itself so it has two decimals too, e.g. 12.50 for 12.5%.
+func (m Money) Percent(pct Money, mode RoundingMode) Money {
+	return m.MulFrac(pct.cents, 100*unit, mode)
+}
+
+func (m Money) mulRat(r *big.Rat, mode RoundingMode) Money {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(m.cents), r)
+	return Money{cents: round(product, mode)}
+}
+
+// fromRat returns the amount of r major units.
+func fromRat(r *big.Rat, mode RoundingMode) Money {
+	return Money{cents: round(new(big.Rat).Mul(r, big.NewRat(unit, 1)), mode)}
+}
+
+// round rounds r to an integer with mode.
+func round(r *big.Rat, mode RoundingMode) int64 {
+	num := new(big.Int).Set(r.Num())
+	den := r.Denom()
+	negative := num.Sign() < 0
+	num.Abs(num)
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() != 0 {
+		// Compare the remainder to half the denominator
+		cmp := new(big.Int).Mul(rem, big.NewInt(2)).Cmp(den)
+		roundUp := false
+		switch mode {
+		case HalfUp:
+			roundUp = cmp >= 0
+		case HalfEven:
+			roundUp = cmp > 0 || cmp == 0 && quo.Bit(0) == 1
+		case Up:
+			roundUp = true
+		case Down:
+			roundUp = false
+		}
+		if roundUp {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	if negative {
+		quo.Neg(quo)
+	}
+	return quo.Int64()
+}
+
+// Allocate splits the amount between weights in proportion to them. The
+// shares are whole cents that add up to the amount exactly: each share is
+// rounded down and the cents left over go to the largest remainders, the
+// first weights first on ties. Without any positive weight it returns
+// zeros.
+func (m Money) Allocate(weights []Money) []Money {
+	shares := make([]Money, len(weights))
+
+	var total int64
+	for _, w := range weights {
+		if w.cents > 0 {
+			total += w.cents
+		}
+	}
+	if total == 0 {
+		return shares
+	}
+
+	remainders := make([]*big.Int, len(weights))
+	allocated := int64(0)
+	for i, w := range weights {
+		if w.cents <= 0 {
+			remainders[i] = big.NewInt(-1)
+			continue
+		}
+		product := new(big.Int).Mul(big.NewInt(m.cents), big.NewInt(w.cents))
+		quo, rem := new(big.Int).QuoRem(product, big.NewInt(total), new(big.Int))
+		if rem.Sign() < 0 {
+			// Round negative amounts toward negative infinity too
+			quo.Sub(quo, big.NewInt(1))
+			rem.Add(rem, big.NewInt(total))
+		}
+		shares[i] = Money{cents: quo.Int64()}
+		remainders[i] = rem
+		allocated += quo.Int64()
+	}
+
+	for left := m.cents - allocated; left > 0; left-- {
+		largest := -1
+		for i, rem := range remainders {
+			if rem.Sign() >= 0 && (largest == -1 || rem.Cmp(remainders[largest]) > 0) {
+				largest = i
+			}
+		}
+		shares[largest].cents++
+		remainders[largest] = big.NewInt(-1)
+	}
+
+	return shares
+}
+
+// Cmp returns -1, 0 or +1 when the amount is less than, equal to or more
+// than o.
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.cents < o.cents:
+		return -1
+	case m.cents > o.cents:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.cents > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.cents < 0
+}
+
+func (m Money) LessThan(o Money) bool {
+	return m.cents < o.cents
+}
+
+func (m Money) GreaterThan(o Money) bool {
+	return m.cents > o.cents
+}
+
+// Min returns the smaller of a and b.
+func Min(a Money, b Money) Money {
+	if a.cents < b.cents {
+		return a
+	}
+	return b
+}
+
+// Max returns the larger of a and b.
+func Max(a Money, b Money) Money {
+	if a.cents > b.cents {
+		return a
+	}
+	return b
+}
+
+// Sum returns the total of amounts.
+func Sum(amounts ...Money) Money {
+	var total Money
+	for _, a := range amounts {
+		total = total.Add(a)
+	}
+	return total
+}
+
+// MarshalJSON writes the amount as a number with two decimals.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads an amount from a number or a string with at most two
+// decimals.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	// Numbers may come in exponent form, e.g. 1e3
+	if strings.ContainsAny(s, "eE") {
+		r, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrInvalid, data)
+		}
+		cents := new(big.Rat).Mul(r, big.NewRat(unit, 1))
+		if !cents.IsInt() {
+			return fmt.Errorf("%w: %s has more than %d decimals", ErrInvalid, data, Scale)
+		}
+		*m = fromRat(r, HalfUp)
+		return nil
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// GormDataType is the column type of amounts.
+func (Money) GormDataType() string {
+	return "decimal(12,2)"
+}
+
+// Value stores the amount as a decimal string.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan reads an amount from a decimal column. SQLite hands decimals back as
+// integers or floats, Postgres as strings.
+func (m *Money) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*m = Zero
+	case int64:
+		*m = FromInt(v)
+	case float64:
+		*m = FromFloat(v, HalfUp)
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalid, value)
+	}
+	return nil
+}
+
+func (m *Money) scanString(s string) error {
+	parsed, err := Parse(s)
+	if err != nil {
+		// Numeric columns without a scale may hold more decimals
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil {
+			return err
+		}
+		parsed = FromFloat(f, HalfUp)
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,145 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in    string
+		cents int64
+	}{
+		{"0", 0},
+		{"12", 1200},
+		{"12.5", 1250},
+		{"12.50", 1250},
+		{"-0.05", -5},
+		{".99", 99},
+		{"+3.10", 310},
+	}
+	for _, tt := range tests {
+		m, err := Parse(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.cents, m.Cents(), tt.in)
+	}
+
+	for _, in := range []string{"", "-", "1.005", "abc", "1.2.3", "1,50"} {
+		_, err := Parse(in)
+		assert.ErrorIs(t, err, ErrInvalid, in)
+	}
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "0.00", Zero.String())
+	assert.Equal(t, "12.05", FromCents(1205).String())
+	assert.Equal(t, "-0.50", FromCents(-50).String())
+	assert.Equal(t, "100.00", FromInt(100).String())
+}
+
+func TestRoundingModes(t *testing.T) {
+	price := MustParse("0.25")
+
+	// 0.25 * 0.5 = 0.125
+	assert.Equal(t, "0.13", price.MulRate(0.5, HalfUp).String())
+	assert.Equal(t, "0.12", price.MulRate(0.5, HalfEven).String())
+	assert.Equal(t, "0.12", price.MulRate(0.5, Down).String())
+	assert.Equal(t, "0.13", price.MulRate(0.5, Up).String())
+
+	// Halves of negative amounts round away from zero
+	assert.Equal(t, "-0.13", price.Neg().MulRate(0.5, HalfUp).String())
+	assert.Equal(t, "-0.12", price.Neg().MulRate(0.5, Down).String())
+}
+
+func TestMulRateIsExact(t *testing.T) {
+	// As floats, 1.005 * 100 is 100.49999999999999
+	assert.Equal(t, "1.01", FromInt(1).MulRate(1.005, HalfUp).String())
+	// 7.5% of 19.99 is 1.49925
+	assert.Equal(t, "1.50", MustParse("19.99").MulRate(0.075, HalfUp).String())
+}
+
+func TestDivRate(t *testing.T) {
+	// 1,000 NGN at 1,550.25 NGN to the dollar
+	assert.Equal(t, "0.65", FromInt(1000).DivRate(1550.25, HalfUp).String())
+	assert.Equal(t, "0.64", FromInt(1000).DivRate(1550.25, Down).String())
+	assert.Equal(t, "20.00", FromInt(10).DivRate(0.5, HalfUp).String())
+}
+
+func TestPercentAndMulFrac(t *testing.T) {
+	assert.Equal(t, "25.00", FromInt(250).Percent(FromInt(10), HalfUp).String())
+	assert.Equal(t, "3.13", FromInt(25).Percent(MustParse("12.5"), HalfUp).String())
+	assert.Equal(t, "3.33", FromInt(10).MulFrac(1, 3, HalfUp).String())
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		weights []Money
+		want    []string
+	}{
+		{"proportional", FromInt(25), []Money{FromInt(200), FromInt(50)}, []string{"20.00", "5.00"}},
+		{"remainder to largest fraction", FromInt(10), []Money{FromInt(1), FromInt(1), FromInt(1)}, []string{"3.34", "3.33", "3.33"}},
+		{"uneven", MustParse("0.05"), []Money{FromInt(3), FromInt(7)}, []string{"0.02", "0.03"}},
+		{"zero weight gets nothing", FromInt(9), []Money{FromInt(1), Zero, FromInt(2)}, []string{"3.00", "0.00", "6.00"}},
+		{"no weights", FromInt(9), []Money{Zero}, []string{"0.00"}},
+		{"negative", MustParse("-0.10"), []Money{FromInt(1), FromInt(2)}, []string{"-0.03", "-0.07"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := tt.amount.Allocate(tt.weights)
+			got := make([]string, len(shares))
+			for i, s := range shares {
+				got[i] = s.String()
+			}
+			assert.Equal(t, tt.want, got)
+
+			if len(tt.weights) > 0 && Sum(tt.weights...).IsPositive() {
+				assert.Equal(t, tt.amount, Sum(shares...))
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var v struct {
+		Price Money  `json:"price"`
+		Fare  *Money `json:"fare"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"price": 12.5, "fare": "3"}`), &v))
+	assert.Equal(t, MustParse("12.50"), v.Price)
+	assert.Equal(t, FromInt(3), *v.Fare)
+
+	out, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price": 12.50, "fare": 3.00}`, string(out))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"price": 1.5e2}`), &v))
+	assert.Equal(t, FromInt(150), v.Price)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price": 0.125}`), &v))
+	assert.Error(t, json.Unmarshal([]byte(`{"price": true}`), &v))
+}
+
+func TestScan(t *testing.T) {
+	var m Money
+	require.NoError(t, m.Scan(int64(12)))
+	assert.Equal(t, FromInt(12), m)
+	require.NoError(t, m.Scan(12.3))
+	assert.Equal(t, MustParse("12.30"), m)
+	require.NoError(t, m.Scan([]byte("-4.05")))
+	assert.Equal(t, MustParse("-4.05"), m)
+	require.NoError(t, m.Scan("0.123456"))
+	assert.Equal(t, MustParse("0.12"), m)
+	require.NoError(t, m.Scan(nil))
+	assert.True(t, m.IsZero())
+
+	value, err := MustParse("7.10").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "7.10", value)
+}
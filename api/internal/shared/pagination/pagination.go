@@ -1,6 +1,7 @@
 package pagination
 
 import (
+	"context"
 	"math"
 	"net/url"
 	"strconv"
@@ -82,7 +83,12 @@ func ParsePaginationOptions(query url.Values, allowedFields map[string]bool) (Op
 }
 
 // Sample pagination query: ?manufacturer_id_in=0,1&code=33000&manufacturer_Id=1&sort=code desc&preloads=Manufacturer&type_like=floor
-func Paginate[T any](db *gorm.DB, opts Options) (items []T, total int64, err error) {
+//
+// ctx is attached to every query so tenant scoping (see package tenant) is
+// applied to both the count and the page query.
+func Paginate[T any](ctx context.Context, db *gorm.DB, opts Options) (items []T, total int64, err error) {
+	db = db.WithContext(ctx)
+
 	// Count total matching records with filters
 	countDB := db.Model(new(T))
 	countDB = applyFilters(countDB, opts.Filters, opts.SearchFields, opts.SearchJoinQuery)
@@ -1,6 +1,7 @@
 package pagination
 
 import (
+	"context"
 	"net/url"
 	"regexp"
 	"testing"
@@ -86,7 +87,7 @@ func TestPaginate(t *testing.T) {
 	}
 
 	var items []Product
-	items, total, err := Paginate[Product](db, opts)
+	items, total, err := Paginate[Product](context.Background(), db, opts)
 
 	assert.NoError(t, err)
 	assert.Equal(t, int64(2), total)
@@ -0,0 +1,121 @@
+// Package rbac maps user roles to the permissions they are granted.
+package rbac
+
+import "github.com/deveasyclick/openb2b/internal/model"
+
+// Permission is an action on a resource, written as "<resource>:<action>".
+type Permission string
+
+const (
+	OrgsRead   Permission = "orgs:read"
+	OrgsWrite  Permission = "orgs:write"
+	OrgsDelete Permission = "orgs:delete"
+
+	ProductsRead  Permission = "products:read"
+	ProductsWrite Permission = "products:write"
+
+	OrdersRead    Permission = "orders:read"
+	OrdersWrite   Permission = "orders:write"
+	OrdersApprove Permission = "orders:approve"
+	// OrdersFulfil ships and delivers orders, which moves their stock
+	OrdersFulfil Permission = "orders:fulfil"
+
+	CustomersRead  Permission = "customers:read"
+	CustomersWrite Permission = "customers:write"
+
+	InvoicesRead  Permission = "invoices:read"
+	InvoicesWrite Permission = "invoices:write"
+	InvoicesIssue Permission = "invoices:issue"
+
+	PaymentsRead  Permission = "payments:read"
+	PaymentsWrite Permission = "payments:write"
+
+	WebhooksManage Permission = "webhooks:manage"
+
+	APIKeysManage Permission = "api_keys:manage"
+
+	AuditLogsRead Permission = "audit_logs:read"
+
+	ReportsRead Permission = "reports:read"
+)
+
+var readOnly = []Permission{
+	OrgsRead,
+	ProductsRead,
+	OrdersRead,
+	CustomersRead,
+	InvoicesRead,
+	PaymentsRead,
+	ReportsRead,
+}
+
+var sales = append([]Permission{
+	OrdersWrite,
+	CustomersWrite,
+	InvoicesWrite,
+}, readOnly...)
+
+var admin = append([]Permission{
+	OrgsWrite,
+	ProductsWrite,
+	OrdersApprove,
+	OrdersFulfil,
+	InvoicesIssue,
+	PaymentsWrite,
+	WebhooksManage,
+	APIKeysManage,
+	AuditLogsRead,
+}, sales...)
+
+var owner = append([]Permission{
+	OrgsDelete,
+}, admin...)
+
+// matrix is the permission matrix. A role not listed here has no permissions.
+var matrix = map[model.Role]map[Permission]bool{
+	model.RoleOwner:  toSet(owner),
+	model.RoleAdmin:  toSet(admin),
+	model.RoleSales:  toSet(sales),
+	model.RoleViewer: toSet(readOnly),
+}
+
+func toSet(perms []Permission) map[Permission]bool {
+	set := make(map[Permission]bool, len(perms))
+	for _, p := range perms {
+		set[p] = true
+	}
+	return set
+}
+
+// Can reports whether role is granted every one of perms.
+func Can(role model.Role, perms ...Permission) bool {
+	granted, ok := matrix[role]
+	if !ok {
+		return false
+	}
+
+	for _, p := range perms {
+		if !granted[p] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// InScopes reports whether scopes, the permissions of an API key, include
+// every one of perms.
+func InScopes(scopes []string, perms ...Permission) bool {
+	granted := make(map[Permission]bool, len(scopes))
+	for _, s := range scopes {
+		granted[Permission(s)] = true
+	}
+
+	for _, p := range perms {
+		if !granted[p] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,56 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCan(t *testing.T) {
+	tests := []struct {
+		name  string
+		role  model.Role
+		perms []Permission
+		want  bool
+	}{
+		{"owner can delete org", model.RoleOwner, []Permission{OrgsDelete}, true},
+		{"admin cannot delete org", model.RoleAdmin, []Permission{OrgsDelete}, false},
+		{"admin can issue invoices", model.RoleAdmin, []Permission{InvoicesIssue}, true},
+		{"admin can approve orders", model.RoleAdmin, []Permission{OrdersApprove}, true},
+		{"sales can write orders", model.RoleSales, []Permission{OrdersWrite, CustomersWrite}, true},
+		{"sales cannot write products", model.RoleSales, []Permission{ProductsWrite}, false},
+		{"sales cannot approve orders", model.RoleSales, []Permission{OrdersApprove}, false},
+		{"admin can fulfil orders", model.RoleAdmin, []Permission{OrdersFulfil}, true},
+		{"sales cannot fulfil orders", model.RoleSales, []Permission{OrdersFulfil}, false},
+		{"sales cannot record payments", model.RoleSales, []Permission{PaymentsWrite}, false},
+		{"sales cannot issue invoices", model.RoleSales, []Permission{InvoicesIssue}, false},
+		{"admin can manage webhooks", model.RoleAdmin, []Permission{WebhooksManage}, true},
+		{"sales cannot manage webhooks", model.RoleSales, []Permission{WebhooksManage}, false},
+		{"admin can manage api keys", model.RoleAdmin, []Permission{APIKeysManage}, true},
+		{"sales cannot manage api keys", model.RoleSales, []Permission{APIKeysManage}, false},
+		{"admin can read audit logs", model.RoleAdmin, []Permission{AuditLogsRead}, true},
+		{"sales cannot read audit logs", model.RoleSales, []Permission{AuditLogsRead}, false},
+		{"viewer can read reports", model.RoleViewer, []Permission{ReportsRead}, true},
+		{"viewer can read", model.RoleViewer, []Permission{ProductsRead, InvoicesRead}, true},
+		{"viewer cannot write products", model.RoleViewer, []Permission{ProductsWrite}, false},
+		{"viewer needs every permission", model.RoleViewer, []Permission{ProductsRead, ProductsWrite}, false},
+		{"unknown role", model.Role("guest"), []Permission{ProductsRead}, false},
+		{"empty role", model.Role(""), []Permission{ProductsRead}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Can(tt.role, tt.perms...))
+		})
+	}
+}
+
+func TestInScopes(t *testing.T) {
+	scopes := []string{"orders:read", "orders:write"}
+
+	assert.True(t, InScopes(scopes, OrdersRead))
+	assert.True(t, InScopes(scopes, OrdersRead, OrdersWrite))
+	assert.False(t, InScopes(scopes, OrdersRead, ProductsRead))
+	assert.False(t, InScopes(nil, OrdersRead))
+}
@@ -0,0 +1,73 @@
+// Package safehttp provides an HTTP client for requests to addresses orgs
+// configure, such as webhook endpoints. It only connects to public
+// addresses, so those requests can't reach the internal network, the host
+// itself or cloud metadata services.
+package safehttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// dialTimeout bounds how long connecting to an address can take.
+const dialTimeout = 10 * time.Second
+
+// ErrPrivateAddress is returned when a request would connect to an address
+// that is not public.
+var ErrPrivateAddress = errors.New("connecting to a non-public address is not allowed")
+
+// NewClient returns a client that refuses to connect to loopback, private,
+// link-local and unspecified addresses and does not follow redirects, so an
+// endpoint can't bounce requests to such an address either.
+func NewClient() *http.Client {
+	dialer := &net.Dialer{Timeout: dialTimeout, Control: control}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect on our behalf, past the check of the dialer
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// IsPublic reports whether ip is an address requests may connect to.
+func IsPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsValid() &&
+		!ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// control checks the address of every connection right before it is made,
+// after the host name was resolved. Checking here rather than the URL means
+// a host can't resolve to a public address when checked and to a private
+// one when connected to.
+func control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !IsPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, ip)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+// Package tenant enforces org (tenant) isolation at the database layer.
+//
+// Register installs GORM callbacks that, for every model carrying a
+// non-nullable `OrgID` field, inject an `org_id = ?` predicate into
+// queries, updates and deletes, and stamp the org on inserted rows. The org
+// is resolved from the request context: an explicit org set with WithOrg
+// wins, otherwise the authenticated user from identity.UserFromContext is
+// used. Statements that touch a tenant-scoped model without a resolvable
+// org are rejected with ErrMissingTenant instead of silently reading or
+// writing across orgs.
+//
+// System code that legitimately works across orgs (migrations, seeding,
+// maintenance jobs) must opt out explicitly with Bypass.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type contextKey string
+
+const (
+	orgKey    contextKey = "tenantOrg"
+	bypassKey contextKey = "tenantBypass"
+
+	// orgFieldName is the struct field that marks a model as tenant scoped.
+	orgFieldName = "OrgID"
+
+	scopedKey = "tenant:scoped"
+)
+
+var (
+	// ErrMissingTenant is returned when a tenant-scoped statement runs
+	// without an org in its context.
+	ErrMissingTenant = errors.New(apperrors.ErrMissingTenant)
+
+	// ErrTenantMismatch is returned when a row being inserted belongs to a
+	// different org than the one in the context.
+	ErrTenantMismatch = errors.New(apperrors.ErrTenantMismatch)
+)
+
+// WithOrg returns a copy of ctx scoped to the given org. It takes precedence
+// over the org found in the Clerk session claims and is meant for code that
+// runs outside an HTTP request, e.g. background workers.
+func WithOrg(ctx context.Context, orgID uint) context.Context {
+	return context.WithValue(ctx, orgKey, orgID)
+}
+
+// Bypass returns a copy of ctx for which tenant scoping is disabled.
+// Use it sparingly and only for system level work.
+func Bypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey, true)
+}
+
+// IsBypassed reports whether tenant scoping is disabled for ctx.
+func IsBypassed(ctx context.Context) bool {
+	bypassed, _ := ctx.Value(bypassKey).(bool)
+	return bypassed
+}
+
+// OrgFromContext resolves the org a statement should be scoped to.
+func OrgFromContext(ctx context.Context) (uint, error) {
+	if orgID, ok := ctx.Value(orgKey).(uint); ok && orgID != 0 {
+		return orgID, nil
+	}
+
+	user, err := identity.UserFromContext(ctx)
+	if err != nil || user.Org == 0 {
+		return 0, ErrMissingTenant
+	}
+
+	return user.Org, nil
+}
+
+// Register installs the tenant scoping callbacks on db.
+func Register(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", scopeStatement); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenant:row", scopeStatement); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", scopeStatement); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:delete", scopeStatement); err != nil {
+		return err
+	}
+	return cb.Create().Before("gorm:create").Register("tenant:create", stampCreate)
+}
+
+// orgField returns the OrgID field of s when s is tenant scoped.
+// Models with a nullable org (e.g. users that have not joined an org yet)
+// are not scoped.
+func orgField(s *schema.Schema) *schema.Field {
+	if s == nil {
+		return nil
+	}
+
+	field := s.LookUpField(orgFieldName)
+	if field == nil || field.DBName == "" || field.FieldType.Kind() != reflect.Uint {
+		return nil
+	}
+
+	return field
+}
+
+// scopeStatement adds `org_id = ?` to the WHERE clause of the statement.
+// Existing conditions are grouped first so OR filters cannot escape the
+// tenant predicate.
+func scopeStatement(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.SQL.Len() > 0 {
+		return
+	}
+
+	field := orgField(stmt.Schema)
+	if field == nil || IsBypassed(stmt.Context) {
+		return
+	}
+
+	if _, ok := db.InstanceGet(scopedKey); ok {
+		return
+	}
+
+	orgID, err := OrgFromContext(stmt.Context)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	tenantExpr := clause.Eq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName},
+		Value:  orgID,
+	}
+
+	c, ok := stmt.Clauses["WHERE"]
+	if !ok {
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{tenantExpr}})
+	} else {
+		where, _ := c.Expression.(clause.Where)
+		exprs := []clause.Expression{tenantExpr}
+		if len(where.Exprs) > 0 {
+			exprs = []clause.Expression{clause.And(where.Exprs...), tenantExpr}
+		}
+		c.Expression = clause.Where{Exprs: exprs}
+		stmt.Clauses["WHERE"] = c
+	}
+
+	db.InstanceSet(scopedKey, true)
+}
+
+// stampCreate sets the org on new rows that don't carry one and rejects rows
+// that belong to another org.
+func stampCreate(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.SQL.Len() > 0 {
+		return
+	}
+
+	field := orgField(stmt.Schema)
+	if field == nil || IsBypassed(stmt.Context) {
+		return
+	}
+
+	orgID, err := OrgFromContext(stmt.Context)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+
+	stamp := func(rv reflect.Value) {
+		value, isZero := field.ValueOf(stmt.Context, rv)
+		if isZero {
+			db.AddError(field.Set(stmt.Context, rv, orgID))
+			return
+		}
+
+		if value != orgID {
+			db.AddError(fmt.Errorf("%w: got org %v, expected %d", ErrTenantMismatch, value, orgID))
+		}
+	}
+
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			rv := reflect.Indirect(stmt.ReflectValue.Index(i))
+			if rv.Kind() == reflect.Struct {
+				stamp(rv)
+			}
+		}
+	case reflect.Struct:
+		stamp(stmt.ReflectValue)
+	}
+}
@@ -0,0 +1,132 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type Widget struct {
+	ID    uint
+	Name  string
+	OrgID uint
+}
+
+type Member struct {
+	ID    uint
+	Name  string
+	OrgID *uint
+}
+
+func setupDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Widget{}, &Member{}))
+	require.NoError(t, Register(db))
+
+	system := db.WithContext(Bypass(context.Background()))
+	require.NoError(t, system.Create(&[]Widget{
+		{Name: "alpha", OrgID: 1},
+		{Name: "beta", OrgID: 1},
+		{Name: "alpha", OrgID: 2},
+	}).Error)
+
+	return db
+}
+
+func TestOrgFromContext(t *testing.T) {
+	_, err := OrgFromContext(context.Background())
+	assert.ErrorIs(t, err, ErrMissingTenant)
+
+	_, err = OrgFromContext(WithOrg(context.Background(), 0))
+	assert.ErrorIs(t, err, ErrMissingTenant)
+
+	orgID, err := OrgFromContext(WithOrg(context.Background(), 7))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), orgID)
+}
+
+func TestQueryWithoutTenant(t *testing.T) {
+	db := setupDB(t)
+
+	var widgets []Widget
+	err := db.WithContext(context.Background()).Find(&widgets).Error
+	assert.ErrorIs(t, err, ErrMissingTenant)
+}
+
+func TestQueryIsScoped(t *testing.T) {
+	db := setupDB(t)
+	ctx := WithOrg(context.Background(), 2)
+
+	var widgets []Widget
+	assert.NoError(t, db.WithContext(ctx).Find(&widgets).Error)
+	assert.Len(t, widgets, 1)
+	assert.Equal(t, uint(2), widgets[0].OrgID)
+
+	var count int64
+	assert.NoError(t, db.WithContext(ctx).Model(&Widget{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	var widget Widget
+	err := db.WithContext(ctx).First(&widget, 1).Error
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestOrConditionsCannotEscapeTenant(t *testing.T) {
+	db := setupDB(t)
+	ctx := WithOrg(context.Background(), 1)
+
+	var widgets []Widget
+	err := db.WithContext(ctx).
+		Where("name = ?", "alpha").
+		Or("name = ?", "beta").
+		Find(&widgets).Error
+	assert.NoError(t, err)
+	assert.Len(t, widgets, 2)
+	for _, w := range widgets {
+		assert.Equal(t, uint(1), w.OrgID)
+	}
+}
+
+func TestUpdateAndDeleteAreScoped(t *testing.T) {
+	db := setupDB(t)
+	ctx := WithOrg(context.Background(), 1)
+
+	res := db.WithContext(ctx).Model(&Widget{}).Where("id = ?", 3).Update("name", "hijacked")
+	assert.NoError(t, res.Error)
+	assert.Equal(t, int64(0), res.RowsAffected)
+
+	res = db.WithContext(ctx).Delete(&Widget{}, 3)
+	assert.NoError(t, res.Error)
+	assert.Equal(t, int64(0), res.RowsAffected)
+
+	var widget Widget
+	assert.NoError(t, db.WithContext(Bypass(context.Background())).First(&widget, 3).Error)
+	assert.Equal(t, "alpha", widget.Name)
+}
+
+func TestCreateStampsOrg(t *testing.T) {
+	db := setupDB(t)
+	ctx := WithOrg(context.Background(), 2)
+
+	widget := Widget{Name: "gamma"}
+	assert.NoError(t, db.WithContext(ctx).Create(&widget).Error)
+	assert.Equal(t, uint(2), widget.OrgID)
+
+	err := db.WithContext(ctx).Create(&Widget{Name: "delta", OrgID: 1}).Error
+	assert.ErrorIs(t, err, ErrTenantMismatch)
+
+	err = db.WithContext(context.Background()).Create(&Widget{Name: "epsilon"}).Error
+	assert.ErrorIs(t, err, ErrMissingTenant)
+}
+
+func TestNullableOrgIsNotScoped(t *testing.T) {
+	db := setupDB(t)
+
+	var members []Member
+	assert.NoError(t, db.WithContext(context.Background()).Find(&members).Error)
+}
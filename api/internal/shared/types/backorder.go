@@ -0,0 +1,48 @@
+package types
+
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+)
+
+// Backorder is an order item of an open order waiting for its variant to
+// be restocked.
+type Backorder struct {
+	OrderID             uint              `json:"orderId"`
+	OrderNumber         string            `json:"orderNumber"`
+	OrderStatus         model.OrderStatus `json:"orderStatus"`
+	OrderedAt           time.Time         `json:"orderedAt"`
+	CustomerID          uint              `json:"customerId"`
+	CustomerFirstName   string            `json:"customerFirstName"`
+	CustomerLastName    string            `json:"customerLastName"`
+	OrderItemID         uint              `json:"orderItemId"`
+	VariantID           uint              `json:"variantId"`
+	SKU                 string            `json:"sku"`
+	Quantity            int               `json:"quantity"`
+	AllocatedQuantity   int               `json:"allocatedQuantity"`
+	BackorderedQuantity int               `json:"backorderedQuantity"`
+	WarehouseID         *uint             `json:"warehouseId,omitempty"` // fulfilment location of the order
+}
+
+// BackorderReport totals what is backordered by variant and customer.
+// @Description Backorder report
+type BackorderReport struct {
+	BackorderedQuantity int                   `json:"backorderedQuantity"`
+	Lines               []BackorderReportLine `json:"lines"`
+}
+
+// BackorderReportLine is what a customer has backordered of a variant, next
+// to the stock of the variant. Oldest is when the oldest of the orders was
+// placed, which is allocated first.
+type BackorderReportLine struct {
+	VariantID           uint      `json:"variantId"`
+	SKU                 string    `json:"sku"`
+	Stock               int       `json:"stock"`
+	Available           int       `json:"available"`
+	CustomerID          uint      `json:"customerId"`
+	CustomerName        string    `json:"customerName"`
+	Orders              int       `json:"orders"`
+	BackorderedQuantity int       `json:"backorderedQuantity"`
+	Oldest              time.Time `json:"oldest"`
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// Domain event types, see internal/outbox. The payload of each event is the
+// struct of the same name.
+const (
+	OrderCreatedEventType        = "order.created"
+	OrderUpdatedEventType        = "order.updated"
+	OrderStatusChangedEventType  = "order.status_changed"
+	InvoiceProFormaSentEventType = "invoice.pro_forma_sent"
+	InvoiceIssuedEventType       = "invoice.issued"
+	PaymentRecordedEventType     = "payment.recorded"
+	OrgCreatedEventType          = "org.created"
+	StockReplenishedEventType    = "stock.replenished"
+)
+
+// WebhookEventTypes are the event types orgs can subscribe webhooks to.
+var WebhookEventTypes = []string{
+	OrderCreatedEventType,
+	OrderUpdatedEventType,
+	OrderStatusChangedEventType,
+	InvoiceIssuedEventType,
+	PaymentRecordedEventType,
+}
+
+type OrderCreatedEvent struct {
+	OrderID     uint              `json:"orderId"`
+	OrderNumber string            `json:"orderNumber"`
+	CustomerID  uint              `json:"customerId"`
+	Status      model.OrderStatus `json:"status"`
+	Total       money.Money       `json:"total"`
+}
+
+type OrderUpdatedEvent struct {
+	OrderID     uint        `json:"orderId"`
+	OrderNumber string      `json:"orderNumber"`
+	Total       money.Money `json:"total"`
+}
+
+type OrderStatusChangedEvent struct {
+	OrderID     uint              `json:"orderId"`
+	OrderNumber string            `json:"orderNumber"`
+	From        model.OrderStatus `json:"from"`
+	To          model.OrderStatus `json:"to"`
+}
+
+// InvoiceEvent is the payload of invoice.pro_forma_sent and invoice.issued.
+type InvoiceEvent struct {
+	InvoiceID     uint                `json:"invoiceId"`
+	InvoiceNumber string              `json:"invoiceNumber"`
+	OrderID       uint                `json:"orderId"`
+	Status        model.InvoiceStatus `json:"status"`
+	Currency      string              `json:"currency"`
+	Total         money.Money         `json:"total"`
+}
+
+type PaymentRecordedEvent struct {
+	PaymentID  uint                `json:"paymentId"`
+	InvoiceID  uint                `json:"invoiceId"`
+	CustomerID uint                `json:"customerId"`
+	Amount     money.Money         `json:"amount"`
+	Currency   string              `json:"currency"`
+	Method     model.PaymentMethod `json:"method"`
+}
+
+// OrgCreatedEvent is recorded when a user creates their org. The org is set
+// on the Clerk user of its creator on this event.
+type OrgCreatedEvent struct {
+	OrgID   uint   `json:"orgId"`
+	UserID  uint   `json:"userId"`
+	ClerkID string `json:"clerkId"`
+}
+
+// StockReplenishedEvent is recorded when more of a variant becomes
+// available, e.g. it is restocked or an order gives its stock back.
+type StockReplenishedEvent struct {
+	VariantID uint `json:"variantId"`
+	Quantity  int  `json:"quantity"` // how much more is available
+}
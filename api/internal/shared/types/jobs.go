@@ -0,0 +1,37 @@
+package types
+
+// Background job types, see internal/jobs. The payload of each job is the
+// struct of the same name.
+const (
+	CreditNoteEmailJobType     = "credit_note.send_email"
+	DeleteClerkUserJobType     = "clerk.delete_user"
+	MarkOverdueInvoicesJobType = "invoice.mark_overdue"
+	PurchaseOrderEmailJobType  = "purchase_order.send_email"
+	WebhookDeliveryJobType     = "webhook.deliver"
+)
+
+// CreditNoteEmailJob emails a credit note to the customer of its invoice.
+type CreditNoteEmailJob struct {
+	CreditNoteID uint `json:"creditNoteId"`
+}
+
+// DeleteClerkUserJob deletes a Clerk user whose local user could not be
+// created.
+type DeleteClerkUserJob struct {
+	ClerkID string `json:"clerkId"`
+}
+
+// MarkOverdueInvoicesJob moves the invoices of every org that are past their
+// due date to overdue. It is scheduled, see internal/routes.
+type MarkOverdueInvoicesJob struct{}
+
+// PurchaseOrderEmailJob emails a purchase order to its supplier.
+type PurchaseOrderEmailJob struct {
+	PurchaseOrderID uint `json:"purchaseOrderId"`
+}
+
+// WebhookDeliveryJob posts a webhook delivery to the endpoint of its
+// subscription.
+type WebhookDeliveryJob struct {
+	DeliveryID uint `json:"deliveryId"`
+}
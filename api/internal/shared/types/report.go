@@ -0,0 +1,34 @@
+package types
+
+import (
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+)
+
+// SalesReport totals the invoices issued in a period. Totals are in the org
+// base currency, each invoice converted at the exchange rate of its order.
+// @Description Sales report
+type SalesReport struct {
+	BaseCurrency   string          `json:"baseCurrency"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"` // exclusive
+	Invoices       int             `json:"invoices"`
+	Total          money.Money     `json:"total"`
+	AmountPaid     money.Money     `json:"amountPaid"`
+	AmountCredited money.Money     `json:"amountCredited"`
+	AmountDue      money.Money     `json:"amountDue"`
+	Currencies     []CurrencySales `json:"currencies"`
+}
+
+// CurrencySales totals the invoices of a report in one currency, in that
+// currency. BaseTotal is Total in the org base currency.
+type CurrencySales struct {
+	Currency       string      `json:"currency"`
+	Invoices       int         `json:"invoices"`
+	Total          money.Money `json:"total"`
+	AmountPaid     money.Money `json:"amountPaid"`
+	AmountCredited money.Money `json:"amountCredited"`
+	AmountDue      money.Money `json:"amountDue"`
+	BaseTotal      money.Money `json:"baseTotal"`
+}
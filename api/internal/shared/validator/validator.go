@@ -5,8 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"slices"
 
 	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -14,6 +18,16 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+
+	// Validate amounts of money by their value, so tags like gt=0 apply
+	validate.RegisterCustomTypeFunc(func(v reflect.Value) any {
+		return v.Interface().(money.Money).Float64()
+	}, money.Money{})
+
+	// Event types webhooks can subscribe to
+	_ = validate.RegisterValidation("webhook_event", func(fl validator.FieldLevel) bool {
+		return slices.Contains(types.WebhookEventTypes, fl.Field().String())
+	})
 }
 
 func ValidateRequest(r *http.Request, req interface{}) []apperrors.ValidationError {
@@ -5,3 +5,11 @@ import "embed"
 //go:embed invoice/invoice.html
 var InvoiceFS embed.FS
 var InvoicePath = "invoice/invoice.html"
+
+//go:embed creditnote/creditnote.html
+var CreditNoteFS embed.FS
+var CreditNotePath = "creditnote/creditnote.html"
+
+//go:embed purchaseorder/purchaseorder.html
+var PurchaseOrderFS embed.FS
+var PurchaseOrderPath = "purchaseorder/purchaseorder.html"
@@ -0,0 +1,76 @@
+// Package apikeyutil generates and parses API keys.
+//
+// A key looks like "ob2b_<prefix>_<secret>". The prefix identifies the key
+// and is stored in the clear, so keys can be looked up and shown to users.
+// Only a SHA-256 hash of the whole key is stored, which is enough for keys
+// with this much entropy.
+package apikeyutil
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	// Scheme starts every API key, so they can be told apart from Clerk
+	// session tokens.
+	Scheme = "ob2b_"
+
+	prefixBytes = 4
+	secretBytes = 24
+)
+
+// ErrMalformed is returned for strings that are not API keys.
+var ErrMalformed = errors.New("malformed api key")
+
+// Generate returns a new random API key with its prefix and hash.
+func Generate() (key string, prefix string, hash string, err error) {
+	random := make([]byte, prefixBytes+secretBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(random[:prefixBytes])
+	key = Scheme + prefix + "_" + hex.EncodeToString(random[prefixBytes:])
+	return key, prefix, Hash(key), nil
+}
+
+// Parse returns the prefix of key.
+func Parse(key string) (string, error) {
+	rest, ok := strings.CutPrefix(key, Scheme)
+	if !ok {
+		return "", ErrMalformed
+	}
+
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 2*prefixBytes || len(secret) != 2*secretBytes {
+		return "", ErrMalformed
+	}
+
+	return prefix, nil
+}
+
+// FromAuthorization returns the API key of an Authorization header, if it
+// carries one as a bearer token.
+func FromAuthorization(header string) (string, bool) {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || !strings.HasPrefix(token, Scheme) {
+		return "", false
+	}
+	return token, true
+}
+
+// Hash returns the hash of key to store.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches reports whether key hashes to hash, in constant time.
+func Matches(key string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(key)), []byte(hash)) == 1
+}
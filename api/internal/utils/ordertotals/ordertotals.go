@@ -1,164 +1,302 @@
 // Package ordertotals provides utilities to calculate discounts, taxes,
 // and totals for orders and their items.
+//
+// Amounts are exact decimals; every rate or percentage applied is rounded
+// half up to the cent.
 package ordertotals
 
 import (
-	"math"
-
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 )
 
-// round2 rounds a float64 value to 2 decimal places (e.g., cents).
-func round2(val float64) float64 {
-	return math.Round(val*100) / 100
+// lineSubtotal returns the item's unit price times its quantity.
+func lineSubtotal(item *model.OrderItem) money.Money {
+	return item.UnitPrice.Mul(item.Quantity)
 }
 
 // calculateItemDiscount calculates the discount applied to a single order item.
 // The discount is determined by the item's discount type (percentage or fixed amount).
 // The discount cannot exceed the item's subtotal.
-func calculateItemDiscount(item *model.OrderItem) float64 {
-	subtotal := item.UnitPrice * float64(item.Quantity)
-	var discount float64
+func calculateItemDiscount(item *model.OrderItem) money.Money {
+	subtotal := lineSubtotal(item)
+	var discount money.Money
 
 	switch item.Discount.Type {
 	case model.DiscountPercentage:
-		discount = subtotal * (item.Discount.Amount / 100)
+		discount = subtotal.Percent(item.Discount.Amount, money.HalfUp)
 	case model.DiscountFixed:
 		discount = item.Discount.Amount
 	default:
-		discount = 0
+		discount = money.Zero
 	}
 
 	// Ensure discount does not exceed subtotal
-	if discount > subtotal {
-		discount = subtotal
+	return money.Min(discount, subtotal)
+}
+
+// calculatePromotionDiscount sets what the order promotion takes off each
+// promoted item, after the item's own discount, and returns the sum.
+// Percentages and fixed amounts are shared between the promoted items in
+// proportion to their value; buy X get Y makes units of each promoted item
+// free. Orders below the minimum order value get nothing.
+func calculatePromotionDiscount(order *model.Order) money.Money {
+	for i := range order.Items {
+		order.Items[i].AppliedPromotionDiscount = money.Zero
+	}
+	promotion := order.Promotion
+	if order.PromotionID == nil || order.Subtotal.LessThan(promotion.MinOrderValue) {
+		return money.Zero
+	}
+
+	// What is left of each promoted item after its own discount
+	weights := make([]money.Money, len(order.Items))
+	var base money.Money
+	for i := range order.Items {
+		item := &order.Items[i]
+		if item.Promoted {
+			weights[i] = money.Max(lineSubtotal(item).Sub(item.AppliedDiscount), money.Zero)
+			base = base.Add(weights[i])
+		}
+	}
+
+	var discount money.Money
+	switch promotion.Type {
+	case model.PromotionPercentage:
+		discount = base.Percent(promotion.Amount, money.HalfUp)
+	case model.PromotionFixed:
+		discount = money.Min(promotion.Amount, base)
+	case model.PromotionBuyXGetY:
+		if promotion.BuyQuantity <= 0 || promotion.GetQuantity <= 0 {
+			return money.Zero
+		}
+		set := promotion.BuyQuantity + promotion.GetQuantity
+		for i := range order.Items {
+			item := &order.Items[i]
+			if !item.Promoted {
+				continue
+			}
+			free := item.Quantity / set * promotion.GetQuantity
+			item.AppliedPromotionDiscount = money.Min(item.UnitPrice.Mul(free), weights[i])
+			discount = discount.Add(item.AppliedPromotionDiscount)
+		}
+		return discount
+	default:
+		return money.Zero
 	}
 
-	return round2(discount)
+	for i, share := range discount.Allocate(weights) {
+		order.Items[i].AppliedPromotionDiscount = share
+	}
+	return discount
 }
 
 // calculateOrderDiscount calculates the total discount applied at the order level.
 // The discount is based on the order's discount type (percentage or fixed amount).
-// The maximum discount is capped so the combined item- and order-level discounts
-// cannot exceed the subtotal.
-func calculateOrderDiscount(order *model.Order) float64 {
+// The maximum discount is capped so the combined item, promotion and
+// order-level discounts cannot exceed the subtotal.
+func calculateOrderDiscount(order *model.Order) money.Money {
 	subtotal := order.Subtotal
-	itemDiscountTotal := order.ItemDiscountTotal
-	var discount float64
+	itemDiscountTotal := order.ItemDiscountTotal.Add(order.PromotionDiscount)
+	var discount money.Money
 
 	switch order.Discount.Type {
 	case model.DiscountPercentage:
-		discount = subtotal * (order.Discount.Amount / 100)
+		discount = subtotal.Percent(order.Discount.Amount, money.HalfUp)
 	case model.DiscountFixed:
 		discount = order.Discount.Amount
 	default:
-		discount = 0
+		discount = money.Zero
 	}
 
 	// Prevent total discounts from exceeding subtotal
-	maxDiscount := subtotal - itemDiscountTotal
-	if discount > maxDiscount {
-		discount = maxDiscount
-	}
-
-	return round2(discount)
+	return money.Min(discount, subtotal.Sub(itemDiscountTotal))
 }
 
 // applyOrderDiscountToItems distributes the total order-level discount proportionally
-// to all order items based on their share of the subtotal.
-// The last item gets any rounding adjustment to ensure totals match exactly.
+// to all order items based on what is left of them after their item and
+// promotion discounts.
+// The shares are allocated in whole cents so they add up to the discount exactly.
 func applyOrderDiscountToItems(order *model.Order) {
-	subtotal := order.Subtotal
-	if order.AppliedDiscount <= 0 || subtotal <= 0 {
+	for i := range order.Items {
+		order.Items[i].AppliedOrderDiscount = money.Zero
+	}
+	if !order.AppliedDiscount.IsPositive() || !order.Subtotal.IsPositive() {
 		return
 	}
 
-	remaining := order.AppliedDiscount
-
+	weights := make([]money.Money, len(order.Items))
 	for i := range order.Items {
 		item := &order.Items[i]
-		lineSubtotal := item.UnitPrice * float64(item.Quantity)
+		weights[i] = money.Max(lineSubtotal(item).Sub(item.AppliedDiscount).Sub(item.AppliedPromotionDiscount), money.Zero)
+	}
 
-		// Proportionally allocate discount
-		applied := (lineSubtotal / subtotal) * order.AppliedDiscount
+	for i, share := range order.AppliedDiscount.Allocate(weights) {
+		order.Items[i].AppliedOrderDiscount = share
+	}
+}
 
-		// Round and adjust remaining for the last item
-		if i == len(order.Items)-1 {
-			applied = remaining
-		} else {
-			applied = round2(applied)
-			remaining -= applied
+// taxLines returns the taxes charged on an item. Items without a breakdown
+// are charged their TaxRate as a single tax.
+func taxLines(item *model.OrderItem) []model.TaxLine {
+	if len(item.Taxes) > 0 {
+		return item.Taxes
+	}
+	if item.TaxRate > 0 {
+		return []model.TaxLine{{Name: "Tax", Rate: item.TaxRate}}
+	}
+	return nil
+}
+
+// calculateTaxes charges taxes on net in order: a compound tax on net plus
+// the taxes before it, any other tax on net alone. It sets the amount of
+// each tax and returns their sum.
+func calculateTaxes(taxes []model.TaxLine, net money.Money) money.Money {
+	var total money.Money
+	for i := range taxes {
+		base := net
+		if taxes[i].Compound {
+			base = net.Add(total)
 		}
+		taxes[i].Amount = base.MulRate(taxes[i].Rate, money.HalfUp)
+		total = total.Add(taxes[i].Amount)
+	}
+	return total
+}
 
-		item.AppliedOrderDiscount = applied
+// CombinedRate returns the rate taxes add up to, compounding included. It
+// is what a net amount is multiplied by to get the tax on it.
+func CombinedRate(taxes []model.TaxLine) float64 {
+	var rate float64
+	for _, tax := range taxes {
+		if tax.Compound {
+			rate += (1 + rate) * tax.Rate
+		} else {
+			rate += tax.Rate
+		}
 	}
+	return rate
 }
 
 // calculateTaxAndLineTotal recalculates the tax amount and total line cost for an item.
 // Tax is applied on the item's taxable amount (price - discounts).
-func calculateTaxAndLineTotal(item *model.OrderItem) {
+func calculateTaxAndLineTotal(item *model.OrderItem, pricesIncludeTax bool, taxExempt bool) {
 	// Compute taxable base
-	taxable := item.UnitPrice*float64(item.Quantity) - item.AppliedDiscount - item.AppliedOrderDiscount
-	if taxable < 0 {
-		taxable = 0
+	taxable := lineSubtotal(item).Sub(item.AppliedDiscount).Sub(item.AppliedPromotionDiscount).Sub(item.AppliedOrderDiscount)
+	taxable = money.Max(taxable, money.Zero)
+
+	taxes := taxLines(item)
+	if taxExempt {
+		taxes = nil
 	}
+	item.Taxes, item.TaxAmount, item.Total = applyTaxes(taxable, taxes, pricesIncludeTax)
+}
+
+// calculateCharges recalculates the tax and total of each charge of an
+// order and returns the sum of their amounts, taxes and totals. Charges
+// are never discounted.
+func calculateCharges(order *model.Order) (amount money.Money, tax money.Money, total money.Money) {
+	var transportFare money.Money
+	for i := range order.Charges {
+		charge := &order.Charges[i]
+		taxes := charge.Taxes
+		if order.TaxExempt {
+			taxes = nil
+		}
+		charge.Taxes, charge.TaxAmount, charge.Total = applyTaxes(money.Max(charge.Amount, money.Zero), taxes, order.PricesIncludeTax)
 
-	// Calculate tax and total
-	item.TaxAmount = round2(taxable * item.TaxRate)
-	item.Total = round2(taxable + item.TaxAmount)
+		amount = amount.Add(charge.Amount)
+		tax = tax.Add(charge.TaxAmount)
+		total = total.Add(charge.Total)
+		if charge.Type == model.ChargeDelivery {
+			transportFare = transportFare.Add(charge.Amount)
+		}
+	}
+	order.Delivery.TransportFare = transportFare
+	return amount, tax, total
+}
+
+// applyTaxes charges taxes on taxable and returns them with their amounts,
+// the tax charged and the total. When prices include tax, the taxable amount
+// is gross: the net amount is taken out of it and the taxes make up the
+// difference, to the cent.
+func applyTaxes(taxable money.Money, taxes []model.TaxLine, pricesIncludeTax bool) ([]model.TaxLine, money.Money, money.Money) {
+	if len(taxes) == 0 {
+		return nil, money.Zero, taxable
+	}
+
+	if !pricesIncludeTax {
+		tax := calculateTaxes(taxes, taxable)
+		return taxes, tax, taxable.Add(tax)
+	}
+
+	net := taxable.DivRate(1+CombinedRate(taxes), money.HalfUp)
+	tax := calculateTaxes(taxes, net)
+
+	// Rounding each tax may leave a cent or two over; the last tax charged
+	// takes it
+	if diff := taxable.Sub(net).Sub(tax); !diff.IsZero() {
+		for i := len(taxes) - 1; i >= 0; i-- {
+			if taxes[i].Rate > 0 {
+				taxes[i].Amount = taxes[i].Amount.Add(diff)
+				tax = tax.Add(diff)
+				break
+			}
+		}
+	}
+
+	return taxes, tax, taxable
 }
 
 // Calculate recalculates all financial fields of an order, including:
 // - Item-level discounts
+// - Promotion discount
 // - Order-level discount
-// - Tax amounts
+// - Tax amounts, by tax on each item and charge
+// - Charges
 // - Final totals
 // It updates the order in place.
 func Calculate(order *model.Order) {
-	// Handle empty orders
-	if len(order.Items) == 0 {
-		order.Subtotal = 0
-		order.ItemDiscountTotal = 0
-		order.AppliedDiscount = 0
-		order.DiscountTotal = 0
-		order.TaxTotal = 0
-		order.Total = 0
-		return
-	}
-
-	var subtotal float64
-	var itemDiscountTotal float64
+	var subtotal money.Money
+	var itemDiscountTotal money.Money
 
 	// Step 1: calculate per-item discounts and subtotal
 	for i := range order.Items {
 		item := &order.Items[i]
 		item.AppliedDiscount = calculateItemDiscount(item)
-		subtotal += item.UnitPrice * float64(item.Quantity)
-		itemDiscountTotal += item.AppliedDiscount
+		subtotal = subtotal.Add(lineSubtotal(item))
+		itemDiscountTotal = itemDiscountTotal.Add(item.AppliedDiscount)
 	}
 
-	order.Subtotal = round2(subtotal)
-	order.ItemDiscountTotal = round2(itemDiscountTotal)
+	order.Subtotal = subtotal
+	order.ItemDiscountTotal = itemDiscountTotal
+
+	// Step 2: calculate the promotion discount of promoted items
+	order.PromotionDiscount = calculatePromotionDiscount(order)
 
-	// Step 2: calculate total order-level discount
+	// Step 3: calculate total order-level discount
 	order.AppliedDiscount = calculateOrderDiscount(order)
-	order.DiscountTotal = round2(order.ItemDiscountTotal + order.AppliedDiscount)
+	order.DiscountTotal = money.Sum(order.ItemDiscountTotal, order.PromotionDiscount, order.AppliedDiscount)
 
-	// Step 3: allocate order-level discount proportionally
+	// Step 4: allocate order-level discount proportionally
 	applyOrderDiscountToItems(order)
 
-	// Step 4: calculate tax and totals for each item
+	// Step 5: calculate tax and totals for each item
 	for i := range order.Items {
-		calculateTaxAndLineTotal(&order.Items[i])
+		calculateTaxAndLineTotal(&order.Items[i], order.PricesIncludeTax, order.TaxExempt)
 	}
 
-	// Step 5: aggregate tax total and final order total
-	var taxTotal, totalAmount float64
+	// Step 6: calculate tax and totals for each charge
+	chargeTotal, chargeTax, chargesTotal := calculateCharges(order)
+	order.ChargeTotal = chargeTotal
+
+	// Step 7: aggregate tax total and final order total
+	taxTotal, totalAmount := chargeTax, chargesTotal
 	for _, item := range order.Items {
-		taxTotal += item.TaxAmount
-		totalAmount += item.Total
+		taxTotal = taxTotal.Add(item.TaxAmount)
+		totalAmount = totalAmount.Add(item.Total)
 	}
 
-	order.TaxTotal = round2(taxTotal)
-	order.Total = round2(totalAmount)
+	order.TaxTotal = taxTotal
+	order.Total = totalAmount
 }
@@ -4,69 +4,70 @@ import (
 	"testing"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestCalculateOrderTotals_NoDiscounts(t *testing.T) {
 	order := &model.Order{
 		Items: []model.OrderItem{
-			{UnitPrice: 100, Quantity: 1, TaxRate: 0.1},
-			{UnitPrice: 50, Quantity: 2, TaxRate: 0.05},
+			{UnitPrice: money.FromInt(100), Quantity: 1, TaxRate: 0.1},
+			{UnitPrice: money.FromInt(50), Quantity: 2, TaxRate: 0.05},
 		},
 	}
 
 	Calculate(order)
 
 	// Subtotal
-	assert.Equal(t, 200.0, order.Subtotal) // 100*1 + 50*2
+	assert.Equal(t, money.FromInt(200), order.Subtotal) // 100*1 + 50*2
 
 	// No discounts
-	assert.Equal(t, 0.0, order.ItemDiscountTotal)
-	assert.Equal(t, 0.0, order.AppliedDiscount)
-	assert.Equal(t, 0.0, order.DiscountTotal)
+	assert.Equal(t, money.Zero, order.ItemDiscountTotal)
+	assert.Equal(t, money.Zero, order.AppliedDiscount)
+	assert.Equal(t, money.Zero, order.DiscountTotal)
 
 	// Tax
 	//expectedTax := round2(100*0.1 + 100*0.05)
 	// 10 + 5 = 15
-	assert.Equal(t, 15.0, order.TaxTotal)
+	assert.Equal(t, money.FromInt(15), order.TaxTotal)
 
 	// Total
 	// expectedTotal := 200.0 + 15.0
-	assert.Equal(t, 215.0, order.Total)
+	assert.Equal(t, money.FromInt(215), order.Total)
 }
 
 func TestCalculateOrderTotals_PercentageOrderDiscount(t *testing.T) {
 	order := &model.Order{
 		Items: []model.OrderItem{
-			{UnitPrice: 100, Quantity: 2, TaxRate: 0.1}, // 200
-			{UnitPrice: 50, Quantity: 1, TaxRate: 0.05}, // 50
+			{UnitPrice: money.FromInt(100), Quantity: 2, TaxRate: 0.1}, // 200
+			{UnitPrice: money.FromInt(50), Quantity: 1, TaxRate: 0.05}, // 50
 		},
 		Discount: model.DiscountInfo{
 			Type:   model.DiscountPercentage,
-			Amount: 10, // 10% of subtotal
+			Amount: money.FromInt(10), // 10% of subtotal
 		},
 	}
 
 	Calculate(order)
 
 	// Subtotal
-	assert.Equal(t, 250.0, order.Subtotal)
+	assert.Equal(t, money.FromInt(250), order.Subtotal)
 
 	// Order-level discount 10% of 250 = 25
-	assert.Equal(t, 25.0, order.AppliedDiscount)
-	assert.Equal(t, 25.0, order.DiscountTotal)    // no item discounts
-	assert.Equal(t, 0.0, order.ItemDiscountTotal) // no item discounts
+	assert.Equal(t, money.FromInt(25), order.AppliedDiscount)
+	assert.Equal(t, money.FromInt(25), order.DiscountTotal) // no item discounts
+	assert.Equal(t, money.Zero, order.ItemDiscountTotal)    // no item discounts
 
 	// Proportional applied order discount
-	assert.Equal(t, 20.0, order.Items[0].AppliedOrderDiscount) // 200/250 * 25
-	assert.Equal(t, 5.0, order.Items[1].AppliedOrderDiscount)  // 50/250 * 25
+	assert.Equal(t, money.FromInt(20), order.Items[0].AppliedOrderDiscount) // 200/250 * 25
+	assert.Equal(t, money.FromInt(5), order.Items[1].AppliedOrderDiscount)  // 50/250 * 25
 
 	// Tax
-	expectedTax := round2(order.Items[0].TaxAmount + order.Items[1].TaxAmount)
+	expectedTax := order.Items[0].TaxAmount.Add(order.Items[1].TaxAmount)
 	assert.Equal(t, expectedTax, order.TaxTotal)
 
 	// Total
-	expectedTotal := round2(order.Items[0].Total + order.Items[1].Total)
+	expectedTotal := order.Items[0].Total.Add(order.Items[1].Total)
 	assert.Equal(t, expectedTotal, order.Total)
 }
 
@@ -75,12 +76,12 @@ func TestCalculateOrderTotals_ZeroItems(t *testing.T) {
 
 	Calculate(order)
 
-	assert.Equal(t, 0.0, order.Subtotal)
-	assert.Equal(t, 0.0, order.ItemDiscountTotal)
-	assert.Equal(t, 0.0, order.AppliedDiscount)
-	assert.Equal(t, 0.0, order.DiscountTotal)
-	assert.Equal(t, 0.0, order.TaxTotal)
-	assert.Equal(t, 0.0, order.Total)
+	assert.Equal(t, money.Zero, order.Subtotal)
+	assert.Equal(t, money.Zero, order.ItemDiscountTotal)
+	assert.Equal(t, money.Zero, order.AppliedDiscount)
+	assert.Equal(t, money.Zero, order.DiscountTotal)
+	assert.Equal(t, money.Zero, order.TaxTotal)
+	assert.Equal(t, money.Zero, order.Total)
 	assert.Empty(t, order.Items)
 }
 
@@ -88,53 +89,268 @@ func TestCalculateOrderTotals_ItemAndOrderDiscounts(t *testing.T) {
 	order := &model.Order{
 		Items: []model.OrderItem{
 			{
-				UnitPrice: 200,
+				UnitPrice: money.FromInt(200),
 				Quantity:  1,
 				TaxRate:   0.1,
 				Discount: model.DiscountInfo{
 					Type:   model.DiscountFixed,
-					Amount: 20,
+					Amount: money.FromInt(20),
 				},
 			},
 			{
-				UnitPrice: 100,
+				UnitPrice: money.FromInt(100),
 				Quantity:  1,
 				TaxRate:   0.05,
 				Discount: model.DiscountInfo{
 					Type:   model.DiscountPercentage,
-					Amount: 10,
+					Amount: money.FromInt(10),
 				},
 			},
 		},
 		Discount: model.DiscountInfo{
 			Type:   model.DiscountFixed,
-			Amount: 30,
+			Amount: money.FromInt(30),
 		},
 	}
 
 	Calculate(order)
 
 	// Check subtotal
-	assert.Equal(t, 300.0, order.Subtotal)
+	assert.Equal(t, money.FromInt(300), order.Subtotal)
 
 	// Item discounts
-	assert.Equal(t, 20.0, order.Items[0].AppliedDiscount) // fixed
-	assert.Equal(t, 10.0, order.Items[1].AppliedDiscount) // 10% of 100
+	assert.Equal(t, money.FromInt(20), order.Items[0].AppliedDiscount) // fixed
+	assert.Equal(t, money.FromInt(10), order.Items[1].AppliedDiscount) // 10% of 100
 
 	// Order discount
-	assert.Equal(t, 30.0, order.AppliedDiscount)
+	assert.Equal(t, money.FromInt(30), order.AppliedDiscount)
 
 	// AppliedOrderDiscount distribution
-	assert.Equal(t, 20.0, order.Items[0].AppliedOrderDiscount) // 200/300 * 30
-	assert.Equal(t, 10.0, order.Items[1].AppliedOrderDiscount) // 100/300 * 30
+	assert.Equal(t, money.FromInt(20), order.Items[0].AppliedOrderDiscount) // 200/300 * 30
+	assert.Equal(t, money.FromInt(10), order.Items[1].AppliedOrderDiscount) // 100/300 * 30
 
 	// Tax
-	assert.Equal(t, order.Items[0].TaxAmount+order.Items[1].TaxAmount, order.TaxTotal)
+	assert.Equal(t, order.Items[0].TaxAmount.Add(order.Items[1].TaxAmount), order.TaxTotal)
 
 	// Total
-	assert.Equal(t, order.Items[0].Total+order.Items[1].Total, order.Total)
+	assert.Equal(t, order.Items[0].Total.Add(order.Items[1].Total), order.Total)
 
 	// DiscountTotal
-	expectedTotatDiscount := order.AppliedDiscount + order.Items[0].AppliedDiscount + order.Items[1].AppliedDiscount
+	expectedTotatDiscount := money.Sum(order.AppliedDiscount, order.Items[0].AppliedDiscount, order.Items[1].AppliedDiscount)
 	assert.Equal(t, expectedTotatDiscount, order.DiscountTotal) // 20 + 10 + 30
 }
+
+func TestCalculateOrderTotals_RoundsHalfUp(t *testing.T) {
+	order := &model.Order{
+		Items: []model.OrderItem{
+			// 7.5% of 19.99 is 1.49925
+			{UnitPrice: money.MustParse("19.99"), Quantity: 1, TaxRate: 0.075},
+		},
+	}
+
+	Calculate(order)
+
+	assert.Equal(t, money.MustParse("1.50"), order.TaxTotal)
+	assert.Equal(t, money.MustParse("21.49"), order.Total)
+}
+
+func TestCalculateOrderTotals_OrderDiscountSharesAddUp(t *testing.T) {
+	order := &model.Order{
+		Items: []model.OrderItem{
+			{UnitPrice: money.FromInt(1), Quantity: 1},
+			{UnitPrice: money.FromInt(1), Quantity: 1},
+			{UnitPrice: money.FromInt(1), Quantity: 1},
+		},
+		Discount: model.DiscountInfo{
+			Type:   model.DiscountFixed,
+			Amount: money.MustParse("0.10"),
+		},
+	}
+
+	Calculate(order)
+
+	assert.Equal(t, money.MustParse("0.04"), order.Items[0].AppliedOrderDiscount)
+	assert.Equal(t, money.MustParse("0.03"), order.Items[1].AppliedOrderDiscount)
+	assert.Equal(t, money.MustParse("0.03"), order.Items[2].AppliedOrderDiscount)
+	assert.Equal(t, money.MustParse("2.90"), order.Total)
+}
+
+func TestCalculateOrderTotals_TaxBreakdown(t *testing.T) {
+	order := &model.Order{
+		Items: []model.OrderItem{
+			{UnitPrice: money.FromInt(100), Quantity: 1, Taxes: []model.TaxLine{
+				{Name: "VAT", Rate: 0.075},
+				{Name: "Levy", Rate: 0.02},
+			}},
+			{UnitPrice: money.FromInt(100), Quantity: 1, Taxes: []model.TaxLine{
+				{Name: "VAT", Rate: 0.075},
+				{Name: "Levy", Rate: 0.02, Compound: true},
+			}},
+			{UnitPrice: money.FromInt(100), Quantity: 1, TaxRate: 0.1},
+		},
+	}
+
+	Calculate(order)
+
+	// Stacked taxes are each charged on the price
+	assert.Equal(t, money.MustParse("7.50"), order.Items[0].Taxes[0].Amount)
+	assert.Equal(t, money.MustParse("2.00"), order.Items[0].Taxes[1].Amount)
+	assert.Equal(t, money.MustParse("109.50"), order.Items[0].Total)
+
+	// A compound tax is charged on the price plus VAT, 2% of 107.50
+	assert.Equal(t, money.MustParse("2.15"), order.Items[1].Taxes[1].Amount)
+	assert.Equal(t, money.MustParse("9.65"), order.Items[1].TaxAmount)
+
+	// A bare tax rate is charged as a single tax
+	assert.Equal(t, []model.TaxLine{{Name: "Tax", Rate: 0.1, Amount: money.FromInt(10)}}, order.Items[2].Taxes)
+
+	assert.Equal(t, money.MustParse("29.15"), order.TaxTotal)
+	assert.Equal(t, money.MustParse("329.15"), order.Total)
+}
+
+func TestCalculateOrderTotals_PricesIncludeTax(t *testing.T) {
+	order := &model.Order{
+		PricesIncludeTax: true,
+		Items: []model.OrderItem{
+			{UnitPrice: money.MustParse("107.50"), Quantity: 1, Taxes: []model.TaxLine{{Name: "VAT", Rate: 0.075}}},
+			// 1.00 is 0.87 net, and three 5% taxes of 0.04 leave a cent over
+			{UnitPrice: money.FromInt(1), Quantity: 1, Taxes: []model.TaxLine{
+				{Name: "A", Rate: 0.05},
+				{Name: "B", Rate: 0.05},
+				{Name: "C", Rate: 0.05},
+			}},
+		},
+	}
+
+	Calculate(order)
+
+	assert.Equal(t, money.MustParse("7.50"), order.Items[0].TaxAmount)
+	assert.Equal(t, money.MustParse("107.50"), order.Items[0].Total)
+
+	assert.Equal(t, money.MustParse("0.04"), order.Items[1].Taxes[0].Amount)
+	assert.Equal(t, money.MustParse("0.04"), order.Items[1].Taxes[1].Amount)
+	assert.Equal(t, money.MustParse("0.05"), order.Items[1].Taxes[2].Amount)
+	assert.Equal(t, money.MustParse("0.13"), order.Items[1].TaxAmount)
+	assert.Equal(t, money.FromInt(1), order.Items[1].Total)
+
+	assert.Equal(t, money.MustParse("108.50"), order.Subtotal)
+	assert.Equal(t, money.MustParse("108.50"), order.Total)
+	assert.Equal(t, money.MustParse("7.63"), order.TaxTotal)
+}
+
+func TestCalculateOrderTotals_TaxExempt(t *testing.T) {
+	order := &model.Order{
+		TaxExempt: true,
+		Items: []model.OrderItem{
+			{UnitPrice: money.FromInt(100), Quantity: 1, TaxRate: 0.1, Taxes: []model.TaxLine{{Name: "VAT", Rate: 0.1}}},
+		},
+	}
+
+	Calculate(order)
+
+	assert.Empty(t, order.Items[0].Taxes)
+	assert.Equal(t, money.Zero, order.TaxTotal)
+	assert.Equal(t, money.FromInt(100), order.Total)
+}
+
+func TestCalculateOrderTotals_Charges(t *testing.T) {
+	order := &model.Order{
+		Discount: model.DiscountInfo{Type: model.DiscountPercentage, Amount: money.FromInt(10)},
+		Items: []model.OrderItem{
+			{UnitPrice: money.FromInt(100), Quantity: 1, Taxes: []model.TaxLine{{Name: "VAT", Rate: 0.1}}},
+		},
+		Charges: []model.OrderCharge{
+			{Type: model.ChargeDelivery, Amount: money.FromInt(15), Taxes: []model.TaxLine{{Name: "VAT", Rate: 0.1}}},
+			{Type: model.ChargeHandling, Amount: money.FromInt(5)},
+		},
+	}
+
+	Calculate(order)
+
+	// Charges are not discounted
+	assert.Equal(t, money.FromInt(10), order.DiscountTotal)
+	assert.Equal(t, money.MustParse("1.50"), order.Charges[0].TaxAmount)
+	assert.Equal(t, money.MustParse("16.50"), order.Charges[0].Total)
+	assert.Equal(t, money.Zero, order.Charges[1].TaxAmount)
+	assert.Equal(t, money.FromInt(5), order.Charges[1].Total)
+
+	assert.Equal(t, money.FromInt(100), order.Subtotal)
+	assert.Equal(t, money.FromInt(20), order.ChargeTotal)
+	assert.Equal(t, money.FromInt(15), order.Delivery.TransportFare)
+	assert.Equal(t, money.MustParse("10.50"), order.TaxTotal)
+	assert.Equal(t, money.MustParse("120.50"), order.Total)
+
+	order.PricesIncludeTax = true
+	Calculate(order)
+
+	assert.Equal(t, money.MustParse("1.36"), order.Charges[0].TaxAmount)
+	assert.Equal(t, money.FromInt(15), order.Charges[0].Total)
+	assert.Equal(t, money.FromInt(110), order.Total)
+
+	order.TaxExempt = true
+	Calculate(order)
+
+	assert.Empty(t, order.Charges[0].Taxes)
+	assert.Equal(t, money.FromInt(15), order.Charges[0].Total)
+}
+
+func TestCalculateOrderTotals_Promotions(t *testing.T) {
+	promotionID := uint(1)
+	items := func() []model.OrderItem {
+		// 50 and 50 promoted after the item discount, 100 not promoted
+		return []model.OrderItem{
+			{UnitPrice: money.FromInt(10), Quantity: 7, Promoted: true, Discount: model.DiscountInfo{Type: model.DiscountFixed, Amount: money.FromInt(20)}},
+			{UnitPrice: money.FromInt(25), Quantity: 2, Promoted: true},
+			{UnitPrice: money.FromInt(100), Quantity: 1},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		rule   model.PromotionRule
+		shares []string
+	}{
+		{"percentage of promoted items", model.PromotionRule{Type: model.PromotionPercentage, Amount: money.FromInt(10)}, []string{"5.00", "5.00", "0.00"}},
+		{"fixed shared by value", model.PromotionRule{Type: model.PromotionFixed, Amount: money.MustParse("0.05")}, []string{"0.03", "0.02", "0.00"}},
+		{"fixed capped at promoted items", model.PromotionRule{Type: model.PromotionFixed, Amount: money.FromInt(500)}, []string{"50.00", "50.00", "0.00"}},
+		{"buy 2 get 1 free", model.PromotionRule{Type: model.PromotionBuyXGetY, BuyQuantity: 2, GetQuantity: 1}, []string{"20.00", "0.00", "0.00"}},
+		{"below minimum order value", model.PromotionRule{Type: model.PromotionPercentage, Amount: money.FromInt(10), MinOrderValue: money.FromInt(500)}, []string{"0.00", "0.00", "0.00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &model.Order{PromotionID: &promotionID, Promotion: tt.rule, Items: items()}
+			Calculate(order)
+
+			var shares []string
+			var sum money.Money
+			for _, item := range order.Items {
+				shares = append(shares, item.AppliedPromotionDiscount.String())
+				sum = sum.Add(item.AppliedPromotionDiscount)
+			}
+			assert.Equal(t, tt.shares, shares)
+			assert.Equal(t, sum, order.PromotionDiscount)
+			assert.Equal(t, money.Sum(order.ItemDiscountTotal, order.PromotionDiscount), order.DiscountTotal)
+			assert.Equal(t, money.FromInt(220).Sub(order.DiscountTotal), order.Total)
+		})
+	}
+
+	t.Run("order discount capped after the promotion", func(t *testing.T) {
+		order := &model.Order{
+			PromotionID: &promotionID,
+			Promotion:   model.PromotionRule{Type: model.PromotionFixed, Amount: money.FromInt(100)},
+			Discount:    model.DiscountInfo{Type: model.DiscountFixed, Amount: money.FromInt(500)},
+			Items:       items(),
+		}
+		Calculate(order)
+		assert.Equal(t, money.FromInt(100), order.PromotionDiscount)
+		assert.Equal(t, money.FromInt(100), order.AppliedDiscount)
+		assert.Equal(t, money.Zero, order.Total)
+	})
+}
+
+func TestCombinedRate(t *testing.T) {
+	assert.InDelta(t, 0.095, CombinedRate([]model.TaxLine{{Rate: 0.075}, {Rate: 0.02}}), 1e-9)
+	assert.InDelta(t, 0.0965, CombinedRate([]model.TaxLine{{Rate: 0.075}, {Rate: 0.02, Compound: true}}), 1e-9)
+	assert.Zero(t, CombinedRate(nil))
+}
@@ -0,0 +1,69 @@
+package pdfutil
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/SebastiaanKlippert/go-wkhtmltopdf"
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/templates"
+)
+
+type CreditNoteViewData struct {
+	Number        string
+	InvoiceNumber string
+	Date          string
+	CustomerName  string
+	Reason        string
+	Items         []model.CreditNoteItem
+	Currency      string
+	Subtotal      money.Money
+	TaxTotal      money.Money
+	Total         money.Money
+}
+
+// GenerateCreditNotePDF renders a credit note. creditNote must be loaded with
+// its items and invoice.
+func GenerateCreditNotePDF(creditNote *model.CreditNote) ([]byte, error) {
+	tmpl, err := template.New("creditnote.html").ParseFS(templates.CreditNoteFS, templates.CreditNotePath)
+	if err != nil {
+		return nil, err
+	}
+
+	data := CreditNoteViewData{
+		Number:   creditNote.CreditNoteNumber,
+		Date:     creditNote.IssuedAt.Format("02 Jan 2006"),
+		Reason:   creditNote.Reason,
+		Items:    creditNote.Items,
+		Currency: creditNote.Currency,
+		Subtotal: creditNote.Subtotal,
+		TaxTotal: creditNote.TaxTotal,
+		Total:    creditNote.Total,
+	}
+	if creditNote.Invoice != nil {
+		data.InvoiceNumber = creditNote.Invoice.InvoiceNumber
+		data.CustomerName = creditNote.Invoice.CustomerName
+	}
+
+	var htmlBuf bytes.Buffer
+	if err := tmpl.Execute(&htmlBuf, data); err != nil {
+		return nil, err
+	}
+
+	pdfg, err := wkhtmltopdf.NewPDFGenerator()
+	if err != nil {
+		return nil, err
+	}
+
+	pdfg.AddPage(wkhtmltopdf.NewPageReader(bytes.NewReader(htmlBuf.Bytes())))
+	pdfg.Dpi.Set(300)
+	pdfg.Orientation.Set(wkhtmltopdf.OrientationPortrait)
+	pdfg.PageSize.Set(wkhtmltopdf.PageSizeA4)
+
+	if err := pdfg.Create(); err != nil {
+		return nil, err
+	}
+
+	return pdfg.Bytes(), nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/SebastiaanKlippert/go-wkhtmltopdf"
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/deveasyclick/openb2b/internal/templates"
 )
 
@@ -15,16 +16,24 @@ type InvoiceViewData struct {
 	Date          string
 	CustomerName  string
 	Items         []model.InvoiceItem
-	Total         float64
+	Charges       []model.InvoiceCharge
+	Currency      string
+	Total         money.Money
 	ProForma      bool
-	Subtotal      float64
-	TaxTotal      float64
-	DiscountTotal float64
+	Subtotal      money.Money
+	TaxTotal      money.Money
+	DiscountTotal money.Money
+	ChargeTotal   money.Money
+
+	// Taxes is TaxTotal by tax
+	Taxes            []model.TaxLine
+	PricesIncludeTax bool
+	TaxExempt        bool
 }
 
 func GenerateInvoicePDF(invoice *model.Invoice, proForma bool) ([]byte, error) {
 	// Parse template
-	tmpl, err := template.New("invoice.html").Funcs(funcMap).ParseFS(templates.InvoiceFS, templates.InvoicePath)
+	tmpl, err := template.New("invoice.html").ParseFS(templates.InvoiceFS, templates.InvoicePath)
 	if err != nil {
 		return nil, err
 	}
@@ -44,11 +53,18 @@ func GenerateInvoicePDF(invoice *model.Invoice, proForma bool) ([]byte, error) {
 		Date:          time.Now().Format("02 Jan 2006"),
 		CustomerName:  customerName,
 		Items:         items,
+		Charges:       invoice.Charges,
+		Currency:      invoice.Currency,
 		Total:         invoice.Total,
 		ProForma:      proForma,
 		Subtotal:      invoice.Subtotal,
 		TaxTotal:      invoice.TaxTotal,
 		DiscountTotal: invoice.DiscountTotal,
+		ChargeTotal:   invoice.ChargeTotal,
+
+		Taxes:            sumTaxes(items, invoice.Charges),
+		PricesIncludeTax: invoice.PricesIncludeTax,
+		TaxExempt:        invoice.TaxExempt,
 	}
 
 	// Render HTML
@@ -75,3 +91,31 @@ func GenerateInvoicePDF(invoice *model.Invoice, proForma bool) ([]byte, error) {
 
 	return pdfg.Bytes(), nil
 }
+
+// sumTaxes adds up the taxes of items and charges by tax and rate, in the
+// order they first appear.
+func sumTaxes(items []model.InvoiceItem, charges []model.InvoiceCharge) []model.TaxLine {
+	lines := make([][]model.TaxLine, 0, len(items)+len(charges))
+	for _, item := range items {
+		lines = append(lines, item.Taxes)
+	}
+	for _, charge := range charges {
+		lines = append(lines, charge.Taxes)
+	}
+
+	var taxes []model.TaxLine
+	index := map[model.TaxLine]int{}
+	for _, lineTaxes := range lines {
+		for _, tax := range lineTaxes {
+			key := model.TaxLine{Name: tax.Name, Rate: tax.Rate, Compound: tax.Compound}
+			i, ok := index[key]
+			if !ok {
+				i = len(taxes)
+				index[key] = i
+				taxes = append(taxes, key)
+			}
+			taxes[i].Amount = taxes[i].Amount.Add(tax.Amount)
+		}
+	}
+	return taxes
+}
@@ -0,0 +1,73 @@
+package pdfutil
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/SebastiaanKlippert/go-wkhtmltopdf"
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/templates"
+)
+
+type PurchaseOrderViewData struct {
+	Number       string
+	Date         string
+	ExpectedDate string
+	SupplierName string
+	ContactName  string
+	Notes        string
+	Lines        []model.PurchaseOrderLine
+	Currency     string
+	Total        money.Money
+}
+
+// GeneratePurchaseOrderPDF renders a purchase order. po must be loaded with
+// its lines and supplier.
+func GeneratePurchaseOrderPDF(po *model.PurchaseOrder) ([]byte, error) {
+	tmpl, err := template.New("purchaseorder.html").ParseFS(templates.PurchaseOrderFS, templates.PurchaseOrderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	date := po.CreatedAt
+	if po.SentAt != nil {
+		date = *po.SentAt
+	}
+	data := PurchaseOrderViewData{
+		Number:   po.PurchaseOrderNumber,
+		Date:     date.Format("02 Jan 2006"),
+		Notes:    po.Notes,
+		Lines:    po.Lines,
+		Currency: po.Currency,
+		Total:    po.Total,
+	}
+	if po.ExpectedAt != nil {
+		data.ExpectedDate = po.ExpectedAt.Format("02 Jan 2006")
+	}
+	if po.Supplier != nil {
+		data.SupplierName = po.Supplier.Name
+		data.ContactName = po.Supplier.ContactName
+	}
+
+	var htmlBuf bytes.Buffer
+	if err := tmpl.Execute(&htmlBuf, data); err != nil {
+		return nil, err
+	}
+
+	pdfg, err := wkhtmltopdf.NewPDFGenerator()
+	if err != nil {
+		return nil, err
+	}
+
+	pdfg.AddPage(wkhtmltopdf.NewPageReader(bytes.NewReader(htmlBuf.Bytes())))
+	pdfg.Dpi.Set(300)
+	pdfg.Orientation.Set(wkhtmltopdf.OrientationPortrait)
+	pdfg.PageSize.Set(wkhtmltopdf.PageSizeA4)
+
+	if err := pdfg.Create(); err != nil {
+		return nil, err
+	}
+
+	return pdfg.Bytes(), nil
+}
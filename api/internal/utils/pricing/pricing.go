@@ -0,0 +1,85 @@
+// Package pricing resolves the unit prices and taxes of order items.
+package pricing
+
+import (
+	"sort"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/utils/ordertotals"
+)
+
+// ListPrices are the prices of variants on the price lists that apply to an
+// order, by variant ID. They are in the order currency.
+type ListPrices map[uint]model.PriceListItem
+
+// UnitPrice returns the price of one unit of variant on order when buying
+// quantity units, in the order currency, and the price list it comes from,
+// if any. A price list price wins, then a price set on the variant for that
+// currency, otherwise the base price is converted at the exchange rate of
+// the order. List and base prices drop to the largest price break the
+// quantity reaches.
+func UnitPrice(order *model.Order, variant model.Variant, quantity int, listPrices ListPrices) (money.Money, *uint) {
+	if item, ok := listPrices[variant.ID]; ok {
+		listID := item.PriceListID
+		if price, ok := item.PriceBreaks.For(quantity); ok {
+			return price, &listID
+		}
+		return item.Price, &listID
+	}
+
+	if price, ok := variant.PriceIn(order.Currency); ok {
+		return price, nil
+	}
+
+	price := variant.Price
+	if brk, ok := variant.PriceBreaks.For(quantity); ok {
+		price = brk
+	}
+	if order.ExchangeRate <= 0 || order.ExchangeRate == 1 {
+		return price, nil
+	}
+	return price.DivRate(order.ExchangeRate, money.HalfUp), nil
+}
+
+// Taxes returns the taxes charged on variant on order, without amounts, and
+// their combined rate. They are the rates of the variant tax class by
+// priority, else its bare tax rate. Exempt customers and exempt classes are
+// charged none. The variant must be loaded with its tax class and rates.
+func Taxes(order *model.Order, variant model.Variant) ([]model.TaxLine, float64) {
+	if order.TaxExempt {
+		return nil, 0
+	}
+
+	if variant.TaxClass == nil {
+		if variant.TaxRate <= 0 {
+			return nil, 0
+		}
+		return []model.TaxLine{{Name: "Tax", Rate: variant.TaxRate}}, variant.TaxRate
+	}
+	return ClassTaxes(order, variant.TaxClass)
+}
+
+// ClassTaxes returns the taxes of class charged on order, without amounts,
+// and their combined rate: its rates by priority, none for exempt classes
+// and customers or without a class. The class must be loaded with its
+// rates.
+func ClassTaxes(order *model.Order, class *model.TaxClass) ([]model.TaxLine, float64) {
+	if order.TaxExempt || class == nil || class.Exempt {
+		return nil, 0
+	}
+
+	rates := append([]model.TaxRate(nil), class.Rates...)
+	sort.SliceStable(rates, func(i, j int) bool {
+		if rates[i].Priority != rates[j].Priority {
+			return rates[i].Priority < rates[j].Priority
+		}
+		return rates[i].ID < rates[j].ID
+	})
+
+	taxes := make([]model.TaxLine, len(rates))
+	for i, rate := range rates {
+		taxes[i] = model.TaxLine{Name: rate.Name, Rate: rate.Rate, Compound: rate.Compound}
+	}
+	return taxes, ordertotals.CombinedRate(taxes)
+}
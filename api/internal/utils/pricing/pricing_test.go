@@ -0,0 +1,135 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitPrice(t *testing.T) {
+	variant := model.Variant{
+		Price:  money.FromInt(1000),
+		Prices: map[string]money.Money{"EUR": money.MustParse("0.59")},
+	}
+
+	tests := []struct {
+		name  string
+		order model.Order
+		want  money.Money
+	}{
+		{"base currency", model.Order{Currency: "NGN", ExchangeRate: 1}, money.FromInt(1000)},
+		{"price set for currency", model.Order{Currency: "EUR", ExchangeRate: 1700}, money.MustParse("0.59")},
+		{"converted at the order rate", model.Order{Currency: "USD", ExchangeRate: 1550.25}, money.MustParse("0.65")},
+		{"no rate", model.Order{Currency: "USD"}, money.FromInt(1000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, listID := UnitPrice(&tt.order, variant, 1, nil)
+			assert.Equal(t, tt.want, price)
+			assert.Nil(t, listID)
+		})
+	}
+}
+
+func TestUnitPriceFromPriceList(t *testing.T) {
+	variant := model.Variant{
+		BaseModel: model.BaseModel{ID: 7},
+		Price:     money.FromInt(1000),
+		Prices:    map[string]money.Money{"EUR": money.MustParse("0.59")},
+	}
+	listPrices := ListPrices{7: {PriceListID: 3, VariantID: 7, Price: money.MustParse("0.50")}}
+
+	price, listID := UnitPrice(&model.Order{Currency: "EUR", ExchangeRate: 1700}, variant, 1, listPrices)
+	assert.Equal(t, money.MustParse("0.50"), price)
+	if assert.NotNil(t, listID) {
+		assert.Equal(t, uint(3), *listID)
+	}
+
+	price, listID = UnitPrice(&model.Order{Currency: "EUR", ExchangeRate: 1700}, model.Variant{BaseModel: model.BaseModel{ID: 8}, Price: money.FromInt(1700)}, 1, listPrices)
+	assert.Equal(t, money.FromInt(1), price)
+	assert.Nil(t, listID)
+}
+
+func TestUnitPriceBreaks(t *testing.T) {
+	variant := model.Variant{
+		BaseModel: model.BaseModel{ID: 7},
+		Price:     money.FromInt(1000),
+		Prices:    map[string]money.Money{"EUR": money.MustParse("0.59")},
+		PriceBreaks: model.PriceBreaks{
+			{MinQuantity: 50, Price: money.FromInt(800)},
+			{MinQuantity: 10, Price: money.FromInt(900)},
+		},
+	}
+	base := &model.Order{Currency: "NGN", ExchangeRate: 1}
+
+	tests := []struct {
+		quantity int
+		want     money.Money
+	}{
+		{1, money.FromInt(1000)},
+		{9, money.FromInt(1000)},
+		{10, money.FromInt(900)},
+		{49, money.FromInt(900)},
+		{50, money.FromInt(800)},
+		{500, money.FromInt(800)},
+	}
+	for _, tt := range tests {
+		price, _ := UnitPrice(base, variant, tt.quantity, nil)
+		assert.Equal(t, tt.want, price, tt.quantity)
+	}
+
+	// Breaks are converted like the base price, but not over a price set
+	// for the currency
+	price, _ := UnitPrice(&model.Order{Currency: "USD", ExchangeRate: 1600}, variant, 10, nil)
+	assert.Equal(t, money.MustParse("0.56"), price)
+	price, _ = UnitPrice(&model.Order{Currency: "EUR", ExchangeRate: 1700}, variant, 10, nil)
+	assert.Equal(t, money.MustParse("0.59"), price)
+
+	// List breaks replace the variant breaks
+	listPrices := ListPrices{7: {PriceListID: 3, VariantID: 7, Price: money.FromInt(950), PriceBreaks: model.PriceBreaks{
+		{MinQuantity: 20, Price: money.FromInt(700)},
+	}}}
+	price, _ = UnitPrice(base, variant, 10, listPrices)
+	assert.Equal(t, money.FromInt(950), price)
+	price, _ = UnitPrice(base, variant, 20, listPrices)
+	assert.Equal(t, money.FromInt(700), price)
+}
+
+func TestTaxes(t *testing.T) {
+	standard := &model.TaxClass{Rates: []model.TaxRate{
+		{BaseModel: model.BaseModel{ID: 2}, Name: "Levy", Rate: 0.02, Compound: true, Priority: 1},
+		{BaseModel: model.BaseModel{ID: 1}, Name: "VAT", Rate: 0.075},
+	}}
+
+	tests := []struct {
+		name    string
+		order   model.Order
+		variant model.Variant
+		want    []model.TaxLine
+	}{
+		{"class rates by priority", model.Order{}, model.Variant{TaxRate: 0.1, TaxClass: standard}, []model.TaxLine{
+			{Name: "VAT", Rate: 0.075},
+			{Name: "Levy", Rate: 0.02, Compound: true},
+		}},
+		{"zero rated class", model.Order{}, model.Variant{TaxClass: &model.TaxClass{Rates: []model.TaxRate{{Name: "VAT", Rate: 0}}}}, []model.TaxLine{
+			{Name: "VAT", Rate: 0},
+		}},
+		{"exempt class", model.Order{}, model.Variant{TaxClass: &model.TaxClass{Exempt: true, Rates: standard.Rates}}, nil},
+		{"bare tax rate", model.Order{}, model.Variant{TaxRate: 0.1}, []model.TaxLine{{Name: "Tax", Rate: 0.1}}},
+		{"no tax", model.Order{}, model.Variant{}, nil},
+		{"exempt customer", model.Order{TaxExempt: true}, model.Variant{TaxClass: standard}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taxes, _ := Taxes(&tt.order, tt.variant)
+			assert.Equal(t, tt.want, taxes)
+		})
+	}
+
+	_, rate := Taxes(&model.Order{}, model.Variant{TaxClass: standard})
+	assert.InDelta(t, 0.0965, rate, 1e-9)
+}
@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"gorm.io/gorm"
+)
+
+type APIKeyHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+	Revoke(w http.ResponseWriter, r *http.Request)
+}
+
+type APIKeyService interface {
+	Create(ctx context.Context, creator *identity.ContextUser, dto *dto.CreateAPIKeyDTO) (*model.APIKey, error)
+	Revoke(ctx context.Context, ID uint) (*model.APIKey, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.APIKey, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.APIKey, int64, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, apiKey *model.APIKey) error
+	Update(ctx context.Context, apiKey *model.APIKey) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.APIKey, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.APIKey, int64, error)
+	WithTx(tx *gorm.DB) APIKeyRepository
+}
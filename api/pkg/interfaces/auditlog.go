@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+)
+
+type AuditLogHandler interface {
+	Filter(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+}
+
+type AuditLogService interface {
+	Filter(ctx context.Context, opts pagination.Options) ([]model.AuditEntry, int64, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.AuditEntry, error)
+}
+
+// AuditLogRepository reads the audit log. Entries are written by the audit
+// GORM callbacks, never through the repository.
+type AuditLogRepository interface {
+	Filter(ctx context.Context, opts pagination.Options) ([]model.AuditEntry, int64, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.AuditEntry, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+)
+
+type BackorderHandler interface {
+	Report(w http.ResponseWriter, r *http.Request)
+}
+
+type BackorderService interface {
+	Report(ctx context.Context, where map[string]any) (*types.BackorderReport, error)
+}
@@ -0,0 +1,36 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"gorm.io/gorm"
+)
+
+type CreditNoteHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+	Send(w http.ResponseWriter, r *http.Request)
+}
+
+type CreditNoteService interface {
+	Create(ctx context.Context, orgID uint, dto *dto.CreateCreditNoteDTO) (*model.CreditNote, error)
+	Send(ctx context.Context, ID uint) error
+	SendEmail(ctx context.Context, job types.CreditNoteEmailJob) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.CreditNote, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.CreditNote, int64, error)
+	WithTx(tx *gorm.DB) CreditNoteService
+}
+
+type CreditNoteRepository interface {
+	Create(ctx context.Context, creditNote *model.CreditNote) error
+	CreditedQuantities(ctx context.Context, invoiceID uint) (map[uint]int, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.CreditNote, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.CreditNote, int64, error)
+	WithTx(tx *gorm.DB) CreditNoteRepository
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
 	"gorm.io/gorm"
 )
@@ -22,17 +23,21 @@ type CustomerService interface {
 	Create(ctx context.Context, customer *model.Customer) error
 	Update(ctx context.Context, customerId uint, dto *dto.UpdateCustomerDTO) (*model.Customer, error)
 	Delete(ctx context.Context, ID uint) error
+	AdjustCredit(ctx context.Context, ID uint, delta money.Money) error
 	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Customer, error)
 	Filter(ctx context.Context, opts pagination.Options) ([]model.Customer, int64, error)
 	FindByID(ctx context.Context, ID uint, preloads []string) (*model.Customer, error)
+	FindByIDs(ctx context.Context, IDs []uint) ([]model.Customer, error)
 	WithTx(tx *gorm.DB) CustomerService
 }
 
 type CustomerRepository interface {
 	Create(ctx context.Context, customer *model.Customer) error
 	Update(ctx context.Context, customer *model.Customer) error
+	AdjustCredit(ctx context.Context, ID uint, delta money.Money) error
 	Delete(ctx context.Context, ID uint) error
 	FindByID(ctx context.Context, ID uint) (*model.Customer, error)
+	FindByIDs(ctx context.Context, IDs []uint) ([]model.Customer, error)
 	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Customer, error)
 	Filter(ctx context.Context, opts pagination.Options) ([]model.Customer, int64, error)
 	WithTx(tx *gorm.DB) CustomerRepository
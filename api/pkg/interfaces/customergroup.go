@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"gorm.io/gorm"
+)
+
+type CustomerGroupHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+}
+
+type CustomerGroupService interface {
+	Create(ctx context.Context, group *model.CustomerGroup) error
+	Update(ctx context.Context, ID uint, dto *dto.UpdateCustomerGroupDTO) (*model.CustomerGroup, error)
+	Delete(ctx context.Context, ID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.CustomerGroup, error)
+	FindByIDs(ctx context.Context, IDs []uint) ([]model.CustomerGroup, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.CustomerGroup, int64, error)
+	WithTx(tx *gorm.DB) CustomerGroupService
+}
+
+type CustomerGroupRepository interface {
+	Create(ctx context.Context, group *model.CustomerGroup) error
+	Update(ctx context.Context, group *model.CustomerGroup) error
+	Delete(ctx context.Context, ID uint) error
+	FindByID(ctx context.Context, ID uint) (*model.CustomerGroup, error)
+	FindByIDs(ctx context.Context, IDs []uint) ([]model.CustomerGroup, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.CustomerGroup, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.CustomerGroup, int64, error)
+	WithTx(tx *gorm.DB) CustomerGroupRepository
+}
@@ -0,0 +1,36 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"gorm.io/gorm"
+)
+
+type ExchangeRateHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+}
+
+type ExchangeRateService interface {
+	Create(ctx context.Context, rate *model.ExchangeRate) error
+	Delete(ctx context.Context, ID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.ExchangeRate, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.ExchangeRate, int64, error)
+	Rate(ctx context.Context, base string, currency string, at time.Time) (float64, error)
+	WithTx(tx *gorm.DB) ExchangeRateService
+}
+
+type ExchangeRateRepository interface {
+	Create(ctx context.Context, rate *model.ExchangeRate) error
+	Delete(ctx context.Context, ID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.ExchangeRate, error)
+	FindEffective(ctx context.Context, base string, currency string, at time.Time) (*model.ExchangeRate, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.ExchangeRate, int64, error)
+	WithTx(tx *gorm.DB) ExchangeRateRepository
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+)
+
+// IdempotencyStore keeps the responses of requests sent with an
+// `Idempotency-Key` header, per org and key.
+type IdempotencyStore interface {
+	// Reserve claims key for a request with fingerprint. When key is already
+	// claimed it returns the existing record and false instead.
+	Reserve(ctx context.Context, orgID uint, key string, fingerprint string) (*model.IdempotencyKey, bool, error)
+	// Complete stores the response of the request that reserved key.
+	Complete(ctx context.Context, orgID uint, key string, status int, body []byte) error
+	// Release frees key so the request can be retried.
+	Release(ctx context.Context, orgID uint, key string) error
+}
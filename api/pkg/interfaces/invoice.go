@@ -3,10 +3,13 @@ package interfaces
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
 	"gorm.io/gorm"
 )
 
@@ -16,7 +19,10 @@ type InvoiceHandler interface {
 	Delete(w http.ResponseWriter, r *http.Request)
 	Get(w http.ResponseWriter, r *http.Request)
 	Filter(w http.ResponseWriter, r *http.Request)
+	SendProForma(w http.ResponseWriter, r *http.Request)
 	Issue(w http.ResponseWriter, r *http.Request)
+	Cancel(w http.ResponseWriter, r *http.Request)
+	Void(w http.ResponseWriter, r *http.Request)
 }
 
 type InvoiceService interface {
@@ -26,15 +32,24 @@ type InvoiceService interface {
 	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Invoice, error)
 	Filter(ctx context.Context, opts pagination.Options) ([]model.Invoice, int64, error)
 	FindByID(ctx context.Context, ID uint, preloads []string) (*model.Invoice, error)
+	FindForUpdate(ctx context.Context, ID uint, preloads []string) (*model.Invoice, error)
 	WithTx(tx *gorm.DB) InvoiceService
-	Issue(ctx context.Context, id uint) error
+	Transition(ctx context.Context, id uint, to model.InvoiceStatus) (*model.Invoice, error)
+	ApplyPayments(ctx context.Context, invoice *model.Invoice, paid money.Money) error
+	ApplyCredit(ctx context.Context, invoice *model.Invoice, credited money.Money) error
+	SendEmail(ctx context.Context, ID uint, proForma bool) error
+	MarkOverdue(ctx context.Context, job types.MarkOverdueInvoicesJob) error
 }
 
 type InvoiceRepository interface {
 	Create(ctx context.Context, invoice *model.Invoice) error
-	Update(ctx context.Context, invoice *model.Invoice) error
+	Update(ctx context.Context, invoice *model.Invoice) (bool, error)
+	UpdateStatus(ctx context.Context, invoice *model.Invoice, from model.InvoiceStatus) (bool, error)
 	Delete(ctx context.Context, ID uint) error
 	FindByID(ctx context.Context, ID uint) (*model.Invoice, error)
+	Invoiced(ctx context.Context, where map[string]any) (bool, error)
+	FindPastDue(ctx context.Context, now time.Time) ([]model.Invoice, error)
+	FindForUpdate(ctx context.Context, ID uint, preloads []string) (*model.Invoice, error)
 	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Invoice, error)
 	Filter(ctx context.Context, opts pagination.Options) ([]model.Invoice, int64, error)
 	WithTx(tx *gorm.DB) InvoiceRepository
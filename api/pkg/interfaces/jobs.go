@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// JobHandler runs a background job. payload is the JSON the job was
+// enqueued with. Returning an error retries the job later.
+type JobHandler func(ctx context.Context, payload []byte) error
+
+type JobQueue interface {
+	// Enqueue stores a job of jobType to run as soon as a worker is free.
+	// The job runs for the org of ctx, if any.
+	Enqueue(ctx context.Context, jobType string, payload any) error
+	// Handle registers the handler for jobs of jobType.
+	Handle(jobType string, handler JobHandler)
+	// Schedule runs system jobs of jobType every interval. The first run is
+	// enqueued right away unless one is already waiting, every later one
+	// when the previous run finished.
+	Schedule(ctx context.Context, jobType string, interval time.Duration) error
+	// WithTx returns a queue that enqueues in tx, so jobs are only stored
+	// when tx commits.
+	WithTx(tx *gorm.DB) JobQueue
+}
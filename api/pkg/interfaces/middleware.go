@@ -4,10 +4,14 @@ import (
 	"net/http"
 
 	clerkHttp "github.com/clerk/clerk-sdk-go/v2/http"
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
 )
 
 type Middleware interface {
 	Recover(logger Logger) func(http.Handler) http.Handler
 	ValidateJWT(opts ...clerkHttp.AuthorizationOption) func(http.Handler) http.Handler
+	Authenticate() func(http.Handler) http.Handler
+	Idempotent() func(http.Handler) http.Handler
 	VerifyWebhook() func(http.Handler) http.Handler
+	RequirePermission(perms ...rbac.Permission) func(http.Handler) http.Handler
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
 	"gorm.io/gorm"
 )
 
@@ -16,11 +17,19 @@ type OrderHandler interface {
 	Delete(w http.ResponseWriter, r *http.Request)
 	Get(w http.ResponseWriter, r *http.Request)
 	Filter(w http.ResponseWriter, r *http.Request)
+	Approve(w http.ResponseWriter, r *http.Request)
+	Deliver(w http.ResponseWriter, r *http.Request)
+	Cancel(w http.ResponseWriter, r *http.Request)
 }
 
 type OrderService interface {
 	Create(ctx context.Context, DTO dto.CreateOrderDTO, orgId uint) (*model.Order, error)
 	Update(ctx context.Context, order *model.Order, dtos dto.UpdateOrderDTO) error
+	Transition(ctx context.Context, ID uint, to model.OrderStatus) (*model.Order, error)
+	ApplyShipment(ctx context.Context, shipment *model.Shipment) error
+	AllocateBackorders(ctx context.Context, variantID uint) error
+	Restock(ctx context.Context, orderID uint, quantities map[uint]int) error
+	FindBackorders(ctx context.Context, where map[string]any) ([]types.Backorder, error)
 	Delete(ctx context.Context, ID uint) error
 	FindByID(ctx context.Context, ID uint) (*model.Order, error)
 	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Order, error)
@@ -32,9 +41,19 @@ type OrderService interface {
 type OrderRepository interface {
 	Create(ctx context.Context, model *model.Order) error
 	Update(ctx context.Context, model *model.Order) error
+	FindForUpdate(ctx context.Context, ID uint, preloads []string) (*model.Order, error)
 	FindByID(ctx context.Context, ID uint) (*model.Order, error)
 	Filter(ctx context.Context, opts pagination.Options) ([]model.Order, int64, error)
 	Delete(ctx context.Context, ID uint) error
+	DeleteItems(ctx context.Context, orderID uint) error
+	DeleteCharges(ctx context.Context, orderID uint) error
+	AddShippedQuantities(ctx context.Context, itemID uint, shipped int, delivered int) error
+	AddRestockedQuantity(ctx context.Context, itemID uint, restocked int) error
+	CancelShipments(ctx context.Context, orderID uint) error
+	FindBackorders(ctx context.Context, where map[string]any) ([]types.Backorder, error)
+	AllocateItem(ctx context.Context, itemID uint, qty int) (bool, error)
+	UpdateStatus(ctx context.Context, order *model.Order, from model.OrderStatus) (bool, error)
+	CreateStatusHistory(ctx context.Context, history *model.OrderStatusHistory) error
 	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Order, error)
 	WithTx(tx *gorm.DB) OrderRepository
 }
@@ -36,4 +36,5 @@ type OrgHandler interface {
 
 type CreateOrgUseCase interface {
 	Execute(cxt context.Context, input types.CreateOrgInput) error
+	SetClerkOrg(ctx context.Context, event types.OrgCreatedEvent) error
 }
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"gorm.io/gorm"
+)
+
+// EventHandler reacts to a published domain event. Returning an error
+// retries the event for this handler later.
+type EventHandler func(ctx context.Context, event *model.OutboxEvent) error
+
+type Outbox interface {
+	// Record stores a domain event of eventType about the record with ID
+	// aggregateID. Use WithTx to record it with the change it describes.
+	Record(ctx context.Context, eventType string, aggregateID uint, payload any) error
+	// Subscribe registers handler, named subscriber, for events of
+	// eventType. Each subscriber gets every event once, independent of the
+	// others.
+	Subscribe(eventType string, subscriber string, handler EventHandler)
+	// WithTx returns an outbox that records in tx, so events are only
+	// stored, and published, when tx commits.
+	WithTx(tx *gorm.DB) Outbox
+}
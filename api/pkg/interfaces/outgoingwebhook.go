@@ -0,0 +1,50 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"gorm.io/gorm"
+)
+
+type OutgoingWebhookHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+	GetDelivery(w http.ResponseWriter, r *http.Request)
+	FilterDeliveries(w http.ResponseWriter, r *http.Request)
+	Redeliver(w http.ResponseWriter, r *http.Request)
+}
+
+type OutgoingWebhookService interface {
+	Create(ctx context.Context, orgID uint, dto *dto.CreateWebhookSubscriptionDTO) (*model.WebhookSubscription, error)
+	Update(ctx context.Context, ID uint, dto *dto.UpdateWebhookSubscriptionDTO) (*model.WebhookSubscription, error)
+	Delete(ctx context.Context, ID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.WebhookSubscription, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.WebhookSubscription, int64, error)
+	FindDelivery(ctx context.Context, ID uint) (*model.WebhookDelivery, error)
+	FilterDeliveries(ctx context.Context, opts pagination.Options) ([]model.WebhookDelivery, int64, error)
+	Dispatch(ctx context.Context, event *model.OutboxEvent) error
+	Deliver(ctx context.Context, job types.WebhookDeliveryJob) error
+	Redeliver(ctx context.Context, ID uint) (*model.WebhookDelivery, error)
+}
+
+type OutgoingWebhookRepository interface {
+	Create(ctx context.Context, subscription *model.WebhookSubscription) error
+	Update(ctx context.Context, subscription *model.WebhookSubscription) error
+	Delete(ctx context.Context, ID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.WebhookSubscription, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.WebhookSubscription, int64, error)
+	FindActive(ctx context.Context) ([]model.WebhookSubscription, error)
+	CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) (bool, error)
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	FindDelivery(ctx context.Context, ID uint, preloads []string) (*model.WebhookDelivery, error)
+	FilterDeliveries(ctx context.Context, opts pagination.Options) ([]model.WebhookDelivery, int64, error)
+	WithTx(tx *gorm.DB) OutgoingWebhookRepository
+}
@@ -0,0 +1,39 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"gorm.io/gorm"
+)
+
+type PaymentHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+}
+
+type PaymentService interface {
+	Create(ctx context.Context, orgID uint, dto *dto.CreatePaymentDTO) (*model.Payment, error)
+	Update(ctx context.Context, ID uint, dto *dto.UpdatePaymentDTO) (*model.Payment, error)
+	Delete(ctx context.Context, ID uint) error
+	Settle(ctx context.Context, invoiceID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Payment, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.Payment, int64, error)
+	WithTx(tx *gorm.DB) PaymentService
+}
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *model.Payment) error
+	Update(ctx context.Context, payment *model.Payment) error
+	Delete(ctx context.Context, ID uint) error
+	FindByInvoice(ctx context.Context, invoiceID uint) ([]model.Payment, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Payment, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.Payment, int64, error)
+	WithTx(tx *gorm.DB) PaymentRepository
+}
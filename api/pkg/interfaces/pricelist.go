@@ -0,0 +1,42 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/utils/pricing"
+	"gorm.io/gorm"
+)
+
+type PriceListHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+}
+
+type PriceListService interface {
+	Create(ctx context.Context, list *model.PriceList, customerIDs []uint, groupIDs []uint) error
+	Update(ctx context.Context, ID uint, dto *dto.UpdatePriceListDTO) (*model.PriceList, error)
+	Delete(ctx context.Context, ID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.PriceList, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.PriceList, int64, error)
+	Prices(ctx context.Context, customer *model.Customer, currency string, variantIDs []uint, at time.Time) (pricing.ListPrices, error)
+	WithTx(tx *gorm.DB) PriceListService
+}
+
+type PriceListRepository interface {
+	Create(ctx context.Context, list *model.PriceList) error
+	Update(ctx context.Context, list *model.PriceList) error
+	Delete(ctx context.Context, ID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.PriceList, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.PriceList, int64, error)
+	FindForCustomer(ctx context.Context, customerID uint, currency string, variantIDs []uint, at time.Time) ([]model.PriceList, error)
+	FindForGroup(ctx context.Context, groupID uint, currency string, variantIDs []uint, at time.Time) ([]model.PriceList, error)
+	WithTx(tx *gorm.DB) PriceListRepository
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
 	"github.com/deveasyclick/openb2b/internal/shared/pagination"
 	"gorm.io/gorm"
 )
@@ -21,15 +22,27 @@ type ProductService interface {
 
 	// Varaiants
 	CreateVariant(ctx context.Context, variant *model.Variant) error
-	UpdateVariant(ctx context.Context, variant *model.Variant) error
+	UpdateVariant(ctx context.Context, variant *model.Variant, DTO dto.UpdateVariantDTO) error
 	DeleteVariant(ctx context.Context, productID uint, variantID uint) error
 	FindVariantByID(ctx context.Context, productID uint, variantID uint) (*model.Variant, error)
 	CheckVariantExists(ctx context.Context, sku string) (bool, error)
 	FindVariants(ctx context.Context, where map[string]any, preloads []string) ([]model.Variant, error)
+
+	// Stock
+	ReserveStock(ctx context.Context, lines []model.StockLine) error
+	ReserveAvailable(ctx context.Context, line model.StockLine) (int, error)
+	ReleaseStock(ctx context.Context, lines []model.StockLine) error
+	CommitStock(ctx context.Context, orderID uint, lines []model.StockLine) error
+	ReturnStock(ctx context.Context, orderID uint, lines []model.StockLine) error
+	ReceiveStock(ctx context.Context, purchaseOrderID uint, lines []model.StockLine) error
+	AdjustStock(ctx context.Context, variant *model.Variant, warehouseID *uint, stock int, note string) error
+	TransferOut(ctx context.Context, transferID uint, lines []model.StockLine) error
+	TransferIn(ctx context.Context, transferID uint, lines []model.StockLine) error
+	FilterStockMovements(ctx context.Context, opts pagination.Options) ([]model.StockMovement, int64, error)
 }
 
 type ProductRepository interface {
-	Create(ctx context.Context, product *model.Product) error
+	Create(ctx context.Context, product *model.Product, warehouseID *uint) error
 	Update(ctx context.Context, product *model.Product) error
 	FindByID(ctx context.Context, ID uint) (*model.Product, error)
 	Filter(ctx context.Context, opts pagination.Options) ([]model.Product, int64, error)
@@ -38,12 +51,20 @@ type ProductRepository interface {
 	WithTx(tx *gorm.DB) ProductRepository
 
 	// Variants
-	CreateVariant(ctx context.Context, variant *model.Variant) error
+	CreateVariant(ctx context.Context, variant *model.Variant, warehouseID *uint) error
 	UpdateVariant(ctx context.Context, variant *model.Variant) error
 	DeleteVariant(ctx context.Context, variantID uint, productID uint) error
 	FindVariantByID(ctx context.Context, variantID uint, productID uint) (*model.Variant, error)
 	CheckVariantExistsBySKU(ctx context.Context, sku string) (bool, error)
 	FindVariants(ctx context.Context, where map[string]any, preloads []string) ([]model.Variant, error)
+
+	// Stock
+	ReserveStock(ctx context.Context, variantID uint, warehouseID *uint, qty int) (bool, error)
+	ReleaseStock(ctx context.Context, variantID uint, warehouseID *uint, qty int) error
+	MoveStock(ctx context.Context, movement *model.StockMovement, reservedDelta int) error
+	FindStockForUpdate(ctx context.Context, variantID uint, warehouseID *uint) (int, error)
+	FindStockLevels(ctx context.Context, where map[string]any) ([]model.StockLevel, error)
+	FilterStockMovements(ctx context.Context, opts pagination.Options) ([]model.StockMovement, int64, error)
 }
 
 type ProductHandler interface {
@@ -58,4 +79,5 @@ type ProductHandler interface {
 	UpdateVariant(w http.ResponseWriter, r *http.Request)
 	DeleteVariant(w http.ResponseWriter, r *http.Request)
 	GetVariant(w http.ResponseWriter, r *http.Request)
+	FilterStockMovements(w http.ResponseWriter, r *http.Request)
 }
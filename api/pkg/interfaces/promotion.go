@@ -0,0 +1,55 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"gorm.io/gorm"
+)
+
+type PromotionHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+	Redemptions(w http.ResponseWriter, r *http.Request)
+}
+
+type PromotionService interface {
+	Create(ctx context.Context, promotion *model.Promotion) error
+	Update(ctx context.Context, ID uint, dto *dto.UpdatePromotionDTO) (*model.Promotion, error)
+	Delete(ctx context.Context, ID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Promotion, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.Promotion, int64, error)
+	Redemptions(ctx context.Context, promotionID uint, opts pagination.Options) ([]model.PromotionRedemption, int64, error)
+	Apply(ctx context.Context, order *model.Order, code string) error
+	Remove(order *model.Order)
+	Redeem(ctx context.Context, order *model.Order) error
+	Release(ctx context.Context, orderID uint) error
+	WithTx(tx *gorm.DB) PromotionService
+}
+
+type PromotionRepository interface {
+	Create(ctx context.Context, promotion *model.Promotion) error
+	Update(ctx context.Context, promotion *model.Promotion) error
+	Delete(ctx context.Context, ID uint) error
+	FindByID(ctx context.Context, ID uint) (*model.Promotion, error)
+	FindForUpdate(ctx context.Context, ID uint) (*model.Promotion, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Promotion, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.Promotion, int64, error)
+	CodeExists(ctx context.Context, code string) (bool, error)
+	ProductCategories(ctx context.Context, productIDs []uint) (map[uint]string, error)
+	Use(ctx context.Context, ID uint) (bool, error)
+	Unuse(ctx context.Context, ID uint) error
+	FindRedemption(ctx context.Context, orderID uint) (*model.PromotionRedemption, error)
+	CountRedemptions(ctx context.Context, promotionID uint, customerID uint, exceptOrderID uint) (int64, error)
+	CreateRedemption(ctx context.Context, redemption *model.PromotionRedemption) error
+	UpdateRedemption(ctx context.Context, redemption *model.PromotionRedemption) error
+	DeleteRedemption(ctx context.Context, ID uint) error
+	FilterRedemptions(ctx context.Context, opts pagination.Options) ([]model.PromotionRedemption, int64, error)
+	WithTx(tx *gorm.DB) PromotionRepository
+}
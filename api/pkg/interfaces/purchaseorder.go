@@ -0,0 +1,44 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"gorm.io/gorm"
+)
+
+type PurchaseOrderHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+	Send(w http.ResponseWriter, r *http.Request)
+	Receive(w http.ResponseWriter, r *http.Request)
+	Close(w http.ResponseWriter, r *http.Request)
+}
+
+type PurchaseOrderService interface {
+	Create(ctx context.Context, po *model.PurchaseOrder) error
+	Update(ctx context.Context, ID uint, dto *dto.UpdatePurchaseOrderDTO) (*model.PurchaseOrder, error)
+	Send(ctx context.Context, ID uint) (*model.PurchaseOrder, error)
+	Receive(ctx context.Context, ID uint, lines []dto.ReceivePurchaseOrderLineDTO) (*model.PurchaseOrder, error)
+	Close(ctx context.Context, ID uint) (*model.PurchaseOrder, error)
+	SendEmail(ctx context.Context, job types.PurchaseOrderEmailJob) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.PurchaseOrder, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.PurchaseOrder, int64, error)
+	WithTx(tx *gorm.DB) PurchaseOrderService
+}
+
+type PurchaseOrderRepository interface {
+	Create(ctx context.Context, po *model.PurchaseOrder) error
+	Update(ctx context.Context, po *model.PurchaseOrder) error
+	UpdateStatus(ctx context.Context, po *model.PurchaseOrder, from model.PurchaseOrderStatus) (bool, error)
+	ReceiveLine(ctx context.Context, lineID uint, qty int) (bool, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.PurchaseOrder, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.PurchaseOrder, int64, error)
+	WithTx(tx *gorm.DB) PurchaseOrderRepository
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+)
+
+type ReportHandler interface {
+	Sales(w http.ResponseWriter, r *http.Request)
+}
+
+type ReportService interface {
+	Sales(ctx context.Context, orgID uint, from time.Time, to time.Time) (*types.SalesReport, error)
+}
+
+type ReportRepository interface {
+	FindIssuedInvoices(ctx context.Context, from time.Time, to time.Time) ([]model.Invoice, error)
+}
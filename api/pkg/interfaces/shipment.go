@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"gorm.io/gorm"
+)
+
+type ShipmentHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+	Ship(w http.ResponseWriter, r *http.Request)
+	Deliver(w http.ResponseWriter, r *http.Request)
+	Cancel(w http.ResponseWriter, r *http.Request)
+}
+
+type ShipmentService interface {
+	Create(ctx context.Context, shipment *model.Shipment, lines []dto.CreateShipmentLineDTO) error
+	Update(ctx context.Context, ID uint, dto *dto.UpdateShipmentDTO) (*model.Shipment, error)
+	Transition(ctx context.Context, ID uint, to model.ShipmentStatus) (*model.Shipment, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Shipment, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.Shipment, int64, error)
+	WithTx(tx *gorm.DB) ShipmentService
+}
+
+type ShipmentRepository interface {
+	Create(ctx context.Context, shipment *model.Shipment) error
+	Update(ctx context.Context, shipment *model.Shipment) error
+	UpdateStatus(ctx context.Context, shipment *model.Shipment, from model.ShipmentStatus) (bool, error)
+	ShippingQuantities(ctx context.Context, orderID uint) (map[uint]int, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Shipment, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.Shipment, int64, error)
+	WithTx(tx *gorm.DB) ShipmentRepository
+}
@@ -0,0 +1,37 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"gorm.io/gorm"
+)
+
+type StockTransferHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+	Ship(w http.ResponseWriter, r *http.Request)
+	Receive(w http.ResponseWriter, r *http.Request)
+	Cancel(w http.ResponseWriter, r *http.Request)
+}
+
+type StockTransferService interface {
+	Create(ctx context.Context, transfer *model.StockTransfer) error
+	Ship(ctx context.Context, ID uint) (*model.StockTransfer, error)
+	Receive(ctx context.Context, ID uint) (*model.StockTransfer, error)
+	Cancel(ctx context.Context, ID uint) (*model.StockTransfer, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.StockTransfer, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.StockTransfer, int64, error)
+	WithTx(tx *gorm.DB) StockTransferService
+}
+
+type StockTransferRepository interface {
+	Create(ctx context.Context, transfer *model.StockTransfer) error
+	UpdateStatus(ctx context.Context, transfer *model.StockTransfer, from model.StockTransferStatus) (bool, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.StockTransfer, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.StockTransfer, int64, error)
+	WithTx(tx *gorm.DB) StockTransferRepository
+}
@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"gorm.io/gorm"
+)
+
+type SupplierHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+}
+
+type SupplierService interface {
+	Create(ctx context.Context, supplier *model.Supplier) error
+	Update(ctx context.Context, ID uint, dto *dto.UpdateSupplierDTO) (*model.Supplier, error)
+	Delete(ctx context.Context, ID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Supplier, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.Supplier, int64, error)
+	WithTx(tx *gorm.DB) SupplierService
+}
+
+type SupplierRepository interface {
+	Create(ctx context.Context, supplier *model.Supplier) error
+	Update(ctx context.Context, supplier *model.Supplier) error
+	Delete(ctx context.Context, ID uint) error
+	HasPurchaseOrders(ctx context.Context, ID uint) (bool, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Supplier, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.Supplier, int64, error)
+	WithTx(tx *gorm.DB) SupplierRepository
+}
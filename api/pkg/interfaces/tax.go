@@ -0,0 +1,69 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"gorm.io/gorm"
+)
+
+type TaxRateHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+}
+
+type TaxRateService interface {
+	Create(ctx context.Context, rate *model.TaxRate) error
+	Update(ctx context.Context, ID uint, dto *dto.UpdateTaxRateDTO) (*model.TaxRate, error)
+	Delete(ctx context.Context, ID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.TaxRate, error)
+	FindByIDs(ctx context.Context, IDs []uint) ([]model.TaxRate, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.TaxRate, int64, error)
+	WithTx(tx *gorm.DB) TaxRateService
+}
+
+type TaxRateRepository interface {
+	Create(ctx context.Context, rate *model.TaxRate) error
+	Update(ctx context.Context, rate *model.TaxRate) error
+	Delete(ctx context.Context, ID uint) error
+	FindByID(ctx context.Context, ID uint) (*model.TaxRate, error)
+	FindByIDs(ctx context.Context, IDs []uint) ([]model.TaxRate, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.TaxRate, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.TaxRate, int64, error)
+	WithTx(tx *gorm.DB) TaxRateRepository
+}
+
+type TaxClassHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+}
+
+type TaxClassService interface {
+	Create(ctx context.Context, class *model.TaxClass, rateIDs []uint) error
+	Update(ctx context.Context, ID uint, dto *dto.UpdateTaxClassDTO) (*model.TaxClass, error)
+	Delete(ctx context.Context, ID uint) error
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.TaxClass, error)
+	FindByIDs(ctx context.Context, IDs []uint) ([]model.TaxClass, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.TaxClass, int64, error)
+	WithTx(tx *gorm.DB) TaxClassService
+}
+
+type TaxClassRepository interface {
+	Create(ctx context.Context, class *model.TaxClass) error
+	Update(ctx context.Context, class *model.TaxClass) error
+	Delete(ctx context.Context, ID uint) error
+	InUse(ctx context.Context, ID uint) (bool, error)
+	FindByIDs(ctx context.Context, IDs []uint) ([]model.TaxClass, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.TaxClass, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.TaxClass, int64, error)
+	WithTx(tx *gorm.DB) TaxClassRepository
+}
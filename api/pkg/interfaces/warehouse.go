@@ -0,0 +1,39 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/pagination"
+	"gorm.io/gorm"
+)
+
+type WarehouseHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Filter(w http.ResponseWriter, r *http.Request)
+}
+
+type WarehouseService interface {
+	Create(ctx context.Context, warehouse *model.Warehouse) error
+	Update(ctx context.Context, ID uint, dto *dto.UpdateWarehouseDTO) (*model.Warehouse, error)
+	Delete(ctx context.Context, ID uint) error
+	Location(ctx context.Context, ID *uint) (*uint, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Warehouse, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.Warehouse, int64, error)
+	WithTx(tx *gorm.DB) WarehouseService
+}
+
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *model.Warehouse) error
+	Update(ctx context.Context, warehouse *model.Warehouse) error
+	Delete(ctx context.Context, ID uint) error
+	InUse(ctx context.Context, ID uint) (bool, error)
+	FindOneWithFields(ctx context.Context, fields []string, where map[string]any, preloads []string) (*model.Warehouse, error)
+	Filter(ctx context.Context, opts pagination.Options) ([]model.Warehouse, int64, error)
+	WithTx(tx *gorm.DB) WarehouseRepository
+}
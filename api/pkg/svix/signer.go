@@ -0,0 +1,32 @@
+package svix
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	svix "github.com/svix/svix-webhooks/go"
+)
+
+const secretPrefix = "whsec_"
+
+// NewSecret returns a random signing secret in the Svix format.
+func NewSecret() (string, error) {
+	key := make([]byte, 24)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return secretPrefix + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Sign returns the svix-signature header of payload sent as message msgID
+// at timestamp, so receivers can verify it like we verify Clerk webhooks.
+func Sign(secret string, msgID string, timestamp time.Time, payload []byte) (string, error) {
+	wh, err := svix.NewWebhook(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return wh.Sign(msgID, timestamp, payload)
+}
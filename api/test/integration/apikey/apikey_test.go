@@ -0,0 +1,153 @@
+package apikey_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createKey(t *testing.T, url string, reqBody dto.CreateAPIKeyDTO) *http.Response {
+	t.Helper()
+	body, _ := json.Marshal(reqBody)
+	resp, err := http.Post(url+"/api/v1/api-keys", "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	return resp
+}
+
+func decodeKey(t *testing.T, resp *http.Response) model.APIKey {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[model.APIKey]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out.Data
+}
+
+func getWithKey(t *testing.T, url string, key string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Authorization", "Bearer "+key)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestAPIKeys(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+
+	var created model.APIKey
+	t.Run("Create API key", func(t *testing.T) {
+		resp := createKey(t, ts.URL, dto.CreateAPIKeyDTO{Name: "ERP sync", Scopes: []string{"orders:read"}})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		created = decodeKey(t, resp)
+
+		assert.Contains(t, created.Key, "ob2b_"+created.Prefix+"_")
+		assert.Equal(t, setup.DefaultOrgID, created.OrgID)
+		assert.Equal(t, setup.DefaultUserID, created.CreatedByID)
+
+		var stored model.APIKey
+		require.NoError(t, db.First(&stored, created.ID).Error)
+		assert.NotEmpty(t, stored.SecretHash)
+		assert.NotContains(t, stored.SecretHash, created.Key)
+	})
+
+	t.Run("Get API key - does not return the key", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf("%s/api/v1/api-keys/%d", ts.URL, created.ID))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		apiKey := decodeKey(t, resp)
+		assert.Empty(t, apiKey.Key)
+		assert.Equal(t, created.Prefix, apiKey.Prefix)
+	})
+
+	t.Run("Create API key - missing scopes (400)", func(t *testing.T) {
+		resp := createKey(t, ts.URL, dto.CreateAPIKeyDTO{Name: "No scopes"})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Authenticate with API key", func(t *testing.T) {
+		resp := getWithKey(t, ts.URL+"/api/v1/orders", created.Key)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var stored model.APIKey
+		require.NoError(t, db.First(&stored, created.ID).Error)
+		assert.NotNil(t, stored.LastUsedAt)
+	})
+
+	t.Run("Authenticate with API key - scope not granted (403)", func(t *testing.T) {
+		resp := getWithKey(t, ts.URL+"/api/v1/products", created.Key)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Authenticate with API key - wrong secret (401)", func(t *testing.T) {
+		resp := getWithKey(t, ts.URL+"/api/v1/orders", "ob2b_"+created.Prefix+"_"+"000000000000000000000000000000000000000000000000")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Authenticate with API key - expired (401)", func(t *testing.T) {
+		resp := createKey(t, ts.URL, dto.CreateAPIKeyDTO{Name: "Expiring", Scopes: []string{"orders:read"}})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		expiring := decodeKey(t, resp)
+
+		require.NoError(t, db.Model(&model.APIKey{}).Where("id = ?", expiring.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		resp = getWithKey(t, ts.URL+"/api/v1/orders", expiring.Key)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Revoke API key", func(t *testing.T) {
+		resp, err := http.Post(fmt.Sprintf("%s/api/v1/api-keys/%d/revoke", ts.URL, created.ID), "application/json", nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotNil(t, decodeKey(t, resp).RevokedAt)
+
+		resp = getWithKey(t, ts.URL+"/api/v1/orders", created.Key)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestAPIKeyScopesAreLimitedToCreator(t *testing.T) {
+	ts := setup.SetupTestServerAs(setup.DefaultUserID, setup.DefaultOrgID, model.RoleAdmin)
+	defer ts.Close()
+
+	t.Run("Admin cannot grant org deletion (403)", func(t *testing.T) {
+		resp := createKey(t, ts.URL, dto.CreateAPIKeyDTO{Name: "Too broad", Scopes: []string{"orders:read", "orgs:delete"}})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Admin can grant its own permissions", func(t *testing.T) {
+		resp := createKey(t, ts.URL, dto.CreateAPIKeyDTO{Name: "Catalog", Scopes: []string{"products:read", "products:write"}})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	sales := setup.SetupTestServerAs(setup.DefaultUserID, setup.DefaultOrgID, model.RoleSales)
+	defer sales.Close()
+
+	t.Run("Sales cannot manage API keys (403)", func(t *testing.T) {
+		resp := createKey(t, sales.URL, dto.CreateAPIKeyDTO{Name: "Orders", Scopes: []string{"orders:read"}})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
@@ -0,0 +1,178 @@
+package auditlog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func do(t *testing.T, method string, url string, reqBody any) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	if reqBody != nil {
+		_ = json.NewEncoder(&body).Encode(reqBody)
+	}
+	req, _ := http.NewRequest(method, url, &body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func filterAuditLogs(t *testing.T, url string, query string) []model.AuditEntry {
+	t.Helper()
+	resp, err := http.Get(url + "/api/v1/audit-logs?" + query)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var out response.APIResponse[response.FilterResponse[model.AuditEntry]]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out.Data.Items
+}
+
+func TestAuditLog(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "AUDIT-SKU")
+	variant := product.Variants[0]
+
+	t.Run("Update variant price - recorded with before and after", func(t *testing.T) {
+		price := money.MustParse("12.50")
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/products/%d/variants/%d", ts.URL, product.ID, variant.ID), dto.UpdateVariantDTO{Price: &price})
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		entries := filterAuditLogs(t, ts.URL, fmt.Sprintf("entity_type=variants&entity_id=%d&action=update", variant.ID))
+		require.Len(t, entries, 1)
+		entry := entries[0]
+
+		assert.Equal(t, setup.DefaultOrgID, entry.OrgID)
+		require.NotNil(t, entry.ActorID)
+		assert.Equal(t, setup.DefaultUserID, *entry.ActorID)
+		assert.Nil(t, entry.APIKeyID)
+		assert.NotEmpty(t, entry.RequestID)
+		assert.Equal(t, "127.0.0.1", entry.IP)
+
+		assert.Equal(t, map[string]model.AuditChange{"price": {Before: 10.0, After: 12.5}}, entry.Changes)
+	})
+
+	t.Run("Create and delete customer - recorded", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/customers", dto.CreateCustomerDTO{
+			FirstName:   "Audit",
+			LastName:    "Trail",
+			PhoneNumber: "+1-202-555-0142",
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created response.APIResponse[model.Customer]
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		resp.Body.Close()
+		customerID := created.Data.ID
+
+		resp = do(t, http.MethodDelete, fmt.Sprintf("%s/api/v1/customers/%d", ts.URL, customerID), nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		entries := filterAuditLogs(t, ts.URL, fmt.Sprintf("entity_type=customers&entity_id=%d", customerID))
+		require.Len(t, entries, 2)
+
+		// Newest first
+		assert.Equal(t, model.AuditActionDelete, entries[0].Action)
+		assert.Equal(t, "Audit", entries[0].Changes["first_name"].Before)
+		assert.Nil(t, entries[0].Changes["first_name"].After)
+
+		assert.Equal(t, model.AuditActionCreate, entries[1].Action)
+		assert.Nil(t, entries[1].Changes["first_name"].Before)
+		assert.Equal(t, "Audit", entries[1].Changes["first_name"].After)
+	})
+
+	t.Run("Create webhook subscription - secret not recorded", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/webhook-subscriptions", dto.CreateWebhookSubscriptionDTO{
+			URL:        "https://erp.example.com/hooks",
+			EventTypes: []string{types.OrderCreatedEventType},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created response.APIResponse[model.WebhookSubscription]
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		resp.Body.Close()
+		require.NotEmpty(t, created.Data.Secret)
+
+		entries := filterAuditLogs(t, ts.URL, fmt.Sprintf("entity_type=webhook_subscriptions&entity_id=%d", created.Data.ID))
+		require.Len(t, entries, 1)
+		assert.Equal(t, "https://erp.example.com/hooks", entries[0].Changes["url"].After)
+		assert.NotContains(t, entries[0].Changes, "secret")
+	})
+
+	t.Run("Edit order notes - items not recorded", func(t *testing.T) {
+		seed.InsertOrgWithID(db, setup.DefaultOrgID)
+		customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/orders", dto.CreateOrderDTO{
+			CustomerID: customer.ID,
+			Items:      []dto.CreateOrderItemDTO{{VariantID: variant.ID, Quantity: 1}},
+			Delivery: dto.CreateDeliveryInfoDTO{
+				Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+			},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created response.APIResponse[model.Order]
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		resp.Body.Close()
+		order := created.Data
+		require.Len(t, order.Items, 1)
+
+		notes := "Leave at the back door"
+		resp = do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, order.ID), dto.UpdateOrderDTO{Notes: &notes})
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		entries := filterAuditLogs(t, ts.URL, fmt.Sprintf("entity_type=orders&entity_id=%d&action=update", order.ID))
+		require.Len(t, entries, 1)
+		assert.Equal(t, notes, entries[0].Changes["notes"].After)
+
+		entries = filterAuditLogs(t, ts.URL, fmt.Sprintf("entity_type=order_items&entity_id=%d", order.Items[0].ID))
+		require.Len(t, entries, 1)
+		assert.Equal(t, model.AuditActionCreate, entries[0].Action)
+	})
+
+	t.Run("Audit log is per org", func(t *testing.T) {
+		other := setup.SetupTestServerForOrg(setup.DefaultUserID, 2)
+		defer other.Close()
+
+		entries := filterAuditLogs(t, other.URL, fmt.Sprintf("entity_type=variants&entity_id=%d", variant.ID))
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Get audit log entry", func(t *testing.T) {
+		entries := filterAuditLogs(t, ts.URL, "entity_type=variants&limit=1")
+		require.Len(t, entries, 1)
+
+		resp, err := http.Get(fmt.Sprintf("%s/api/v1/audit-logs/%d", ts.URL, entries[0].ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Sales cannot read audit logs (403)", func(t *testing.T) {
+		sales := setup.SetupTestServerAs(setup.DefaultUserID, setup.DefaultOrgID, model.RoleSales)
+		defer sales.Close()
+
+		resp, err := http.Get(sales.URL + "/api/v1/audit-logs")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
@@ -0,0 +1,206 @@
+package backorder_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func do(t *testing.T, method string, url string, reqBody any) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	if reqBody != nil {
+		_ = json.NewEncoder(&body).Encode(reqBody)
+	}
+	req, _ := http.NewRequest(method, url, &body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func createOrder(t *testing.T, url string, customerID uint, variantID uint, quantity int) *http.Response {
+	t.Helper()
+	return do(t, http.MethodPost, url+"/api/v1/orders", dto.CreateOrderDTO{
+		CustomerID: customerID,
+		Items:      []dto.CreateOrderItemDTO{{VariantID: variantID, Quantity: quantity}},
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	})
+}
+
+func getItem(t *testing.T, url string, orderID uint) model.OrderItem {
+	t.Helper()
+	resp := do(t, http.MethodGet, fmt.Sprintf("%s/api/v1/orders/%d", url, orderID), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	order := decode[model.Order](t, resp).Data
+	require.Len(t, order.Items, 1)
+	return order.Items[0]
+}
+
+func findVariant(t *testing.T, db *gorm.DB, ID uint) model.Variant {
+	t.Helper()
+	var variant model.Variant
+	require.NoError(t, db.First(&variant, ID).Error)
+	return variant
+}
+
+func TestBackorders(t *testing.T) {
+	ts, worker := setup.SetupTestServerWithWorker(setup.DefaultUserID, setup.DefaultOrgID, model.RoleOwner)
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	jane := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	john := model.Customer{FirstName: "John", LastName: "Doe", PhoneNumber: "+1-202-555-0101", OrgID: setup.DefaultOrgID}
+	require.NoError(t, db.Create(&john).Error)
+
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "BACK-SKU") // stock 10
+	variant := product.Variants[0]
+	strict := seed.InsertProductForOrg(db, setup.DefaultOrgID, "STRICT-SKU").Variants[0]
+
+	variantURL := fmt.Sprintf("%s/api/v1/products/%d/variants/%d", ts.URL, product.ID, variant.ID)
+	allow := true
+	resp := do(t, http.MethodPatch, variantURL, dto.UpdateVariantDTO{AllowBackorder: &allow})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, decode[model.Variant](t, resp).Data.AllowBackorder)
+
+	t.Run("Create order - variant without backorders is still short (409)", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, jane.ID, strict.ID, 11)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	var first, second, third model.Order
+	t.Run("Create order - the rest is backordered", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, jane.ID, variant.ID, 15)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		first = decode[model.Order](t, resp).Data
+
+		item := first.Items[0]
+		assert.Equal(t, 15, item.Quantity)
+		assert.Equal(t, 10, item.AllocatedQuantity)
+		assert.Equal(t, 5, item.BackorderedQuantity)
+
+		v := findVariant(t, db, variant.ID)
+		assert.Equal(t, 10, v.Stock)
+		assert.Equal(t, 10, v.Reserved)
+	})
+
+	t.Run("Create order - out of stock is backordered in full", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, john.ID, variant.ID, 3)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		second = decode[model.Order](t, resp).Data
+		assert.Equal(t, 0, second.Items[0].AllocatedQuantity)
+		assert.Equal(t, 3, second.Items[0].BackorderedQuantity)
+
+		resp = createOrder(t, ts.URL, jane.ID, variant.ID, 2)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		third = decode[model.Order](t, resp).Data
+	})
+
+	t.Run("Backorder report - by variant and customer", func(t *testing.T) {
+		resp := do(t, http.MethodGet, ts.URL+"/api/v1/backorders", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		report := decode[types.BackorderReport](t, resp).Data
+
+		assert.Equal(t, 10, report.BackorderedQuantity)
+		require.Len(t, report.Lines, 2)
+		assert.Equal(t, jane.ID, report.Lines[0].CustomerID)
+		assert.Equal(t, "Jane Roe", report.Lines[0].CustomerName)
+		assert.Equal(t, 2, report.Lines[0].Orders)
+		assert.Equal(t, 7, report.Lines[0].BackorderedQuantity)
+		assert.Equal(t, 10, report.Lines[0].Stock)
+		assert.Equal(t, 0, report.Lines[0].Available)
+		assert.Equal(t, john.ID, report.Lines[1].CustomerID)
+		assert.Equal(t, 3, report.Lines[1].BackorderedQuantity)
+
+		resp = do(t, http.MethodGet, fmt.Sprintf("%s/api/v1/backorders?customer_id=%d", ts.URL, john.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		report = decode[types.BackorderReport](t, resp).Data
+		require.Len(t, report.Lines, 1)
+		assert.Equal(t, 3, report.BackorderedQuantity)
+
+		resp = do(t, http.MethodGet, ts.URL+"/api/v1/backorders?variant_id=abc", nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	t.Run("Ship - backordered quantities can't ship", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/api/v1/orders/%d/approve", ts.URL, second.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		decode[any](t, resp)
+
+		resp = do(t, http.MethodPost, ts.URL+"/api/v1/shipments", dto.CreateShipmentDTO{OrderID: second.ID})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	t.Run("Restock - backorders are allocated oldest first", func(t *testing.T) {
+		stock := 17
+		resp := do(t, http.MethodPatch, variantURL, dto.UpdateVariantDTO{Stock: &stock})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		decode[any](t, resp)
+		worker.Drain(t)
+
+		// The pending first order reserves its 5...
+		item := getItem(t, ts.URL, first.ID)
+		assert.Equal(t, 15, item.AllocatedQuantity)
+		assert.Equal(t, 0, item.BackorderedQuantity)
+
+		// ...the approved second order has the other 2 deducted...
+		item = getItem(t, ts.URL, second.ID)
+		assert.Equal(t, 2, item.AllocatedQuantity)
+		assert.Equal(t, 1, item.BackorderedQuantity)
+
+		// ...and nothing is left for the third
+		item = getItem(t, ts.URL, third.ID)
+		assert.Equal(t, 0, item.AllocatedQuantity)
+		assert.Equal(t, 2, item.BackorderedQuantity)
+
+		v := findVariant(t, db, variant.ID)
+		assert.Equal(t, 15, v.Stock)
+		assert.Equal(t, 15, v.Reserved)
+	})
+
+	t.Run("Cancel order - its stock goes to the backorders", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/api/v1/orders/%d/cancel", ts.URL, first.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		decode[any](t, resp)
+		worker.Drain(t)
+
+		assert.Equal(t, 3, getItem(t, ts.URL, second.ID).AllocatedQuantity)
+		assert.Equal(t, 2, getItem(t, ts.URL, third.ID).AllocatedQuantity)
+
+		resp = do(t, http.MethodGet, ts.URL+"/api/v1/backorders", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		report := decode[types.BackorderReport](t, resp).Data
+		assert.Equal(t, 0, report.BackorderedQuantity)
+		assert.Empty(t, report.Lines)
+
+		v := findVariant(t, db, variant.ID)
+		assert.Equal(t, 14, v.Stock)
+		assert.Equal(t, 2, v.Reserved)
+	})
+}
@@ -0,0 +1,173 @@
+package charge_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func do(t *testing.T, method string, url string, reqBody any) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	if reqBody != nil {
+		_ = json.NewEncoder(&body).Encode(reqBody)
+	}
+	req, _ := http.NewRequest(method, url, &body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func createOrder(t *testing.T, url string, customerID uint, variantID uint, fare money.Money, charges []dto.CreateOrderChargeDTO) *http.Response {
+	t.Helper()
+	return do(t, http.MethodPost, url+"/api/v1/orders", dto.CreateOrderDTO{
+		CustomerID: customerID,
+		Items:      []dto.CreateOrderItemDTO{{VariantID: variantID, Quantity: 1}},
+		Charges:    charges,
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address:       dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+			TransportFare: fare,
+		},
+	})
+}
+
+func TestCharges(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "CHARGE-SKU")
+	variantID := product.Variants[0].ID
+	require.NoError(t, db.Model(&model.Variant{}).Where("id = ?", variantID).Update("stock", 100).Error)
+
+	resp := do(t, http.MethodPost, ts.URL+"/api/v1/tax-rates", dto.CreateTaxRateDTO{Name: "VAT", Rate: 0.1})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	vat := decode[model.TaxRate](t, resp).Data
+	resp = do(t, http.MethodPost, ts.URL+"/api/v1/tax-classes", dto.CreateTaxClassDTO{Name: "Standard", RateIDs: []uint{vat.ID}})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	standard := decode[model.TaxClass](t, resp).Data
+
+	t.Run("Create order - transport fare is charged", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, variantID, money.FromInt(5), nil)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		order := decode[model.Order](t, resp).Data
+
+		require.Len(t, order.Charges, 1)
+		assert.Equal(t, model.ChargeDelivery, order.Charges[0].Type)
+		assert.Equal(t, money.FromInt(5), order.Charges[0].Total)
+		assert.Equal(t, money.FromInt(5), order.ChargeTotal)
+		assert.Equal(t, money.FromInt(5), order.Delivery.TransportFare)
+		assert.Equal(t, money.FromInt(15), order.Total)
+	})
+
+	t.Run("Create order - unknown tax class (400)", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, variantID, money.Zero, []dto.CreateOrderChargeDTO{
+			{Type: model.ChargeHandling, Amount: money.FromInt(2), TaxClassID: func() *uint { ID := uint(999); return &ID }()},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrUnknownChargeTaxClass, decode[any](t, resp).Message)
+	})
+
+	resp = createOrder(t, ts.URL, customer.ID, variantID, money.Zero, []dto.CreateOrderChargeDTO{
+		{Type: model.ChargeDelivery, Description: "Express delivery", Amount: money.FromInt(20), TaxClassID: &standard.ID},
+		{Type: model.ChargePackaging, Amount: money.FromInt(3)},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	order := decode[model.Order](t, resp).Data
+
+	t.Run("Create order - taxable charges", func(t *testing.T) {
+		require.Len(t, order.Charges, 2)
+		assert.Equal(t, money.FromInt(2), order.Charges[0].TaxAmount)
+		assert.Equal(t, money.FromInt(22), order.Charges[0].Total)
+		assert.Equal(t, money.Zero, order.Charges[1].TaxAmount)
+		assert.Equal(t, money.FromInt(23), order.ChargeTotal)
+		assert.Equal(t, money.FromInt(20), order.Delivery.TransportFare)
+		assert.Equal(t, money.FromInt(2), order.TaxTotal)
+		assert.Equal(t, money.FromInt(35), order.Total)
+	})
+
+	t.Run("Update order - transport fare replaces the delivery charge", func(t *testing.T) {
+		fare := money.FromInt(30)
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, order.ID), dto.UpdateOrderDTO{
+			Delivery: &dto.UpdateDeliveryInfoDTO{TransportFare: &fare},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		decode[any](t, resp)
+
+		resp = do(t, http.MethodGet, fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, order.ID), nil)
+		updated := decode[model.Order](t, resp).Data
+
+		// The delivery charge keeps its description and tax class
+		require.Len(t, updated.Charges, 2)
+		var delivery model.OrderCharge
+		for _, charge := range updated.Charges {
+			if charge.Type == model.ChargeDelivery {
+				delivery = charge
+			}
+		}
+		assert.Equal(t, "Express delivery", delivery.Description)
+		assert.Equal(t, money.FromInt(33), delivery.Total)
+		assert.Equal(t, money.FromInt(33), updated.ChargeTotal)
+		assert.Equal(t, money.FromInt(46), updated.Total)
+	})
+
+	t.Run("Update order - remove charges", func(t *testing.T) {
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, order.ID), dto.UpdateOrderDTO{
+			Charges: []dto.CreateOrderChargeDTO{},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		updated := decode[model.Order](t, resp).Data
+
+		assert.Empty(t, updated.Charges)
+		assert.Equal(t, money.Zero, updated.Delivery.TransportFare)
+		assert.Equal(t, money.FromInt(10), updated.Total)
+
+		// Put them back for the invoice
+		resp = do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, order.ID), dto.UpdateOrderDTO{
+			Charges: []dto.CreateOrderChargeDTO{
+				{Type: model.ChargeDelivery, Amount: money.FromInt(20), TaxClassID: &standard.ID},
+				{Type: model.ChargePackaging, Amount: money.FromInt(3)},
+			},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, money.FromInt(35), decode[model.Order](t, resp).Data.Total)
+	})
+
+	t.Run("Create invoice - charges are invoiced lines", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/invoices", dto.CreateInvoiceDTO{OrderID: order.ID})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		invoice := decode[model.Invoice](t, resp).Data
+
+		require.Len(t, invoice.Charges, 2)
+		assert.Equal(t, model.ChargeDelivery, invoice.Charges[0].Type)
+		assert.Equal(t, money.FromInt(2), invoice.Charges[0].TaxAmount)
+		assert.Equal(t, money.FromInt(22), invoice.Charges[0].Total)
+		assert.Equal(t, money.FromInt(3), invoice.Charges[1].Total)
+		assert.Equal(t, money.FromInt(23), invoice.ChargeTotal)
+		assert.Equal(t, money.FromInt(35), invoice.Total)
+		assert.Equal(t, money.FromInt(35), invoice.AmountDue)
+	})
+}
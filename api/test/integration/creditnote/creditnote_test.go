@@ -0,0 +1,192 @@
+package creditnote_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func send(t *testing.T, method, url string, reqBody any) *http.Response {
+	t.Helper()
+	var body []byte
+	if reqBody != nil {
+		body, _ = json.Marshal(reqBody)
+	}
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func TestCreditNotes(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+	api := ts.URL + "/api/v1"
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "CREDIT-SKU")
+	variantID := product.Variants[0].ID // stock 10
+
+	resp := send(t, http.MethodPost, api+"/orders", dto.CreateOrderDTO{
+		CustomerID: customer.ID,
+		Items:      []dto.CreateOrderItemDTO{{VariantID: variantID, Quantity: 4}},
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	orderID := decode[model.Order](t, resp).Data.ID
+
+	resp = send(t, http.MethodPost, fmt.Sprintf("%s/orders/%d/approve", api, orderID), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	createInvoice := func(t *testing.T) model.Invoice {
+		resp := send(t, http.MethodPost, api+"/invoices", dto.CreateInvoiceDTO{OrderID: orderID})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		return decode[model.Invoice](t, resp).Data
+	}
+	findInvoice := func(t *testing.T, id uint) model.Invoice {
+		var inv model.Invoice
+		assert.NoError(t, db.First(&inv, id).Error)
+		return inv
+	}
+
+	draft := createInvoice(t)
+	invoice := createInvoice(t)
+	resp = send(t, http.MethodPost, fmt.Sprintf("%s/invoices/%d/issue", api, invoice.ID), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+	require.Len(t, invoice.Items, 1)
+	invoiceItemID := invoice.Items[0].ID
+	unitTotal := invoice.Total.MulFrac(1, 4, money.HalfUp)
+
+	t.Run("Create credit note - draft invoice (409)", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/credit-notes", dto.CreateCreditNoteDTO{InvoiceID: draft.ID, Reason: "Damaged"})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrInvoiceNotIssued, decode[any](t, resp).Message)
+	})
+
+	t.Run("Create credit note - missing reason (400)", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/credit-notes", dto.CreateCreditNoteDTO{InvoiceID: invoice.ID})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Create credit note - item not on invoice (400)", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/credit-notes", dto.CreateCreditNoteDTO{
+			InvoiceID: invoice.ID,
+			Reason:    "Damaged",
+			Items:     []dto.CreateCreditNoteItemDTO{{InvoiceItemID: 9999, Quantity: 1}},
+		})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Create credit note - more than invoiced (400)", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/credit-notes", dto.CreateCreditNoteDTO{
+			InvoiceID: invoice.ID,
+			Reason:    "Damaged",
+			Items:     []dto.CreateCreditNoteItemDTO{{InvoiceItemID: invoiceItemID, Quantity: 5}},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "credit quantity exceeds the invoiced quantity: CREDIT-SKU (invoiced 4, credited 0, requested 5)", decode[any](t, resp).Message)
+	})
+
+	var creditNoteID uint
+	t.Run("Create credit note - partial credit with restock", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/credit-notes", dto.CreateCreditNoteDTO{
+			InvoiceID: invoice.ID,
+			Reason:    "Returned",
+			Restock:   true,
+			Items:     []dto.CreateCreditNoteItemDTO{{InvoiceItemID: invoiceItemID, Quantity: 1}},
+		})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		creditNote := decode[model.CreditNote](t, resp).Data
+		creditNoteID = creditNote.ID
+		assert.NotEmpty(t, creditNote.CreditNoteNumber)
+		assert.Equal(t, unitTotal, creditNote.Total)
+
+		inv := findInvoice(t, invoice.ID)
+		assert.Equal(t, model.InvoiceStatusIssued, inv.Status)
+		assert.Equal(t, unitTotal, inv.AmountCredited)
+		assert.Equal(t, invoice.Total.Sub(unitTotal), inv.AmountDue)
+
+		var variant model.Variant
+		assert.NoError(t, db.First(&variant, variantID).Error)
+		assert.Equal(t, 7, variant.Stock)
+	})
+
+	t.Run("Create credit note - paid invoice credits the customer", func(t *testing.T) {
+		due := findInvoice(t, invoice.ID).AmountDue
+		resp := send(t, http.MethodPost, api+"/payments", dto.CreatePaymentDTO{InvoiceID: invoice.ID, Amount: due, Method: model.PaymentMethodCash})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp.Body.Close()
+		require.Equal(t, model.InvoiceStatusPaid, findInvoice(t, invoice.ID).Status)
+
+		// Credits the 3 units left
+		resp = send(t, http.MethodPost, api+"/credit-notes", dto.CreateCreditNoteDTO{InvoiceID: invoice.ID, Reason: "Order cancelled by customer"})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		creditNote := decode[model.CreditNote](t, resp).Data
+		assert.Len(t, creditNote.Items, 1)
+		assert.Equal(t, 3, creditNote.Items[0].Quantity)
+
+		inv := findInvoice(t, invoice.ID)
+		assert.Equal(t, model.InvoiceStatusPaid, inv.Status)
+		assert.Equal(t, money.Zero, inv.AmountDue)
+		assert.Equal(t, money.Zero, inv.AmountPaid)
+
+		var c model.Customer
+		assert.NoError(t, db.First(&c, customer.ID).Error)
+		assert.Equal(t, due, c.CreditBalance)
+	})
+
+	t.Run("Create credit note - nothing left to credit (409)", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/credit-notes", dto.CreateCreditNoteDTO{InvoiceID: invoice.ID, Reason: "Again"})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrNothingToCredit, decode[any](t, resp).Message)
+	})
+
+	t.Run("Get credit note - success", func(t *testing.T) {
+		resp := send(t, http.MethodGet, fmt.Sprintf("%s/credit-notes/%d", api, creditNoteID), nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		creditNote := decode[model.CreditNote](t, resp).Data
+		assert.Len(t, creditNote.Items, 1)
+		assert.Equal(t, invoice.ID, creditNote.Invoice.ID)
+	})
+
+	t.Run("Filter credit notes - by invoice", func(t *testing.T) {
+		resp := send(t, http.MethodGet, fmt.Sprintf("%s/credit-notes?invoice_id=%d", api, invoice.ID), nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, decode[response.FilterResponse[model.CreditNote]](t, resp).Data.Items, 2)
+	})
+
+	t.Run("Send credit note - not found (404)", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/credit-notes/9999/send", nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
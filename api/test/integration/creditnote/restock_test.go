@@ -0,0 +1,112 @@
+package creditnote_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreditNoteRestock(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+	api := ts.URL + "/api/v1"
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+
+	// issuedInvoice orders quantity of a new variant with stock 10 and issues
+	// an invoice for the order.
+	issuedInvoice := func(t *testing.T, sku string, quantity int) (model.Order, model.Invoice) {
+		t.Helper()
+		product := seed.InsertProductForOrg(db, setup.DefaultOrgID, sku)
+		resp := send(t, http.MethodPost, api+"/orders", dto.CreateOrderDTO{
+			CustomerID: customer.ID,
+			Items:      []dto.CreateOrderItemDTO{{VariantID: product.Variants[0].ID, Quantity: quantity}},
+			Delivery: dto.CreateDeliveryInfoDTO{
+				Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+			},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		order := decode[model.Order](t, resp).Data
+
+		resp = send(t, http.MethodPost, api+"/invoices", dto.CreateInvoiceDTO{OrderID: order.ID})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		invoice := decode[model.Invoice](t, resp).Data
+		resp = send(t, http.MethodPost, fmt.Sprintf("%s/invoices/%d/issue", api, invoice.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+		return order, invoice
+	}
+	creditWithRestock := func(t *testing.T, invoice model.Invoice, quantity int) {
+		t.Helper()
+		resp := send(t, http.MethodPost, api+"/credit-notes", dto.CreateCreditNoteDTO{
+			InvoiceID: invoice.ID,
+			Reason:    "Returned",
+			Restock:   true,
+			Items:     []dto.CreateCreditNoteItemDTO{{InvoiceItemID: invoice.Items[0].ID, Quantity: quantity}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp.Body.Close()
+	}
+	findVariant := func(t *testing.T, order model.Order) model.Variant {
+		t.Helper()
+		var variant model.Variant
+		require.NoError(t, db.First(&variant, order.Items[0].VariantID).Error)
+		return variant
+	}
+
+	t.Run("Restock on pending order - stock was only reserved", func(t *testing.T) {
+		order, invoice := issuedInvoice(t, "RESTOCK-PENDING-SKU", 3)
+
+		creditWithRestock(t, invoice, 1)
+
+		variant := findVariant(t, order)
+		assert.Equal(t, 10, variant.Stock)
+		assert.Equal(t, 3, variant.Reserved)
+	})
+
+	t.Run("Restock then cancel approved order - stock returned once", func(t *testing.T) {
+		order, invoice := issuedInvoice(t, "RESTOCK-CANCEL-SKU", 4)
+		resp := send(t, http.MethodPost, fmt.Sprintf("%s/orders/%d/approve", api, order.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+		require.Equal(t, 6, findVariant(t, order).Stock)
+
+		creditWithRestock(t, invoice, 2)
+		assert.Equal(t, 8, findVariant(t, order).Stock)
+
+		resp = send(t, http.MethodPost, fmt.Sprintf("%s/orders/%d/cancel", api, order.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+
+		variant := findVariant(t, order)
+		assert.Equal(t, 10, variant.Stock)
+		assert.Equal(t, 0, variant.Reserved)
+
+		var item model.OrderItem
+		require.NoError(t, db.First(&item, order.Items[0].ID).Error)
+		assert.Equal(t, 2, item.RestockedQuantity)
+	})
+
+	t.Run("Restock on cancelled order - nothing shipped to take back", func(t *testing.T) {
+		order, invoice := issuedInvoice(t, "RESTOCK-CANCELLED-SKU", 2)
+		resp := send(t, http.MethodPost, fmt.Sprintf("%s/orders/%d/approve", api, order.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+		resp = send(t, http.MethodPost, fmt.Sprintf("%s/orders/%d/cancel", api, order.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+
+		creditWithRestock(t, invoice, 2)
+
+		assert.Equal(t, 10, findVariant(t, order).Stock)
+	})
+}
@@ -0,0 +1,75 @@
+package customer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomerTenantIsolation(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.ClearCustomers(db)
+	defer seed.ClearCustomers(db)
+
+	own := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	foreign := seed.InsertCustomerForOrg(db, 2)
+	foreignURL := fmt.Sprintf("%s/api/v1/customers/%d", ts.URL, foreign.ID)
+
+	t.Run("Get customer of another org - not found (404)", func(t *testing.T) {
+		resp, err := http.Get(foreignURL)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Update customer of another org - not found (404)", func(t *testing.T) {
+		firstName := "Hijacked"
+		body, _ := json.Marshal(dto.UpdateCustomerDTO{FirstName: &firstName})
+		req, _ := http.NewRequest(http.MethodPatch, foreignURL, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Delete customer of another org - not found (404)", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, foreignURL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Filter customers - only own org", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/v1/customers")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var customers response.APIResponse[response.FilterResponse[model.Customer]]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&customers))
+		assert.Len(t, customers.Data.Items, 1)
+		assert.Equal(t, own.ID, customers.Data.Items[0].ID)
+		assert.Equal(t, int64(1), customers.Data.Pagination.Total)
+	})
+
+	t.Run("Customer of another org is untouched", func(t *testing.T) {
+		var stored model.Customer
+		assert.NoError(t, db.First(&stored, foreign.ID).Error)
+		assert.Equal(t, "Jane", stored.FirstName)
+		assert.Equal(t, uint(2), stored.OrgID)
+	})
+}
@@ -0,0 +1,144 @@
+package exchangerate_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postJSON(t *testing.T, url string, reqBody any) *http.Response {
+	t.Helper()
+	body, _ := json.Marshal(reqBody)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func createOrder(t *testing.T, url string, customerID uint, currency string, variantID uint) *http.Response {
+	t.Helper()
+	return postJSON(t, url+"/api/v1/orders", dto.CreateOrderDTO{
+		CustomerID: customerID,
+		Currency:   currency,
+		Items:      []dto.CreateOrderItemDTO{{VariantID: variantID, Quantity: 2}},
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	})
+}
+
+func issueInvoice(t *testing.T, url string, orderID uint) model.Invoice {
+	t.Helper()
+	resp := postJSON(t, url+"/api/v1/invoices", dto.CreateInvoiceDTO{OrderID: orderID})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	invoice := decode[model.Invoice](t, resp).Data
+
+	resp, err := http.Post(fmt.Sprintf("%s/api/v1/invoices/%d/issue", url, invoice.ID), "application/json", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	return decode[model.Invoice](t, resp).Data
+}
+
+func TestMultiCurrency(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "FX-SKU")
+	variant := product.Variants[0]
+	variant.Price = money.FromInt(3000)
+	variant.Prices = map[string]money.Money{"EUR": money.MustParse("2.50")}
+	require.NoError(t, db.Save(&variant).Error)
+
+	t.Run("Create rate for the base currency - bad request (400)", func(t *testing.T) {
+		resp := postJSON(t, ts.URL+"/api/v1/exchange-rates", dto.CreateExchangeRateDTO{Currency: "NGN", Rate: 1})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrSameCurrencyRate, decode[any](t, resp).Message)
+	})
+
+	t.Run("Create order without a rate - bad request (400)", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, "USD", variant.ID)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, decode[any](t, resp).Message, apperrors.ErrNoExchangeRate)
+	})
+
+	for _, rate := range []dto.CreateExchangeRateDTO{{Currency: "USD", Rate: 1500}, {Currency: "EUR", Rate: 1600}} {
+		resp := postJSON(t, ts.URL+"/api/v1/exchange-rates", rate)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		created := decode[model.ExchangeRate](t, resp).Data
+		assert.Equal(t, "NGN", created.BaseCurrency)
+	}
+
+	var usdOrder model.Order
+	t.Run("Create order in a converted currency - success", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, "USD", variant.ID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		usdOrder = decode[model.Order](t, resp).Data
+		assert.Equal(t, "USD", usdOrder.Currency)
+		assert.Equal(t, 1500.0, usdOrder.ExchangeRate)
+		assert.Equal(t, money.FromInt(2), usdOrder.Items[0].UnitPrice)
+	})
+
+	t.Run("Create order in a currency the variant is priced in - success", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, "EUR", variant.ID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		order := decode[model.Order](t, resp).Data
+		assert.Equal(t, 1600.0, order.ExchangeRate)
+		assert.Equal(t, money.MustParse("2.50"), order.Items[0].UnitPrice)
+	})
+
+	t.Run("Sales report - converted to the base currency", func(t *testing.T) {
+		usdInvoice := issueInvoice(t, ts.URL, usdOrder.ID)
+		assert.Equal(t, "USD", usdInvoice.Currency)
+		assert.Equal(t, 1500.0, usdInvoice.ExchangeRate)
+
+		resp := createOrder(t, ts.URL, customer.ID, "", variant.ID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		ngnInvoice := issueInvoice(t, ts.URL, decode[model.Order](t, resp).Data.ID)
+		assert.Equal(t, "NGN", ngnInvoice.Currency)
+
+		resp, err := http.Get(ts.URL + "/api/v1/reports/sales")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		report := decode[types.SalesReport](t, resp).Data
+
+		usdInBase := usdInvoice.Total.MulRate(1500, money.HalfUp)
+		assert.Equal(t, "NGN", report.BaseCurrency)
+		assert.Equal(t, 2, report.Invoices)
+		assert.Equal(t, usdInBase.Add(ngnInvoice.Total), report.Total)
+		require.Len(t, report.Currencies, 2)
+		assert.Equal(t, "NGN", report.Currencies[0].Currency)
+		assert.Equal(t, "USD", report.Currencies[1].Currency)
+		assert.Equal(t, usdInvoice.Total, report.Currencies[1].Total)
+		assert.Equal(t, usdInBase, report.Currencies[1].BaseTotal)
+	})
+
+	t.Run("Sales report with an invalid period - bad request (400)", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/v1/reports/sales?from=2026-02-01&to=2026-01-01")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrInvalidReportPeriod, decode[any](t, resp).Message)
+	})
+}
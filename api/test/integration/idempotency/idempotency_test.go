@@ -0,0 +1,151 @@
+package idempotency_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func postWithKey(t *testing.T, url string, key string, reqBody any) *http.Response {
+	t.Helper()
+	body, _ := json.Marshal(reqBody)
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestIdempotentOrderCreation(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "IDEMPOTENT-SKU")
+
+	orderDTO := func(quantity int) dto.CreateOrderDTO {
+		return dto.CreateOrderDTO{
+			CustomerID: customer.ID,
+			Items:      []dto.CreateOrderItemDTO{{VariantID: product.Variants[0].ID, Quantity: quantity}},
+			Delivery: dto.CreateDeliveryInfoDTO{
+				Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+			},
+		}
+	}
+	countOrders := func() int64 {
+		var count int64
+		db.Model(&model.Order{}).Where("customer_id = ?", customer.ID).Count(&count)
+		return count
+	}
+
+	var first []byte
+	t.Run("First request - creates order", func(t *testing.T) {
+		resp := postWithKey(t, ts.URL+"/api/v1/orders", "order-1", orderDTO(2))
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+
+		first, _ = io.ReadAll(resp.Body)
+		assert.Equal(t, int64(1), countOrders())
+	})
+
+	t.Run("Retry - replays response", func(t *testing.T) {
+		resp := postWithKey(t, ts.URL+"/api/v1/orders", "order-1", orderDTO(2))
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, first, body)
+		assert.Equal(t, int64(1), countOrders())
+		assert.Equal(t, 2, findVariant(t, db, product.Variants[0].ID).Reserved)
+	})
+
+	t.Run("Reuse key for a different request (409)", func(t *testing.T) {
+		resp := postWithKey(t, ts.URL+"/api/v1/orders", "order-1", orderDTO(3))
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		var apiErr response.APIResponse[any]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&apiErr))
+		assert.Equal(t, "idempotency key was already used for a different request", apiErr.Message)
+		assert.Equal(t, int64(1), countOrders())
+	})
+
+	t.Run("Different key - creates another order", func(t *testing.T) {
+		resp := postWithKey(t, ts.URL+"/api/v1/orders", "order-2", orderDTO(2))
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, int64(2), countOrders())
+	})
+
+	t.Run("No key - not idempotent", func(t *testing.T) {
+		for range 2 {
+			resp := postWithKey(t, ts.URL+"/api/v1/orders", "", orderDTO(1))
+			resp.Body.Close()
+			assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		}
+		assert.Equal(t, int64(4), countOrders())
+	})
+
+	t.Run("Keys are per org", func(t *testing.T) {
+		other := setup.SetupTestServerForOrg(setup.DefaultUserID, 2)
+		defer other.Close()
+
+		resp := postWithKey(t, other.URL+"/api/v1/customers", "order-1", dto.CreateCustomerDTO{
+			FirstName:   "John",
+			LastName:    "Doe",
+			PhoneNumber: "+1-202-555-0199",
+		})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	})
+
+	t.Run("Keys are per user - not replayed for another user of the org (409)", func(t *testing.T) {
+		viewer := setup.SetupTestServerAs(setup.DefaultUserID+1, setup.DefaultOrgID, model.RoleViewer)
+		defer viewer.Close()
+
+		resp := postWithKey(t, viewer.URL+"/api/v1/orders", "order-1", orderDTO(2))
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.NotEqual(t, first, body)
+	})
+
+	t.Run("Failed requests are stored too", func(t *testing.T) {
+		resp := postWithKey(t, ts.URL+"/api/v1/orders", "order-3", orderDTO(100))
+		resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		var record model.IdempotencyKey
+		require.NoError(t, db.Where("org_id = ? AND key = ?", setup.DefaultOrgID, "order-3").First(&record).Error)
+		assert.Equal(t, http.StatusConflict, record.Status)
+		assert.True(t, record.Completed())
+	})
+}
+
+func findVariant(t *testing.T, db *gorm.DB, id uint) model.Variant {
+	t.Helper()
+	var variant model.Variant
+	assert.NoError(t, db.First(&variant, id).Error)
+	return variant
+}
@@ -0,0 +1,165 @@
+package invoice_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+)
+
+func postJSON(t *testing.T, url string, reqBody any) *http.Response {
+	t.Helper()
+	body, _ := json.Marshal(reqBody)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func createInvoice(t *testing.T, url string, orderID uint) model.Invoice {
+	t.Helper()
+	resp := postJSON(t, url+"/api/v1/invoices", dto.CreateInvoiceDTO{OrderID: orderID, Notes: "first draft"})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	return decode[model.Invoice](t, resp).Data
+}
+
+func invoiceAction(t *testing.T, url string, id uint, action string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(fmt.Sprintf("%s/api/v1/invoices/%d/%s", url, id, action), "application/json", nil)
+	assert.NoError(t, err)
+	return resp
+}
+
+func updateInvoice(t *testing.T, url string, id uint, notes string) *http.Response {
+	t.Helper()
+	body, _ := json.Marshal(dto.UpdateInvoiceDTO{Notes: &notes})
+	req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v1/invoices/%d", url, id), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestInvoiceLifecycle(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "INVOICE-SKU")
+
+	resp := postJSON(t, ts.URL+"/api/v1/orders", dto.CreateOrderDTO{
+		CustomerID: customer.ID,
+		Items:      []dto.CreateOrderItemDTO{{VariantID: product.Variants[0].ID, Quantity: 2}},
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	orderID := decode[model.Order](t, resp).Data.ID
+
+	invoice := createInvoice(t, ts.URL, orderID)
+	assert.Equal(t, model.InvoiceStatusDraft, invoice.Status)
+
+	t.Run("Void draft invoice - not allowed (409)", func(t *testing.T) {
+		resp := invoiceAction(t, ts.URL, invoice.ID, "void")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, "invalid invoice status: cannot move invoice from draft to void", decode[any](t, resp).Message)
+	})
+
+	t.Run("Send pro forma - success", func(t *testing.T) {
+		resp := invoiceAction(t, ts.URL, invoice.ID, "send-pro-forma")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, model.InvoiceStatusProForma, decode[model.Invoice](t, resp).Data.Status)
+	})
+
+	t.Run("Update pro forma invoice - success", func(t *testing.T) {
+		resp := updateInvoice(t, ts.URL, invoice.ID, "reviewed")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "reviewed", decode[model.Invoice](t, resp).Data.Notes)
+	})
+
+	t.Run("Issue invoice - success", func(t *testing.T) {
+		resp := invoiceAction(t, ts.URL, invoice.ID, "issue")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, model.InvoiceStatusIssued, decode[model.Invoice](t, resp).Data.Status)
+
+		var event model.OutboxEvent
+		assert.NoError(t, db.Where("type = ? AND aggregate_id = ?", types.InvoiceIssuedEventType, invoice.ID).First(&event).Error)
+		assert.Equal(t, setup.DefaultOrgID, *event.OrgID)
+		assert.Nil(t, event.PublishedAt)
+
+		var payload types.InvoiceEvent
+		assert.NoError(t, json.Unmarshal([]byte(event.Payload), &payload))
+		assert.Equal(t, invoice.InvoiceNumber, payload.InvoiceNumber)
+		assert.Equal(t, model.InvoiceStatusIssued, payload.Status)
+	})
+
+	t.Run("Update issued invoice - locked (409)", func(t *testing.T) {
+		resp := updateInvoice(t, ts.URL, invoice.ID, "changed after issue")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrInvoiceLocked, decode[any](t, resp).Message)
+
+		var stored model.Invoice
+		assert.NoError(t, db.First(&stored, invoice.ID).Error)
+		assert.Equal(t, model.InvoiceStatusIssued, stored.Status)
+		assert.NotEqual(t, "changed after issue", stored.Notes)
+	})
+
+	t.Run("Delete issued invoice - locked (409)", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/invoices/%d", ts.URL, invoice.ID), nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Cancel issued invoice - not allowed (409)", func(t *testing.T) {
+		resp := invoiceAction(t, ts.URL, invoice.ID, "cancel")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Void issued invoice - success", func(t *testing.T) {
+		resp := invoiceAction(t, ts.URL, invoice.ID, "void")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, model.InvoiceStatusVoid, decode[model.Invoice](t, resp).Data.Status)
+	})
+
+	t.Run("Issue void invoice - not allowed (409)", func(t *testing.T) {
+		resp := invoiceAction(t, ts.URL, invoice.ID, "issue")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Cancel draft invoice - success", func(t *testing.T) {
+		draft := createInvoice(t, ts.URL, orderID)
+		resp := invoiceAction(t, ts.URL, draft.ID, "cancel")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, model.InvoiceStatusCancelled, decode[model.Invoice](t, resp).Data.Status)
+	})
+
+	t.Run("Issue invoice - not found (404)", func(t *testing.T) {
+		resp := invoiceAction(t, ts.URL, 9999, "issue")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,106 @@
+package invoice_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkOverdueInvoices(t *testing.T) {
+	ts, worker := setup.SetupTestServerWithWorker(setup.DefaultUserID, setup.DefaultOrgID, model.RoleOwner)
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "OVERDUE-SKU")
+
+	// issuedInvoice creates an order and issues an invoice for it that is due
+	// at dueDate.
+	issuedInvoice := func(t *testing.T, dueDate time.Time) model.Invoice {
+		t.Helper()
+		resp := postJSON(t, ts.URL+"/api/v1/orders", dto.CreateOrderDTO{
+			CustomerID: customer.ID,
+			Items:      []dto.CreateOrderItemDTO{{VariantID: product.Variants[0].ID, Quantity: 1}},
+			Delivery: dto.CreateDeliveryInfoDTO{
+				Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+			},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		invoice := createInvoice(t, ts.URL, decode[model.Order](t, resp).Data.ID)
+
+		body, _ := json.Marshal(dto.UpdateInvoiceDTO{DueDate: &dueDate})
+		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v1/invoices/%d", ts.URL, invoice.ID), bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+
+		resp = invoiceAction(t, ts.URL, invoice.ID, "issue")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return decode[model.Invoice](t, resp).Data
+	}
+
+	// markOverdue runs the scheduled job now instead of at its next run.
+	markOverdue := func(t *testing.T) {
+		t.Helper()
+		require.NoError(t, db.Model(&model.Job{}).
+			Where("type = ? AND status = ?", types.MarkOverdueInvoicesJobType, model.JobStatusPending).
+			Update("run_at", time.Now()).Error)
+		worker.Drain(t)
+	}
+
+	statusOf := func(t *testing.T, id uint) model.InvoiceStatus {
+		t.Helper()
+		var invoice model.Invoice
+		require.NoError(t, db.First(&invoice, id).Error)
+		return invoice.Status
+	}
+
+	t.Run("Issued invoice past its due date - overdue", func(t *testing.T) {
+		invoice := issuedInvoice(t, time.Now().AddDate(0, 0, -1))
+
+		markOverdue(t)
+
+		assert.Equal(t, model.InvoiceStatusOverdue, statusOf(t, invoice.ID))
+	})
+
+	t.Run("Issued invoice not due yet - stays issued", func(t *testing.T) {
+		invoice := issuedInvoice(t, time.Now().AddDate(0, 0, 7))
+
+		markOverdue(t)
+
+		assert.Equal(t, model.InvoiceStatusIssued, statusOf(t, invoice.ID))
+	})
+
+	t.Run("Void invoice past its due date - stays void", func(t *testing.T) {
+		invoice := issuedInvoice(t, time.Now().AddDate(0, 0, -1))
+		resp := invoiceAction(t, ts.URL, invoice.ID, "void")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+
+		markOverdue(t)
+
+		assert.Equal(t, model.InvoiceStatusVoid, statusOf(t, invoice.ID))
+	})
+
+	t.Run("Scheduled again after running", func(t *testing.T) {
+		markOverdue(t)
+
+		var next model.Job
+		require.NoError(t, db.Where("type = ? AND status = ?", types.MarkOverdueInvoicesJobType, model.JobStatusPending).First(&next).Error)
+		assert.True(t, next.RunAt.After(time.Now()))
+	})
+}
@@ -0,0 +1,96 @@
+package invoice_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvoiceTenantIsolation(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+	foreignTS := setup.SetupTestServerForOrg(setup.DefaultUserID, 2)
+	defer foreignTS.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	seed.InsertOrgWithID(db, 2)
+
+	customer := seed.InsertCustomerForOrg(db, 2)
+	product := seed.InsertProductForOrg(db, 2, "TENANT-INVOICE-SKU")
+	resp := postJSON(t, foreignTS.URL+"/api/v1/orders", dto.CreateOrderDTO{
+		CustomerID: customer.ID,
+		Items:      []dto.CreateOrderItemDTO{{VariantID: product.Variants[0].ID, Quantity: 1}},
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	foreignOrder := decode[model.Order](t, resp).Data
+	foreign := createInvoice(t, foreignTS.URL, foreignOrder.ID)
+	foreignURL := fmt.Sprintf("%s/api/v1/invoices/%d", ts.URL, foreign.ID)
+
+	t.Run("Create invoice for order of another org - not found (404)", func(t *testing.T) {
+		resp := postJSON(t, ts.URL+"/api/v1/invoices", dto.CreateInvoiceDTO{OrderID: foreignOrder.ID})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Get invoice of another org - not found (404)", func(t *testing.T) {
+		resp, err := http.Get(foreignURL)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Update invoice of another org - not found (404)", func(t *testing.T) {
+		resp := updateInvoice(t, ts.URL, foreign.ID, "hijacked")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Delete invoice of another org - not found (404)", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, foreignURL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	for _, action := range []string{"Send-pro-forma", "Issue", "Cancel", "Void"} {
+		t.Run(action+" invoice of another org - not found (404)", func(t *testing.T) {
+			resp := invoiceAction(t, ts.URL, foreign.ID, strings.ToLower(action))
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	}
+
+	t.Run("Filter invoices - only own org", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/v1/invoices")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		invoices := decode[response.FilterResponse[model.Invoice]](t, resp).Data.Items
+		for _, invoice := range invoices {
+			assert.Equal(t, setup.DefaultOrgID, invoice.OrgID)
+			assert.NotEqual(t, foreign.ID, invoice.ID)
+		}
+	})
+
+	t.Run("Invoice of another org is untouched", func(t *testing.T) {
+		var stored model.Invoice
+		assert.NoError(t, db.First(&stored, foreign.ID).Error)
+		assert.Equal(t, uint(2), stored.OrgID)
+		assert.Equal(t, model.InvoiceStatusDraft, stored.Status)
+		assert.Equal(t, "first draft", stored.Notes)
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/deveasyclick/openb2b/internal/shared/response"
 	"github.com/deveasyclick/openb2b/test/integration/seed"
 	"github.com/deveasyclick/openb2b/test/integration/setup"
@@ -20,15 +21,17 @@ func TestOrderHandlers(t *testing.T) {
 	defer ts.Close()
 
 	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
 	seed.ClearOrders(db) // reset DB for controlled testing
-	seed.InsertOrders(db)
+	seed.InsertOrders(db, customer.ID)
 
 	seed.ClearProducts(db)
 	product := seed.InsertProducts(db)
 	// -------------------- CREATE ORDER --------------------
 	t.Run("Create order - success", func(t *testing.T) {
 		reqBody := dto.CreateOrderDTO{
-			CustomerID: 1,
+			CustomerID: customer.ID,
 			Items: []dto.CreateOrderItemDTO{
 				{VariantID: product.Variants[0].ID, Quantity: 1},
 				{VariantID: product.Variants[1].ID, Quantity: 3},
@@ -41,12 +44,12 @@ func TestOrderHandlers(t *testing.T) {
 					Country: "Country 1",
 					Zip:     "02912",
 				},
-				TransportFare: 10.0,
+				TransportFare: money.FromInt(10),
 			},
 			Notes: "Notes",
 			Discount: dto.CreateDiscountInfoDTO{
 				Type:   model.DiscountFixed,
-				Amount: 3,
+				Amount: money.FromInt(3),
 			},
 		}
 		body, _ := json.Marshal(reqBody)
@@ -62,40 +65,42 @@ func TestOrderHandlers(t *testing.T) {
 		assert.Equal(t, http.StatusCreated, order.Code)
 		assert.Equal(t, order.Message, "success")
 
-		assert.Equal(t, int(order.Data.CustomerID), 1)
+		assert.Equal(t, customer.ID, order.Data.CustomerID)
 		assert.Equal(t, order.Data.Notes, "Notes")
 		// Order delivery
-		assert.Equal(t, float64(order.Data.Delivery.TransportFare), 10.0)
+		assert.Equal(t, money.FromInt(10), order.Data.Delivery.TransportFare)
 		assert.Equal(t, order.Data.Delivery.Address.Address, "Street 1")
 		assert.Equal(t, order.Data.Delivery.Address.City, "City 1")
 		assert.Equal(t, order.Data.Delivery.Address.State, "State 1")
 		assert.Equal(t, order.Data.Delivery.Address.Country, "Country 1")
 		// Order totals
-		assert.Equal(t, int(order.Data.Discount.Amount), 3)
+		assert.Equal(t, money.FromInt(3), order.Data.Discount.Amount)
 		assert.Equal(t, order.Data.Discount.Type, model.DiscountFixed)
-		assert.Equal(t, order.Data.AppliedDiscount, float64(3))
+		assert.Equal(t, money.FromInt(3), order.Data.AppliedDiscount)
 		// discount total = applied discount + sum of all item discounts
-		assert.Equal(t, order.Data.DiscountTotal, order.Data.AppliedDiscount+order.Data.Items[0].AppliedDiscount+order.Data.Items[1].AppliedDiscount)
+		assert.Equal(t, order.Data.DiscountTotal, money.Sum(order.Data.AppliedDiscount, order.Data.Items[0].AppliedDiscount, order.Data.Items[1].AppliedDiscount))
 		// item discount total = sum of all item discounts
-		assert.Equal(t, order.Data.ItemDiscountTotal, order.Data.Items[0].AppliedDiscount+order.Data.Items[1].AppliedDiscount)
-		// order total = sum of all item totals
-		assert.Equal(t, order.Data.Total, order.Data.Items[0].Total+order.Data.Items[1].Total)
+		assert.Equal(t, order.Data.ItemDiscountTotal, order.Data.Items[0].AppliedDiscount.Add(order.Data.Items[1].AppliedDiscount))
+		// order total = sum of all item totals + the transport fare charge
+		assert.Equal(t, order.Data.Total, money.Sum(order.Data.Items[0].Total, order.Data.Items[1].Total, money.FromInt(10)))
+		assert.Equal(t, money.FromInt(10), order.Data.ChargeTotal)
+		assert.Len(t, order.Data.Charges, 1)
 		// sum of item (unitPrice * qty), before discounts & tax
-		subtotal := order.Data.Items[0].UnitPrice*float64(order.Data.Items[0].Quantity) + order.Data.Items[1].UnitPrice*float64(order.Data.Items[1].Quantity)
+		subtotal := order.Data.Items[0].UnitPrice.Mul(order.Data.Items[0].Quantity).Add(order.Data.Items[1].UnitPrice.Mul(order.Data.Items[1].Quantity))
 		assert.Equal(t, order.Data.Subtotal, subtotal)
 		// order tax = sum of all item taxes
-		assert.Equal(t, order.Data.TaxTotal, order.Data.Items[0].TaxAmount+order.Data.Items[1].TaxAmount)
+		assert.Equal(t, order.Data.TaxTotal, order.Data.Items[0].TaxAmount.Add(order.Data.Items[1].TaxAmount))
 
 		// Order items totals
 		assert.Equal(t, len(order.Data.Items), 2)
 		assert.Equal(t, int(order.Data.Items[0].VariantID), 100)
 		assert.Equal(t, order.Data.Items[0].Quantity, 1)
-		assert.Equal(t, order.Data.Items[0].UnitPrice, 10.0)
+		assert.Equal(t, money.FromInt(10), order.Data.Items[0].UnitPrice)
 	})
 
 	t.Run("Create order - (zero discount) success", func(t *testing.T) {
 		reqBody := dto.CreateOrderDTO{
-			CustomerID: 1,
+			CustomerID: customer.ID,
 			Items: []dto.CreateOrderItemDTO{
 				{VariantID: 99, Quantity: 1},
 			},
@@ -107,12 +112,12 @@ func TestOrderHandlers(t *testing.T) {
 					Country: "Country 1",
 					Zip:     "02912",
 				},
-				TransportFare: 10.0,
+				TransportFare: money.FromInt(10),
 			},
 			Notes: "Notes",
 			Discount: dto.CreateDiscountInfoDTO{
 				Type:   model.DiscountPercentage,
-				Amount: 0,
+				Amount: money.Zero,
 			},
 		}
 		body, _ := json.Marshal(reqBody)
@@ -127,10 +132,10 @@ func TestOrderHandlers(t *testing.T) {
 		assert.Equal(t, http.StatusCreated, order.Code)
 		assert.Equal(t, order.Message, "success")
 
-		assert.Equal(t, int(order.Data.CustomerID), 1)
-		assert.Equal(t, int(order.Data.Discount.Amount), 0)
+		assert.Equal(t, customer.ID, order.Data.CustomerID)
+		assert.Equal(t, money.Zero, order.Data.Discount.Amount)
 		assert.Equal(t, order.Data.Discount.Type, model.DiscountPercentage)
-		assert.Equal(t, float64(order.Data.Delivery.TransportFare), 10.0)
+		assert.Equal(t, money.FromInt(10), order.Data.Delivery.TransportFare)
 		assert.Equal(t, order.Data.Delivery.Address.Address, "Street 1")
 		assert.Equal(t, order.Data.Delivery.Address.City, "City 1")
 		assert.Equal(t, order.Data.Delivery.Address.State, "State 1")
@@ -138,9 +143,9 @@ func TestOrderHandlers(t *testing.T) {
 		assert.Equal(t, order.Data.Notes, "Notes")
 		assert.Equal(t, len(order.Data.Items), 1)
 		assert.Equal(t, order.Data.Items[0].Quantity, 1)
-		assert.Equal(t, order.Data.Items[0].UnitPrice, 20.0)
+		assert.Equal(t, money.FromInt(20), order.Data.Items[0].UnitPrice)
 		assert.Equal(t, int(order.Data.Items[0].VariantID), 99)
-		assert.Equal(t, int(order.Data.Total), 20)
+		assert.Equal(t, money.FromInt(30), order.Data.Total)
 	})
 
 	t.Run("Create order - missing name (400)", func(t *testing.T) {
@@ -193,7 +198,7 @@ func TestOrderHandlers(t *testing.T) {
 			},
 			Discount: &dto.CreateDiscountInfoDTO{
 				Type:   model.DiscountFixed,
-				Amount: 3,
+				Amount: money.FromInt(3),
 			},
 		}
 		body, _ := json.Marshal(reqBody)
@@ -215,20 +220,20 @@ func TestOrderHandlers(t *testing.T) {
 
 		assert.Equal(t, order.Data.Notes, "This is a note")
 		// Order totals
-		assert.Equal(t, int(order.Data.Discount.Amount), 3)
+		assert.Equal(t, money.FromInt(3), order.Data.Discount.Amount)
 		assert.Equal(t, order.Data.Discount.Type, model.DiscountFixed)
-		assert.Equal(t, order.Data.AppliedDiscount, float64(3))
+		assert.Equal(t, money.FromInt(3), order.Data.AppliedDiscount)
 		// discount total = applied discount + sum of all item discounts
-		assert.Equal(t, order.Data.DiscountTotal, order.Data.AppliedDiscount+order.Data.Items[0].AppliedDiscount+order.Data.Items[1].AppliedDiscount)
+		assert.Equal(t, order.Data.DiscountTotal, money.Sum(order.Data.AppliedDiscount, order.Data.Items[0].AppliedDiscount, order.Data.Items[1].AppliedDiscount))
 		// item discount total = sum of all item discounts
-		assert.Equal(t, order.Data.ItemDiscountTotal, order.Data.Items[0].AppliedDiscount+order.Data.Items[1].AppliedDiscount)
+		assert.Equal(t, order.Data.ItemDiscountTotal, order.Data.Items[0].AppliedDiscount.Add(order.Data.Items[1].AppliedDiscount))
 		// order total = sum of all item totals
-		assert.Equal(t, order.Data.Total, order.Data.Items[0].Total+order.Data.Items[1].Total)
+		assert.Equal(t, order.Data.Total, order.Data.Items[0].Total.Add(order.Data.Items[1].Total))
 		// sum of item (unitPrice * qty), before discounts & tax
-		subtotal := order.Data.Items[0].UnitPrice*float64(order.Data.Items[0].Quantity) + order.Data.Items[1].UnitPrice*float64(order.Data.Items[1].Quantity)
+		subtotal := order.Data.Items[0].UnitPrice.Mul(order.Data.Items[0].Quantity).Add(order.Data.Items[1].UnitPrice.Mul(order.Data.Items[1].Quantity))
 		assert.Equal(t, order.Data.Subtotal, subtotal)
 		// order tax = sum of all item taxes
-		assert.Equal(t, order.Data.TaxTotal, order.Data.Items[0].TaxAmount+order.Data.Items[1].TaxAmount)
+		assert.Equal(t, order.Data.TaxTotal, order.Data.Items[0].TaxAmount.Add(order.Data.Items[1].TaxAmount))
 
 		// Order items totals
 		assert.Equal(t, len(order.Data.Items), 2)
@@ -302,7 +307,7 @@ func TestOrderHandlers(t *testing.T) {
 		err = json.NewDecoder(resp.Body).Decode(&order)
 		assert.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, order.Code)
-		assert.Equal(t, "cannot update order not in pending: processing", order.Message)
+		assert.Equal(t, "cannot update order not in pending: approved", order.Message)
 	})
 
 	// -------------------- DELETE PRODUCT --------------------
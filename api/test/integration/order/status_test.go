@@ -0,0 +1,99 @@
+package order_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderStatusTransitions(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "STATUS-SKU")
+	variantID := product.Variants[0].ID
+
+	resp := createOrder(t, ts.URL, customer.ID, dto.CreateOrderItemDTO{VariantID: variantID, Quantity: 1})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	orderID := decodeOrder(t, resp).ID
+
+	t.Run("Deliver pending order - not allowed (409)", func(t *testing.T) {
+		resp := postOrderAction(t, ts.URL, orderID, "deliver")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		var apiErr response.APIResponse[any]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&apiErr))
+		assert.Equal(t, "invalid order status transition: cannot move order from pending to delivered", apiErr.Message)
+	})
+
+	t.Run("Approve order - success", func(t *testing.T) {
+		resp := postOrderAction(t, ts.URL, orderID, "approve")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, model.OrderStatusApproved, decodeOrder(t, resp).Status)
+	})
+
+	t.Run("Approve order twice - not allowed (409)", func(t *testing.T) {
+		resp := postOrderAction(t, ts.URL, orderID, "approve")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Update approved order - items are locked (400)", func(t *testing.T) {
+		resp := patchOrder(t, ts.URL, orderID, dto.UpdateOrderDTO{
+			Items: []*dto.CreateOrderItemDTO{{VariantID: variantID, Quantity: 2}},
+		})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Deliver order - success", func(t *testing.T) {
+		resp := postOrderAction(t, ts.URL, orderID, "deliver")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		order := decodeOrder(t, resp)
+		assert.Equal(t, model.OrderStatusDelivered, order.Status)
+		assert.Equal(t, model.DeliveryDelivered, order.Delivery.Status)
+		assert.NotNil(t, order.Delivery.At)
+	})
+
+	t.Run("Cancel delivered order - not allowed (409)", func(t *testing.T) {
+		resp := postOrderAction(t, ts.URL, orderID, "cancel")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Get order - includes status history", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, orderID))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		history := decodeOrder(t, resp).StatusHistory
+		assert.Len(t, history, 3)
+		if len(history) == 3 {
+			assert.Equal(t, model.OrderStatus(""), history[0].FromStatus)
+			assert.Equal(t, model.OrderStatusPending, history[0].ToStatus)
+			assert.Equal(t, model.OrderStatusPending, history[1].FromStatus)
+			assert.Equal(t, model.OrderStatusApproved, history[1].ToStatus)
+			assert.Equal(t, model.OrderStatusDelivered, history[2].ToStatus)
+			assert.NotNil(t, history[2].UserID)
+		}
+	})
+
+	t.Run("Cancel order - not found (404)", func(t *testing.T) {
+		resp := postOrderAction(t, ts.URL, 9999, "cancel")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
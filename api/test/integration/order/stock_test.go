@@ -0,0 +1,172 @@
+package order_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func createOrder(t *testing.T, url string, customerID uint, items ...dto.CreateOrderItemDTO) *http.Response {
+	t.Helper()
+	reqBody := dto.CreateOrderDTO{
+		CustomerID: customerID,
+		Items:      items,
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{
+				Address: "Street 1",
+				City:    "City 1",
+				State:   "State 1",
+				Country: "Country 1",
+				Zip:     "02912",
+			},
+		},
+	}
+	body, _ := json.Marshal(reqBody)
+	resp, err := http.Post(url+"/api/v1/orders", "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	return resp
+}
+
+func patchOrder(t *testing.T, url string, id uint, reqBody any) *http.Response {
+	t.Helper()
+	body, _ := json.Marshal(reqBody)
+	req, _ := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/orders/%d", url, id), bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func postOrderAction(t *testing.T, url string, id uint, action string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(fmt.Sprintf("%s/api/v1/orders/%d/%s", url, id, action), "application/json", nil)
+	assert.NoError(t, err)
+	return resp
+}
+
+func decodeOrder(t *testing.T, resp *http.Response) model.Order {
+	t.Helper()
+	defer resp.Body.Close()
+	var order response.APIResponse[model.Order]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&order))
+	return order.Data
+}
+
+func findVariant(t *testing.T, db *gorm.DB, id uint) model.Variant {
+	t.Helper()
+	var variant model.Variant
+	assert.NoError(t, db.First(&variant, id).Error)
+	return variant
+}
+
+func TestOrderStockReservation(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "STOCK-SKU")
+	variantID := product.Variants[0].ID // stock 10
+
+	t.Run("Create order - insufficient stock (409)", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, dto.CreateOrderItemDTO{VariantID: variantID, Quantity: 11})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		var apiErr response.APIResponse[any]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&apiErr))
+		assert.Equal(t, "insufficient stock: STOCK-SKU (requested 11, available 10)", apiErr.Message)
+
+		variant := findVariant(t, db, variantID)
+		assert.Equal(t, 10, variant.Stock)
+		assert.Equal(t, 0, variant.Reserved)
+	})
+
+	var pendingID uint
+	t.Run("Create order - reserves stock", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, dto.CreateOrderItemDTO{VariantID: variantID, Quantity: 4})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		pendingID = decodeOrder(t, resp).ID
+
+		variant := findVariant(t, db, variantID)
+		assert.Equal(t, 10, variant.Stock)
+		assert.Equal(t, 4, variant.Reserved)
+	})
+
+	t.Run("Create order - reserved stock is not available (409)", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, dto.CreateOrderItemDTO{VariantID: variantID, Quantity: 7})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Update order items - moves reservation", func(t *testing.T) {
+		resp := patchOrder(t, ts.URL, pendingID, dto.UpdateOrderDTO{
+			Items: []*dto.CreateOrderItemDTO{{VariantID: variantID, Quantity: 6}},
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, decodeOrder(t, resp).Items, 1)
+
+		variant := findVariant(t, db, variantID)
+		assert.Equal(t, 6, variant.Reserved)
+
+		var count int64
+		db.Unscoped().Model(&model.OrderItem{}).Where("order_id = ?", pendingID).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Approve order - commits reservation", func(t *testing.T) {
+		resp := postOrderAction(t, ts.URL, pendingID, "approve")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		variant := findVariant(t, db, variantID)
+		assert.Equal(t, 4, variant.Stock)
+		assert.Equal(t, 0, variant.Reserved)
+
+		var movement model.StockMovement
+		assert.NoError(t, db.Where("variant_id = ? AND reason = ?", variantID, model.StockReasonOrder).First(&movement).Error)
+		assert.Equal(t, -6, movement.Delta)
+		assert.Equal(t, 4, movement.Balance)
+		assert.Equal(t, pendingID, *movement.ReferenceID)
+	})
+
+	t.Run("Cancel order - releases reservation", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, dto.CreateOrderItemDTO{VariantID: variantID, Quantity: 3})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		orderID := decodeOrder(t, resp).ID
+		assert.Equal(t, 3, findVariant(t, db, variantID).Reserved)
+
+		resp = postOrderAction(t, ts.URL, orderID, "cancel")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		variant := findVariant(t, db, variantID)
+		assert.Equal(t, 4, variant.Stock)
+		assert.Equal(t, 0, variant.Reserved)
+	})
+
+	t.Run("Delete pending order - releases reservation", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, dto.CreateOrderItemDTO{VariantID: variantID, Quantity: 2})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		orderID := decodeOrder(t, resp).ID
+
+		req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, orderID), nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		assert.Equal(t, 0, findVariant(t, db, variantID).Reserved)
+	})
+}
@@ -0,0 +1,96 @@
+package order_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderTenantIsolation(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+	foreignTS := setup.SetupTestServerForOrg(setup.DefaultUserID, 2)
+	defer foreignTS.Close()
+
+	db := setup.SetupTestDB()
+	seed.ClearOrders(db)
+	defer seed.ClearOrders(db)
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	seed.InsertOrgWithID(db, 2)
+
+	ownCustomer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	ownProduct := seed.InsertProductForOrg(db, setup.DefaultOrgID, "TENANT-OWN-SKU")
+	resp := createOrder(t, ts.URL, ownCustomer.ID, dto.CreateOrderItemDTO{VariantID: ownProduct.Variants[0].ID, Quantity: 1})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	own := decodeOrder(t, resp)
+
+	foreignCustomer := seed.InsertCustomerForOrg(db, 2)
+	foreignProduct := seed.InsertProductForOrg(db, 2, "TENANT-FOREIGN-SKU")
+	resp = createOrder(t, foreignTS.URL, foreignCustomer.ID, dto.CreateOrderItemDTO{VariantID: foreignProduct.Variants[0].ID, Quantity: 2})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	foreign := decodeOrder(t, resp)
+	foreignURL := fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, foreign.ID)
+
+	t.Run("Get order of another org - not found (404)", func(t *testing.T) {
+		resp, err := http.Get(foreignURL)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Update order of another org - not found (404)", func(t *testing.T) {
+		notes := "Hijacked"
+		resp := patchOrder(t, ts.URL, foreign.ID, dto.UpdateOrderDTO{Notes: &notes})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Delete order of another org - not found (404)", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, foreignURL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	for _, action := range []string{"Approve", "Deliver", "Cancel"} {
+		t.Run(action+" order of another org - not found (404)", func(t *testing.T) {
+			resp := postOrderAction(t, ts.URL, foreign.ID, strings.ToLower(action))
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	}
+
+	t.Run("Filter orders - only own org", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/v1/orders")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var orders response.APIResponse[response.FilterResponse[model.Order]]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&orders))
+		require.Len(t, orders.Data.Items, 1)
+		assert.Equal(t, own.ID, orders.Data.Items[0].ID)
+		assert.Equal(t, int64(1), orders.Data.Pagination.Total)
+	})
+
+	t.Run("Order of another org is untouched", func(t *testing.T) {
+		var stored model.Order
+		assert.NoError(t, db.Preload("Items").First(&stored, foreign.ID).Error)
+		assert.Equal(t, uint(2), stored.OrgID)
+		assert.Equal(t, foreign.Status, stored.Status)
+		assert.Empty(t, stored.Notes)
+		require.Len(t, stored.Items, 1)
+		assert.Equal(t, 2, stored.Items[0].Quantity)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
 	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
 	"github.com/deveasyclick/openb2b/test/integration/seed"
 	"github.com/deveasyclick/openb2b/test/integration/setup"
 	"github.com/stretchr/testify/assert"
@@ -210,3 +211,36 @@ func TestOrderHandlers(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 }
+
+func TestCreateOrgSetsClerkOrg(t *testing.T) {
+	ts, worker := setup.SetupTestServerWithWorker(setup.DefaultUserID, setup.DefaultOrgID, model.RoleOwner)
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+
+	body, _ := json.Marshal(dto.CreateOrgDTO{
+		Name:             "OpenB2D",
+		OrganizationName: "OpenB2D NG",
+		Phone:            "+1-202-555-0199",
+		Email:            "info@openb2d.com",
+		Address: dto.AddressRequired{
+			Address: "123 Market Street",
+			City:    "San Francisco",
+			State:   "California",
+			Country: "USA",
+			Zip:     "02912",
+		},
+	})
+	resp, err := http.Post(ts.URL+"/api/v1/orgs", "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	worker.Drain(t)
+
+	// The Clerk user is updated by the org.created subscriber, through the
+	// job queue
+	var job model.Job
+	assert.NoError(t, db.Where("type = ?", "event:"+types.OrgCreatedEventType+":clerk").Last(&job).Error)
+	assert.Equal(t, model.JobStatusDone, job.Status)
+}
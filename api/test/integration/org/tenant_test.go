@@ -0,0 +1,63 @@
+package org_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgTenantIsolation(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	foreign := seed.InsertOrgWithID(db, 2)
+	foreignURL := fmt.Sprintf("%s/api/v1/orgs/%d", ts.URL, foreign.ID)
+
+	t.Run("Get another org - not found (404)", func(t *testing.T) {
+		resp, err := http.Get(foreignURL)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Update another org - not found (404)", func(t *testing.T) {
+		body, _ := json.Marshal(dto.UpdateOrgDTO{Name: "Hijacked"})
+		req, _ := http.NewRequest(http.MethodPatch, foreignURL, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Delete another org - not found (404)", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, foreignURL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Get own org - success", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf("%s/api/v1/orgs/%d", ts.URL, setup.DefaultOrgID))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Another org is untouched", func(t *testing.T) {
+		var stored model.Org
+		assert.NoError(t, db.First(&stored, foreign.ID).Error)
+		assert.Equal(t, foreign.Name, stored.Name)
+	})
+}
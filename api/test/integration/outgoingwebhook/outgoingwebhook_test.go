@@ -0,0 +1,238 @@
+package outgoingwebhook_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	svix "github.com/svix/svix-webhooks/go"
+)
+
+func postJSON(t *testing.T, url string, reqBody any) *http.Response {
+	t.Helper()
+	body, _ := json.Marshal(reqBody)
+	resp, err := http.Post(url, "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	return resp
+}
+
+func patchJSON(t *testing.T, url string, reqBody any) *http.Response {
+	t.Helper()
+	body, _ := json.Marshal(reqBody)
+	req, _ := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+// receiver is an endpoint recording the webhooks posted to it.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver() *receiver {
+	rec := &receiver{status: http.StatusOK}
+	rec.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.requests = append(rec.requests, r)
+		rec.bodies = append(rec.bodies, body)
+		w.WriteHeader(rec.status)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	return rec
+}
+
+func (rec *receiver) respondWith(status int) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.status = status
+}
+
+func (rec *receiver) count() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return len(rec.requests)
+}
+
+func TestOutgoingWebhooks(t *testing.T) {
+	ts, worker := setup.SetupTestServerWithWorker(setup.DefaultUserID, setup.DefaultOrgID, model.RoleOwner)
+	defer ts.Close()
+	rec := newReceiver()
+	defer rec.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "WEBHOOK-SKU")
+
+	createOrder := func(t *testing.T) model.Order {
+		t.Helper()
+		resp := postJSON(t, ts.URL+"/api/v1/orders", dto.CreateOrderDTO{
+			CustomerID: customer.ID,
+			Items:      []dto.CreateOrderItemDTO{{VariantID: product.Variants[0].ID, Quantity: 1}},
+			Delivery: dto.CreateDeliveryInfoDTO{
+				Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+			},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		return decode[model.Order](t, resp).Data
+	}
+
+	t.Run("Create subscription - unknown event type (400)", func(t *testing.T) {
+		resp := postJSON(t, ts.URL+"/api/v1/webhook-subscriptions", dto.CreateWebhookSubscriptionDTO{
+			URL:        rec.URL,
+			EventTypes: []string{"order.exploded"},
+		})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Create subscription - plain http endpoint (400)", func(t *testing.T) {
+		resp := postJSON(t, ts.URL+"/api/v1/webhook-subscriptions", dto.CreateWebhookSubscriptionDTO{
+			URL:        "http://169.254.169.254/latest/meta-data",
+			EventTypes: []string{"order.created"},
+		})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	var subscription model.WebhookSubscription
+	t.Run("Create subscription - success", func(t *testing.T) {
+		resp := postJSON(t, ts.URL+"/api/v1/webhook-subscriptions", dto.CreateWebhookSubscriptionDTO{
+			URL:        rec.URL,
+			EventTypes: []string{"order.created"},
+		})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		subscription = decode[model.WebhookSubscription](t, resp).Data
+		assert.True(t, subscription.Active)
+		assert.True(t, strings.HasPrefix(subscription.Secret, "whsec_"))
+	})
+
+	var order model.Order
+	t.Run("Order created - delivered signed", func(t *testing.T) {
+		order = createOrder(t)
+		assert.Equal(t, 0, rec.count(), "nothing is sent before the event is relayed")
+
+		worker.Drain(t)
+		require.Equal(t, 1, rec.count())
+
+		wh, err := svix.NewWebhook(subscription.Secret)
+		require.NoError(t, err)
+		assert.NoError(t, wh.Verify(rec.bodies[0], rec.requests[0].Header))
+
+		var body struct {
+			Type string `json:"type"`
+			Data struct {
+				OrderID     uint   `json:"orderId"`
+				OrderNumber string `json:"orderNumber"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.bodies[0], &body))
+		assert.Equal(t, "order.created", body.Type)
+		assert.Equal(t, order.ID, body.Data.OrderID)
+		assert.Equal(t, order.OrderNumber, body.Data.OrderNumber)
+	})
+
+	t.Run("Order updated - not subscribed", func(t *testing.T) {
+		notes := "leave at the door"
+		resp := patchJSON(t, fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, order.ID), dto.UpdateOrderDTO{Notes: &notes})
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		worker.Drain(t)
+		assert.Equal(t, 1, rec.count())
+	})
+
+	var failed model.WebhookDelivery
+	t.Run("Endpoint failing - delivery is retried later", func(t *testing.T) {
+		rec.respondWith(http.StatusInternalServerError)
+		createOrder(t)
+		worker.Drain(t)
+		assert.Equal(t, 2, rec.count())
+
+		resp, err := http.Get(fmt.Sprintf("%s/api/v1/webhook-deliveries?subscription_id=%d&sort=id", ts.URL, subscription.ID))
+		require.NoError(t, err)
+		deliveries := decode[response.FilterResponse[model.WebhookDelivery]](t, resp).Data.Items
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, model.WebhookDeliverySucceeded, deliveries[0].Status)
+		assert.Equal(t, http.StatusOK, deliveries[0].ResponseStatus)
+
+		failed = deliveries[1]
+		assert.Equal(t, model.WebhookDeliveryPending, failed.Status)
+		assert.Equal(t, 1, failed.Attempts)
+		assert.Equal(t, http.StatusInternalServerError, failed.ResponseStatus)
+		assert.Equal(t, "endpoint answered 500", failed.LastError)
+	})
+
+	t.Run("Redeliver - success", func(t *testing.T) {
+		rec.respondWith(http.StatusOK)
+		resp := postJSON(t, fmt.Sprintf("%s/api/v1/webhook-deliveries/%d/redeliver", ts.URL, failed.ID), nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 0, decode[model.WebhookDelivery](t, resp).Data.Attempts)
+
+		worker.Drain(t)
+		require.Equal(t, 3, rec.count())
+		assert.Equal(t, rec.requests[1].Header.Get("svix-id"), rec.requests[2].Header.Get("svix-id"))
+
+		resp, err := http.Get(fmt.Sprintf("%s/api/v1/webhook-deliveries/%d", ts.URL, failed.ID))
+		require.NoError(t, err)
+		delivery := decode[model.WebhookDelivery](t, resp).Data
+		assert.Equal(t, model.WebhookDeliverySucceeded, delivery.Status)
+		assert.NotNil(t, delivery.DeliveredAt)
+	})
+
+	t.Run("Redeliver - not found (404)", func(t *testing.T) {
+		resp := postJSON(t, ts.URL+"/api/v1/webhook-deliveries/9999/redeliver", nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Inactive subscription - nothing delivered", func(t *testing.T) {
+		active := false
+		resp := patchJSON(t, fmt.Sprintf("%s/api/v1/webhook-subscriptions/%d", ts.URL, subscription.ID), dto.UpdateWebhookSubscriptionDTO{Active: &active})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.False(t, decode[model.WebhookSubscription](t, resp).Data.Active)
+
+		createOrder(t)
+		worker.Drain(t)
+		assert.Equal(t, 3, rec.count())
+	})
+}
+
+func TestOutgoingWebhooksRequireAdmin(t *testing.T) {
+	ts := setup.SetupTestServerAs(setup.DefaultUserID, setup.DefaultOrgID, model.RoleSales)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/v1/webhook-subscriptions")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
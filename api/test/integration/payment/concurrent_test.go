@@ -0,0 +1,82 @@
+package payment_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentPayments(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+	api := ts.URL + "/api/v1"
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "CONCURRENT-PAYMENT-SKU")
+
+	resp := send(t, http.MethodPost, api+"/orders", dto.CreateOrderDTO{
+		CustomerID: customer.ID,
+		Items:      []dto.CreateOrderItemDTO{{VariantID: product.Variants[0].ID, Quantity: 4}},
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	orderID := decode[model.Order](t, resp).Data.ID
+
+	resp = send(t, http.MethodPost, api+"/invoices", dto.CreateInvoiceDTO{OrderID: orderID})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	invoice := decode[model.Invoice](t, resp).Data
+
+	resp = send(t, http.MethodPost, fmt.Sprintf("%s/invoices/%d/issue", api, invoice.ID), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	// Four payments that add up to the total, recorded at the same time. The
+	// test database has a single connection, so this checks that the balance
+	// adds up; the lock on the invoice only contends on Postgres.
+	quarter := invoice.Total.MulFrac(1, 4, money.Down)
+	amounts := []money.Money{quarter, quarter, quarter, invoice.Total.Sub(quarter.Mul(3))}
+
+	var wg sync.WaitGroup
+	statuses := make(chan int, len(amounts))
+	for _, amount := range amounts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, _ := json.Marshal(dto.CreatePaymentDTO{InvoiceID: invoice.ID, Amount: amount, Method: model.PaymentMethodBankTransfer})
+			resp, err := http.Post(api+"/payments", "application/json", bytes.NewBuffer(body))
+			if err != nil {
+				statuses <- 0
+				return
+			}
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		assert.Equal(t, http.StatusCreated, status)
+	}
+
+	var stored model.Invoice
+	require.NoError(t, db.First(&stored, invoice.ID).Error)
+	assert.Equal(t, model.InvoiceStatusPaid, stored.Status)
+	assert.Equal(t, invoice.Total, stored.AmountPaid)
+	assert.True(t, stored.AmountDue.IsZero())
+}
@@ -0,0 +1,172 @@
+package payment_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func send(t *testing.T, method, url string, reqBody any) *http.Response {
+	t.Helper()
+	var body []byte
+	if reqBody != nil {
+		body, _ = json.Marshal(reqBody)
+	}
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func TestPayments(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+	api := ts.URL + "/api/v1"
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "PAYMENT-SKU")
+
+	resp := send(t, http.MethodPost, api+"/orders", dto.CreateOrderDTO{
+		CustomerID: customer.ID,
+		Items:      []dto.CreateOrderItemDTO{{VariantID: product.Variants[0].ID, Quantity: 4}},
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	orderID := decode[model.Order](t, resp).Data.ID
+
+	resp = send(t, http.MethodPost, api+"/invoices", dto.CreateInvoiceDTO{OrderID: orderID})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	invoice := decode[model.Invoice](t, resp).Data
+	require.True(t, invoice.Total.GreaterThan(money.FromInt(10)))
+	half := invoice.Total.MulFrac(1, 2, money.Down)
+
+	findInvoice := func(t *testing.T) model.Invoice {
+		var inv model.Invoice
+		assert.NoError(t, db.First(&inv, invoice.ID).Error)
+		return inv
+	}
+	customerCredit := func(t *testing.T) money.Money {
+		var c model.Customer
+		assert.NoError(t, db.First(&c, customer.ID).Error)
+		return c.CreditBalance
+	}
+
+	t.Run("Record payment - draft invoice (409)", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/payments", dto.CreatePaymentDTO{InvoiceID: invoice.ID, Amount: half, Method: model.PaymentMethodCash})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrInvoiceNotPayable, decode[any](t, resp).Message)
+	})
+
+	resp = send(t, http.MethodPost, fmt.Sprintf("%s/invoices/%d/issue", api, invoice.ID), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	t.Run("Record payment - invalid method (400)", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/payments", dto.CreatePaymentDTO{InvoiceID: invoice.ID, Amount: half, Method: "cheque"})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Record payment - currency mismatch (400)", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/payments", dto.CreatePaymentDTO{InvoiceID: invoice.ID, Amount: half, Currency: "USD", Method: model.PaymentMethodCash})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrPaymentCurrencyMismatch, decode[any](t, resp).Message)
+	})
+
+	t.Run("Record payment - invoice not found (404)", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/payments", dto.CreatePaymentDTO{InvoiceID: 9999, Amount: half, Method: model.PaymentMethodCash})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	var firstID uint
+	t.Run("Record payment - partially paid", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/payments", dto.CreatePaymentDTO{InvoiceID: invoice.ID, Amount: half, Method: model.PaymentMethodBankTransfer, Reference: "TRF-1"})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		payment := decode[model.Payment](t, resp).Data
+		firstID = payment.ID
+		assert.Equal(t, invoice.Currency, payment.Currency)
+		assert.Equal(t, customer.ID, payment.CustomerID)
+		assert.Zero(t, payment.CreditAmount)
+
+		inv := findInvoice(t)
+		assert.Equal(t, model.InvoiceStatusPartiallyPaid, inv.Status)
+		assert.Equal(t, half, inv.AmountPaid)
+		assert.Equal(t, invoice.Total.Sub(half), inv.AmountDue)
+	})
+
+	var secondID uint
+	t.Run("Record payment - over-payment becomes customer credit", func(t *testing.T) {
+		resp := send(t, http.MethodPost, api+"/payments", dto.CreatePaymentDTO{InvoiceID: invoice.ID, Amount: invoice.Total.Sub(half).Add(money.FromInt(5)), Method: model.PaymentMethodCard})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		payment := decode[model.Payment](t, resp).Data
+		secondID = payment.ID
+		assert.Equal(t, money.FromInt(5), payment.CreditAmount)
+
+		inv := findInvoice(t)
+		assert.Equal(t, model.InvoiceStatusPaid, inv.Status)
+		assert.Equal(t, invoice.Total, inv.AmountPaid)
+		assert.Equal(t, money.Zero, inv.AmountDue)
+		assert.Equal(t, money.FromInt(5), customerCredit(t))
+	})
+
+	t.Run("Delete payment - reopens invoice and takes back credit", func(t *testing.T) {
+		resp := send(t, http.MethodDelete, fmt.Sprintf("%s/payments/%d", api, firstID), nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		inv := findInvoice(t)
+		assert.Equal(t, model.InvoiceStatusPartiallyPaid, inv.Status)
+		assert.Equal(t, invoice.Total.Sub(half).Add(money.FromInt(5)), inv.AmountPaid)
+		assert.Equal(t, money.Zero, customerCredit(t))
+	})
+
+	t.Run("Update payment - pays invoice in full", func(t *testing.T) {
+		amount := invoice.Total
+		resp := send(t, http.MethodPatch, fmt.Sprintf("%s/payments/%d", api, secondID), dto.UpdatePaymentDTO{Amount: &amount})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, invoice.Total, decode[model.Payment](t, resp).Data.Amount)
+
+		inv := findInvoice(t)
+		assert.Equal(t, model.InvoiceStatusPaid, inv.Status)
+		assert.Equal(t, money.Zero, inv.AmountDue)
+	})
+
+	t.Run("Filter payments - by invoice", func(t *testing.T) {
+		resp := send(t, http.MethodGet, fmt.Sprintf("%s/payments?invoice_id=%d", api, invoice.ID), nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		result := decode[response.FilterResponse[model.Payment]](t, resp).Data
+		assert.Len(t, result.Items, 1)
+	})
+
+	t.Run("Get payment - not found (404)", func(t *testing.T) {
+		resp := send(t, http.MethodGet, api+"/payments/9999", nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
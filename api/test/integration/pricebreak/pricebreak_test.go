@@ -0,0 +1,111 @@
+package pricebreak_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceBreaks(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+
+	send := func(t *testing.T, method string, url string, reqBody any) *http.Response {
+		body, _ := json.Marshal(reqBody)
+		req, _ := http.NewRequest(method, url, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Create variant with duplicate break quantities (400)", func(t *testing.T) {
+		resp := send(t, http.MethodPost, ts.URL+"/api/v1/products", dto.CreateProductDTO{
+			Name: "Broken Breaks",
+			Variants: []dto.CreateProductVariantDTO{{SKU: "BREAK-0", Price: money.FromInt(10), Stock: 1, PriceBreaks: []dto.PriceBreakDTO{
+				{MinQuantity: 10, Price: money.FromInt(9)},
+				{MinQuantity: 10, Price: money.FromInt(8)},
+			}}},
+		})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	resp := send(t, http.MethodPost, ts.URL+"/api/v1/products", dto.CreateProductDTO{
+		Name: "Exercise Book",
+		Variants: []dto.CreateProductVariantDTO{{SKU: "BREAK-1", Price: money.FromInt(10), Stock: 200, PriceBreaks: []dto.PriceBreakDTO{
+			{MinQuantity: 10, Price: money.FromInt(9)},
+			{MinQuantity: 50, Price: money.MustParse("7.50")},
+		}}},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var created response.APIResponse[model.Product]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+	variant := created.Data.Variants[0]
+	variantURL := fmt.Sprintf("%s/api/v1/products/%d/variants/%d", ts.URL, created.Data.ID, variant.ID)
+
+	t.Run("Get variant - exposes the breaks", func(t *testing.T) {
+		resp, err := http.Get(variantURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var got response.APIResponse[model.Variant]
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+		assert.Equal(t, model.PriceBreaks{
+			{MinQuantity: 10, Price: money.FromInt(9)},
+			{MinQuantity: 50, Price: money.MustParse("7.50")},
+		}, got.Data.PriceBreaks)
+	})
+
+	order := func(t *testing.T, quantity int) model.Order {
+		resp := send(t, http.MethodPost, ts.URL+"/api/v1/orders", dto.CreateOrderDTO{
+			CustomerID: customer.ID,
+			Items:      []dto.CreateOrderItemDTO{{VariantID: variant.ID, Quantity: quantity}},
+			Delivery: dto.CreateDeliveryInfoDTO{
+				Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+			},
+		})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var out response.APIResponse[model.Order]
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		return out.Data
+	}
+
+	t.Run("Create order - priced at the tier of the quantity", func(t *testing.T) {
+		for quantity, want := range map[int]money.Money{
+			9:  money.FromInt(10),
+			10: money.FromInt(9),
+			49: money.FromInt(9),
+			50: money.MustParse("7.50"),
+		} {
+			o := order(t, quantity)
+			assert.Equal(t, want, o.Items[0].UnitPrice, quantity)
+			assert.Equal(t, want.Mul(quantity), o.Subtotal, quantity)
+		}
+	})
+
+	t.Run("Update variant - empty breaks clear them", func(t *testing.T) {
+		resp := send(t, http.MethodPatch, variantURL, dto.UpdateVariantDTO{PriceBreaks: []dto.PriceBreakDTO{}})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		o := order(t, 50)
+		assert.Equal(t, money.FromInt(10), o.Items[0].UnitPrice)
+	})
+}
@@ -0,0 +1,237 @@
+package pricelist_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func do(t *testing.T, method string, url string, reqBody any) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	if reqBody != nil {
+		_ = json.NewEncoder(&body).Encode(reqBody)
+	}
+	req, _ := http.NewRequest(method, url, &body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func createOrder(t *testing.T, url string, customerID uint, currency string, variantIDs ...uint) model.Order {
+	t.Helper()
+	var items []dto.CreateOrderItemDTO
+	for _, ID := range variantIDs {
+		items = append(items, dto.CreateOrderItemDTO{VariantID: ID, Quantity: 1})
+	}
+	resp := do(t, http.MethodPost, url+"/api/v1/orders", dto.CreateOrderDTO{
+		CustomerID: customerID,
+		Currency:   currency,
+		Items:      items,
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	return decode[model.Order](t, resp).Data
+}
+
+func createList(t *testing.T, url string, list dto.CreatePriceListDTO) model.PriceList {
+	t.Helper()
+	resp := do(t, http.MethodPost, url+"/api/v1/price-lists", list)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	return decode[model.PriceList](t, resp).Data
+}
+
+// priceOf returns the unit price and price list of the item for variantID.
+func priceOf(order model.Order, variantID uint) (money.Money, *uint) {
+	for _, item := range order.Items {
+		if item.VariantID == variantID {
+			return item.UnitPrice, item.PriceListID
+		}
+	}
+	return money.Zero, nil
+}
+
+func TestPriceLists(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	first := seed.InsertProductForOrg(db, setup.DefaultOrgID, "PL-SKU-1").Variants[0]
+	second := seed.InsertProductForOrg(db, setup.DefaultOrgID, "PL-SKU-2").Variants[0]
+
+	resp := do(t, http.MethodPost, ts.URL+"/api/v1/customer-groups", dto.CreateCustomerGroupDTO{Name: "Wholesale"})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	wholesale := decode[model.CustomerGroup](t, resp).Data
+
+	resp = do(t, http.MethodPost, ts.URL+"/api/v1/customers", dto.CreateCustomerDTO{FirstName: "Bulk", LastName: "Buyer", PhoneNumber: "+2348000000011", CustomerGroupID: &wholesale.ID})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	buyer := decode[model.Customer](t, resp).Data
+	assert.Equal(t, &wholesale.ID, buyer.CustomerGroupID)
+
+	retail := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+
+	t.Run("Create list with unknown customers - bad request (400)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/price-lists", dto.CreatePriceListDTO{Name: "Broken", CustomerIDs: []uint{999}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrUnknownPriceListRefs, decode[any](t, resp).Message)
+	})
+
+	t.Run("Create list ending before it starts - bad request (400)", func(t *testing.T) {
+		from := time.Now()
+		to := from.Add(-time.Hour)
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/price-lists", dto.CreatePriceListDTO{Name: "Broken", ValidFrom: &from, ValidTo: &to})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrInvalidPriceListPeriod, decode[any](t, resp).Message)
+	})
+
+	groupList := createList(t, ts.URL, dto.CreatePriceListDTO{
+		Name:     "Wholesale",
+		GroupIDs: []uint{wholesale.ID},
+		Items: []dto.PriceListItemDTO{
+			{VariantID: first.ID, Price: money.FromInt(8)},
+			{VariantID: second.ID, Price: money.FromInt(7)},
+		},
+	})
+	assert.Equal(t, "NGN", groupList.Currency)
+
+	t.Run("Create order for a group customer - group prices", func(t *testing.T) {
+		order := createOrder(t, ts.URL, buyer.ID, "", first.ID, second.ID)
+		price, listID := priceOf(order, first.ID)
+		assert.Equal(t, money.FromInt(8), price)
+		assert.Equal(t, &groupList.ID, listID)
+		assert.Equal(t, money.FromInt(15), order.Subtotal)
+	})
+
+	t.Run("Create order for a customer without lists - variant prices", func(t *testing.T) {
+		order := createOrder(t, ts.URL, retail.ID, "", first.ID)
+		price, listID := priceOf(order, first.ID)
+		assert.Equal(t, money.FromInt(10), price)
+		assert.Nil(t, listID)
+	})
+
+	directList := createList(t, ts.URL, dto.CreatePriceListDTO{
+		Name:        "Bulk Buyer contract",
+		CustomerIDs: []uint{buyer.ID},
+		Items:       []dto.PriceListItemDTO{{VariantID: first.ID, Price: money.MustParse("9.50")}},
+	})
+
+	t.Run("Create order - customer lists win over group lists", func(t *testing.T) {
+		order := createOrder(t, ts.URL, buyer.ID, "", first.ID, second.ID)
+		price, listID := priceOf(order, first.ID)
+		assert.Equal(t, money.MustParse("9.50"), price)
+		assert.Equal(t, &directList.ID, listID)
+
+		// Variants the customer list leaves out still get group prices
+		price, listID = priceOf(order, second.ID)
+		assert.Equal(t, money.FromInt(7), price)
+		assert.Equal(t, &groupList.ID, listID)
+	})
+
+	t.Run("Create order - higher priority wins", func(t *testing.T) {
+		promo := createList(t, ts.URL, dto.CreatePriceListDTO{
+			Name:        "Promo",
+			Priority:    10,
+			CustomerIDs: []uint{buyer.ID},
+			Items:       []dto.PriceListItemDTO{{VariantID: first.ID, Price: money.FromInt(6)}},
+		})
+
+		order := createOrder(t, ts.URL, buyer.ID, "", first.ID)
+		price, listID := priceOf(order, first.ID)
+		assert.Equal(t, money.FromInt(6), price)
+		assert.Equal(t, &promo.ID, listID)
+
+		resp := do(t, http.MethodDelete, fmt.Sprintf("%s/api/v1/price-lists/%d", ts.URL, promo.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+	})
+
+	t.Run("Create order - expired and future lists are ignored", func(t *testing.T) {
+		past := time.Now().Add(-48 * time.Hour)
+		yesterday := time.Now().Add(-24 * time.Hour)
+		tomorrow := time.Now().Add(24 * time.Hour)
+		createList(t, ts.URL, dto.CreatePriceListDTO{
+			Name: "Expired", Priority: 20, ValidFrom: &past, ValidTo: &yesterday, CustomerIDs: []uint{buyer.ID},
+			Items: []dto.PriceListItemDTO{{VariantID: first.ID, Price: money.FromInt(1)}},
+		})
+		createList(t, ts.URL, dto.CreatePriceListDTO{
+			Name: "Next season", Priority: 20, ValidFrom: &tomorrow, CustomerIDs: []uint{buyer.ID},
+			Items: []dto.PriceListItemDTO{{VariantID: first.ID, Price: money.FromInt(2)}},
+		})
+
+		order := createOrder(t, ts.URL, buyer.ID, "", first.ID)
+		price, listID := priceOf(order, first.ID)
+		assert.Equal(t, money.MustParse("9.50"), price)
+		assert.Equal(t, &directList.ID, listID)
+	})
+
+	t.Run("Create order in another currency - lists in that currency only", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/exchange-rates", dto.CreateExchangeRateDTO{Currency: "USD", Rate: 2})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp.Body.Close()
+
+		usdList := createList(t, ts.URL, dto.CreatePriceListDTO{
+			Name: "Wholesale USD", Currency: "USD", GroupIDs: []uint{wholesale.ID},
+			Items: []dto.PriceListItemDTO{{VariantID: second.ID, Price: money.MustParse("3.10")}},
+		})
+
+		order := createOrder(t, ts.URL, buyer.ID, "USD", first.ID, second.ID)
+		price, listID := priceOf(order, first.ID)
+		assert.Equal(t, money.FromInt(5), price) // 10 NGN at 2 NGN to the dollar
+		assert.Nil(t, listID)
+		price, listID = priceOf(order, second.ID)
+		assert.Equal(t, money.MustParse("3.10"), price)
+		assert.Equal(t, &usdList.ID, listID)
+	})
+
+	t.Run("Update list - replaces items and groups", func(t *testing.T) {
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/price-lists/%d", ts.URL, groupList.ID), dto.UpdatePriceListDTO{
+			Items:    []dto.PriceListItemDTO{{VariantID: first.ID, Price: money.FromInt(4)}},
+			GroupIDs: []uint{},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		updated := decode[model.PriceList](t, resp).Data
+		require.Len(t, updated.Items, 1)
+		assert.Empty(t, updated.Groups)
+
+		order := createOrder(t, ts.URL, buyer.ID, "", second.ID)
+		price, listID := priceOf(order, second.ID)
+		assert.Equal(t, money.FromInt(10), price)
+		assert.Nil(t, listID)
+	})
+
+	t.Run("Update customer - group can be removed", func(t *testing.T) {
+		none := uint(0)
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/customers/%d", ts.URL, buyer.ID), dto.UpdateCustomerDTO{CustomerGroupID: &none})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+
+		var stored model.Customer
+		require.NoError(t, db.First(&stored, buyer.ID).Error)
+		assert.Nil(t, stored.CustomerGroupID)
+	})
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/deveasyclick/openb2b/internal/shared/response"
 	"github.com/deveasyclick/openb2b/test/integration/seed"
 	"github.com/deveasyclick/openb2b/test/integration/setup"
@@ -26,7 +27,7 @@ func TestProductHandlers(t *testing.T) {
 		reqBody := dto.CreateProductDTO{
 			Name: "New Product",
 			Variants: []dto.CreateProductVariantDTO{
-				{SKU: "SKU1", Price: 10.5, Stock: 5},
+				{SKU: "SKU1", Price: money.MustParse("10.50"), Stock: 5},
 			},
 		}
 		body, _ := json.Marshal(reqBody)
@@ -45,7 +46,7 @@ func TestProductHandlers(t *testing.T) {
 	t.Run("Create product - missing name (400)", func(t *testing.T) {
 		reqBody := dto.CreateProductDTO{
 			Variants: []dto.CreateProductVariantDTO{
-				{SKU: "SKU2", Price: 12, Stock: 3},
+				{SKU: "SKU2", Price: money.FromInt(12), Stock: 3},
 			},
 		}
 		body, _ := json.Marshal(reqBody)
@@ -58,7 +59,7 @@ func TestProductHandlers(t *testing.T) {
 		reqBody := dto.CreateProductDTO{
 			Name: "Duplicate Product",
 			Variants: []dto.CreateProductVariantDTO{
-				{SKU: "SKU3", Price: 15, Stock: 2},
+				{SKU: "SKU3", Price: money.FromInt(15), Stock: 2},
 			},
 		}
 		body, _ := json.Marshal(reqBody)
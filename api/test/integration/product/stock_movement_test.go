@@ -0,0 +1,114 @@
+package product_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockMovementHandlers(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+
+	// Create through the API so the opening stock is recorded
+	reqBody := dto.CreateProductDTO{
+		Name:     "Ledger Product",
+		Variants: []dto.CreateProductVariantDTO{{SKU: "LEDGER-1", Price: money.FromInt(5), Stock: 20}},
+	}
+	body, _ := json.Marshal(reqBody)
+	resp, err := http.Post(ts.URL+"/api/v1/products", "application/json", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var created response.APIResponse[model.Product]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+
+	product := created.Data
+	variant := product.Variants[0]
+	variantURL := fmt.Sprintf("%s/api/v1/products/%d/variants/%d", ts.URL, product.ID, variant.ID)
+
+	patchStock := func(t *testing.T, stock int) *http.Response {
+		body, _ := json.Marshal(dto.UpdateVariantDTO{Stock: &stock})
+		req, _ := http.NewRequest(http.MethodPatch, variantURL, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Update variant stock - records adjustment", func(t *testing.T) {
+		resp := patchStock(t, 15)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var updated response.APIResponse[model.Variant]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&updated))
+		assert.Equal(t, 15, updated.Data.Stock)
+	})
+
+	t.Run("Update variant stock below reserved (409)", func(t *testing.T) {
+		assert.NoError(t, db.Model(&model.Variant{}).Where("id = ?", variant.ID).Update("reserved", 10).Error)
+		defer db.Model(&model.Variant{}).Where("id = ?", variant.ID).Update("reserved", 0)
+
+		resp := patchStock(t, 9)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		var stored model.Variant
+		assert.NoError(t, db.First(&stored, variant.ID).Error)
+		assert.Equal(t, 15, stored.Stock)
+	})
+
+	t.Run("List stock movements - success", func(t *testing.T) {
+		resp, err := http.Get(variantURL + "/movements")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var movements response.APIResponse[response.FilterResponse[model.StockMovement]]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&movements))
+		assert.Equal(t, int64(2), movements.Data.Pagination.Total)
+		if assert.Len(t, movements.Data.Items, 2) {
+			adjustment, receipt := movements.Data.Items[0], movements.Data.Items[1]
+
+			assert.Equal(t, model.StockReasonAdjustment, adjustment.Reason)
+			assert.Equal(t, -5, adjustment.Delta)
+			assert.Equal(t, 15, adjustment.Balance)
+			assert.Equal(t, setup.DefaultUserID, *adjustment.UserID)
+
+			assert.Equal(t, model.StockReasonReceipt, receipt.Reason)
+			assert.Equal(t, 20, receipt.Delta)
+			assert.Equal(t, 20, receipt.Balance)
+		}
+	})
+
+	t.Run("List stock movements - filter by reason", func(t *testing.T) {
+		resp, err := http.Get(variantURL + "/movements?reason=receipt")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var movements response.APIResponse[response.FilterResponse[model.StockMovement]]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&movements))
+		assert.Len(t, movements.Data.Items, 1)
+	})
+
+	t.Run("List stock movements - variant of another product (404)", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf("%s/api/v1/products/%d/variants/%d/movements", ts.URL, product.ID+1000, variant.ID))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,91 @@
+package product_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductTenantIsolation(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.ClearProducts(db)
+	defer seed.ClearProducts(db)
+
+	own := seed.InsertProductForOrg(db, setup.DefaultOrgID, "OWN-SKU")
+	foreign := seed.InsertProductForOrg(db, 2, "FOREIGN-SKU")
+	foreignURL := fmt.Sprintf("%s/api/v1/products/%d", ts.URL, foreign.ID)
+	foreignVariantURL := fmt.Sprintf("%s/variants/%d", foreignURL, foreign.Variants[0].ID)
+
+	t.Run("Get product of another org - not found (404)", func(t *testing.T) {
+		resp, err := http.Get(foreignURL)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Update product of another org - not found (404)", func(t *testing.T) {
+		name := "Hijacked"
+		body, _ := json.Marshal(dto.UpdateProductDTO{Name: &name})
+		req, _ := http.NewRequest(http.MethodPatch, foreignURL, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Delete product of another org - not found (404)", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, foreignURL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Get variant of another org - not found (404)", func(t *testing.T) {
+		resp, err := http.Get(foreignVariantURL)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Delete variant of another org - not found (404)", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, foreignVariantURL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Filter products - only own org", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/v1/products?preloads=Variants")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var products response.APIResponse[response.FilterResponse[model.Product]]
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&products))
+		assert.Len(t, products.Data.Items, 1)
+		assert.Equal(t, own.ID, products.Data.Items[0].ID)
+		assert.Equal(t, int64(1), products.Data.Pagination.Total)
+	})
+
+	t.Run("Product of another org is untouched", func(t *testing.T) {
+		var stored model.Product
+		assert.NoError(t, db.Preload("Variants").First(&stored, foreign.ID).Error)
+		assert.Equal(t, "Product FOREIGN-SKU", stored.Name)
+		assert.Len(t, stored.Variants, 1)
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
 	"github.com/deveasyclick/openb2b/internal/shared/response"
 	"github.com/deveasyclick/openb2b/test/integration/seed"
 	"github.com/deveasyclick/openb2b/test/integration/setup"
@@ -27,7 +28,7 @@ func TestProductVariantHandlers(t *testing.T) {
 			SKU:     "SKU-003",
 			Color:   "Red",
 			Size:    "M",
-			Price:   19.99,
+			Price:   money.MustParse("19.99"),
 			Stock:   100,
 			TaxRate: 0.1,
 		}
@@ -46,7 +47,7 @@ func TestProductVariantHandlers(t *testing.T) {
 		assert.Equal(t, variant.Data.SKU, "SKU-003")
 		assert.Equal(t, variant.Data.Color, "Red")
 		assert.Equal(t, variant.Data.Size, "M")
-		assert.Equal(t, variant.Data.Price, 19.99)
+		assert.Equal(t, money.MustParse("19.99"), variant.Data.Price)
 		assert.Equal(t, variant.Data.Stock, 100)
 		assert.Equal(t, variant.Data.TaxRate, 0.1)
 		assert.Equal(t, variant.Data.ProductID, product.ID)
@@ -62,7 +63,7 @@ func TestProductVariantHandlers(t *testing.T) {
 			SKU:     "SKU-003",
 			Color:   "Blue",
 			Size:    "L",
-			Price:   29.99,
+			Price:   money.MustParse("29.99"),
 			Stock:   50,
 			TaxRate: 0.1,
 		}
@@ -94,7 +95,7 @@ func TestProductVariantHandlers(t *testing.T) {
 		var variant response.APIResponse[model.Variant]
 		err = json.NewDecoder(resp.Body).Decode(&variant)
 		assert.NoError(t, err)
-		assert.Equal(t, variant.Data.Price, 24.99)
+		assert.Equal(t, money.MustParse("24.99"), variant.Data.Price)
 		assert.Equal(t, variant.Data.ProductID, product.ID)
 	})
 
@@ -0,0 +1,69 @@
+package promotion_test
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentCustomerRedemptions(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "CONCURRENT-PROMO-SKU")
+
+	perCustomerLimit := 1
+	promotion := createPromotion(t, ts.URL, dto.CreatePromotionDTO{
+		Code:             "ONCE5",
+		Name:             "Once per customer",
+		Type:             model.PromotionPercentage,
+		Amount:           money.FromInt(5),
+		PerCustomerLimit: &perCustomerLimit,
+	})
+
+	// Three orders of one customer with the code, created at the same time.
+	// The test database has a single connection, so this checks that only
+	// one gets the promotion; the lock on the promotion only contends on
+	// Postgres.
+	const orders = 3
+	var wg sync.WaitGroup
+	statuses := make(chan int, orders)
+	for range orders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := createOrder(t, ts.URL, customer.ID, "ONCE5", dto.CreateOrderItemDTO{VariantID: product.Variants[0].ID, Quantity: 1})
+			resp.Body.Close()
+			statuses <- resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	created := 0
+	for status := range statuses {
+		if status == http.StatusCreated {
+			created++
+			continue
+		}
+		assert.Equal(t, http.StatusConflict, status)
+	}
+	assert.Equal(t, 1, created)
+
+	var redemptions int64
+	require.NoError(t, db.Model(&model.PromotionRedemption{}).
+		Where("promotion_id = ? AND customer_id = ?", promotion.ID, customer.ID).
+		Count(&redemptions).Error)
+	assert.Equal(t, int64(1), redemptions)
+}
@@ -0,0 +1,249 @@
+package promotion_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func do(t *testing.T, method string, url string, reqBody any) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	if reqBody != nil {
+		_ = json.NewEncoder(&body).Encode(reqBody)
+	}
+	req, _ := http.NewRequest(method, url, &body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func createOrder(t *testing.T, url string, customerID uint, code string, items ...dto.CreateOrderItemDTO) *http.Response {
+	t.Helper()
+	return do(t, http.MethodPost, url+"/api/v1/orders", dto.CreateOrderDTO{
+		CustomerID:    customerID,
+		Items:         items,
+		PromotionCode: code,
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	})
+}
+
+func createPromotion(t *testing.T, url string, promotion dto.CreatePromotionDTO) model.Promotion {
+	t.Helper()
+	resp := do(t, http.MethodPost, url+"/api/v1/promotions", promotion)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	return decode[model.Promotion](t, resp).Data
+}
+
+func TestPromotions(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+
+	book := seed.InsertProductForOrg(db, setup.DefaultOrgID, "PROMO-BOOK")
+	book.Category = "Stationery"
+	require.NoError(t, db.Save(&book).Error)
+	pen := seed.InsertProductForOrg(db, setup.DefaultOrgID, "PROMO-PEN")
+	require.NoError(t, db.Model(&model.Variant{}).Where("product_id IN ?", []uint{book.ID, pen.ID}).Update("stock", 100).Error)
+	require.NoError(t, db.Model(&pen.Variants[0]).Update("price", money.FromInt(20)).Error)
+
+	bookItem := func(quantity int) dto.CreateOrderItemDTO {
+		return dto.CreateOrderItemDTO{VariantID: book.Variants[0].ID, Quantity: quantity}
+	}
+	penItem := dto.CreateOrderItemDTO{VariantID: pen.Variants[0].ID, Quantity: 1}
+
+	usageLimit, perCustomerLimit := 2, 1
+	spring := createPromotion(t, ts.URL, dto.CreatePromotionDTO{
+		Code:             "spring10",
+		Name:             "Spring sale",
+		Type:             model.PromotionPercentage,
+		Amount:           money.FromInt(10),
+		Categories:       []string{"Stationery"},
+		UsageLimit:       &usageLimit,
+		PerCustomerLimit: &perCustomerLimit,
+	})
+	assert.Equal(t, "SPRING10", spring.Code)
+	assert.True(t, spring.Active)
+
+	t.Run("Create promotion with a used code - conflict (409)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/promotions", dto.CreatePromotionDTO{Code: "Spring10", Name: "Again", Type: model.PromotionFixed, Amount: money.FromInt(1)})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrPromotionCodeExists, decode[any](t, resp).Message)
+	})
+
+	t.Run("Create promotion over 100% - bad request (400)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/promotions", dto.CreatePromotionDTO{Code: "ALL", Name: "All", Type: model.PromotionPercentage, Amount: money.FromInt(101)})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrInvalidPromotionPercentage, decode[any](t, resp).Message)
+	})
+
+	t.Run("Create buy X get Y promotion without quantities - bad request (400)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/promotions", dto.CreatePromotionDTO{Code: "FREE", Name: "Free", Type: model.PromotionBuyXGetY})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Create order with an unknown code - bad request (400)", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, "NOPE", bookItem(1))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrUnknownPromotion, decode[any](t, resp).Message)
+	})
+
+	t.Run("Create order with a code for none of its items - bad request (400)", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, "SPRING10", penItem)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrPromotionNotApplicable, decode[any](t, resp).Message)
+	})
+
+	var order model.Order
+	t.Run("Create order with a code - discounts the eligible items", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, "spring10", bookItem(2), penItem)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		order = decode[model.Order](t, resp).Data
+
+		require.NotNil(t, order.PromotionID)
+		assert.Equal(t, spring.ID, *order.PromotionID)
+		assert.Equal(t, "SPRING10", order.PromotionCode)
+		assert.True(t, order.Items[0].Promoted)
+		assert.False(t, order.Items[1].Promoted)
+		assert.Equal(t, money.FromInt(2), order.Items[0].AppliedPromotionDiscount)
+		assert.Equal(t, money.FromInt(2), order.PromotionDiscount)
+		assert.Equal(t, money.FromInt(2), order.DiscountTotal)
+		assert.Equal(t, money.FromInt(38), order.Total)
+
+		var stored model.Promotion
+		require.NoError(t, db.First(&stored, spring.ID).Error)
+		assert.Equal(t, 1, stored.TimesUsed)
+	})
+
+	t.Run("Use a code twice as one customer - conflict (409)", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, "SPRING10", bookItem(1))
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrPromotionUsedUp, decode[any](t, resp).Message)
+	})
+
+	t.Run("List redemptions - the order and its discount", func(t *testing.T) {
+		resp := do(t, http.MethodGet, fmt.Sprintf("%s/api/v1/promotions/%d/redemptions", ts.URL, spring.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		redemptions := decode[response.FilterResponse[model.PromotionRedemption]](t, resp).Data.Items
+		require.Len(t, redemptions, 1)
+		assert.Equal(t, order.ID, redemptions[0].OrderID)
+		assert.Equal(t, customer.ID, redemptions[0].CustomerID)
+		assert.Equal(t, money.FromInt(2), redemptions[0].Amount)
+		assert.Equal(t, "NGN", redemptions[0].Currency)
+	})
+
+	t.Run("Update order items - the promotion follows them", func(t *testing.T) {
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, order.ID), dto.UpdateOrderDTO{Items: []*dto.CreateOrderItemDTO{{VariantID: book.Variants[0].ID, Quantity: 5}}})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		updated := decode[model.Order](t, resp).Data
+		assert.Equal(t, money.FromInt(5), updated.PromotionDiscount)
+		assert.Equal(t, money.FromInt(45), updated.Total)
+
+		var redemption model.PromotionRedemption
+		require.NoError(t, db.Where("order_id = ?", order.ID).First(&redemption).Error)
+		assert.Equal(t, money.FromInt(5), redemption.Amount)
+	})
+
+	t.Run("Remove the code from an order - gives the use back", func(t *testing.T) {
+		none := ""
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, order.ID), dto.UpdateOrderDTO{PromotionCode: &none})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		updated := decode[model.Order](t, resp).Data
+		assert.Nil(t, updated.PromotionID)
+		assert.Equal(t, money.Zero, updated.PromotionDiscount)
+		assert.Equal(t, money.FromInt(50), updated.Total)
+
+		var stored model.Promotion
+		require.NoError(t, db.First(&stored, spring.ID).Error)
+		assert.Equal(t, 0, stored.TimesUsed)
+	})
+
+	t.Run("Cancel an order - gives the use back", func(t *testing.T) {
+		resp := createOrder(t, ts.URL, customer.ID, "SPRING10", bookItem(1))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		created := decode[model.Order](t, resp).Data
+
+		resp = do(t, http.MethodPost, fmt.Sprintf("%s/api/v1/orders/%d/cancel", ts.URL, created.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+
+		var stored model.Promotion
+		require.NoError(t, db.First(&stored, spring.ID).Error)
+		assert.Equal(t, 0, stored.TimesUsed)
+	})
+
+	t.Run("Create order with buy 2 get 1 - a unit free in every 3", func(t *testing.T) {
+		createPromotion(t, ts.URL, dto.CreatePromotionDTO{
+			Code:        "B2G1",
+			Name:        "Buy 2 get 1 free",
+			Type:        model.PromotionBuyXGetY,
+			BuyQuantity: 2,
+			GetQuantity: 1,
+			ProductIDs:  []uint{book.ID},
+		})
+
+		resp := createOrder(t, ts.URL, customer.ID, "B2G1", bookItem(7), penItem)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		order := decode[model.Order](t, resp).Data
+		assert.Equal(t, money.FromInt(20), order.PromotionDiscount)
+		assert.Equal(t, money.FromInt(70), order.Total)
+	})
+
+	t.Run("Create order below the minimum order value - bad request (400)", func(t *testing.T) {
+		createPromotion(t, ts.URL, dto.CreatePromotionDTO{
+			Code:          "BIG5",
+			Name:          "5 off big orders",
+			Type:          model.PromotionFixed,
+			Amount:        money.FromInt(5),
+			MinOrderValue: money.FromInt(100),
+		})
+
+		resp := createOrder(t, ts.URL, customer.ID, "BIG5", penItem)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrPromotionMinOrderValue, decode[any](t, resp).Message)
+	})
+
+	t.Run("Create order with an ended promotion - bad request (400)", func(t *testing.T) {
+		startsAt, endsAt := time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour)
+		createPromotion(t, ts.URL, dto.CreatePromotionDTO{
+			Code:     "OLD",
+			Name:     "Over",
+			Type:     model.PromotionFixed,
+			Amount:   money.FromInt(1),
+			StartsAt: &startsAt,
+			EndsAt:   &endsAt,
+		})
+
+		resp := createOrder(t, ts.URL, customer.ID, "OLD", penItem)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrPromotionInactive, decode[any](t, resp).Message)
+	})
+}
@@ -0,0 +1,207 @@
+package purchaseorder_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/internal/shared/types"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func do(t *testing.T, method string, url string, reqBody any) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	if reqBody != nil {
+		_ = json.NewEncoder(&body).Encode(reqBody)
+	}
+	req, _ := http.NewRequest(method, url, &body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func findVariant(t *testing.T, db *gorm.DB, ID uint) model.Variant {
+	t.Helper()
+	var variant model.Variant
+	require.NoError(t, db.First(&variant, ID).Error)
+	return variant
+}
+
+func TestPurchaseOrders(t *testing.T) {
+	ts, worker := setup.SetupTestServerWithWorker(setup.DefaultUserID, setup.DefaultOrgID, model.RoleOwner)
+	defer ts.Close()
+	api := ts.URL + "/api/v1"
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	first := seed.InsertProductForOrg(db, setup.DefaultOrgID, "PO-SKU-1").Variants[0] // stock 10
+	second := seed.InsertProductForOrg(db, setup.DefaultOrgID, "PO-SKU-2").Variants[0]
+
+	var supplier model.Supplier
+	t.Run("Create supplier", func(t *testing.T) {
+		resp := do(t, http.MethodPost, api+"/suppliers", dto.CreateSupplierDTO{Name: "Acme Wholesale", Email: "orders@acme.test", Currency: "USD"})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		supplier = decode[model.Supplier](t, resp).Data
+		assert.Equal(t, "Acme Wholesale", supplier.Name)
+
+		resp = do(t, http.MethodPost, api+"/suppliers", dto.CreateSupplierDTO{Name: "No Email"})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	var po model.PurchaseOrder
+	t.Run("Create purchase order - draft at cost prices", func(t *testing.T) {
+		resp := do(t, http.MethodPost, api+"/purchase-orders", dto.CreatePurchaseOrderDTO{
+			SupplierID: supplier.ID,
+			Lines: []dto.PurchaseOrderLineDTO{
+				{VariantID: first.ID, Quantity: 5, UnitCost: money.FromInt(4)},
+				{VariantID: second.ID, Quantity: 3, UnitCost: money.FromCents(250)},
+			},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		po = decode[model.PurchaseOrder](t, resp).Data
+
+		assert.Equal(t, model.PurchaseOrderDraft, po.Status)
+		assert.Equal(t, "USD", po.Currency)
+		assert.Equal(t, "27.50", po.Total.String())
+		require.Len(t, po.Lines, 2)
+		assert.Equal(t, "PO-SKU-1", po.Lines[0].SKU)
+		assert.Equal(t, "20.00", po.Lines[0].LineTotal.String())
+	})
+
+	t.Run("Create purchase order - unknown variant (400)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, api+"/purchase-orders", dto.CreatePurchaseOrderDTO{
+			SupplierID: supplier.ID,
+			Lines:      []dto.PurchaseOrderLineDTO{{VariantID: 9999, Quantity: 1, UnitCost: money.FromInt(1)}},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	t.Run("Receive - draft purchase orders can't be received (409)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/purchase-orders/%d/receive", api, po.ID), dto.ReceivePurchaseOrderDTO{})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	t.Run("Send - emails the purchase order", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/purchase-orders/%d/send", api, po.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		sent := decode[model.PurchaseOrder](t, resp).Data
+		assert.Equal(t, model.PurchaseOrderSent, sent.Status)
+		assert.NotNil(t, sent.SentAt)
+
+		var jobs int64
+		db.Model(&model.Job{}).Where("type = ?", types.PurchaseOrderEmailJobType).Count(&jobs)
+		assert.Equal(t, int64(1), jobs)
+
+		// Sent purchase orders are locked
+		notes := "too late"
+		resp = do(t, http.MethodPatch, fmt.Sprintf("%s/purchase-orders/%d", api, po.ID), dto.UpdatePurchaseOrderDTO{Notes: &notes})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	t.Run("Receive - part of a line", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/purchase-orders/%d/receive", api, po.ID), dto.ReceivePurchaseOrderDTO{
+			Lines: []dto.ReceivePurchaseOrderLineDTO{{LineID: po.Lines[0].ID, Quantity: 2}},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		received := decode[model.PurchaseOrder](t, resp).Data
+		assert.Equal(t, model.PurchaseOrderPartiallyReceived, received.Status)
+		assert.Equal(t, 2, received.Lines[0].ReceivedQuantity)
+
+		assert.Equal(t, 12, findVariant(t, db, first.ID).Stock)
+
+		var movement model.StockMovement
+		require.NoError(t, db.Where("variant_id = ? AND reason = ?", first.ID, model.StockReasonReceipt).
+			Order("id desc").First(&movement).Error)
+		assert.Equal(t, 2, movement.Delta)
+		require.NotNil(t, movement.ReferenceID)
+		assert.Equal(t, po.ID, *movement.ReferenceID)
+	})
+
+	t.Run("Receive - more than is expected (400)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/purchase-orders/%d/receive", api, po.ID), dto.ReceivePurchaseOrderDTO{
+			Lines: []dto.ReceivePurchaseOrderLineDTO{{LineID: po.Lines[0].ID, Quantity: 4}},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	t.Run("Receive - the rest allocates backorders", func(t *testing.T) {
+		allow := true
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/products/%d/variants/%d", api, second.ProductID, second.ID), dto.UpdateVariantDTO{AllowBackorder: &allow})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		decode[any](t, resp)
+
+		resp = do(t, http.MethodPost, api+"/orders", dto.CreateOrderDTO{
+			CustomerID: customer.ID,
+			Items:      []dto.CreateOrderItemDTO{{VariantID: second.ID, Quantity: 12}},
+			Delivery: dto.CreateDeliveryInfoDTO{
+				Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+			},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		order := decode[model.Order](t, resp).Data
+		require.Equal(t, 2, order.Items[0].BackorderedQuantity)
+
+		resp = do(t, http.MethodPost, fmt.Sprintf("%s/purchase-orders/%d/receive", api, po.ID), dto.ReceivePurchaseOrderDTO{})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		received := decode[model.PurchaseOrder](t, resp).Data
+		assert.Equal(t, model.PurchaseOrderReceived, received.Status)
+		assert.NotNil(t, received.ReceivedAt)
+
+		assert.Equal(t, 15, findVariant(t, db, first.ID).Stock)
+		assert.Equal(t, 13, findVariant(t, db, second.ID).Stock)
+
+		worker.Drain(t)
+		var item model.OrderItem
+		require.NoError(t, db.Where("order_id = ?", order.ID).First(&item).Error)
+		assert.Equal(t, 12, item.AllocatedQuantity)
+		assert.Equal(t, 0, item.BackorderedQuantity)
+	})
+
+	t.Run("Receive - nothing left (409)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/purchase-orders/%d/receive", api, po.ID), dto.ReceivePurchaseOrderDTO{})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	t.Run("Close - received purchase order", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/purchase-orders/%d/close", api, po.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, model.PurchaseOrderClosed, decode[model.PurchaseOrder](t, resp).Data.Status)
+
+		resp = do(t, http.MethodPost, fmt.Sprintf("%s/purchase-orders/%d/send", api, po.ID), nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	t.Run("Delete supplier - with purchase orders (409)", func(t *testing.T) {
+		resp := do(t, http.MethodDelete, fmt.Sprintf("%s/suppliers/%d", api, supplier.ID), nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+	})
+}
@@ -0,0 +1,106 @@
+package rbac_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertForbidden(t *testing.T, resp *http.Response) {
+	t.Helper()
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	var apiErr apperrors.APIErrorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&apiErr))
+	assert.Equal(t, http.StatusForbidden, apiErr.Code)
+	assert.Equal(t, apperrors.ErrForbidden, apiErr.Message)
+}
+
+func TestViewerPermissions(t *testing.T) {
+	ts := setup.SetupTestServerAs(setup.DefaultUserID, setup.DefaultOrgID, model.RoleViewer)
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.ClearProducts(db)
+	product := seed.InsertProducts(db)
+	defer seed.ClearProducts(db)
+
+	t.Run("Filter products - allowed", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/v1/products")
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Create product - forbidden (403)", func(t *testing.T) {
+		body, _ := json.Marshal(dto.CreateProductDTO{
+			Name:     "Viewer Product",
+			Variants: []dto.CreateProductVariantDTO{{SKU: "VIEWER-1", Price: money.FromInt(1), Stock: 1}},
+		})
+		resp, err := http.Post(ts.URL+"/api/v1/products", "application/json", bytes.NewBuffer(body))
+		assert.NoError(t, err)
+		assertForbidden(t, resp)
+	})
+
+	t.Run("Delete product - forbidden (403)", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/products/%d", ts.URL, product.ID), nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assertForbidden(t, resp)
+
+		var count int64
+		db.Model(&model.Product{}).Where("id = ?", product.ID).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Create customer - forbidden (403)", func(t *testing.T) {
+		body, _ := json.Marshal(dto.CreateCustomerDTO{FirstName: "John", LastName: "Doe", PhoneNumber: "+1-202-555-0199"})
+		resp, err := http.Post(ts.URL+"/api/v1/customers", "application/json", bytes.NewBuffer(body))
+		assert.NoError(t, err)
+		assertForbidden(t, resp)
+	})
+
+	t.Run("Delete org - forbidden (403)", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/api/v1/orgs/1", nil)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assertForbidden(t, resp)
+	})
+}
+
+func TestSalesPermissions(t *testing.T) {
+	ts := setup.SetupTestServerAs(setup.DefaultUserID, setup.DefaultOrgID, model.RoleSales)
+	defer ts.Close()
+
+	t.Run("Issue invoice - forbidden (403)", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/api/v1/invoices/1/issue", "application/json", nil)
+		assert.NoError(t, err)
+		assertForbidden(t, resp)
+	})
+
+	t.Run("Update product - forbidden (403)", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPatch, ts.URL+"/api/v1/products/1", bytes.NewBufferString(`{"name":"Renamed"}`))
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assertForbidden(t, resp)
+	})
+
+	for _, path := range []string{"/orders/1/deliver", "/shipments/1/ship", "/shipments/1/deliver"} {
+		t.Run("Post "+path+" - forbidden (403)", func(t *testing.T) {
+			resp, err := http.Post(ts.URL+"/api/v1"+path, "application/json", nil)
+			assert.NoError(t, err)
+			assertForbidden(t, resp)
+		})
+	}
+}
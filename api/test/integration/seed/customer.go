@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
 	"gorm.io/gorm"
 )
 
@@ -23,6 +24,7 @@ func InsertCustomers(db *gorm.DB) {
 			Zip:     "02912",
 		},
 		Company: "OpenB2B",
+		OrgID:   setup.DefaultOrgID,
 	}
 
 	err := db.Create(&Customer).Error
@@ -38,3 +40,19 @@ func ClearCustomers(db *gorm.DB) {
 	}
 
 }
+
+// InsertCustomerForOrg creates a customer owned by the given org.
+func InsertCustomerForOrg(db *gorm.DB, orgID uint) model.Customer {
+	customer := model.Customer{
+		FirstName:   "Jane",
+		LastName:    "Roe",
+		PhoneNumber: "+1-202-555-0100",
+		OrgID:       orgID,
+	}
+
+	if err := db.Create(&customer).Error; err != nil {
+		log.Fatalf("failed to create customer: %v", err)
+	}
+
+	return customer
+}
@@ -4,17 +4,21 @@ import (
 	"log"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
 	"gorm.io/gorm"
 )
 
 var NonPendingOrderId uint
 
-func InsertOrders(db *gorm.DB) {
+// InsertOrders inserts a pending and an approved order of the customer.
+func InsertOrders(db *gorm.DB, customerID uint) {
 	db.Create(&model.Order{
 		Notes:       "Notes",
 		OrderNumber: "ORD-123",
+		OrgID:       setup.DefaultOrgID,
+		CustomerID:  customerID,
 	}) // create an order to seed the database
-	nonPendingOrder := &model.Order{Status: "processing", OrderNumber: "ORD-124"}
+	nonPendingOrder := &model.Order{Status: model.OrderStatusApproved, OrderNumber: "ORD-124", OrgID: setup.DefaultOrgID, CustomerID: customerID}
 	err := db.Create(nonPendingOrder).Error
 	if err != nil {
 		log.Fatalf("failed to create order: %v", err)
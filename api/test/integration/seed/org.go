@@ -1,6 +1,7 @@
 package seed
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/deveasyclick/openb2b/internal/model"
@@ -39,3 +40,21 @@ func ClearOrgs(db *gorm.DB) {
 	}
 
 }
+
+// InsertOrgWithID creates the org with the given ID, unless it exists.
+// Requests of test servers are authenticated as a member of such an org.
+func InsertOrgWithID(db *gorm.DB, orgID uint) model.Org {
+	org := model.Org{
+		BaseModel:        model.BaseModel{ID: orgID},
+		Name:             fmt.Sprintf("org-%d", orgID),
+		OrganizationName: fmt.Sprintf("Org %d", orgID),
+		Email:            fmt.Sprintf("org-%d@openb2b.io", orgID),
+		Phone:            "+1-202-555-0199",
+	}
+
+	if err := db.Unscoped().Where("id = ?", orgID).Assign(map[string]any{"deleted_at": nil}).FirstOrCreate(&org).Error; err != nil {
+		log.Fatalf("failed to create org: %v", err)
+	}
+
+	return org
+}
@@ -4,6 +4,8 @@ import (
 	"log"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
 	"gorm.io/gorm"
 )
 
@@ -12,8 +14,9 @@ func InsertProducts(db *gorm.DB) model.Product {
 		SKU:   "SKU-001",
 		Color: "Red",
 		Size:  "M",
-		Price: 10.0,
+		Price: money.FromInt(10),
 		Stock: 100,
+		OrgID: setup.DefaultOrgID,
 		BaseModel: model.BaseModel{
 			ID: 100,
 		},
@@ -23,8 +26,9 @@ func InsertProducts(db *gorm.DB) model.Product {
 		SKU:   "SKU-002",
 		Color: "Blue",
 		Size:  "L",
-		Price: 20.0,
+		Price: money.FromInt(20),
 		Stock: 50,
+		OrgID: setup.DefaultOrgID,
 		BaseModel: model.BaseModel{
 			ID: 99,
 		},
@@ -32,6 +36,7 @@ func InsertProducts(db *gorm.DB) model.Product {
 
 	product := model.Product{
 		Name:     "Test Product 1",
+		OrgID:    setup.DefaultOrgID,
 		Variants: []model.Variant{variant, variant2},
 	}
 
@@ -51,3 +56,20 @@ func ClearProducts(db *gorm.DB) {
 	}
 
 }
+
+// InsertProductForOrg creates a product with a single variant owned by the given org.
+func InsertProductForOrg(db *gorm.DB, orgID uint, sku string) model.Product {
+	product := model.Product{
+		Name:  "Product " + sku,
+		OrgID: orgID,
+		Variants: []model.Variant{
+			{SKU: sku, Price: money.FromInt(10), Stock: 10, OrgID: orgID},
+		},
+	}
+
+	if err := db.Create(&product).Error; err != nil {
+		log.Fatalf("failed to create product: %v", err)
+	}
+
+	return product
+}
@@ -1,16 +1,25 @@
 package setup
 
 import (
+	"context"
 	"log"
 
 	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/audit"
+	"github.com/deveasyclick/openb2b/internal/shared/tenant"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 var TestDB *gorm.DB
 
+// SetupTestDB returns a handle on the shared test database that bypasses
+// tenant scoping, so tests can seed and inspect rows of any org.
 func SetupTestDB() *gorm.DB {
+	return openTestDB().WithContext(tenant.Bypass(context.Background()))
+}
+
+func openTestDB() *gorm.DB {
 	if TestDB != nil {
 		return TestDB
 	}
@@ -19,6 +28,13 @@ func SetupTestDB() *gorm.DB {
 	if err != nil {
 		log.Fatalf("failed to connect test db: %v", err)
 	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("failed to connect test db: %v", err)
+	}
+	// Every connection to :memory: opens a database of its own, so requests
+	// running at the same time share the one connection
+	sqlDB.SetMaxOpenConns(1)
 
 	err = db.AutoMigrate(
 		&model.User{},
@@ -28,12 +44,53 @@ func SetupTestDB() *gorm.DB {
 		&model.Customer{},
 		&model.Order{},
 		&model.OrderItem{},
+		&model.OrderStatusHistory{},
+		&model.Invoice{},
+		&model.InvoiceItem{},
+		&model.Payment{},
+		&model.CreditNote{},
+		&model.CreditNoteItem{},
+		&model.StockMovement{},
+		&model.Job{},
+		&model.OutboxEvent{},
+		&model.WebhookSubscription{},
+		&model.WebhookDelivery{},
+		&model.APIKey{},
+		&model.IdempotencyKey{},
+		&model.AuditEntry{},
+		&model.ExchangeRate{},
+		&model.TaxRate{},
+		&model.TaxClass{},
+		&model.CustomerGroup{},
+		&model.PriceList{},
+		&model.PriceListItem{},
+		&model.Promotion{},
+		&model.PromotionRedemption{},
+		&model.OrderCharge{},
+		&model.InvoiceCharge{},
+		&model.Shipment{},
+		&model.ShipmentLine{},
+		&model.Supplier{},
+		&model.PurchaseOrder{},
+		&model.PurchaseOrderLine{},
+		&model.Warehouse{},
+		&model.StockLevel{},
+		&model.StockTransfer{},
+		&model.StockTransferLine{},
 	)
 
 	if err != nil {
 		log.Fatalf("failed to migrate test database: %v", err)
 	}
 
+	if err := tenant.Register(db); err != nil {
+		log.Fatalf("failed to register tenant callbacks: %v", err)
+	}
+
+	if err := audit.Register(db); err != nil {
+		log.Fatalf("failed to register audit callbacks: %v", err)
+	}
+
 	TestDB = db
 
 	return db
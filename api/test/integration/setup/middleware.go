@@ -7,27 +7,40 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 
 	"github.com/clerk/clerk-sdk-go/v2"
 	clerkHttp "github.com/clerk/clerk-sdk-go/v2/http"
+	"github.com/deveasyclick/openb2b/internal/idempotency"
+	"github.com/deveasyclick/openb2b/internal/middleware"
+	"github.com/deveasyclick/openb2b/internal/model"
 	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
 	"github.com/deveasyclick/openb2b/internal/shared/identity"
+	"github.com/deveasyclick/openb2b/internal/shared/rbac"
 	"github.com/deveasyclick/openb2b/internal/shared/response"
 	"github.com/deveasyclick/openb2b/internal/shared/types"
 	"github.com/deveasyclick/openb2b/pkg/interfaces"
+	"github.com/deveasyclick/openb2b/pkg/logger"
+	"gorm.io/gorm"
 )
 
 type fakeMiddleware struct {
 	UserID  uint
 	OrgID   uint
 	ClerkID string
+	Role    model.Role
+	db      *gorm.DB
+	logger  interfaces.Logger
 }
 
-func NewFake(userId uint, orgId uint, clerkId string) interfaces.Middleware {
+func NewFake(db *gorm.DB, userId uint, orgId uint, clerkId string, role model.Role) interfaces.Middleware {
 	return &fakeMiddleware{
-		UserID:  1,
-		OrgID:   1,
+		UserID:  userId,
+		OrgID:   orgId,
 		ClerkID: clerkId,
+		Role:    role,
+		db:      db,
+		logger:  logger.New(os.Getenv("ENV")),
 	}
 }
 
@@ -61,6 +74,7 @@ func (m *fakeMiddleware) ValidateJWT(opts ...clerkHttp.AuthorizationOption) func
 				Custom: &identity.CustomSessionClaims{
 					UserID: fmt.Sprintf("%d", m.UserID),
 					OrgID:  fmt.Sprintf("%d", m.OrgID),
+					Role:   string(m.Role),
 				},
 			}
 
@@ -71,6 +85,17 @@ func (m *fakeMiddleware) ValidateJWT(opts ...clerkHttp.AuthorizationOption) func
 	}
 }
 
+// Authenticate in tests checks API keys against the test database like the
+// real middleware, and falls back to the fake Clerk session of ValidateJWT.
+func (m *fakeMiddleware) Authenticate() func(http.Handler) http.Handler {
+	return middleware.WithAPIKeys(m.db, m.logger, m.ValidateJWT())
+}
+
+// Idempotent in tests keeps idempotency keys in the test database.
+func (m *fakeMiddleware) Idempotent() func(http.Handler) http.Handler {
+	return middleware.WithIdempotency(idempotency.NewStore(m.db), m.logger)
+}
+
 func (m *fakeMiddleware) VerifyWebhook() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -99,3 +124,27 @@ func (m *fakeMiddleware) VerifyWebhook() func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// RequirePermission in tests checks the role injected by ValidateJWT against
+// the permission matrix, or the scopes of the API key.
+func (m *fakeMiddleware) RequirePermission(perms ...rbac.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, err := identity.UserFromContext(r.Context())
+			if err == nil && user.APIKeyID != 0 {
+				if !rbac.InScopes(user.Scopes, perms...) {
+					response.WriteJSONErrorV2(w, http.StatusForbidden, nil, apperrors.ErrForbidden, m.logger)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err != nil || !rbac.Can(model.Role(user.Role), perms...) {
+				response.WriteJSONErrorV2(w, http.StatusForbidden, nil, apperrors.ErrForbidden, m.logger)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
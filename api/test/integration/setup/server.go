@@ -1,10 +1,15 @@
 package setup
 
 import (
+	"crypto/tls"
+	"net/http"
 	"net/http/httptest"
 	"os"
 
 	"github.com/deveasyclick/openb2b/internal/config"
+	"github.com/deveasyclick/openb2b/internal/jobs"
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/outbox"
 	"github.com/deveasyclick/openb2b/internal/routes"
 	"github.com/deveasyclick/openb2b/internal/shared/deps"
 	"github.com/deveasyclick/openb2b/pkg/clerk"
@@ -12,20 +17,60 @@ import (
 	"github.com/go-chi/chi"
 )
 
+const (
+	// DefaultUserID is the authenticated user for SetupTestServer.
+	DefaultUserID uint = 1
+	// DefaultOrgID is the org of the authenticated user for SetupTestServer.
+	DefaultOrgID uint = 1
+)
+
 func SetupTestServer() *httptest.Server {
+	return SetupTestServerForOrg(DefaultUserID, DefaultOrgID)
+}
+
+// SetupTestServerForOrg starts a test server whose requests are authenticated
+// as the given user of the given org, with the owner role.
+func SetupTestServerForOrg(userID uint, orgID uint) *httptest.Server {
+	return SetupTestServerAs(userID, orgID, model.RoleOwner)
+}
+
+// SetupTestServerAs starts a test server whose requests are authenticated
+// as the given user of the given org with the given role.
+func SetupTestServerAs(userID uint, orgID uint, role model.Role) *httptest.Server {
+	ts, _ := SetupTestServerWithWorker(userID, orgID, role)
+	return ts
+}
+
+// SetupTestServerWithWorker starts a test server like SetupTestServerAs and
+// returns the worker running its background jobs and events. Nothing runs
+// in the background, tests drain the worker when they need to.
+func SetupTestServerWithWorker(userID uint, orgID uint, role model.Role) (*httptest.Server, *Worker) {
 	r := chi.NewRouter()
-	db := SetupTestDB()
+	db := openTestDB()
 	config := &config.Config{
 		Env: "test",
 	}
 
+	logger := logger.New(os.Getenv("ENV")) // you can use a no-op logger
+	worker := &Worker{Jobs: jobs.New(db, logger)}
+	worker.Events = outbox.New(db, worker.Jobs, logger)
 	appCtx := &deps.AppContext{
 		DB:     db,
-		Config: config,                       // or a test config
-		Logger: logger.New(os.Getenv("ENV")), // you can use a no-op logger
+		Config: config, // or a test config
+		Logger: logger,
 		Cache:  nil,
+		Jobs:   worker.Jobs,
+		Events: worker.Events,
+		// Webhook receivers of tests listen on loopback with the self-signed
+		// certificate of httptest
+		HTTPClient: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
 	}
-	middlewares := NewFake(1, 2, "clerk-user-1")
+	middlewares := NewFake(db, userID, orgID, "clerk-user-1", role)
 	routes.Register(r, appCtx, middlewares, clerk.NewMock())
-	return httptest.NewServer(r)
+	return httptest.NewServer(r), worker
 }
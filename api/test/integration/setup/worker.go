@@ -0,0 +1,45 @@
+package setup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/jobs"
+	"github.com/deveasyclick/openb2b/internal/outbox"
+)
+
+// Worker runs the jobs and events of a test server on demand.
+type Worker struct {
+	Jobs   *jobs.Queue
+	Events *outbox.Outbox
+}
+
+// Drain publishes every recorded event and runs jobs until none are due.
+// Failed jobs are retried later, so they don't run again here.
+func (w *Worker) Drain(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+
+	for {
+		published, err := w.Events.RelayOnce(ctx)
+		if err != nil {
+			t.Fatalf("failed to relay events: %v", err)
+		}
+
+		ran := false
+		for {
+			ok, err := w.Jobs.RunOnce(ctx)
+			if err != nil {
+				t.Fatalf("failed to run job: %v", err)
+			}
+			if !ok {
+				break
+			}
+			ran = true
+		}
+
+		if published == 0 && !ran {
+			return
+		}
+	}
+}
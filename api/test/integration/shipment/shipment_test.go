@@ -0,0 +1,269 @@
+package shipment_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func do(t *testing.T, method string, url string, reqBody any) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	if reqBody != nil {
+		_ = json.NewEncoder(&body).Encode(reqBody)
+	}
+	req, _ := http.NewRequest(method, url, &body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func createApprovedOrder(t *testing.T, url string, customerID uint, items ...dto.CreateOrderItemDTO) model.Order {
+	t.Helper()
+	resp := do(t, http.MethodPost, url+"/api/v1/orders", dto.CreateOrderDTO{
+		CustomerID: customerID,
+		Items:      items,
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	order := decode[model.Order](t, resp).Data
+
+	resp = do(t, http.MethodPost, fmt.Sprintf("%s/api/v1/orders/%d/approve", url, order.ID), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	return decode[model.Order](t, resp).Data
+}
+
+func getOrder(t *testing.T, url string, ID uint) model.Order {
+	t.Helper()
+	resp := do(t, http.MethodGet, fmt.Sprintf("%s/api/v1/orders/%d", url, ID), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	return decode[model.Order](t, resp).Data
+}
+
+func itemOf(order model.Order, variantID uint) model.OrderItem {
+	for _, item := range order.Items {
+		if item.VariantID == variantID {
+			return item
+		}
+	}
+	return model.OrderItem{}
+}
+
+func findVariant(t *testing.T, db *gorm.DB, ID uint) model.Variant {
+	t.Helper()
+	var variant model.Variant
+	require.NoError(t, db.First(&variant, ID).Error)
+	return variant
+}
+
+func TestShipments(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	variantA := seed.InsertProductForOrg(db, setup.DefaultOrgID, "SHIP-A").Variants[0].ID // stock 10, price 10
+	variantB := seed.InsertProductForOrg(db, setup.DefaultOrgID, "SHIP-B").Variants[0].ID
+
+	order := createApprovedOrder(t, ts.URL, customer.ID,
+		dto.CreateOrderItemDTO{VariantID: variantA, Quantity: 4},
+		dto.CreateOrderItemDTO{VariantID: variantB, Quantity: 2},
+	)
+	itemA, itemB := itemOf(order, variantA), itemOf(order, variantB)
+
+	t.Run("Create shipment - pending order (409)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/orders", dto.CreateOrderDTO{
+			CustomerID: customer.ID,
+			Items:      []dto.CreateOrderItemDTO{{VariantID: variantB, Quantity: 1}},
+			Delivery: dto.CreateDeliveryInfoDTO{
+				Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+			},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		pending := decode[model.Order](t, resp).Data
+
+		resp = do(t, http.MethodPost, ts.URL+"/api/v1/shipments", dto.CreateShipmentDTO{OrderID: pending.ID})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrShipmentOrderNotApproved, decode[any](t, resp).Message)
+	})
+
+	var first model.Shipment
+	t.Run("Create shipment - part of an item", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/shipments", dto.CreateShipmentDTO{
+			OrderID: order.ID,
+			Carrier: "DHL",
+			Lines:   []dto.CreateShipmentLineDTO{{OrderItemID: itemA.ID, Quantity: 3}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		first = decode[model.Shipment](t, resp).Data
+
+		assert.Equal(t, model.ShipmentPending, first.Status)
+		assert.NotEmpty(t, first.ShipmentNumber)
+		require.Len(t, first.Lines, 1)
+		assert.Equal(t, 3, first.Lines[0].Quantity)
+		assert.Equal(t, "SHIP-A", first.Lines[0].SKU)
+	})
+
+	t.Run("Create shipment - more than is left to ship (400)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/shipments", dto.CreateShipmentDTO{
+			OrderID: order.ID,
+			Lines:   []dto.CreateShipmentLineDTO{{OrderItemID: itemA.ID, Quantity: 2}},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "shipment quantity exceeds what is left to ship: SHIP-A (allocated 4, on shipments 3, requested 2)", decode[any](t, resp).Message)
+	})
+
+	t.Run("Create shipment - item of another order (400)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/shipments", dto.CreateShipmentDTO{
+			OrderID: order.ID,
+			Lines:   []dto.CreateShipmentLineDTO{{OrderItemID: 999, Quantity: 1}},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Invoice shipment - not delivered (400)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/invoices", dto.CreateInvoiceDTO{OrderID: order.ID, ShipmentID: &first.ID})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrShipmentNotDelivered, decode[any](t, resp).Message)
+	})
+
+	t.Run("Ship shipment - order is shipped", func(t *testing.T) {
+		tracking := "TRACK-1"
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/shipments/%d", ts.URL, first.ID), dto.UpdateShipmentDTO{TrackingNumber: &tracking})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "TRACK-1", decode[model.Shipment](t, resp).Data.TrackingNumber)
+
+		resp = do(t, http.MethodPost, fmt.Sprintf("%s/api/v1/shipments/%d/ship", ts.URL, first.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		shipped := decode[model.Shipment](t, resp).Data
+		assert.Equal(t, model.ShipmentShipped, shipped.Status)
+		assert.NotNil(t, shipped.ShippedAt)
+
+		updated := getOrder(t, ts.URL, order.ID)
+		assert.Equal(t, model.OrderStatusApproved, updated.Status)
+		assert.Equal(t, model.DeliveryShipped, updated.Delivery.Status)
+		assert.Equal(t, 3, itemOf(updated, variantA).ShippedQuantity)
+		assert.Equal(t, 0, itemOf(updated, variantA).DeliveredQuantity)
+		assert.Len(t, updated.Shipments, 1)
+	})
+
+	t.Run("Ship shipment - twice (409)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/api/v1/shipments/%d/ship", ts.URL, first.ID), nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Deliver shipment - order is partly delivered", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/api/v1/shipments/%d/deliver", ts.URL, first.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotNil(t, decode[model.Shipment](t, resp).Data.DeliveredAt)
+
+		updated := getOrder(t, ts.URL, order.ID)
+		assert.Equal(t, model.OrderStatusApproved, updated.Status)
+		assert.Equal(t, model.DeliveryShipped, updated.Delivery.Status)
+		assert.Equal(t, 3, itemOf(updated, variantA).DeliveredQuantity)
+	})
+
+	t.Run("Invoice shipment - only what it delivered", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/invoices", dto.CreateInvoiceDTO{OrderID: order.ID, ShipmentID: &first.ID})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		invoice := decode[model.Invoice](t, resp).Data
+
+		require.NotNil(t, invoice.ShipmentID)
+		assert.Equal(t, first.ID, *invoice.ShipmentID)
+		require.Len(t, invoice.Items, 1)
+		assert.Equal(t, 3, invoice.Items[0].Quantity)
+		assert.Equal(t, money.FromInt(30), invoice.Subtotal)
+		assert.Equal(t, money.FromInt(30), invoice.Total)
+		assert.Equal(t, money.FromInt(30), invoice.AmountDue)
+
+		resp = do(t, http.MethodPost, ts.URL+"/api/v1/invoices", dto.CreateInvoiceDTO{OrderID: order.ID, ShipmentID: &first.ID})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrShipmentAlreadyInvoiced, decode[any](t, resp).Message)
+	})
+
+	t.Run("Deliver the rest - order is delivered", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/shipments", dto.CreateShipmentDTO{OrderID: order.ID})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		rest := decode[model.Shipment](t, resp).Data
+		require.Len(t, rest.Lines, 2)
+		quantities := map[uint]int{}
+		for _, line := range rest.Lines {
+			quantities[line.OrderItemID] = line.Quantity
+		}
+		assert.Equal(t, map[uint]int{itemA.ID: 1, itemB.ID: 2}, quantities)
+
+		resp = do(t, http.MethodPost, ts.URL+"/api/v1/shipments", dto.CreateShipmentDTO{OrderID: order.ID})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrNothingToShip, decode[any](t, resp).Message)
+
+		resp = do(t, http.MethodPost, fmt.Sprintf("%s/api/v1/shipments/%d/ship", ts.URL, rest.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = do(t, http.MethodPost, fmt.Sprintf("%s/api/v1/shipments/%d/deliver", ts.URL, rest.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		updated := getOrder(t, ts.URL, order.ID)
+		assert.Equal(t, model.OrderStatusDelivered, updated.Status)
+		assert.Equal(t, model.DeliveryDelivered, updated.Delivery.Status)
+		assert.NotNil(t, updated.Delivery.At)
+
+		// Delivery doesn't move stock again
+		assert.Equal(t, 6, findVariant(t, db, variantA).Stock)
+		assert.Equal(t, 8, findVariant(t, db, variantB).Stock)
+	})
+
+	t.Run("Cancel order - only what did not ship returns to stock", func(t *testing.T) {
+		order := createApprovedOrder(t, ts.URL, customer.ID, dto.CreateOrderItemDTO{VariantID: variantA, Quantity: 5})
+		item := itemOf(order, variantA)
+		assert.Equal(t, 1, findVariant(t, db, variantA).Stock)
+
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/shipments", dto.CreateShipmentDTO{
+			OrderID: order.ID,
+			Lines:   []dto.CreateShipmentLineDTO{{OrderItemID: item.ID, Quantity: 2}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		shipped := decode[model.Shipment](t, resp).Data
+		resp = do(t, http.MethodPost, fmt.Sprintf("%s/api/v1/shipments/%d/ship", ts.URL, shipped.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = do(t, http.MethodPost, ts.URL+"/api/v1/shipments", dto.CreateShipmentDTO{
+			OrderID: order.ID,
+			Lines:   []dto.CreateShipmentLineDTO{{OrderItemID: item.ID, Quantity: 1}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		pending := decode[model.Shipment](t, resp).Data
+
+		resp = do(t, http.MethodPost, fmt.Sprintf("%s/api/v1/orders/%d/cancel", ts.URL, order.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		assert.Equal(t, 4, findVariant(t, db, variantA).Stock)
+
+		resp = do(t, http.MethodGet, fmt.Sprintf("%s/api/v1/shipments/%d", ts.URL, pending.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, model.ShipmentCancelled, decode[model.Shipment](t, resp).Data.Status)
+	})
+}
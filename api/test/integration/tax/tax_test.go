@@ -0,0 +1,181 @@
+package tax_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/apperrors"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/money"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func do(t *testing.T, method string, url string, reqBody any) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	if reqBody != nil {
+		_ = json.NewEncoder(&body).Encode(reqBody)
+	}
+	req, _ := http.NewRequest(method, url, &body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func createOrder(t *testing.T, url string, customerID uint, variantID uint) model.Order {
+	t.Helper()
+	resp := do(t, http.MethodPost, url+"/api/v1/orders", dto.CreateOrderDTO{
+		CustomerID: customerID,
+		Items:      []dto.CreateOrderItemDTO{{VariantID: variantID, Quantity: 1}},
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	return decode[model.Order](t, resp).Data
+}
+
+func TestTaxes(t *testing.T) {
+	ts := setup.SetupTestServer()
+	defer ts.Close()
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "TAX-SKU")
+	variant := product.Variants[0]
+	variant.Price = money.FromInt(100)
+	require.NoError(t, db.Save(&variant).Error)
+
+	var rates []model.TaxRate
+	for _, rate := range []dto.CreateTaxRateDTO{
+		{Name: "VAT", Rate: 0.075},
+		{Name: "Levy", Rate: 0.02, Compound: true, Priority: 1},
+	} {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/tax-rates", rate)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		rates = append(rates, decode[model.TaxRate](t, resp).Data)
+	}
+
+	t.Run("Create tax class with unknown rates - bad request (400)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/tax-classes", dto.CreateTaxClassDTO{Name: "Broken", RateIDs: []uint{rates[0].ID, 999}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrUnknownTaxRates, decode[any](t, resp).Message)
+	})
+
+	resp := do(t, http.MethodPost, ts.URL+"/api/v1/tax-classes", dto.CreateTaxClassDTO{Name: "Standard", RateIDs: []uint{rates[1].ID, rates[0].ID}})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	standard := decode[model.TaxClass](t, resp).Data
+	assert.Len(t, standard.Rates, 2)
+
+	resp = do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/products/%d/variants/%d", ts.URL, product.ID, variant.ID), dto.UpdateVariantDTO{TaxClassID: &standard.ID})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	var order model.Order
+	t.Run("Create order - taxes by class", func(t *testing.T) {
+		order = createOrder(t, ts.URL, customer.ID, variant.ID)
+		item := order.Items[0]
+		assert.Equal(t, []model.TaxLine{
+			{Name: "VAT", Rate: 0.075, Amount: money.MustParse("7.50")},
+			{Name: "Levy", Rate: 0.02, Compound: true, Amount: money.MustParse("2.15")},
+		}, item.Taxes)
+		assert.Equal(t, money.MustParse("9.65"), order.TaxTotal)
+		assert.Equal(t, money.MustParse("109.65"), order.Total)
+	})
+
+	t.Run("Create invoice - snapshots the tax breakdown", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/invoices", dto.CreateInvoiceDTO{OrderID: order.ID})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		invoice := decode[model.Invoice](t, resp).Data
+		assert.Equal(t, order.Items[0].Taxes, invoice.Items[0].Taxes)
+		assert.Equal(t, order.TaxTotal, invoice.TaxTotal)
+	})
+
+	t.Run("Update class rates - replaces them", func(t *testing.T) {
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/tax-classes/%d", ts.URL, standard.ID), dto.UpdateTaxClassDTO{RateIDs: []uint{rates[0].ID}})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, decode[model.TaxClass](t, resp).Data.Rates, 1)
+
+		order := createOrder(t, ts.URL, customer.ID, variant.ID)
+		assert.Equal(t, money.MustParse("7.50"), order.TaxTotal)
+
+		resp = do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/tax-classes/%d", ts.URL, standard.ID), dto.UpdateTaxClassDTO{RateIDs: []uint{rates[0].ID, rates[1].ID}})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+	})
+
+	var exempt model.Customer
+	t.Run("Create order for a tax exempt customer - no tax", func(t *testing.T) {
+		resp := do(t, http.MethodPost, ts.URL+"/api/v1/customers", dto.CreateCustomerDTO{FirstName: "Exempt", LastName: "Buyer", PhoneNumber: "+2348000000009", TaxExempt: true})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		exempt = decode[model.Customer](t, resp).Data
+
+		order := createOrder(t, ts.URL, exempt.ID, variant.ID)
+		assert.Empty(t, order.Items[0].Taxes)
+		assert.Equal(t, money.Zero, order.TaxTotal)
+		assert.Equal(t, money.FromInt(100), order.Total)
+	})
+
+	t.Run("Move order to a tax exempt customer - taxes dropped", func(t *testing.T) {
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/orders/%d", ts.URL, order.ID), dto.UpdateOrderDTO{CustomerID: &exempt.ID})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+
+		var stored model.Order
+		require.NoError(t, db.Preload("Items").First(&stored, order.ID).Error)
+		assert.True(t, stored.TaxExempt)
+		assert.Equal(t, money.Zero, stored.TaxTotal)
+		assert.Empty(t, stored.Items[0].Taxes)
+		assert.Equal(t, money.FromInt(100), stored.Items[0].Total)
+	})
+
+	t.Run("Update customer - tax exemption can be lifted", func(t *testing.T) {
+		taxExempt := false
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/customers/%d", ts.URL, exempt.ID), dto.UpdateCustomerDTO{TaxExempt: &taxExempt})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+
+		var stored model.Customer
+		require.NoError(t, db.First(&stored, exempt.ID).Error)
+		assert.False(t, stored.TaxExempt)
+	})
+
+	t.Run("Create order with prices including tax - tax taken out", func(t *testing.T) {
+		pricesIncludeTax := true
+		resp := do(t, http.MethodPatch, fmt.Sprintf("%s/api/v1/orgs/%d", ts.URL, setup.DefaultOrgID), dto.UpdateOrgDTO{PricesIncludeTax: &pricesIncludeTax})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+
+		// 100.00 is 91.20 net, 6.84 VAT and 1.96 levy
+		order := createOrder(t, ts.URL, customer.ID, variant.ID)
+		assert.True(t, order.PricesIncludeTax)
+		assert.Equal(t, money.MustParse("6.84"), order.Items[0].Taxes[0].Amount)
+		assert.Equal(t, money.MustParse("1.96"), order.Items[0].Taxes[1].Amount)
+		assert.Equal(t, money.MustParse("8.80"), order.TaxTotal)
+		assert.Equal(t, money.FromInt(100), order.Total)
+	})
+
+	t.Run("Delete tax class in use - conflict (409)", func(t *testing.T) {
+		resp := do(t, http.MethodDelete, fmt.Sprintf("%s/api/v1/tax-classes/%d", ts.URL, standard.ID), nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		assert.Equal(t, apperrors.ErrTaxClassInUse, decode[any](t, resp).Message)
+	})
+}
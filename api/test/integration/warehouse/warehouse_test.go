@@ -0,0 +1,261 @@
+package warehouse_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/deveasyclick/openb2b/internal/model"
+	"github.com/deveasyclick/openb2b/internal/shared/dto"
+	"github.com/deveasyclick/openb2b/internal/shared/response"
+	"github.com/deveasyclick/openb2b/test/integration/seed"
+	"github.com/deveasyclick/openb2b/test/integration/setup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func do(t *testing.T, method string, url string, reqBody any) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	if reqBody != nil {
+		_ = json.NewEncoder(&body).Encode(reqBody)
+	}
+	req, _ := http.NewRequest(method, url, &body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) response.APIResponse[T] {
+	t.Helper()
+	defer resp.Body.Close()
+	var out response.APIResponse[T]
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out
+}
+
+func findVariant(t *testing.T, db *gorm.DB, ID uint) model.Variant {
+	t.Helper()
+	var variant model.Variant
+	require.NoError(t, db.First(&variant, ID).Error)
+	return variant
+}
+
+func findLevel(t *testing.T, db *gorm.DB, warehouseID, variantID uint) model.StockLevel {
+	t.Helper()
+	var level model.StockLevel
+	require.NoError(t, db.Where("warehouse_id = ? AND variant_id = ?", warehouseID, variantID).First(&level).Error)
+	return level
+}
+
+func orderDTO(customerID, variantID uint, quantity int, warehouseID *uint) dto.CreateOrderDTO {
+	return dto.CreateOrderDTO{
+		CustomerID:  customerID,
+		WarehouseID: warehouseID,
+		Items:       []dto.CreateOrderItemDTO{{VariantID: variantID, Quantity: quantity}},
+		Delivery: dto.CreateDeliveryInfoDTO{
+			Address: dto.AddressRequired{Address: "Street 1", City: "City 1", State: "State 1", Country: "Country 1", Zip: "02912"},
+		},
+	}
+}
+
+func TestWarehouses(t *testing.T) {
+	ts, _ := setup.SetupTestServerWithWorker(setup.DefaultUserID, setup.DefaultOrgID, model.RoleOwner)
+	defer ts.Close()
+	api := ts.URL + "/api/v1"
+
+	db := setup.SetupTestDB()
+	seed.InsertOrgWithID(db, setup.DefaultOrgID)
+	customer := seed.InsertCustomerForOrg(db, setup.DefaultOrgID)
+	product := seed.InsertProductForOrg(db, setup.DefaultOrgID, "WH-SKU") // stock 10
+	variant := product.Variants[0]
+
+	var main, depot model.Warehouse
+	t.Run("Create warehouse - the first is the default and takes over the stock", func(t *testing.T) {
+		resp := do(t, http.MethodPost, api+"/warehouses", dto.CreateWarehouseDTO{Name: "Main store", Code: "MAIN"})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		main = decode[model.Warehouse](t, resp).Data
+		assert.True(t, main.IsDefault)
+
+		assert.Equal(t, 10, findLevel(t, db, main.ID, variant.ID).Stock)
+	})
+
+	t.Run("Create warehouse - further warehouses start empty", func(t *testing.T) {
+		resp := do(t, http.MethodPost, api+"/warehouses", dto.CreateWarehouseDTO{Name: "North depot", Code: "DEPOT"})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		depot = decode[model.Warehouse](t, resp).Data
+		assert.False(t, depot.IsDefault)
+
+		var levels int64
+		db.Model(&model.StockLevel{}).Where("warehouse_id = ?", depot.ID).Count(&levels)
+		assert.Zero(t, levels)
+	})
+
+	t.Run("Create warehouse - duplicate code (409)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, api+"/warehouses", dto.CreateWarehouseDTO{Name: "Other depot", Code: "DEPOT"})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	t.Run("Create order - stock is short at the fulfilment location (409)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, api+"/orders", orderDTO(customer.ID, variant.ID, 1, &depot.ID))
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+
+		unknown := uint(9999)
+		resp = do(t, http.MethodPost, api+"/orders", orderDTO(customer.ID, variant.ID, 1, &unknown))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	var transfer model.StockTransfer
+	t.Run("Create stock transfer - pending, nothing moves", func(t *testing.T) {
+		resp := do(t, http.MethodPost, api+"/stock-transfers", dto.CreateStockTransferDTO{
+			FromWarehouseID: main.ID,
+			ToWarehouseID:   depot.ID,
+			Lines:           []dto.StockTransferLineDTO{{VariantID: variant.ID, Quantity: 4}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		transfer = decode[model.StockTransfer](t, resp).Data
+		assert.Equal(t, model.StockTransferPending, transfer.Status)
+		require.Len(t, transfer.Lines, 1)
+		assert.Equal(t, "WH-SKU", transfer.Lines[0].SKU)
+
+		assert.Equal(t, 10, findLevel(t, db, main.ID, variant.ID).Stock)
+
+		resp = do(t, http.MethodPost, api+"/stock-transfers", dto.CreateStockTransferDTO{
+			FromWarehouseID: main.ID,
+			ToWarehouseID:   9999,
+			Lines:           []dto.StockTransferLineDTO{{VariantID: variant.ID, Quantity: 1}},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	t.Run("Ship stock transfer - stock leaves the source warehouse", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/stock-transfers/%d/ship", api, transfer.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		shipped := decode[model.StockTransfer](t, resp).Data
+		assert.Equal(t, model.StockTransferInTransit, shipped.Status)
+		assert.NotNil(t, shipped.ShippedAt)
+
+		assert.Equal(t, 6, findLevel(t, db, main.ID, variant.ID).Stock)
+		assert.Equal(t, 6, findVariant(t, db, variant.ID).Stock)
+
+		// In transit transfers can't be cancelled or shipped again
+		resp = do(t, http.MethodPost, fmt.Sprintf("%s/stock-transfers/%d/cancel", api, transfer.ID), nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+	})
+
+	t.Run("Receive stock transfer - stock arrives at the destination", func(t *testing.T) {
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/stock-transfers/%d/receive", api, transfer.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, model.StockTransferReceived, decode[model.StockTransfer](t, resp).Data.Status)
+
+		assert.Equal(t, 4, findLevel(t, db, depot.ID, variant.ID).Stock)
+		assert.Equal(t, 10, findVariant(t, db, variant.ID).Stock)
+
+		var movements []model.StockMovement
+		require.NoError(t, db.Where("variant_id = ? AND reason = ?", variant.ID, model.StockReasonTransfer).
+			Order("id").Find(&movements).Error)
+		require.Len(t, movements, 2)
+		assert.Equal(t, -4, movements[0].Delta)
+		assert.Equal(t, main.ID, *movements[0].WarehouseID)
+		assert.Equal(t, 4, movements[1].Delta)
+		assert.Equal(t, depot.ID, *movements[1].WarehouseID)
+	})
+
+	t.Run("Ship stock transfer - more than is available (409)", func(t *testing.T) {
+		resp := do(t, http.MethodPost, api+"/stock-transfers", dto.CreateStockTransferDTO{
+			FromWarehouseID: depot.ID,
+			ToWarehouseID:   main.ID,
+			Lines:           []dto.StockTransferLineDTO{{VariantID: variant.ID, Quantity: 5}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		short := decode[model.StockTransfer](t, resp).Data
+
+		resp = do(t, http.MethodPost, fmt.Sprintf("%s/stock-transfers/%d/ship", api, short.ID), nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+		assert.Equal(t, 4, findLevel(t, db, depot.ID, variant.ID).Stock)
+
+		resp = do(t, http.MethodPost, fmt.Sprintf("%s/stock-transfers/%d/cancel", api, short.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, model.StockTransferCancelled, decode[model.StockTransfer](t, resp).Data.Status)
+	})
+
+	t.Run("Create order - stock is reserved at the fulfilment location", func(t *testing.T) {
+		resp := do(t, http.MethodPost, api+"/orders", orderDTO(customer.ID, variant.ID, 3, &depot.ID))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		order := decode[model.Order](t, resp).Data
+		require.NotNil(t, order.WarehouseID)
+		assert.Equal(t, depot.ID, *order.WarehouseID)
+
+		assert.Equal(t, 3, findLevel(t, db, depot.ID, variant.ID).Reserved)
+		assert.Zero(t, findLevel(t, db, main.ID, variant.ID).Reserved)
+		assert.Equal(t, 3, findVariant(t, db, variant.ID).Reserved)
+	})
+
+	t.Run("Create order - without a location the default warehouse is used", func(t *testing.T) {
+		// 7 are available in total, but only 6 at the main store
+		resp := do(t, http.MethodPost, api+"/orders", orderDTO(customer.ID, variant.ID, 7, nil))
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+
+		resp = do(t, http.MethodPost, api+"/orders", orderDTO(customer.ID, variant.ID, 6, nil))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		order := decode[model.Order](t, resp).Data
+		require.NotNil(t, order.WarehouseID)
+		assert.Equal(t, main.ID, *order.WarehouseID)
+
+		// Moving the order to the depot needs its stock available there
+		resp = do(t, http.MethodPatch, fmt.Sprintf("%s/orders/%d", api, order.ID), dto.UpdateOrderDTO{WarehouseID: &depot.ID})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+		assert.Equal(t, 6, findLevel(t, db, main.ID, variant.ID).Reserved)
+	})
+
+	t.Run("Get variant - availability per location", func(t *testing.T) {
+		resp := do(t, http.MethodGet, fmt.Sprintf("%s/products/%d/variants/%d", api, product.ID, variant.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		v := decode[model.Variant](t, resp).Data
+		assert.Equal(t, 10, v.Stock)
+		assert.Equal(t, 9, v.Reserved)
+
+		require.Len(t, v.Locations, 2)
+		assert.Equal(t, main.ID, v.Locations[0].WarehouseID)
+		assert.Equal(t, 0, v.Locations[0].Available())
+		assert.Equal(t, depot.ID, v.Locations[1].WarehouseID)
+		assert.Equal(t, 1, v.Locations[1].Available())
+	})
+
+	t.Run("Delete order - the reservation is released at its fulfilment location", func(t *testing.T) {
+		resp := do(t, http.MethodPost, api+"/orders", orderDTO(customer.ID, variant.ID, 1, &depot.ID))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		order := decode[model.Order](t, resp).Data
+		assert.Equal(t, 4, findLevel(t, db, depot.ID, variant.ID).Reserved)
+
+		resp = do(t, http.MethodDelete, fmt.Sprintf("%s/orders/%d", api, order.ID), nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		decode[any](t, resp)
+
+		assert.Equal(t, 3, findLevel(t, db, depot.ID, variant.ID).Reserved)
+		assert.Equal(t, 6, findLevel(t, db, main.ID, variant.ID).Reserved)
+		assert.Equal(t, 9, findVariant(t, db, variant.ID).Reserved)
+	})
+
+	t.Run("Delete warehouse - default and in use warehouses are kept (409)", func(t *testing.T) {
+		resp := do(t, http.MethodDelete, fmt.Sprintf("%s/warehouses/%d", api, main.ID), nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+
+		resp = do(t, http.MethodDelete, fmt.Sprintf("%s/warehouses/%d", api, depot.ID), nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		decode[any](t, resp)
+	})
+}